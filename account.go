@@ -2,9 +2,14 @@ package ecobank
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
-
-	"github.com/shopspring/decimal"
+	"slices"
+	"time"
 )
 
 // AccountService handles communication with the account related methods of the Ecobank API.
@@ -19,13 +24,7 @@ type AccountService struct {
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#89d7f8b9-49d8-4a8a-ae3c-acd26cb3e6fe
 type AccountBalance struct {
-	HostHeaderInfo struct {
-		SourceCode      string `json:"sourceCode"`
-		RequestID       string `json:"requestId"`
-		AffiliateCode   string `json:"affiliateCode"`
-		ResponseCode    string `json:"responseCode"`
-		ResponseMessage string `json:"responseMessage"`
-	} `json:"hostHeaderInfo"`
+	HostHeaderInfo HostHeaderInfo `json:"hostHeaderInfo"`
 
 	AccountNo        string          `json:"accountNo"`
 	ResponseCode     string          `json:"responseCode"`
@@ -34,9 +33,9 @@ type AccountBalance struct {
 	Currency         string          `json:"ccy"`
 	BranchCode       string          `json:"branchCode"`
 	CustomerID       string          `json:"customerID"`
-	AvailableBalance decimal.Decimal `json:"availableBalance"`
-	CurrentBalance   decimal.Decimal `json:"currentBalance"`
-	OverdraftLimit   decimal.Decimal `json:"odlimit"`
+	AvailableBalance FlexibleDecimal `json:"availableBalance"`
+	CurrentBalance   FlexibleDecimal `json:"currentBalance"`
+	OverdraftLimit   FlexibleDecimal `json:"odlimit"`
 	AccountType      string          `json:"accountType"`
 	AccountClass     string          `json:"accountClass"`
 	AccountStatus    string          `json:"accountStatus"`
@@ -48,7 +47,7 @@ type AccountBalance struct {
 type AccountBalanceOptions struct {
 	RequestID     string `json:"requestId"`
 	AffiliateCode string `json:"affiliateCode"`
-	AccountNo     string `json:"accountNo"`
+	AccountNo     string `json:"accountNo" redact:"partial"`
 	ClientID      string `json:"clientId"`
 	CompanyName   string `json:"companyName"`
 
@@ -58,8 +57,8 @@ type AccountBalanceOptions struct {
 // GetBalance gets the account balance for the given account.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#89d7f8b9-49d8-4a8a-ae3c-acd26cb3e6fe
-func (a *AccountService) GetBalance(ctx context.Context, opt *AccountBalanceOptions) (*AccountBalance, *Response, error) {
-	return DoRequest[AccountBalance](ctx, a.client, http.MethodPost, "merchant/accountbalance", opt)
+func (a *AccountService) GetBalance(ctx context.Context, opt *AccountBalanceOptions, options ...RequestOptionFunc) (*AccountBalance, *Response, error) {
+	return DoRequest[AccountBalance](ctx, a.client, http.MethodPost, a.client.path("Account", "GetBalance"), opt, options...)
 }
 
 // AccountEnquiry represents a response to an account enquiry request.
@@ -81,7 +80,7 @@ type AccountEnquiry struct {
 type AccountEnquiryOptions struct {
 	RequestID     string `json:"requestId"`
 	AffiliateCode string `json:"affiliateCode"`
-	AccountNo     string `json:"accountNo"`
+	AccountNo     string `json:"accountNo" redact:"partial"`
 	ClientID      string `json:"clientId"`
 	CompanyName   string `json:"companyName"`
 
@@ -91,24 +90,18 @@ type AccountEnquiryOptions struct {
 // Enquiry gets the account details for the given account.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#065afcf7-402b-4625-82d2-24f2dbbfe663
-func (a *AccountService) Enquiry(ctx context.Context, opt *AccountEnquiryOptions) (*AccountEnquiry, *Response, error) {
-	return DoRequest[AccountEnquiry](ctx, a.client, http.MethodPost, "merchant/accountinquiry", opt)
+func (a *AccountService) Enquiry(ctx context.Context, opt *AccountEnquiryOptions, options ...RequestOptionFunc) (*AccountEnquiry, *Response, error) {
+	return DoRequest[AccountEnquiry](ctx, a.client, http.MethodPost, a.client.path("Account", "Enquiry"), opt, options...)
 }
 
 // AccountEnquiryThirdParty represents the response from the account inquiry for third-party payment.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#26923112-e8b8-4956-9f64-0f7f7b489290
 type AccountEnquiryThirdParty struct {
-	AccountName    string `json:"accountName"`
-	AccountType    string `json:"accountType"`
-	AccountStatus  string `json:"accountStatus"`
-	HostHeaderInfo struct {
-		SourceCode      string `json:"sourceCode"`
-		RequestID       string `json:"requestId"`
-		AffiliateCode   string `json:"affiliateCode"`
-		ResponseCode    string `json:"responseCode"`
-		ResponseMessage string `json:"responseMessage"`
-	} `json:"hostHeaderInfo"`
+	AccountName    string         `json:"accountName"`
+	AccountType    string         `json:"accountType"`
+	AccountStatus  string         `json:"accountStatus"`
+	HostHeaderInfo HostHeaderInfo `json:"hostHeaderInfo"`
 }
 
 // AccountEnquiryThirdPartyOptions represents a request to perform an account inquiry for third-party payment.
@@ -117,7 +110,7 @@ type AccountEnquiryThirdParty struct {
 type AccountEnquiryThirdPartyOptions struct {
 	RequestID           string `json:"requestId"`
 	AffiliateCode       string `json:"affiliateCode"`
-	AccountNo           string `json:"accountNo"`
+	AccountNo           string `json:"accountNo" redact:"partial"`
 	DestinationBankCode string `json:"destinationBankCode"`
 	ClientID            string `json:"clientId"`
 	CompanyName         string `json:"companyName"`
@@ -128,8 +121,8 @@ type AccountEnquiryThirdPartyOptions struct {
 // EnquiryThirdParty performs an account inquiry for third-party payment.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#26923112-e8b8-4956-9f64-0f7f7b489290
-func (a *AccountService) EnquiryThirdParty(ctx context.Context, opt *AccountEnquiryThirdPartyOptions) (*AccountEnquiryThirdParty, *Response, error) {
-	return DoRequest[AccountEnquiryThirdParty](ctx, a.client, http.MethodPost, "merchant/accountinquirythridpay", opt)
+func (a *AccountService) EnquiryThirdParty(ctx context.Context, opt *AccountEnquiryThirdPartyOptions, options ...RequestOptionFunc) (*AccountEnquiryThirdParty, *Response, error) {
+	return DoRequest[AccountEnquiryThirdParty](ctx, a.client, http.MethodPost, a.client.path("Account", "EnquiryThirdParty"), opt, options...)
 }
 
 // StatementTransaction represents a single transaction record.
@@ -154,7 +147,7 @@ type GenerateStatementOptions struct {
 	RequestID     string `json:"requestId"`
 	ClientID      string `json:"clientId"`
 	AffiliateCode string `json:"affiliateCode"`
-	AccountNumber string `json:"accountNumber"`
+	AccountNumber string `json:"accountNumber" redact:"partial"`
 	StartDate     Date   `json:"startDate"`
 	EndDate       Date   `json:"endDate"`
 
@@ -164,8 +157,8 @@ type GenerateStatementOptions struct {
 // GenerateStatement generates an account statement for the given account.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#01be6373-e019-4995-aca3-733366acf557
-func (a *AccountService) GenerateStatement(ctx context.Context, opt *GenerateStatementOptions) ([]*StatementTransaction, *Response, error) {
-	statements, resp, err := DoRequest[[]*StatementTransaction](ctx, a.client, http.MethodPost, "merchant/statement", opt)
+func (a *AccountService) GenerateStatement(ctx context.Context, opt *GenerateStatementOptions, options ...RequestOptionFunc) ([]*StatementTransaction, *Response, error) {
+	statements, resp, err := DoRequest[[]*StatementTransaction](ctx, a.client, http.MethodPost, a.client.path("Account", "GenerateStatement"), opt, options...)
 	if err != nil || statements == nil {
 		return nil, resp, err
 	}
@@ -173,58 +166,184 @@ func (a *AccountService) GenerateStatement(ctx context.Context, opt *GenerateSta
 	return *statements, resp, nil
 }
 
+// ForEachStatementTransaction streams an account statement for the given account, calling fn for each
+// transaction as it is decoded. Unlike GenerateStatement, the whole statement is never held in memory,
+// which makes it suitable for long statement periods. Returning an error from fn stops the iteration
+// and the error is returned.
+//
+// API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#01be6373-e019-4995-aca3-733366acf557
+func (a *AccountService) ForEachStatementTransaction(ctx context.Context, opt *GenerateStatementOptions, fn func(*StatementTransaction) error, options ...RequestOptionFunc) (*Response, error) {
+	req, err := a.client.NewRequest(ctx, http.MethodPost, a.client.path("Account", "GenerateStatement"), opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.DoStream(req, func(dec *json.Decoder) error {
+		return decodeArray(dec, fn)
+	})
+}
+
 // CreateAccountOptions represents the parameters for creating an account.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#80dc2169-8b2c-435e-8259-5bda0f6ab94c
 type CreateAccountOptions struct {
-	ClientID           string `json:"clientId"`
-	RequestID          string `json:"requestId"`
-	AffiliateCode      string `json:"affiliateCode"`
-	FirstName          string `json:"firstName"`
-	Middlename         string `json:"middlename"`
-	Lastname           string `json:"lastname"`
-	MobileNo           string `json:"mobileNo"`
-	Gender             string `json:"gender"`
-	IdentityNo         string `json:"identityNo"`
-	IdentityType       string `json:"identityType"`
-	IDIssueDate        string `json:"iDIssueDate"`
-	IDExpiryDate       string `json:"iDExpiryDate"`
-	Ccy                string `json:"ccy"`
-	Country            string `json:"country"`
-	BranchCode         string `json:"branchCode"`
-	DateOfBirth        string `json:"dateOfBirth"`
-	CountryOfResidence string `json:"countryOfResidence"`
-	Email              string `json:"email"`
-	Street             string `json:"street"`
-	City               string `json:"city"`
-	State              string `json:"state"`
-	Image              string `json:"image"`
-	Signature          string `json:"signature"`
+	ClientID           string       `json:"clientId"`
+	RequestID          string       `json:"requestId"`
+	AffiliateCode      string       `json:"affiliateCode"`
+	FirstName          string       `json:"firstName"`
+	Middlename         string       `json:"middlename"`
+	Lastname           string       `json:"lastname"`
+	MobileNo           string       `json:"mobileNo" redact:"partial"`
+	Gender             Gender       `json:"gender"`
+	IdentityNo         string       `json:"identityNo" redact:"partial"`
+	IdentityType       IdentityType `json:"identityType"`
+	IDIssueDate        Date         `json:"iDIssueDate"`
+	IDExpiryDate       Date         `json:"iDExpiryDate"`
+	Ccy                string       `json:"ccy"`
+	Country            string       `json:"country"`
+	BranchCode         string       `json:"branchCode"`
+	DateOfBirth        Date         `json:"dateOfBirth"`
+	CountryOfResidence string       `json:"countryOfResidence"`
+	Email              string       `json:"email"`
+	Street             string       `json:"street"`
+	City               string       `json:"city"`
+	State              string       `json:"state"`
+	Image              string       `json:"image" redact:"secret"`
+	Signature          string       `json:"signature" redact:"secret"`
 
 	secureHashOption
 }
 
+// accountDateFormat is the DDMMYYYY layout expected by the API for dates when creating an account.
+const accountDateFormat = "02012006"
+
+// NewAccountDate returns a new Date with the DDMMYYYY layout expected by CreateAccountOptions.
+func NewAccountDate(time time.Time) Date {
+	return NewDateWithLayout(time, accountDateFormat)
+}
+
+// Gender represents the gender of an account holder.
+type Gender string
+
+const (
+	GenderMale   Gender = "M"
+	GenderFemale Gender = "F"
+)
+
+// IdentityType represents the type of identity document used to open an account.
+type IdentityType string
+
+const (
+	IdentityTypeMobileWalletNo IdentityType = "MOBILE_WALLET_NO"
+	IdentityTypeNationalID     IdentityType = "NATIONAL_ID"
+	IdentityTypePassport       IdentityType = "PASSPORT"
+	IdentityTypeDriversLicense IdentityType = "DRIVERS_LICENSE"
+	IdentityTypeVotersID       IdentityType = "VOTERS_ID"
+)
+
+// Validate checks the fields that are most commonly rejected by the API when creating an account.
+// All violations are returned joined together.
+func (opt *CreateAccountOptions) Validate() error {
+	var errs []error
+
+	if opt.Gender != GenderMale && opt.Gender != GenderFemale {
+		errs = append(errs, fmt.Errorf("invalid gender %q: must be %q or %q", opt.Gender, GenderMale, GenderFemale))
+	}
+
+	if opt.IdentityType == "" {
+		errs = append(errs, errors.New("identity type is required"))
+	}
+
+	if opt.DateOfBirth.GetTime().IsZero() {
+		errs = append(errs, errors.New("date of birth is required"))
+	}
+
+	issue, expiry := opt.IDIssueDate.GetTime(), opt.IDExpiryDate.GetTime()
+	if !issue.IsZero() && !expiry.IsZero() && !expiry.After(issue) {
+		errs = append(errs, fmt.Errorf("id expiry date %s must be after issue date %s", opt.IDExpiryDate, opt.IDIssueDate))
+	}
+
+	return errors.Join(errs...)
+}
+
+// normalizeDates returns a copy of the options with all the dates formatted with the layout expected by the API.
+func (opt *CreateAccountOptions) normalizeDates() *CreateAccountOptions {
+	normalized := *opt
+	for _, d := range []*Date{&normalized.DateOfBirth, &normalized.IDIssueDate, &normalized.IDExpiryDate} {
+		d.layout = accountDateFormat
+	}
+	return &normalized
+}
+
+// MaxAccountImageSize is the maximum size in bytes of the image and signature
+// accepted by SetImageFromReader and SetSignatureFromReader.
+const MaxAccountImageSize = 1 << 20
+
+var (
+	// ErrImageTooLarge is returned when an image exceeds MaxAccountImageSize.
+	ErrImageTooLarge = errors.New("image too large")
+	// ErrUnsupportedImageFormat is returned when an image is not in one of the supported formats (JPEG or PNG).
+	ErrUnsupportedImageFormat = errors.New("unsupported image format")
+)
+
+var supportedImageTypes = []string{"image/jpeg", "image/png"}
+
+// SetImageFromReader reads the customer's photo from r, validates it and sets it as the base64 encoded Image.
+//
+// The image must be a JPEG or PNG no larger than MaxAccountImageSize.
+func (opt *CreateAccountOptions) SetImageFromReader(r io.Reader) (err error) {
+	opt.Image, err = encodeImage(r)
+	return err
+}
+
+// SetSignatureFromReader reads the customer's signature from r, validates it and sets it as the base64 encoded Signature.
+//
+// The image must be a JPEG or PNG no larger than MaxAccountImageSize.
+func (opt *CreateAccountOptions) SetSignatureFromReader(r io.Reader) (err error) {
+	opt.Signature, err = encodeImage(r)
+	return err
+}
+
+// encodeImage reads an image from r and returns it base64 encoded.
+func encodeImage(r io.Reader) (string, error) {
+	// read one byte past the limit so we can tell when the image is too large
+	b, err := io.ReadAll(io.LimitReader(r, MaxAccountImageSize+1))
+	if err != nil {
+		return "", err
+	}
+
+	if len(b) > MaxAccountImageSize {
+		return "", fmt.Errorf("%w: maximum size is %d bytes", ErrImageTooLarge, MaxAccountImageSize)
+	}
+
+	if ct := http.DetectContentType(b); !slices.Contains(supportedImageTypes, ct) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedImageFormat, ct)
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
 // CreateAccountResponse represents the response after attempting to create an account.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#80dc2169-8b2c-435e-8259-5bda0f6ab94c
 type CreateAccountResponse struct {
-	Shortname      string `json:"shortname"`
-	AccountNo      string `json:"accountNo"`
-	MobileNo       string `json:"mobileNo"`
-	TrackRef       string `json:"trackRef"`
-	ClientID       string `json:"clientId"`
-	HostHeaderInfo struct {
-		SourceCode      string `json:"sourceCode"`
-		RequestID       string `json:"requestId"`
-		AffiliateCode   string `json:"affiliateCode"`
-		ResponseCode    string `json:"responseCode"`
-		ResponseMessage string `json:"responseMessage"`
-	} `json:"hostHeaderInfo"`
+	Shortname string `json:"shortname"`
+	AccountNo string `json:"accountNo"`
+	MobileNo  string `json:"mobileNo"`
+	// TrackRef references the account opening request. The published API has no endpoint to track it yet,
+	// so use AccountService.Enquiry with AccountNo to check whether the account is active.
+	TrackRef       string         `json:"trackRef"`
+	ClientID       string         `json:"clientId"`
+	HostHeaderInfo HostHeaderInfo `json:"hostHeaderInfo"`
 }
 
 // CreateAccount creates an account.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#80dc2169-8b2c-435e-8259-5bda0f6ab94c
-func (a *AccountService) CreateAccount(ctx context.Context, opt *CreateAccountOptions) (*CreateAccountResponse, *Response, error) {
-	return DoRequest[CreateAccountResponse](ctx, a.client, http.MethodPost, "merchant/createexpressaccount", opt)
+func (a *AccountService) CreateAccount(ctx context.Context, opt *CreateAccountOptions, options ...RequestOptionFunc) (*CreateAccountResponse, *Response, error) {
+	if err := opt.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return DoRequest[CreateAccountResponse](ctx, a.client, http.MethodPost, a.client.path("Account", "CreateAccount"), opt.normalizeDates(), options...)
 }
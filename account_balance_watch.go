@@ -0,0 +1,77 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// balanceWatchJitter is the fraction of the interval by which each poll is randomly shifted,
+// so that many watchers started together do not hit the API at the same time.
+const balanceWatchJitter = 0.1
+
+// BalanceWatchFunc is called by WatchBalance when the available balance crosses the threshold.
+// If polling the balance fails, it is called with a nil balance and the error.
+type BalanceWatchFunc func(balance *AccountBalance, err error)
+
+// WatchBalance polls the balance of the given account every interval until ctx is done, calling fn
+// whenever the available balance crosses threshold in either direction. fn is also called after the
+// first poll if the available balance is already below threshold, which makes it suitable for
+// prefunding alerts in payout systems.
+//
+// Each poll is shifted by a random jitter of up to 10% of interval. The access token is refreshed
+// as needed. Errors from polling are passed to fn and do not stop the watcher.
+//
+// WatchBalance blocks until ctx is done and returns the context's error, or ErrClientClosed if the client is closed first.
+func (a *AccountService) WatchBalance(ctx context.Context, opt *AccountBalanceOptions, interval time.Duration, threshold decimal.Decimal, fn BalanceWatchFunc, options ...RequestOptionFunc) error {
+	if interval <= 0 {
+		return errors.New("interval must be greater than zero")
+	}
+
+	var (
+		polled bool
+		below  bool
+	)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-a.client.closed():
+			return ErrClientClosed
+		case <-timer.C:
+		}
+
+		balance, _, err := a.GetBalance(ctx, opt, options...)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, ErrClientClosed) {
+				return err
+			}
+			fn(nil, err)
+		default:
+			isBelow := balance.AvailableBalance.LessThan(threshold)
+			if isBelow != below || (!polled && isBelow) {
+				fn(balance, nil)
+			}
+			polled, below = true, isBelow
+		}
+
+		timer.Reset(jitter(interval))
+	}
+}
+
+// jitter returns d shifted randomly by up to balanceWatchJitter of its value.
+func jitter(d time.Duration) time.Duration {
+	delta := time.Duration(float64(d) * balanceWatchJitter * (2*rand.Float64() - 1))
+	return d + delta
+}
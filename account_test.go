@@ -1,12 +1,19 @@
 package ecobank
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccountService_GetBalance(t *testing.T) {
@@ -54,8 +61,8 @@ func TestAccountService_GetBalance(t *testing.T) {
 	assert.Equal(t, "1441000574000", resp.AccountNo)
 	assert.Equal(t, "TEST USER", resp.AccountName)
 	assert.Equal(t, "GHS", resp.Currency)
-	assert.Equal(t, decimal.NewFromFloat(15.92), resp.AvailableBalance)
-	assert.Equal(t, decimal.NewFromFloat(15.92), resp.CurrentBalance)
+	assert.Equal(t, decimal.NewFromFloat(15.92), resp.AvailableBalance.Decimal)
+	assert.Equal(t, decimal.NewFromFloat(15.92), resp.CurrentBalance.Decimal)
 	assert.Equal(t, "ACTIVE", resp.AccountStatus)
 }
 
@@ -194,3 +201,295 @@ func TestAccountService_GenerateStatement(t *testing.T) {
 	assert.Equal(t, "10", resp[0].PaidIn)
 	assert.Equal(t, "MOBILE TRANSFER BD1441000820520-SA Xpress Account DT0209", resp[0].Narrative)
 }
+
+func TestAccountService_ForEachStatementTransaction(t *testing.T) {
+	mockResponse := `{
+		"response_code": 200,
+		"response_message": "success",
+		"response_content": [
+			{"acccy": "GHS", "drcrind": "CR", "trnrefno": "H75ZEXA1923800E0", "paidin": "10", "valuedate": "2019-09-02 20:00:00.0", "lcyamount1": "10"},
+			{"acccy": "GHS", "drcrind": "CR", "trnrefno": "H75ZEXA1923800E1", "paidin": "15", "valuedate": "2019-09-02 21:30:00.0", "lcyamount1": "15"}
+		],
+		"response_timestamp": "2022-04-19T19:44:21.866"
+	}`
+
+	client := newMockClient(t, mockResponse, http.StatusOK)
+
+	opt := &GenerateStatementOptions{
+		CorporateID:   "OMNI",
+		RequestID:     "123456",
+		ClientID:      "ZEEPAY",
+		AffiliateCode: "EGH",
+		AccountNumber: "1441000574000",
+		StartDate:     NewDate(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:       NewDate(time.Date(2020, 3, 16, 0, 0, 0, 0, time.UTC)),
+	}
+
+	var refs []string
+	resp, err := client.Account.ForEachStatementTransaction(t.Context(), opt, func(txn *StatementTransaction) error {
+		refs = append(refs, txn.RefNumber)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.Code)
+	assert.Equal(t, []string{"H75ZEXA1923800E0", "H75ZEXA1923800E1"}, refs)
+}
+
+func TestCreateAccountOptions_SetImageFromReader(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), "image-data"...)
+	jpeg := append([]byte("\xff\xd8\xff\xe0"), "image-data"...)
+
+	testCases := []struct {
+		name    string
+		input   []byte
+		wantErr error
+	}{
+		{
+			name:  "png",
+			input: png,
+		},
+		{
+			name:  "jpeg",
+			input: jpeg,
+		},
+		{
+			name:    "unsupported format",
+			input:   []byte("GIF89a-image-data"),
+			wantErr: ErrUnsupportedImageFormat,
+		},
+		{
+			name:    "empty",
+			input:   nil,
+			wantErr: ErrUnsupportedImageFormat,
+		},
+		{
+			name:    "too large",
+			input:   append(png, bytes.Repeat([]byte{0}, MaxAccountImageSize)...),
+			wantErr: ErrImageTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opt CreateAccountOptions
+
+			err := opt.SetImageFromReader(bytes.NewReader(tc.input))
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Empty(t, opt.Image)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, base64.StdEncoding.EncodeToString(tc.input), opt.Image)
+
+			err = opt.SetSignatureFromReader(bytes.NewReader(tc.input))
+			assert.NoError(t, err)
+			assert.Equal(t, opt.Image, opt.Signature)
+		})
+	}
+}
+
+func TestCreateAccountOptions_Validate(t *testing.T) {
+	valid := func() *CreateAccountOptions {
+		return &CreateAccountOptions{
+			Gender:       GenderFemale,
+			IdentityType: IdentityTypePassport,
+			DateOfBirth:  NewAccountDate(time.Date(1990, 7, 1, 0, 0, 0, 0, time.UTC)),
+			IDIssueDate:  NewAccountDate(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)),
+			IDExpiryDate: NewAccountDate(time.Date(2031, 7, 1, 0, 0, 0, 0, time.UTC)),
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		modify  func(opt *CreateAccountOptions)
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			modify: func(opt *CreateAccountOptions) {},
+		},
+		{
+			name:    "invalid gender",
+			modify:  func(opt *CreateAccountOptions) { opt.Gender = "Male" },
+			wantErr: `invalid gender "Male"`,
+		},
+		{
+			name:    "missing identity type",
+			modify:  func(opt *CreateAccountOptions) { opt.IdentityType = "" },
+			wantErr: "identity type is required",
+		},
+		{
+			name:    "missing date of birth",
+			modify:  func(opt *CreateAccountOptions) { opt.DateOfBirth = Date{} },
+			wantErr: "date of birth is required",
+		},
+		{
+			name: "expiry before issue",
+			modify: func(opt *CreateAccountOptions) {
+				opt.IDExpiryDate = NewAccountDate(time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC))
+			},
+			wantErr: "id expiry date 01072020 must be after issue date 01072021",
+		},
+		{
+			name:    "expiry equals issue",
+			modify:  func(opt *CreateAccountOptions) { opt.IDExpiryDate = opt.IDIssueDate },
+			wantErr: "must be after issue date",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := valid()
+			tc.modify(opt)
+
+			err := opt.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestAccountService_CreateAccount(t *testing.T) {
+	mockResponse := `{
+		"response_code": 200,
+		"response_message": "success",
+		"response_content": {
+			"shortname": "ROTIMI AKINOLA",
+			"accountNo": "1441000574000",
+			"mobileNo": "2348089991325",
+			"trackRef": "TRK123",
+			"clientId": "ECO76383823"
+		},
+		"response_timestamp": "2022-04-19T19:44:21.866"
+	}`
+
+	client := newMockClient(t, mockResponse, http.StatusOK)
+
+	var body map[string]any
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			rec := httptest.NewRecorder()
+			_, _ = rec.WriteString(mockResponse)
+			return rec.Result(), nil
+		},
+	}
+
+	opt := &CreateAccountOptions{
+		ClientID:     "ECO76383823",
+		Gender:       GenderMale,
+		IdentityType: IdentityTypeMobileWalletNo,
+		// dates are formatted as DDMMYYYY regardless of the layout they were created with
+		DateOfBirth:  NewDate(time.Date(1990, 7, 1, 0, 0, 0, 0, time.UTC)),
+		IDIssueDate:  NewAccountDate(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)),
+		IDExpiryDate: NewAccountDate(time.Date(2031, 7, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	resp, _, err := client.Account.CreateAccount(t.Context(), opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "TRK123", resp.TrackRef)
+
+	assert.Equal(t, "M", body["gender"])
+	assert.Equal(t, "MOBILE_WALLET_NO", body["identityType"])
+	assert.Equal(t, "01071990", body["dateOfBirth"])
+	assert.Equal(t, "01072021", body["iDIssueDate"])
+	assert.Equal(t, "01072031", body["iDExpiryDate"])
+	assert.Equal(t, dateFormat, opt.DateOfBirth.layout, "the options of the caller are not changed")
+
+	t.Run("without ID dates", func(t *testing.T) {
+		opt := &CreateAccountOptions{
+			ClientID:     "ECO76383823",
+			Gender:       GenderFemale,
+			IdentityType: IdentityTypeMobileWalletNo,
+			DateOfBirth:  NewAccountDate(time.Date(1990, 7, 1, 0, 0, 0, 0, time.UTC)),
+		}
+
+		_, _, err := client.Account.CreateAccount(t.Context(), opt)
+		require.NoError(t, err)
+		assert.Equal(t, "01071990", body["dateOfBirth"])
+		assert.Equal(t, "", body["iDIssueDate"], "unset optional dates are sent empty")
+		assert.Equal(t, "", body["iDExpiryDate"])
+
+		source := secureHashSource(opt.normalizeDates(), AmountFormatTrimZeros)
+		assert.NotContains(t, source, "01010001", "unset optional dates are hashed empty")
+	})
+
+	t.Run("invalid options are not sent", func(t *testing.T) {
+		client := newMockClient(t, mockResponse, http.StatusOK)
+		_, resp, err := client.Account.CreateAccount(t.Context(), &CreateAccountOptions{})
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestAccountService_WatchBalance(t *testing.T) {
+	balances := []string{"100", "40", "30", "error", "120", "130"}
+
+	var calls int
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			rec := httptest.NewRecorder()
+			balance := balances[min(calls, len(balances)-1)]
+			calls++
+			if balance == "error" {
+				rec.WriteHeader(http.StatusBadRequest)
+				_, _ = rec.WriteString(`{"response_code": 400, "errors": ["temporary failure"]}`)
+				return rec.Result(), nil
+			}
+			_, _ = fmt.Fprintf(rec, `{"response_code": 200, "response_content": {"accountNo": "1441000574000", "availableBalance": %s}}`, balance)
+			return rec.Result(), nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var (
+		got  []string
+		errs int
+	)
+
+	err := client.Account.WatchBalance(ctx, &AccountBalanceOptions{AccountNo: "1441000574000"}, time.Millisecond, decimal.NewFromInt(50),
+		func(balance *AccountBalance, err error) {
+			if err != nil {
+				errs++
+				return
+			}
+			got = append(got, balance.AvailableBalance.String())
+			if len(got) == 2 {
+				cancel()
+			}
+		})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"40", "120"}, got)
+	assert.Equal(t, 1, errs)
+}
+
+func TestAccountService_WatchBalance_InitiallyBelow(t *testing.T) {
+	client := newMockClient(t, `{"response_code": 200, "response_content": {"availableBalance": 10}}`, http.StatusOK)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var got []string
+	err := client.Account.WatchBalance(ctx, &AccountBalanceOptions{}, time.Millisecond, decimal.NewFromInt(50),
+		func(balance *AccountBalance, err error) {
+			got = append(got, balance.AvailableBalance.String())
+			cancel()
+		})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"10"}, got)
+
+	err = client.Account.WatchBalance(t.Context(), &AccountBalanceOptions{}, 0, decimal.Zero, nil)
+	assert.Error(t, err)
+}
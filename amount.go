@@ -0,0 +1,240 @@
+package ecobank
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/shopspring/decimal"
+)
+
+// AmountFormat controls how amounts are formatted in request payloads and secure hashes.
+//
+// The API is strict about amount formats, and the same format must be used in the payload
+// and in the string the secure hash is generated from, otherwise the hash is rejected.
+type AmountFormat int32
+
+const (
+	// AmountFormatTrimZeros formats amounts without trailing zeros, e.g. "520" and "520.5".
+	// This is the default.
+	AmountFormatTrimZeros AmountFormat = iota
+	// AmountFormatFixed2 formats amounts with exactly two decimal places, e.g. "520.00" and "520.50".
+	AmountFormatFixed2
+)
+
+var defaultAmountFormat atomic.Int32
+
+// SetAmountFormat sets the format of the amounts sent to the API by the clients created without
+// WithAmountFormat. It also applies to payments encoded with json.Marshal.
+//
+// It is safe to call SetAmountFormat concurrently, but it should be set once before making any requests.
+//
+// Deprecated: use WithAmountFormat to set the format of a single client.
+func SetAmountFormat(format AmountFormat) {
+	defaultAmountFormat.Store(int32(format))
+}
+
+// WithAmountFormat sets the format of the amounts the client sends to the API. It applies to payment
+// headers, payment extensions and payment params, both in the JSON payload and in the secure hash.
+// It defaults to AmountFormatTrimZeros.
+func WithAmountFormat(format AmountFormat) ClientOptionFunc {
+	return func(c *Client) error {
+		if format != AmountFormatTrimZeros && format != AmountFormatFixed2 {
+			return fmt.Errorf("invalid amount format %d", format)
+		}
+		c.amountFormat = &format
+		return nil
+	}
+}
+
+// amounts returns the format of the amounts sent by the client.
+func (c *Client) amounts() AmountFormat {
+	if c.amountFormat != nil {
+		return *c.amountFormat
+	}
+	return globalAmountFormat()
+}
+
+// format formats the amount d.
+func (f AmountFormat) format(d decimal.Decimal) string {
+	if f == AmountFormatFixed2 {
+		return d.StringFixed(2)
+	}
+	return d.String()
+}
+
+// globalAmountFormat returns the format set with SetAmountFormat.
+func globalAmountFormat() AmountFormat {
+	return AmountFormat(defaultAmountFormat.Load())
+}
+
+// MarshalJSON implements the json.Marshaler interface, formatting the amounts with the format set with SetAmountFormat.
+// Clients encode payments with their own JSON codec and amount format instead.
+func (h PaymentHeader) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.payload(globalAmountFormat()))
+}
+
+// MarshalJSON implements the json.Marshaler interface, formatting the amount with the format set with SetAmountFormat.
+// Clients encode payments with their own JSON codec and amount format instead.
+func (ext PaymentExtension) MarshalJSON() ([]byte, error) {
+	payload, err := ext.payload(globalAmountFormat())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(payload)
+}
+
+// paymentHeaderPayload is the JSON payload of a PaymentHeader, with its amounts formatted.
+type paymentHeaderPayload struct {
+	paymentHeader
+	BatchAmount       string `json:"batchamount"`
+	TransactionAmount string `json:"transactionamount"`
+}
+
+// paymentHeader has the fields of PaymentHeader without its MarshalJSON method.
+type paymentHeader PaymentHeader
+
+func (h PaymentHeader) payload(format AmountFormat) paymentHeaderPayload {
+	return paymentHeaderPayload{
+		paymentHeader:     paymentHeader(h),
+		BatchAmount:       format.format(h.BatchAmount),
+		TransactionAmount: format.format(h.TransactionAmount),
+	}
+}
+
+// paymentExtensionPayload is the JSON payload of a PaymentExtension, with its amounts formatted.
+type paymentExtensionPayload struct {
+	paymentExtension
+	ParamList json.RawMessage `json:"param_list"`
+	Amount    string          `json:"amount"`
+}
+
+// paymentExtension has the fields of PaymentExtension without its MarshalJSON method.
+type paymentExtension PaymentExtension
+
+func (ext PaymentExtension) payload(format AmountFormat) (paymentExtensionPayload, error) {
+	payload := paymentExtensionPayload{
+		paymentExtension: paymentExtension(ext),
+		Amount:           format.format(ext.Amount),
+	}
+
+	var err error
+	switch params := ext.ParamList.(type) {
+	case nil:
+		payload.ParamList = json.RawMessage("null")
+	case paramListMarshaler:
+		payload.ParamList, err = params.marshalParamList(format)
+	default:
+		payload.ParamList, err = params.MarshalJSON()
+	}
+	return payload, err
+}
+
+// paymentPayload is the JSON payload of PaymentOptions, with the amounts formatted.
+type paymentPayload struct {
+	PaymentHeader paymentHeaderPayload      `json:"paymentHeader"`
+	Extension     []paymentExtensionPayload `json:"extension"`
+	SecureHash    string                    `json:"secureHash"`
+}
+
+// encodePayment encodes the payment with the JSON codec of the client, formatting its amounts
+// with the amount format of the client.
+func (c *Client) encodePayment(opt *PaymentOptions) ([]byte, error) {
+	format := c.amounts()
+
+	payload := paymentPayload{
+		PaymentHeader: opt.PaymentHeader.payload(format),
+		SecureHash:    opt.SecureHash,
+	}
+	if opt.Extension != nil {
+		payload.Extension = make([]paymentExtensionPayload, len(opt.Extension))
+	}
+	for i, ext := range opt.Extension {
+		var err error
+		if payload.Extension[i], err = ext.payload(format); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.marshalJSON(payload)
+}
+
+// FlexibleDecimal is an amount returned by the API.
+//
+// The gateway returns amounts as numbers, e.g. 15.92, or as strings, e.g. "15.92" or "1,250.00", and
+// empty strings for missing amounts. FlexibleDecimal decodes all of them, an empty string or null as zero.
+// Commas are only accepted as thousands separators; amounts with a decimal comma, e.g. "15,92", fail to
+// decode instead of being misread.
+// The embedded decimal.Decimal provides the arithmetic and comparisons.
+type FlexibleDecimal struct {
+	decimal.Decimal
+}
+
+// NewFlexibleDecimal returns a FlexibleDecimal holding d.
+func NewFlexibleDecimal(d decimal.Decimal) FlexibleDecimal {
+	return FlexibleDecimal{Decimal: d}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *FlexibleDecimal) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		return d.parse(s)
+	}
+	return d.parse(string(b))
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (d *FlexibleDecimal) UnmarshalText(b []byte) error {
+	return d.parse(string(b))
+}
+
+func (d *FlexibleDecimal) parse(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		d.Decimal = decimal.Zero
+		return nil
+	}
+
+	digits, err := stripThousandsSeparators(s)
+	if err != nil {
+		return err
+	}
+
+	v, err := decimal.NewFromString(digits)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	d.Decimal = v
+	return nil
+}
+
+// stripThousandsSeparators removes the commas of an amount which separate groups of three digits
+// of its integer part, e.g. "1,250.50" and "1,250,000". Other commas, such as the decimal comma of
+// "15,92", are rejected rather than guessed. So is a single comma in an amount without decimal
+// point, e.g. "1,250", which may be either.
+func stripThousandsSeparators(s string) (string, error) {
+	if !strings.Contains(s, ",") {
+		return s, nil
+	}
+
+	integer, fraction, hasFraction := strings.Cut(s, ".")
+	groups := strings.Split(strings.TrimPrefix(integer, "-"), ",")
+	valid := !strings.Contains(fraction, ",") && len(groups[0]) >= 1 && len(groups[0]) <= 3 &&
+		(hasFraction || len(groups) > 2)
+	for _, g := range groups[1:] {
+		valid = valid && len(g) == 3
+	}
+	if !valid {
+		return "", fmt.Errorf("invalid amount %q: ambiguous comma", s)
+	}
+	return strings.ReplaceAll(s, ",", ""), nil
+}
@@ -0,0 +1,173 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAmountPayment() *PaymentOptions {
+	return &PaymentOptions{
+		PaymentHeader: PaymentHeader{
+			BatchSequence:     "1",
+			BatchAmount:       decimal.NewFromInt(520),
+			TransactionAmount: decimal.RequireFromString("520.50"),
+			BatchID:           "EG1593490",
+		},
+		Extension: []PaymentExtension{
+			{
+				RequestID:   "2323",
+				RequestType: DOMESTIC,
+				ParamList:   NewPaymentParams(DomesticTransferParams{Amount: decimal.NewFromInt(10)}),
+				Amount:      decimal.NewFromInt(10),
+			},
+		},
+	}
+}
+
+// paymentAmounts decodes the amounts of a payment payload.
+func paymentAmounts(t *testing.T, b []byte) (batchAmount, txnAmount, extAmount any, params string) {
+	t.Helper()
+
+	var payload struct {
+		PaymentHeader map[string]any   `json:"paymentHeader"`
+		Extension     []map[string]any `json:"extension"`
+	}
+	require.NoError(t, json.Unmarshal(b, &payload))
+	require.Len(t, payload.Extension, 1)
+
+	params, _ = payload.Extension[0]["param_list"].(string)
+	return payload.PaymentHeader["batchamount"], payload.PaymentHeader["transactionamount"], payload.Extension[0]["amount"], params
+}
+
+func TestSetAmountFormat(t *testing.T) {
+	t.Cleanup(func() { SetAmountFormat(AmountFormatTrimZeros) })
+
+	testCases := []struct {
+		name         string
+		format       AmountFormat
+		batchAmount  string
+		txnAmount    string
+		extAmount    string
+		paramsAmount string
+	}{
+		{
+			name:         "trim zeros",
+			format:       AmountFormatTrimZeros,
+			batchAmount:  "520",
+			txnAmount:    "520.5",
+			extAmount:    "10",
+			paramsAmount: `{"key": "amount", "value": "10"}`,
+		},
+		{
+			name:         "fixed 2dp",
+			format:       AmountFormatFixed2,
+			batchAmount:  "520.00",
+			txnAmount:    "520.50",
+			extAmount:    "10.00",
+			paramsAmount: `{"key": "amount", "value": "10.00"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetAmountFormat(tc.format)
+			opt := newAmountPayment()
+
+			b, err := json.Marshal(opt)
+			require.NoError(t, err)
+
+			batchAmount, txnAmount, extAmount, params := paymentAmounts(t, b)
+			assert.Equal(t, tc.batchAmount, batchAmount)
+			assert.Equal(t, tc.txnAmount, txnAmount)
+			assert.Equal(t, tc.extAmount, extAmount)
+			assert.Contains(t, params, tc.paramsAmount)
+
+			// the hash must be built from the same amount strings as the payload
+			want := generateSecureHash("1"+tc.batchAmount+tc.txnAmount+"EG1593490"+"00"+formatToStr(opt.PaymentHeader.ExecutionDate), "key")
+			assert.Equal(t, want, generateSecureHashFrom(opt, "key"))
+		})
+	}
+}
+
+func TestWithAmountFormat(t *testing.T) {
+	var marshaled int
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithAmountFormat(AmountFormatFixed2)(client))
+	require.NoError(t, WithJSONCodec(func(v any) ([]byte, error) {
+		marshaled++
+		return json.Marshal(v)
+	}, nil)(client))
+
+	opt := newAmountPayment()
+	req, err := client.NewRequest(t.Context(), http.MethodPost, "merchant/payment", opt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, marshaled, "payments are encoded with the JSON codec of the client")
+
+	b, err := req.BodyBytes()
+	require.NoError(t, err)
+
+	batchAmount, txnAmount, extAmount, params := paymentAmounts(t, b)
+	assert.Equal(t, "520.00", batchAmount)
+	assert.Equal(t, "520.50", txnAmount)
+	assert.Equal(t, "10.00", extAmount)
+	assert.Contains(t, params, `{"key": "amount", "value": "10.00"}`)
+	assert.Contains(t, string(b), `"secureHash":"`+opt.SecureHash+`"`)
+
+	// the hash is built from the same amount strings as the payload
+	source := "1" + "520.00" + "520.50" + "EG1593490" + "00" + formatToStr(opt.PaymentHeader.ExecutionDate)
+	assert.Equal(t, generateSecureHash(source, client.labKey), opt.SecureHash)
+
+	// other clients and json.Marshal keep the default format
+	b, err = json.Marshal(opt)
+	require.NoError(t, err)
+	batchAmount, _, _, _ = paymentAmounts(t, b)
+	assert.Equal(t, "520", batchAmount)
+
+	assert.Error(t, WithAmountFormat(AmountFormat(7))(client))
+}
+
+func TestFlexibleDecimal_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`15.92`, "15.92"},
+		{`"15.92"`, "15.92"},
+		{`" 1,250.50 "`, "1250.5"},
+		{`"1,250,000"`, "1250000"},
+		{`"-12,345.6"`, "-12345.6"},
+		{`""`, "0"},
+		{`null`, "0"},
+		{`0`, "0"},
+	}
+	for _, tt := range tests {
+		var v struct {
+			Amount FlexibleDecimal `json:"amount"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"amount": `+tt.in+`}`), &v), tt.in)
+		assert.Equal(t, tt.want, v.Amount.String(), tt.in)
+	}
+
+	var d FlexibleDecimal
+	assert.Error(t, json.Unmarshal([]byte(`"abc"`), &d))
+	for _, in := range []string{`"15,92"`, `"1,250"`, `"1,25.00"`, `"1250,000.00"`, `"1,250.5,0"`, `",250.00"`} {
+		assert.ErrorContains(t, json.Unmarshal([]byte(in), &d), "ambiguous comma", in)
+	}
+
+	b, err := json.Marshal(NewFlexibleDecimal(decimal.RequireFromString("15.92")))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"15.92"`, string(b))
+}
+
+func TestAccountBalance_StringAmounts(t *testing.T) {
+	var balance AccountBalance
+	require.NoError(t, json.Unmarshal([]byte(`{"availableBalance": "15.92", "currentBalance": 20, "odlimit": ""}`), &balance))
+	assert.Equal(t, "15.92", balance.AvailableBalance.String())
+	assert.Equal(t, "20", balance.CurrentBalance.String())
+	assert.True(t, balance.OverdraftLimit.IsZero())
+}
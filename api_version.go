@@ -0,0 +1,180 @@
+package ecobank
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// APIVersion1 is the version of the operations of the published API collection, which are not prefixed
+// with a version in their paths, e.g. merchant/accountbalance.
+const APIVersion1 = "v1"
+
+var apiVersionRegexp = regexp.MustCompile(`^v[1-9][0-9]*$`)
+
+// Deprecation reports the use of a deprecated version of an API operation.
+type Deprecation struct {
+	// Endpoint is the URL path of the request.
+	Endpoint string
+	// Version is the version of the operation set with WithAPIVersion or WithEndpointVersion, if any.
+	Version string
+	// Sunset is when the version stops being served, if announced.
+	Sunset time.Time
+	// Link is the documentation of the deprecation sent by the API, if any.
+	Link string
+}
+
+// String returns a warning describing the deprecation.
+func (d Deprecation) String() string {
+	var b strings.Builder
+	b.WriteString(d.Endpoint)
+	if d.Version != "" {
+		b.WriteString(" " + d.Version)
+	}
+	b.WriteString(" is deprecated")
+	if !d.Sunset.IsZero() {
+		b.WriteString(" and will be removed on " + d.Sunset.Format(time.DateOnly))
+	}
+	if d.Link != "" {
+		b.WriteString(", see " + d.Link)
+	}
+	return b.String()
+}
+
+// DeprecationFunc is called once for every deprecated operation used by the client.
+type DeprecationFunc func(Deprecation)
+
+// WithAPIVersion pins all the API operations to a version, e.g. "v2", so that a client can migrate to a new
+// version of the API as Ecobank introduces it. The operations of versions other than APIVersion1 are served
+// under the version prefix of the base URL, e.g. /corporateapi/v2/merchant/accountbalance. Operations served
+// at other paths are pinned with WithEndpointVersion, which, like WithEndpointOverride, takes precedence.
+//
+// Once the API announces that an operation of the version is deprecated, the other operations of the client
+// pinned to the version are reported as deprecated before they are sent, see WithDeprecationFunc.
+func WithAPIVersion(version string) ClientOptionFunc {
+	return func(c *Client) error {
+		if !apiVersionRegexp.MatchString(version) {
+			return fmt.Errorf("invalid API version %q: must be of the form v1, v2, ...", version)
+		}
+		c.apiVersion = version
+		return nil
+	}
+}
+
+// WithEndpointVersion pins an API operation, identified as with WithEndpointOverride, to a version served at
+// the given path, relative to the base URL, so that single operations can be migrated to new versions as
+// Ecobank introduces them, e.g. WithEndpointVersion("Account", "GetBalance", "v2", "v2/merchant/accountbalance").
+// The paths of new versions are not part of the published API collection, so the path is required.
+//
+// Like WithEndpointOverride, it replaces the path of the operation, and the last of both options wins.
+// Deprecations of the operation announced by the API are reported with the version, and so are the other
+// operations pinned to it, as with WithAPIVersion.
+func WithEndpointVersion(service, operation, version, path string) ClientOptionFunc {
+	return func(c *Client) error {
+		e := endpoint{service, operation}
+		if !apiVersionRegexp.MatchString(version) {
+			return fmt.Errorf("invalid API version %q of endpoint %s: must be of the form v1, v2, ...", version, e)
+		}
+		if err := WithEndpointOverride(service, operation, path)(c); err != nil {
+			return err
+		}
+
+		if c.endpointVersions == nil {
+			c.endpointVersions = make(map[endpoint]string)
+		}
+		c.endpointVersions[e] = version
+		return nil
+	}
+}
+
+// WithDeprecationFunc sets the function called when the API responds with a Deprecation header, or when an
+// operation pinned to a version the API announced as deprecated is used. It is called once per operation. By default, deprecations are logged with the Logger of the retryable client,
+// if it has one.
+func WithDeprecationFunc(fn DeprecationFunc) ClientOptionFunc {
+	return func(c *Client) error {
+		c.deprecationFunc = fn
+		return nil
+	}
+}
+
+// endpointVersionOf returns the version set with WithAPIVersion or WithEndpointVersion of the operation with
+// the path, relative to the base URL, if any.
+func (c *Client) endpointVersionOf(path string) string {
+	for e := range endpoints {
+		if c.endpointPath(e) != path {
+			continue
+		}
+		if version, ok := c.endpointVersions[e]; ok {
+			return version
+		}
+		if _, ok := c.endpointOverrides[e]; !ok {
+			return c.apiVersion
+		}
+	}
+	return ""
+}
+
+// warnDeprecatedVersion reports the request to the URL path as deprecated if the operation is pinned
+// to a version which the API announced as deprecated for another operation.
+func (c *Client) warnDeprecatedVersion(urlPath string) {
+	version := c.endpointVersionOf(c.relativePath(urlPath))
+	if version == "" {
+		return
+	}
+	if d, ok := c.deprecatedVersions.Load(version); ok {
+		d := d.(Deprecation)
+		d.Endpoint = urlPath
+		c.warnDeprecated(d)
+	}
+}
+
+// checkDeprecationHeaders reports the deprecation announced by the Deprecation and Sunset headers of
+// the response, as defined by RFC 9745 and RFC 8594.
+func (c *Client) checkDeprecationHeaders(resp *http.Response) {
+	if resp.Header.Get("Deprecation") == "" {
+		return
+	}
+
+	d := Deprecation{}
+	if resp.Request != nil {
+		d.Endpoint = resp.Request.URL.Path
+		d.Version = c.endpointVersionOf(c.relativePath(d.Endpoint))
+	}
+	if sunset, err := http.ParseTime(resp.Header.Get("Sunset")); err == nil {
+		d.Sunset = sunset
+	}
+	for _, link := range resp.Header.Values("Link") {
+		if strings.Contains(link, `rel="deprecation"`) {
+			target, _, _ := strings.Cut(link, ";")
+			d.Link = strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+	if d.Version != "" {
+		c.deprecatedVersions.LoadOrStore(d.Version, d)
+	}
+	c.warnDeprecated(d)
+}
+
+// warnDeprecated reports the deprecation, once per operation and version.
+func (c *Client) warnDeprecated(d Deprecation) {
+	key := d.Endpoint + " " + d.Version
+	if _, warned := c.deprecationsWarned.LoadOrStore(key, true); warned {
+		return
+	}
+
+	switch {
+	case c.deprecationFunc != nil:
+		c.deprecationFunc(d)
+	default:
+		switch logger := c.client.Logger.(type) {
+		case retryablehttp.LeveledLogger:
+			logger.Warn(d.String())
+		case retryablehttp.Logger:
+			logger.Printf("[WARN] %s", d)
+		}
+	}
+}
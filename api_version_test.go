@@ -0,0 +1,174 @@
+package ecobank
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAPIVersion(t *testing.T) {
+	client, err := NewClient("user", "password", "lab-key",
+		WithAPIVersion("v2"),
+		WithEndpointVersion("Account", "GetBalance", "v3", "merchant/v3/accountbalance"),
+		WithEndpointOverride("Status", "GetTransactionStatus", "merchant/txns/status"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "v2/merchant/accountinquiry", client.path("Account", "Enquiry"))
+	assert.Equal(t, "merchant/v3/accountbalance", client.path("Account", "GetBalance"), "pinned operations keep their version")
+	assert.Equal(t, "merchant/txns/status", client.path("Status", "GetTransactionStatus"), "overridden paths are not versioned")
+	assert.Equal(t, "v2", client.endpointVersionOf("v2/merchant/accountinquiry"))
+	assert.Equal(t, "v3", client.endpointVersionOf("merchant/v3/accountbalance"))
+	assert.Empty(t, client.endpointVersionOf("merchant/txns/status"))
+
+	client, err = NewClient("user", "password", "lab-key", WithAPIVersion(APIVersion1))
+	require.NoError(t, err)
+	assert.Equal(t, "merchant/accountinquiry", client.path("Account", "Enquiry"), "the published API is not prefixed")
+
+	_, err = NewClient("user", "password", "lab-key", WithAPIVersion("2.0"))
+	assert.EqualError(t, err, `invalid API version "2.0": must be of the form v1, v2, ...`)
+}
+
+func TestWithEndpointVersion(t *testing.T) {
+	client, err := NewClient("user", "password", "lab-key",
+		WithEndpointVersion("Account", "GetBalance", "v2", "/v2/merchant/accountbalance"),
+		WithEndpointVersion("Status", "GetTransactionStatus", "v2", "v2/merchant/txns/status"),
+		WithEndpointOverride("Status", "GetTransactionStatus", "merchant/v3/txns/status"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "v2/merchant/accountbalance", client.path("Account", "GetBalance"))
+	assert.Equal(t, "merchant/accountinquiry", client.path("Account", "Enquiry"), "other operations are not versioned")
+	assert.Equal(t, "merchant/v3/txns/status", client.path("Status", "GetTransactionStatus"), "the last option wins")
+	assert.Equal(t, "v2", client.endpointVersionOf("v2/merchant/accountbalance"))
+	assert.Empty(t, client.endpointVersionOf("merchant/v3/txns/status"))
+
+	_, err = NewClient("user", "password", "lab-key", WithEndpointVersion("Account", "GetBalance", "2", "v2/merchant/accountbalance"))
+	assert.EqualError(t, err, `invalid API version "2" of endpoint Account.GetBalance: must be of the form v1, v2, ...`)
+
+	_, err = NewClient("user", "password", "lab-key", WithEndpointVersion("Account", "GetBalances", "v2", "v2/merchant/accountbalance"))
+	assert.ErrorContains(t, err, "unknown endpoint Account.GetBalances")
+
+	_, err = NewClient("user", "password", "lab-key", WithEndpointVersion("Account", "GetBalance", "v2", ""))
+	assert.EqualError(t, err, "path of endpoint Account.GetBalance must not be empty")
+}
+
+func TestWithDeprecationFunc(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithEndpointVersion("Account", "GetBalance", "v2", "v2/merchant/accountbalance")(client))
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header: http.Header{
+					"Content-Type": {"application/json"},
+					"Deprecation":  {"@1767225600"},
+					"Sunset":       {"Sun, 31 Jan 2027 00:00:00 GMT"},
+				},
+				Body:    io.NopCloser(strings.NewReader(`{"response_code": 200, "response_content": {}}`)),
+				Request: req,
+			}, nil
+		},
+	}
+
+	var deprecations []Deprecation
+	require.NoError(t, WithDeprecationFunc(func(d Deprecation) {
+		deprecations = append(deprecations, d)
+	})(client))
+
+	for range 2 {
+		_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		require.NoError(t, err)
+	}
+
+	sunset := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	require.Len(t, deprecations, 1, "deprecations are reported once")
+	assert.Equal(t, Deprecation{Endpoint: "/corporateapi/v2/merchant/accountbalance", Version: "v2", Sunset: sunset}, deprecations[0])
+	assert.Equal(t, "/corporateapi/v2/merchant/accountbalance v2 is deprecated and will be removed on 2027-01-31", deprecations[0].String())
+}
+
+func TestCheckDeprecationHeaders(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header: http.Header{
+					"Content-Type": {"application/json"},
+					"Deprecation":  {"@1767225600"},
+					"Sunset":       {"Sun, 31 Jan 2027 00:00:00 GMT"},
+					"Link":         {`<https://developer.ecobank.com/migration>; rel="deprecation"; type="text/html"`},
+				},
+				Body:    http.NoBody,
+				Request: req,
+			}, nil
+		},
+	}
+
+	var logs []string
+	client.client.Logger = loggerFunc(func(format string, args ...any) {
+		if msg := fmt.Sprintf(format, args...); strings.HasPrefix(msg, "[WARN]") {
+			logs = append(logs, msg)
+		}
+	})
+
+	_, _, _ = client.Account.Enquiry(t.Context(), &AccountEnquiryOptions{})
+
+	require.Len(t, logs, 1)
+	assert.Equal(t, "[WARN] /corporateapi/merchant/accountinquiry is deprecated and will be removed on 2027-01-31, see https://developer.ecobank.com/migration", logs[0])
+}
+
+func TestDeprecatedVersion(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithAPIVersion("v2")(client))
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			header := http.Header{"Content-Type": {"application/json"}}
+			// the API only announces the deprecation of the version on the streamed biller list
+			if strings.HasSuffix(req.URL.Path, "getbillerlist") {
+				header.Set("Deprecation", "@1767225600")
+				header.Set("Sunset", "Sun, 31 Jan 2027 00:00:00 GMT")
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader(`{"response_code": 200, "response_content": {}}`)),
+				Request:    req,
+			}, nil
+		},
+	}
+
+	var deprecations []Deprecation
+	require.NoError(t, WithDeprecationFunc(func(d Deprecation) {
+		deprecations = append(deprecations, d)
+	})(client))
+
+	_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, deprecations)
+
+	_, err = client.Payment.ForEachBiller(t.Context(), &GetBillerListOptions{}, func(*BillerInfo) error { return nil })
+	require.NoError(t, err)
+
+	// the other operations of the version are reported before they are sent
+	for range 2 {
+		_, _, err = client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		require.NoError(t, err)
+	}
+
+	sunset := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []Deprecation{
+		{Endpoint: "/corporateapi/v2/payment/getbillerlist", Version: "v2", Sunset: sunset},
+		{Endpoint: "/corporateapi/v2/merchant/accountbalance", Version: "v2", Sunset: sunset},
+	}, deprecations)
+}
+
+type loggerFunc func(format string, args ...any)
+
+func (f loggerFunc) Printf(format string, args ...any) { f(format, args...) }
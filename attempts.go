@@ -0,0 +1,127 @@
+package ecobank
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// sentKey is the context key under which the flag set by markSent is stored.
+type sentKey struct{}
+
+// withSentFlag returns a copy of ctx with a flag which is set once a request made with it is sent, to tell
+// the failures of requests which may have reached the API from those of requests which were never sent.
+func withSentFlag(ctx context.Context) (context.Context, *atomic.Bool) {
+	sent := new(atomic.Bool)
+	return context.WithValue(ctx, sentKey{}, sent), sent
+}
+
+// markSent sets the flag of ctx set with withSentFlag, unless ctx is done, in which case the request is not sent.
+func markSent(ctx context.Context) {
+	if sent, ok := ctx.Value(sentKey{}).(*atomic.Bool); ok && ctx.Err() == nil {
+		sent.Store(true)
+	}
+}
+
+// attemptStatsKey is the context key under which the attemptStats of a request are stored.
+type attemptStatsKey struct{}
+
+// attemptStats collects the attempts made by the retryable client for a single request.
+type attemptStats struct {
+	mu        sync.Mutex
+	attempts  int
+	retryWait time.Duration
+	latency   time.Duration
+	start     time.Time // start of the current attempt
+	end       time.Time // end of the previous attempt, zero if unknown
+}
+
+// withAttemptStats returns a copy of req whose context carries a new attemptStats.
+func withAttemptStats(req *retryablehttp.Request) (*retryablehttp.Request, *attemptStats) {
+	stats := &attemptStats{}
+	return req.WithContext(context.WithValue(req.Context(), attemptStatsKey{}, stats)), stats
+}
+
+func attemptStatsFrom(ctx context.Context) *attemptStats {
+	stats, _ := ctx.Value(attemptStatsKey{}).(*attemptStats)
+	return stats
+}
+
+// requestStarted is called before every attempt.
+func (s *attemptStats) requestStarted(attempt int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if attempt > 0 {
+		// attempts that fail without a response are not reported by the response hook,
+		// in which case the wait is measured from the start of that attempt.
+		prev := s.end
+		if prev.IsZero() {
+			prev = s.start
+		}
+		if !prev.IsZero() {
+			s.retryWait += now.Sub(prev)
+		}
+	}
+
+	s.attempts = attempt + 1
+	s.start, s.end = now, time.Time{}
+}
+
+// responseReceived is called when an attempt returns a response.
+func (s *attemptStats) responseReceived() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.end = time.Now()
+	s.latency = s.end.Sub(s.start)
+}
+
+// fill copies the collected stats into r.
+func (s *attemptStats) fill(r *Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.end.IsZero() && !s.start.IsZero() {
+		s.latency = time.Since(s.start)
+	}
+
+	r.Attempts = s.attempts
+	r.RetryWait = s.retryWait
+	r.Latency = s.latency
+}
+
+// installLogHooks wraps the log hooks of the retryable client to record the attempts of every request
+// and log the tags of the request context.
+// Hooks which are already set are still called.
+func (c *Client) installLogHooks() {
+	reqHook, respHook := c.client.RequestLogHook, c.client.ResponseLogHook
+
+	c.client.RequestLogHook = func(l retryablehttp.Logger, req *http.Request, attempt int) {
+		if stats := attemptStatsFrom(req.Context()); stats != nil {
+			stats.requestStarted(attempt)
+		}
+		if tags := Tags(req.Context()); l != nil && len(tags) > 0 {
+			l.Printf("[DEBUG] %s %s (attempt %d) tags: %s", req.Method, req.URL.Path, attempt+1, formatTags(tags))
+		}
+		if reqHook != nil {
+			reqHook(l, req, attempt)
+		}
+	}
+
+	c.client.ResponseLogHook = func(l retryablehttp.Logger, resp *http.Response) {
+		if resp.Request != nil {
+			if stats := attemptStatsFrom(resp.Request.Context()); stats != nil {
+				stats.responseReceived()
+			}
+		}
+		if respHook != nil {
+			respHook(l, resp)
+		}
+	}
+}
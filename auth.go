@@ -1,6 +1,12 @@
 package ecobank
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type AuthService struct {
 	client *Client
@@ -8,19 +14,19 @@ type AuthService struct {
 
 // AccessTokenOptions represents a request to get an access token.
 type AccessTokenOptions struct {
-	UserID   string `json:"userId"`
-	Password string `json:"password"`
+	UserID   string `json:"userId" redact:"partial"`
+	Password string `json:"password" redact:"secret"`
 }
 
 // BearerToken represents a response to an access token request.
 type BearerToken struct {
 	Username string `json:"username"`
-	Token    string `json:"token"`
+	Token    string `json:"token" redact:"secret"`
 }
 
 // GetAccessToken gets an access token for the given user.
-func (a *AuthService) GetAccessToken(ctx context.Context, opt *AccessTokenOptions) (*BearerToken, *Response, error) {
-	req, err := a.client.NewRequest(ctx, "POST", "user/token", opt)
+func (a *AuthService) GetAccessToken(ctx context.Context, opt *AccessTokenOptions, options ...RequestOptionFunc) (*BearerToken, *Response, error) {
+	req, err := a.client.NewRequest(ctx, "POST", a.client.path("Auth", "GetAccessToken"), opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -34,3 +40,79 @@ func (a *AuthService) GetAccessToken(ctx context.Context, opt *AccessTokenOption
 
 	return &token, resp, nil
 }
+
+// TokenClaims holds the claims of an access token.
+type TokenClaims struct {
+	// Subject is the user the token was issued to.
+	Subject string
+	// Affiliate is the affiliate code the token is scoped to, if any.
+	Affiliate string
+	// Scopes are the scopes granted to the token, if any.
+	Scopes []string
+	// IssuedAt is when the token was issued. It is zero if the token has no iat claim.
+	IssuedAt time.Time
+	// ExpiresAt is when the token expires. It is zero if the token has no exp claim.
+	ExpiresAt time.Time
+	// Raw holds all the claims of the token.
+	Raw map[string]any
+}
+
+// ExpiresIn returns the time left until the token expires, which is negative if it has already expired.
+// It returns zero if the token has no expiry.
+func (c *TokenClaims) ExpiresIn() time.Duration {
+	if c.ExpiresAt.IsZero() {
+		return 0
+	}
+	return time.Until(c.ExpiresAt)
+}
+
+// ParseToken decodes the claims of an access token, e.g. to display session information
+// or to refresh the token before it expires.
+//
+// The signature of the token is not verified.
+func ParseToken(token string) (*TokenClaims, error) {
+	decoded, err := decodeTokenPayload(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims tokenClaims
+	if err := json.Unmarshal(decoded, &claims); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	tc := &TokenClaims{
+		Subject:   claims.Sub,
+		Affiliate: claims.AffiliateCode,
+		Scopes:    claims.Scopes,
+	}
+	if tc.Affiliate == "" {
+		tc.Affiliate = claims.Affiliate
+	}
+	if claims.Iat != 0 {
+		tc.IssuedAt = time.Unix(claims.Iat, 0)
+	}
+	if claims.Exp != 0 {
+		tc.ExpiresAt = time.Unix(claims.Exp, 0)
+	}
+
+	// the scope claim is either a space separated string or a list
+	if len(claims.Scope) > 0 {
+		var scope string
+		if err := json.Unmarshal(claims.Scope, &scope); err == nil {
+			tc.Scopes = append(tc.Scopes, strings.Fields(scope)...)
+		} else {
+			var scopes []string
+			if err := json.Unmarshal(claims.Scope, &scopes); err != nil {
+				return nil, fmt.Errorf("invalid scope claim: %w", err)
+			}
+			tc.Scopes = append(tc.Scopes, scopes...)
+		}
+	}
+
+	if err := json.Unmarshal(decoded, &tc.Raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	return tc, nil
+}
@@ -0,0 +1,151 @@
+package ecobank
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Jitter is the randomization applied to the waits of a BackoffPreset.
+type Jitter int
+
+const (
+	// NoJitter waits exactly the exponential backoff.
+	NoJitter Jitter = iota
+	// FullJitter waits a random time between zero and the exponential backoff. It spreads the retries of
+	// many clients the most, so that they do not hit the API at the same time after an outage.
+	FullJitter
+	// EqualJitter waits half the exponential backoff plus a random time up to the other half.
+	EqualJitter
+)
+
+// BackoffPreset is a retry strategy which waits exponentially longer between attempts.
+// Use one of the predefined presets with WithBackoffPreset, or a copy of one with adjusted fields.
+type BackoffPreset struct {
+	// Name describes the preset.
+	Name string
+	// Min is the wait after the first attempt, which doubles after every attempt.
+	Min time.Duration
+	// Max is the longest wait between attempts.
+	Max time.Duration
+	// RetryMax is the maximum number of retries. If zero, the retries of the client are left as is.
+	RetryMax int
+	// Jitter is the randomization of the waits.
+	Jitter Jitter
+	// RespectRetryAfter makes the client wait as long as the Retry-After header of 429 and 503 responses
+	// asks, up to Max.
+	RespectRetryAfter bool
+}
+
+var (
+	// BackoffAggressive retries quickly and often, for latency sensitive calls such as name enquiries.
+	BackoffAggressive = BackoffPreset{
+		Name:     "aggressive",
+		Min:      50 * time.Millisecond,
+		Max:      500 * time.Millisecond,
+		RetryMax: 8,
+		Jitter:   FullJitter,
+	}
+	// BackoffConservative retries a few times with long waits, for batch jobs which can afford to wait.
+	BackoffConservative = BackoffPreset{
+		Name:              "conservative",
+		Min:               time.Second,
+		Max:               30 * time.Second,
+		RetryMax:          4,
+		Jitter:            FullJitter,
+		RespectRetryAfter: true,
+	}
+	// BackoffGatewayFriendly backs off enough for the API gateway to recover from rate limiting and maintenance,
+	// and honours its Retry-After header.
+	BackoffGatewayFriendly = BackoffPreset{
+		Name:              "gateway friendly",
+		Min:               2 * time.Second,
+		Max:               time.Minute,
+		RetryMax:          5,
+		Jitter:            EqualJitter,
+		RespectRetryAfter: true,
+	}
+)
+
+// Backoff returns the retryablehttp.Backoff implementing the preset.
+// The min and max waits configured on the retryable client are ignored in favour of those of the preset.
+func (p BackoffPreset) Backoff() retryablehttp.Backoff {
+	return func(_, _ time.Duration, attempt int, resp *http.Response) time.Duration {
+		if p.RespectRetryAfter {
+			if wait, ok := retryAfter(resp); ok {
+				return clampWait(wait, p.Max)
+			}
+		}
+
+		wait := max(p.Min, 0)
+		for i := 0; i < attempt && wait < p.Max; i++ {
+			// stop doubling before the wait overflows
+			if wait > p.Max/2 {
+				wait = p.Max
+				break
+			}
+			wait *= 2
+		}
+		// presets with a negative or zero Min or Max do not wait, rather than drawing from an invalid range
+		wait = clampWait(wait, p.Max)
+
+		switch p.Jitter {
+		case FullJitter:
+			return randUpTo(wait)
+		case EqualJitter:
+			return wait/2 + randUpTo(wait/2)
+		default:
+			return wait
+		}
+	}
+}
+
+// clampWait returns wait clamped to [0, maxWait].
+func clampWait(wait, maxWait time.Duration) time.Duration {
+	return max(min(wait, maxWait), 0)
+}
+
+// randUpTo returns a random duration in [0, d]. d must not be negative.
+func randUpTo(d time.Duration) time.Duration {
+	if d == math.MaxInt64 {
+		return rand.N(d)
+	}
+	return rand.N(d + 1)
+}
+
+// WithBackoffPreset sets the backoff strategy of the client to the preset, such as BackoffGatewayFriendly,
+// along with its maximum number of retries.
+func WithBackoffPreset(preset BackoffPreset) ClientOptionFunc {
+	return func(c *Client) error {
+		c.client.Backoff = preset.Backoff()
+		c.client.RetryWaitMin, c.client.RetryWaitMax = preset.Min, preset.Max
+		if preset.RetryMax > 0 {
+			c.client.RetryMax = preset.RetryMax
+		}
+		return nil
+	}
+}
+
+// retryAfter returns the wait asked for by the Retry-After header of a 429 or 503 response,
+// given either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,85 @@
+package ecobank
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffPreset_Backoff(t *testing.T) {
+	preset := BackoffPreset{Min: 100 * time.Millisecond, Max: time.Second}
+	backoff := preset.Backoff()
+
+	assert.Equal(t, 100*time.Millisecond, backoff(0, 0, 0, nil))
+	assert.Equal(t, 200*time.Millisecond, backoff(0, 0, 1, nil))
+	assert.Equal(t, 800*time.Millisecond, backoff(0, 0, 3, nil))
+	assert.Equal(t, time.Second, backoff(0, 0, 4, nil))
+	assert.Equal(t, time.Second, backoff(0, 0, 100, nil))
+
+	for _, jitter := range []Jitter{FullJitter, EqualJitter} {
+		preset.Jitter = jitter
+		backoff := preset.Backoff()
+		for range 100 {
+			wait := backoff(0, 0, 2, nil)
+			assert.LessOrEqual(t, wait, 400*time.Millisecond)
+			if jitter == EqualJitter {
+				assert.GreaterOrEqual(t, wait, 200*time.Millisecond)
+			}
+		}
+	}
+}
+
+func TestBackoffPreset_InvalidRange(t *testing.T) {
+	for _, jitter := range []Jitter{NoJitter, FullJitter, EqualJitter} {
+		for _, preset := range []BackoffPreset{
+			{Min: -time.Second, Max: time.Second},
+			{Min: time.Second, Max: -time.Second},
+			{Min: time.Second, Max: math.MaxInt64},
+		} {
+			preset.Jitter = jitter
+			backoff := preset.Backoff()
+			for _, attempt := range []int{0, 1, 100} {
+				wait := backoff(0, 0, attempt, nil)
+				assert.GreaterOrEqual(t, wait, time.Duration(0), "%+v", preset)
+				assert.LessOrEqual(t, wait, max(preset.Max, 0), "%+v", preset)
+			}
+		}
+	}
+}
+
+func TestBackoffPreset_RetryAfter(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"7"}}}
+
+	assert.Equal(t, 7*time.Second, BackoffGatewayFriendly.Backoff()(0, 0, 0, resp))
+
+	preset := BackoffGatewayFriendly
+	preset.Max = 3 * time.Second
+	assert.Equal(t, 3*time.Second, preset.Backoff()(0, 0, 0, resp), "Retry-After is capped at Max")
+
+	preset.RespectRetryAfter, preset.Jitter = false, NoJitter
+	assert.Equal(t, 2*time.Second, preset.Backoff()(0, 0, 0, resp))
+
+	resp.StatusCode = http.StatusBadGateway
+	_, ok := retryAfter(resp)
+	assert.False(t, ok, "Retry-After only applies to 429 and 503")
+
+	resp.StatusCode = http.StatusServiceUnavailable
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	wait, ok := retryAfter(resp)
+	require.True(t, ok)
+	assert.InDelta(t, time.Minute, wait, float64(2*time.Second))
+}
+
+func TestWithBackoffPreset(t *testing.T) {
+	client, err := NewClient("user", "password", "lab-key", WithBackoffPreset(BackoffConservative))
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, client.client.RetryMax)
+	assert.Equal(t, time.Second, client.client.RetryWaitMin)
+	assert.Equal(t, 30*time.Second, client.client.RetryWaitMax)
+	assert.LessOrEqual(t, client.client.Backoff(0, 0, 0, nil), time.Second)
+}
@@ -0,0 +1,247 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// BatchState is the state of a batch of a multi-batch payment recorded by a BatchTracker.
+type BatchState string
+
+const (
+	// BatchSubmitting is a batch being submitted. A batch still submitting after a crash may have been
+	// processed by the API, so its status is checked before it is submitted again. If the API does not know
+	// it, save it as BatchRejected once you have made sure it was not processed, to submit it again.
+	BatchSubmitting BatchState = "SUBMITTING"
+	// BatchSubmitted is a batch accepted by the API.
+	BatchSubmitted BatchState = "SUBMITTED"
+	// BatchRejected is a batch rejected by the API, which can be fixed and submitted again.
+	BatchRejected BatchState = "REJECTED"
+	// BatchDuplicate is a batch rejected by the DuplicateGuard of the client, which was not sent.
+	BatchDuplicate BatchState = "DUPLICATE"
+	// BatchUnsent is a batch whose submission failed before it was sent, e.g. because the context was
+	// cancelled or the client could not log in, which is submitted again.
+	BatchUnsent BatchState = "UNSENT"
+)
+
+// BatchRecord is the state of a batch of a multi-batch payment.
+type BatchRecord struct {
+	// BatchID is the batch ID shared by the batches of the payment.
+	BatchID string `json:"batchId"`
+	// Sequence is the position of the batch in the payment, from 1 to TotalBatches.
+	Sequence int `json:"sequence"`
+	// TotalBatches is the number of batches of the payment.
+	TotalBatches int `json:"totalBatches"`
+	// State is the state of the batch.
+	State BatchState `json:"state"`
+	// RequestIDs are the request IDs of the extensions of the batch.
+	RequestIDs []string `json:"requestIds"`
+	// UpdatedAt is when the record was last saved.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// BatchTracker stores the state of the batches of multi-batch payments submitted with PaymentService.PayBatches,
+// so that a submission interrupted by a crash resumes at the right BatchSequence instead of submitting the
+// first batches again. Implementations must be safe for concurrent use, and durable to survive restarts.
+type BatchTracker interface {
+	// Save stores the record, replacing the record with the same batch ID and sequence.
+	Save(ctx context.Context, record *BatchRecord) error
+	// Batches returns the records of the batches with the batch ID, by ascending sequence.
+	Batches(ctx context.Context, batchID string) ([]*BatchRecord, error)
+}
+
+// PayBatches submits the batches of a multi-batch payment in sequence with Pay, recording their progress in
+// tracker. The batches must share their batch ID, as the batches returned by PaymentOptions.SplitBatches do,
+// and their BatchSequence and TotalBatches are set from their positions.
+//
+// PayBatches can be called again with the same batches after a failure or a crash: batches already submitted
+// according to tracker are skipped, and so are batches whose submission was interrupted if the API knows any
+// of their extensions. The API does not document how it reports unknown requests, so PayBatches stops at an
+// interrupted batch whose status cannot be checked, which must be reconciled by hand. Rejected batches,
+// batches rejected by the DuplicateGuard of the client and batches which failed before they were sent are
+// submitted again. Batches are checked with PaymentOptions.Check and validated like by Pay before they are
+// recorded, so that invalid batches can be fixed. The acknowledgement of skipped batches is nil. PayBatches
+// stops at the first batch which fails, returning the acknowledgements of the batches before it.
+func (p *PaymentService) PayBatches(ctx context.Context, tracker BatchTracker, batches []*PaymentOptions, options ...RequestOptionFunc) ([]*PaymentAck, error) {
+	if len(batches) == 0 {
+		return nil, errors.New("payment has no batches")
+	}
+	batchID := batches[0].PaymentHeader.BatchID
+	if batchID == "" {
+		return nil, errors.New("batch ID is required")
+	}
+	for i, batch := range batches {
+		if batch.PaymentHeader.BatchID != batchID {
+			return nil, fmt.Errorf("batch %d has batch ID %s, but the batches of a payment must share the batch ID %s", i+1, batch.PaymentHeader.BatchID, batchID)
+		}
+	}
+
+	records, err := tracker.Batches(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("loading batches of %s: %w", batchID, err)
+	}
+	bySequence := make(map[int]*BatchRecord, len(records))
+	for _, record := range records {
+		bySequence[record.Sequence] = record
+	}
+
+	acks := make([]*PaymentAck, len(batches))
+	for i, batch := range batches {
+		sequence := i + 1
+		batch.PaymentHeader.BatchSequence = strconv.Itoa(sequence)
+		batch.PaymentHeader.TotalBatches = strconv.Itoa(len(batches))
+
+		record := bySequence[sequence]
+		if record != nil && record.State == BatchSubmitting {
+			known, err := p.knowsBatch(ctx, batch, record, options...)
+			if err != nil {
+				return acks[:i], fmt.Errorf("batch %d of %s: %w", sequence, batchID, err)
+			}
+			if known {
+				record.State = BatchSubmitted
+				if err := p.saveBatch(ctx, tracker, record); err != nil {
+					return acks[:i], err
+				}
+			}
+		}
+		if record != nil && record.State == BatchSubmitted {
+			continue
+		}
+
+		if record != nil && record.State == BatchRejected && p.client.duplicateGuard != nil {
+			// the guard still holds a batch rejected after an interrupted submission
+			if err := p.client.duplicateGuard.forget(batch); err != nil {
+				return acks[:i], fmt.Errorf("batch %d of %s: releasing payment from duplicate guard: %w", sequence, batchID, err)
+			}
+		}
+
+		ack, err := p.payBatch(ctx, tracker, batch, sequence, len(batches), options...)
+		if err != nil {
+			return acks[:i], fmt.Errorf("batch %d of %s: %w", sequence, batchID, err)
+		}
+		acks[i] = ack
+	}
+	return acks, nil
+}
+
+// payBatch submits the batch, recording it as submitting before it is sent and with its outcome after.
+// Batches which fail without an answer from the API remain submitting.
+func (p *PaymentService) payBatch(ctx context.Context, tracker BatchTracker, batch *PaymentOptions, sequence, total int, options ...RequestOptionFunc) (*PaymentAck, error) {
+	// invalid batches are not recorded as submitting, which would block their resubmission once fixed
+	if err := batch.Check(); err != nil {
+		return nil, err
+	}
+	if err := p.validate(batch); err != nil {
+		return nil, err
+	}
+
+	record := &BatchRecord{
+		BatchID:      batch.PaymentHeader.BatchID,
+		Sequence:     sequence,
+		TotalBatches: total,
+		State:        BatchSubmitting,
+		RequestIDs:   make([]string, len(batch.Extension)),
+	}
+	for i, ext := range batch.Extension {
+		record.RequestIDs[i] = ext.RequestID
+	}
+	if err := p.saveBatch(ctx, tracker, record); err != nil {
+		return nil, err
+	}
+
+	payCtx, sent := withSentFlag(ctx)
+	ack, _, err := p.Pay(payCtx, batch, options...)
+	var respErr *ResponseError
+	switch {
+	case err == nil:
+		record.State = BatchSubmitted
+	case errors.As(err, &respErr):
+		record.State = BatchRejected
+	case errors.Is(err, ErrDuplicatePayment):
+		record.State = BatchDuplicate
+	case !sent.Load():
+		record.State = BatchUnsent
+		// the duplicate guard may hold the batch, which the API does not know
+		if guard := p.client.duplicateGuard; guard != nil {
+			err = errors.Join(err, guard.forget(batch))
+		}
+		// the state is saved even if ctx was cancelled, as the batch is no longer being submitted
+		ctx = context.WithoutCancel(ctx)
+	default:
+		return nil, err
+	}
+	return ack, errors.Join(err, p.saveBatch(ctx, tracker, record))
+}
+
+// knowsBatch reports whether the API knows any of the extensions of a batch whose submission was interrupted.
+// It fails if the status of none can be checked.
+func (p *PaymentService) knowsBatch(ctx context.Context, batch *PaymentOptions, record *BatchRecord, options ...RequestOptionFunc) (bool, error) {
+	var errs []error
+	for _, requestID := range record.RequestIDs {
+		_, _, err := p.client.Status.GetTransactionStatus(ctx, &StatusOptions{
+			ClientID:  batch.PaymentHeader.ClientID,
+			RequestID: requestID,
+		}, options...)
+		if err == nil {
+			return true, nil
+		}
+		errs = append(errs, err)
+	}
+	return false, fmt.Errorf("interrupted batch may have been processed, reconcile it before submitting it again: %w", errors.Join(errs...))
+}
+
+func (p *PaymentService) saveBatch(ctx context.Context, tracker BatchTracker, record *BatchRecord) error {
+	record.UpdatedAt = p.client.now()
+	if err := tracker.Save(ctx, record); err != nil {
+		return fmt.Errorf("saving batch %d of %s: %w", record.Sequence, record.BatchID, err)
+	}
+	return nil
+}
+
+// MemoryBatchTracker is an in-memory BatchTracker. Its records do not survive process restarts,
+// so it is mostly useful for tests.
+type MemoryBatchTracker struct {
+	mu      sync.Mutex
+	records map[string]map[int]*BatchRecord
+}
+
+// NewMemoryBatchTracker returns a new, empty MemoryBatchTracker.
+func NewMemoryBatchTracker() *MemoryBatchTracker {
+	return &MemoryBatchTracker{records: make(map[string]map[int]*BatchRecord)}
+}
+
+// Save implements BatchTracker.
+func (t *MemoryBatchTracker) Save(_ context.Context, record *BatchRecord) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.records[record.BatchID] == nil {
+		t.records[record.BatchID] = make(map[int]*BatchRecord)
+	}
+	t.records[record.BatchID][record.Sequence] = cloneBatchRecord(record)
+	return nil
+}
+
+// Batches implements BatchTracker.
+func (t *MemoryBatchTracker) Batches(_ context.Context, batchID string) ([]*BatchRecord, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records := make([]*BatchRecord, 0, len(t.records[batchID]))
+	for _, record := range t.records[batchID] {
+		records = append(records, cloneBatchRecord(record))
+	}
+	slices.SortFunc(records, func(a, b *BatchRecord) int { return a.Sequence - b.Sequence })
+	return records, nil
+}
+
+func cloneBatchRecord(record *BatchRecord) *BatchRecord {
+	c := *record
+	c.RequestIDs = slices.Clone(record.RequestIDs)
+	return &c
+}
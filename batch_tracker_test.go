@@ -0,0 +1,231 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBatches(batchID string, requestIDs ...string) []*PaymentOptions {
+	batches := make([]*PaymentOptions, len(requestIDs))
+	for i, id := range requestIDs {
+		batches[i] = newOutboxPayment(id)
+		batches[i].PaymentHeader.BatchID = batchID
+		batches[i].PaymentHeader.TransactionCount = 1
+		batches[i].PaymentHeader.BatchAmount = batches[i].Extension[0].Amount
+	}
+	return batches
+}
+
+func batchStates(t *testing.T, tracker BatchTracker, batchID string) []BatchState {
+	t.Helper()
+	records, err := tracker.Batches(t.Context(), batchID)
+	require.NoError(t, err)
+
+	states := make([]BatchState, len(records))
+	for i, record := range records {
+		states[i] = record.State
+	}
+	return states
+}
+
+func TestPaymentService_PayBatches(t *testing.T) {
+	api := &outboxAPI{}
+	client := newOutbox(t, api).client
+	tracker := NewMemoryBatchTracker()
+
+	batches := newTestBatches("B1", "1", "2", "3")
+	acks, err := client.Payment.PayBatches(t.Context(), tracker, batches)
+	require.NoError(t, err)
+	assert.Len(t, acks, 3)
+	assert.Equal(t, 3, api.payments)
+	assert.Equal(t, "2", batches[1].PaymentHeader.BatchSequence)
+	assert.Equal(t, "3", batches[1].PaymentHeader.TotalBatches)
+	assert.Equal(t, []BatchState{BatchSubmitted, BatchSubmitted, BatchSubmitted}, batchStates(t, tracker, "B1"))
+
+	records, err := tracker.Batches(t.Context(), "B1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, records[1].RequestIDs)
+	assert.Equal(t, 3, records[1].TotalBatches)
+
+	// submitting the payment again does not submit any batch
+	acks, err = client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B1", "1", "2", "3"))
+	require.NoError(t, err)
+	assert.Equal(t, []*PaymentAck{nil, nil, nil}, acks)
+	assert.Equal(t, 3, api.payments)
+}
+
+func TestPaymentService_PayBatches_Resume(t *testing.T) {
+	api := &outboxAPI{statuses: map[string]string{}}
+	client := newOutbox(t, api).client
+	tracker := NewMemoryBatchTracker()
+
+	// the second batch fails without an answer, as if the process crashed while sending it
+	api.paymentError = errors.New("connection reset")
+	batches := newTestBatches("B1", "1", "2", "3")
+	require.NoError(t, tracker.Save(t.Context(), &BatchRecord{BatchID: "B1", Sequence: 1, State: BatchSubmitted, RequestIDs: []string{"1"}}))
+
+	_, err := client.Payment.PayBatches(t.Context(), tracker, batches)
+	require.ErrorContains(t, err, "batch 2 of B1")
+	assert.Equal(t, []BatchState{BatchSubmitted, BatchSubmitting}, batchStates(t, tracker, "B1"))
+
+	t.Run("interrupted batch processed", func(t *testing.T) {
+		api.paymentError, api.payments = nil, 0
+		api.statuses["2"] = "PENDING"
+
+		acks, err := client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B1", "1", "2", "3"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, api.payments, "only the third batch is submitted")
+		assert.Nil(t, acks[0])
+		assert.Nil(t, acks[1])
+		assert.NotNil(t, acks[2])
+		assert.Equal(t, []BatchState{BatchSubmitted, BatchSubmitted, BatchSubmitted}, batchStates(t, tracker, "B1"))
+	})
+
+	t.Run("interrupted batch not received", func(t *testing.T) {
+		tracker := NewMemoryBatchTracker()
+		record := &BatchRecord{BatchID: "B2", Sequence: 1, State: BatchSubmitting, RequestIDs: []string{"4"}}
+		require.NoError(t, tracker.Save(t.Context(), record))
+		api.payments = 0
+
+		_, err := client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B2", "4"))
+		require.ErrorContains(t, err, "batch 1 of B2: interrupted batch may have been processed")
+		assert.Zero(t, api.payments, "a batch which cannot be checked is not submitted again")
+
+		// the batch is submitted again once reconciled
+		record.State = BatchRejected
+		require.NoError(t, tracker.Save(t.Context(), record))
+		_, err = client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B2", "4"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, api.payments)
+	})
+}
+
+func TestPaymentService_PayBatches_DuplicateGuard(t *testing.T) {
+	api := &outboxAPI{}
+	client := newOutbox(t, api).client
+	require.NoError(t, WithDuplicateGuard(NewDuplicateGuard(time.Hour))(client))
+	tracker := NewMemoryBatchTracker()
+
+	// the batch fails without an answer, so the guard keeps it
+	api.paymentError = errors.New("connection reset")
+	_, err := client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B1", "1"))
+	require.Error(t, err)
+
+	// reconciled as not received, it is resubmitted in the same process
+	require.NoError(t, tracker.Save(t.Context(), &BatchRecord{BatchID: "B1", Sequence: 1, State: BatchRejected, RequestIDs: []string{"1"}}))
+	api.paymentError, api.payments = nil, 0
+	_, err = client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B1", "1"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, api.payments)
+	assert.Equal(t, []BatchState{BatchSubmitted}, batchStates(t, tracker, "B1"))
+
+	// a duplicate of another payment is not released
+	_, err = client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B2", "1"))
+	require.ErrorIs(t, err, ErrDuplicatePayment)
+	assert.Equal(t, []BatchState{BatchDuplicate}, batchStates(t, tracker, "B2"))
+
+	_, err = client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B2", "1"))
+	require.ErrorIs(t, err, ErrDuplicatePayment)
+	assert.Equal(t, 1, api.payments)
+}
+
+func TestPaymentService_PayBatches_SplitBatches(t *testing.T) {
+	api := &outboxAPI{}
+	client := newOutbox(t, api).client
+	tracker := NewMemoryBatchTracker()
+
+	payment := newOutboxPayment("1")
+	payment.PaymentHeader.BatchID = "B1"
+	second := newOutboxPayment("2").Extension[0]
+	second.Currency = "USD"
+	payment.Extension = append(payment.Extension, second)
+
+	batches, err := payment.SplitBatches()
+	require.NoError(t, err)
+	_, err = client.Payment.PayBatches(t.Context(), tracker, batches)
+	require.NoError(t, err)
+	assert.Equal(t, 2, api.payments)
+	assert.Equal(t, []BatchState{BatchSubmitted, BatchSubmitted}, batchStates(t, tracker, "B1"))
+}
+
+func TestPaymentService_PayBatches_Rejected(t *testing.T) {
+	api := &outboxAPI{paymentCode: http.StatusBadRequest}
+	client := newOutbox(t, api).client
+	tracker := NewMemoryBatchTracker()
+
+	acks, err := client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B1", "1", "2"))
+	var respErr *ResponseError
+	require.ErrorAs(t, err, &respErr)
+	assert.Empty(t, acks)
+	assert.Equal(t, 1, api.payments, "the batches after the rejected one are not submitted")
+	assert.Equal(t, []BatchState{BatchRejected}, batchStates(t, tracker, "B1"))
+
+	// rejected batches are submitted again
+	api.paymentCode = 0
+	_, err = client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B1", "1", "2"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, api.payments)
+}
+
+func TestPaymentService_PayBatches_Invalid(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	tracker := NewMemoryBatchTracker()
+
+	_, err := client.Payment.PayBatches(t.Context(), tracker, nil)
+	assert.EqualError(t, err, "payment has no batches")
+
+	batches := append(newTestBatches("B1", "1"), newTestBatches("B2", "2")...)
+	_, err = client.Payment.PayBatches(t.Context(), tracker, batches)
+	assert.EqualError(t, err, "batch 2 has batch ID B2, but the batches of a payment must share the batch ID B1")
+}
+
+func TestPaymentService_PayBatches_NotSent(t *testing.T) {
+	t.Run("invalid batch is not recorded", func(t *testing.T) {
+		api := &outboxAPI{}
+		client := newOutbox(t, api).client
+		tracker := NewMemoryBatchTracker()
+
+		batches := newTestBatches("B1", "1", "2")
+		batches[1].PaymentHeader.ExecutionDate = NewTime(time.Now().AddDate(0, 0, -2))
+		_, err := client.Payment.PayBatches(t.Context(), tracker, batches)
+		require.ErrorIs(t, err, ErrExecutionDateInPast)
+		assert.Equal(t, []BatchState{BatchSubmitted}, batchStates(t, tracker, "B1"))
+
+		batches = newTestBatches("B1", "1", "2")
+		batches[1].PaymentHeader.TransactionCount = 2
+		_, err = client.Payment.PayBatches(t.Context(), tracker, batches)
+		require.ErrorIs(t, err, ErrInvalidBatch)
+		assert.Equal(t, []BatchState{BatchSubmitted}, batchStates(t, tracker, "B1"))
+
+		// once fixed, the batch is submitted
+		_, err = client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B1", "1", "2"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, api.payments)
+		assert.Equal(t, []BatchState{BatchSubmitted, BatchSubmitted}, batchStates(t, tracker, "B1"))
+	})
+
+	t.Run("failed before sending is submitted again", func(t *testing.T) {
+		api := &outboxAPI{}
+		client := newOutbox(t, api).client
+		require.NoError(t, WithDuplicateGuard(NewDuplicateGuard(time.Hour))(client))
+		tracker := NewMemoryBatchTracker()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		_, err := client.Payment.PayBatches(ctx, tracker, newTestBatches("B1", "1"))
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []BatchState{BatchUnsent}, batchStates(t, tracker, "B1"))
+		assert.Zero(t, api.payments)
+
+		_, err = client.Payment.PayBatches(t.Context(), tracker, newTestBatches("B1", "1"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, api.payments)
+		assert.Equal(t, []BatchState{BatchSubmitted}, batchStates(t, tracker, "B1"))
+	})
+}
@@ -0,0 +1,174 @@
+package ecobank
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// billerDetailsKey identifies cached biller details.
+type billerDetailsKey struct {
+	affiliateCode string
+	billerCode    string
+}
+
+// billerDetailsEntry is cached biller details along with the validators to revalidate them.
+type billerDetailsEntry struct {
+	details      *BillerDetails
+	resp         *Response
+	etag         string
+	lastModified string
+	expiresAt    time.Time
+}
+
+// billerDetailsCache caches the responses of PaymentService.GetBillerDetails.
+type billerDetailsCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[billerDetailsKey]*billerDetailsEntry
+	sweeps  sweepSchedule
+}
+
+// WithBillerDetailsCache caches the biller details returned by PaymentService.GetBillerDetails for ttl,
+// so that forms rendered from them do not call the API on every page load. Details are cached by
+// affiliate and biller code.
+//
+// If the API sent an ETag or Last-Modified header with the details, they are revalidated with a
+// conditional request once they expire, and kept for another ttl if they have not changed.
+func WithBillerDetailsCache(ttl time.Duration) ClientOptionFunc {
+	return func(c *Client) error {
+		if ttl <= 0 {
+			c.billerDetailsCache = nil
+			return nil
+		}
+		c.billerDetailsCache = &billerDetailsCache{
+			ttl:     ttl,
+			entries: make(map[billerDetailsKey]*billerDetailsEntry),
+		}
+		return nil
+	}
+}
+
+// get returns the entry of the key and whether it is still fresh at now.
+func (c *billerDetailsCache) get(key billerDetailsKey, now time.Time) (*billerDetailsEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return entry, now.Before(entry.expiresAt)
+}
+
+// put caches the details returned with resp.
+func (c *billerDetailsCache) put(key billerDetailsKey, details *BillerDetails, resp *Response, now time.Time) {
+	entry := &billerDetailsEntry{
+		details:   cloneBillerDetails(details),
+		resp:      cloneResponse(resp),
+		expiresAt: now.Add(c.ttl),
+	}
+	if resp != nil && resp.Response != nil {
+		entry.etag = resp.Header.Get("ETag")
+		entry.lastModified = resp.Header.Get("Last-Modified")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// drop expired entries which cannot be revalidated so the cache does not grow unbounded
+	if c.sweeps.due(len(c.entries)) {
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) && e.etag == "" && e.lastModified == "" {
+				delete(c.entries, k)
+			}
+		}
+		c.sweeps.swept(len(c.entries))
+	}
+	c.entries[key] = entry
+}
+
+// refresh extends the expiry of an entry which was revalidated at now.
+func (c *billerDetailsCache) refresh(key billerDetailsKey, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		refreshed := *entry
+		refreshed.expiresAt = now.Add(c.ttl)
+		c.entries[key] = &refreshed
+	}
+}
+
+// conditionalHeaders returns the request option revalidating the entry, if it has validators.
+func (e *billerDetailsEntry) conditionalHeaders() (RequestOptionFunc, bool) {
+	headers := make(map[string]string)
+	if e.etag != "" {
+		headers["If-None-Match"] = e.etag
+	}
+	if e.lastModified != "" {
+		headers["If-Modified-Since"] = e.lastModified
+	}
+	return WithHeaders(headers), len(headers) > 0
+}
+
+// cloneBillerDetails returns a copy of the details which does not share the form data and products.
+func cloneBillerDetails(d *BillerDetails) *BillerDetails {
+	c := *d
+	c.BillFormData = slices.Clone(d.BillFormData)
+	for i := range c.BillFormData {
+		c.BillFormData[i].LookupValue = slices.Clone(c.BillFormData[i].LookupValue)
+	}
+	c.BillerProductInfo = slices.Clone(d.BillerProductInfo)
+	return &c
+}
+
+// cloneResponse returns a copy of the response which does not share its headers, so that callers served
+// from the cache cannot change the cached response.
+func cloneResponse(r *Response) *Response {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	if r.Response != nil {
+		httpResp := *r.Response
+		httpResp.Header = r.Header.Clone()
+		c.Response = &httpResp
+	}
+	c.RawErrors = slices.Clone(r.RawErrors)
+	if r.Pagination != nil {
+		pagination := *r.Pagination
+		c.Pagination = &pagination
+	}
+	return &c
+}
+
+// getBillerDetails serves GetBillerDetails from the cache, revalidating or fetching the details as needed.
+func (p *PaymentService) getBillerDetails(cache *billerDetailsCache, opt *GetBillerDetailsOptions, send func(options ...RequestOptionFunc) (*BillerDetails, *Response, error), options ...RequestOptionFunc) (*BillerDetails, *Response, error) {
+	key := billerDetailsKey{opt.AffiliateCode, opt.BillerCode}
+
+	entry, fresh := cache.get(key, p.client.now())
+	if fresh {
+		return cloneBillerDetails(entry.details), cloneResponse(entry.resp), nil
+	}
+
+	if entry != nil {
+		if conditional, ok := entry.conditionalHeaders(); ok {
+			options = append(slices.Clip(options), conditional)
+		}
+	}
+
+	details, resp, err := send(options...)
+	if entry != nil && resp != nil && resp.Response != nil && resp.StatusCode == http.StatusNotModified {
+		cache.refresh(key, p.client.now())
+		return cloneBillerDetails(entry.details), resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	cache.put(key, details, resp, p.client.now())
+	return details, resp, nil
+}
@@ -0,0 +1,98 @@
+package ecobank
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/profclems/go-ecobank/ecobanktest"
+)
+
+func TestWithBillerDetailsCache(t *testing.T) {
+	details := ecobanktest.MustLoad(t, "merchant/getbillerdetails", "success")
+
+	var calls, notModified int
+	etag := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if etag != "" && r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		details.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	clock := &fixedClock{now: time.Now()}
+	client, err := NewClient("user", "password", "lab-key",
+		WithBaseURL(srv.URL),
+		WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+		WithClock(clock),
+		WithBillerDetailsCache(5*time.Minute),
+	)
+	require.NoError(t, err)
+
+	getWithResponse := func(billerCode string) (*BillerDetails, *Response) {
+		t.Helper()
+		d, resp, err := client.Payment.GetBillerDetails(t.Context(), &GetBillerDetailsOptions{
+			RequestID:     "ECO2112134346",
+			AffiliateCode: "EGH",
+			BillerCode:    billerCode,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		return d, resp
+	}
+	get := func(billerCode string) *BillerDetails {
+		t.Helper()
+		d, _ := getWithResponse(billerCode)
+		return d
+	}
+
+	d := get("MTNPTU")
+	assert.Equal(t, "MTN PREPAID TOPUP", d.BillerInfo.BillerName)
+	d.BillerProductInfo[0].ProductCode = "changed"
+
+	d = get("MTNPTU")
+	assert.Equal(t, 1, calls, "fresh details are served from the cache")
+	assert.NotEqual(t, "changed", d.BillerProductInfo[0].ProductCode, "cached details are copied")
+
+	_, resp := getWithResponse("MTNPTU")
+	resp.Header.Set("X-Changed", "1")
+	resp.Code = -1
+	_, resp = getWithResponse("MTNPTU")
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, resp.Header.Get("X-Changed"), "cached responses are copied")
+	assert.NotEqual(t, -1, resp.Code)
+
+	get("OTHER")
+	assert.Equal(t, 2, calls, "details are cached per biller")
+
+	clock.now = clock.now.Add(6 * time.Minute)
+	get("MTNPTU")
+	assert.Equal(t, 3, calls, "expired details are fetched again")
+
+	t.Run("revalidation", func(t *testing.T) {
+		etag = `"v1"`
+		clock.now = clock.now.Add(6 * time.Minute)
+		get("MTNPTU")
+		assert.Equal(t, 4, calls)
+
+		clock.now = clock.now.Add(6 * time.Minute)
+		d := get("MTNPTU")
+		assert.Equal(t, 5, calls)
+		assert.Equal(t, 1, notModified)
+		assert.Equal(t, "MTN PREPAID TOPUP", d.BillerInfo.BillerName)
+
+		get("MTNPTU")
+		assert.Equal(t, 5, calls, "revalidated details are fresh again")
+	})
+}
@@ -0,0 +1,167 @@
+package ecobank
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidFormData is wrapped by the violations returned when validating form data against a BillerForm.
+var ErrInvalidFormData = errors.New("invalid form data")
+
+// BillerForm is the form a biller requires to be filled in for bill payments, made of the BillFormData of
+// its details. It validates the form data supplied by users before it is sent in the FormDataValue of
+// BillPaymentParams or ValidateBillerOptions, so that the payment is not rejected by the biller.
+type BillerForm struct {
+	BillerCode string
+	// Fields are the fields of the form, sorted by serial number.
+	Fields []BillFormData
+}
+
+// Form returns the form of the biller.
+func (d *BillerDetails) Form() *BillerForm {
+	fields := slices.Clone(d.BillFormData)
+	slices.SortStableFunc(fields, func(a, b BillFormData) int { return a.SerialNo - b.SerialNo })
+
+	return &BillerForm{BillerCode: d.BillerInfo.BillerCode, Fields: fields}
+}
+
+// Field returns the field of the form with the given name, which is matched case-insensitively.
+func (f *BillerForm) Field(name string) (*BillFormData, bool) {
+	for i := range f.Fields {
+		if strings.EqualFold(f.Fields[i].FieldName, name) {
+			return &f.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// Validate checks the form data against the fields of the form. It is like FormData, without returning
+// the form data.
+func (f *BillerForm) Validate(values FormDataArray) error {
+	_, err := f.FormData(values)
+	return err
+}
+
+// FormData validates the form data against the fields of the form and returns it ready to be sent to the
+// API: in the order of the fields, with their names and listed values as spelt by the biller, trimmed values,
+// and the default values of the fields which are not given. It checks that:
+//
+//   - every value is for a field of the form, which is only given once
+//   - the required fields have a value, see BillFormData.Required
+//   - every value is of the data type of its field, is not longer than its maximum length and is one of
+//     its values if it has a list of values, see BillFormData.ValidateValue
+//
+// All violations are returned joined with errors.Join, each wrapping ErrInvalidFormData.
+func (f *BillerForm) FormData(values FormDataArray) (FormDataArray, error) {
+	var errs []error
+	violation := func(err error) {
+		errs = append(errs, fmt.Errorf("biller %s: %w", f.BillerCode, err))
+	}
+
+	given := make(map[string]string, len(values))
+	for _, v := range values {
+		field, ok := f.Field(v.FieldName)
+		if !ok {
+			violation(fmt.Errorf("%w: unknown field %q", ErrInvalidFormData, v.FieldName))
+			continue
+		}
+		if _, ok := given[field.FieldName]; ok {
+			violation(fmt.Errorf("%w: field %q is given more than once", ErrInvalidFormData, field.FieldName))
+			continue
+		}
+		given[field.FieldName] = strings.TrimSpace(v.FieldValue)
+	}
+
+	data := make(FormDataArray, 0, len(f.Fields))
+	for _, field := range f.Fields {
+		value, ok := given[field.FieldName]
+		if !ok || value == "" {
+			value = field.DefaultValue
+		}
+
+		if value == "" {
+			if field.Required() {
+				violation(fmt.Errorf("%w: field %q is required", ErrInvalidFormData, field.FieldName))
+			}
+			continue
+		}
+
+		if err := field.ValidateValue(value); err != nil {
+			violation(err)
+			continue
+		}
+		// values of a list are sent as listed by the biller
+		if i := slices.IndexFunc(field.Values(), func(v string) bool { return strings.EqualFold(v, value) }); i >= 0 {
+			value = field.Values()[i]
+		}
+		data = append(data, FormData{FieldName: field.FieldName, FieldValue: value})
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Required reports whether the field must have a value, which is the case of the fields validated by
+// the biller.
+func (f BillFormData) Required() bool {
+	return strings.EqualFold(f.ValidateField, "Y")
+}
+
+// Values returns the values the field is restricted to, from its list of values, which are separated by
+// commas, semicolons or pipes, and its lookup values. It returns nil if the field accepts any value.
+func (f BillFormData) Values() []string {
+	var values []string
+	for _, v := range strings.FieldsFunc(f.ListOfValues, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|'
+	}) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	for _, v := range f.LookupValue {
+		if v = strings.TrimSpace(v); v != "" && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// ValidateValue checks a value of the field against its data type, maximum length and values. Numeric,
+// decimal and email data types are checked, and other data types, such as STRING, accept any value.
+// The returned error wraps ErrInvalidFormData.
+func (f BillFormData) ValidateValue(value string) error {
+	if f.MaxFieldLength > 0 {
+		if n := utf8.RuneCountInString(value); n > f.MaxFieldLength {
+			return fmt.Errorf("%w: field %q is %d characters long, the maximum is %d", ErrInvalidFormData, f.FieldName, n, f.MaxFieldLength)
+		}
+	}
+
+	switch strings.ToUpper(f.DataType) {
+	case "NUMBER", "NUMERIC", "INTEGER", "INT", "DIGITS":
+		if strings.Trim(value, "0123456789") != "" {
+			return fmt.Errorf("%w: field %q must be a number, got %q", ErrInvalidFormData, f.FieldName, value)
+		}
+	case "DECIMAL", "AMOUNT", "DOUBLE", "FLOAT":
+		if _, err := decimal.NewFromString(value); err != nil {
+			return fmt.Errorf("%w: field %q must be a decimal number, got %q", ErrInvalidFormData, f.FieldName, value)
+		}
+	case "EMAIL":
+		if _, err := mail.ParseAddress(value); err != nil {
+			return fmt.Errorf("%w: field %q must be an email address, got %q", ErrInvalidFormData, f.FieldName, value)
+		}
+	}
+
+	if values := f.Values(); len(values) > 0 && !slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) }) {
+		return fmt.Errorf("%w: field %q must be one of %q, got %q", ErrInvalidFormData, f.FieldName, values, value)
+	}
+
+	return nil
+}
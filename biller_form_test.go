@@ -0,0 +1,83 @@
+package ecobank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBillerForm_FormData(t *testing.T) {
+	details := &BillerDetails{
+		BillFormData: []BillFormData{
+			{SerialNo: 3, FieldName: "Email", DataType: "EMAIL", MaxFieldLength: 40},
+			{SerialNo: 1, FieldName: "MOBILE NUMBER", DataType: "NUMBER", ValidateField: "Y", MaxFieldLength: 10},
+			{SerialNo: 2, FieldName: "Bouquet", DataType: "STRING", ValidateField: "Y", ListOfValues: "Compact|Premium", LookupValue: []string{"Family"}},
+			{SerialNo: 4, FieldName: "Amount", DataType: "DECIMAL", DefaultValue: "100"},
+		},
+	}
+	details.BillerInfo.BillerCode = "DSTV"
+
+	form := details.Form()
+	assert.Equal(t, "DSTV", form.BillerCode)
+	assert.Equal(t, []int{1, 2, 3, 4}, []int{form.Fields[0].SerialNo, form.Fields[1].SerialNo, form.Fields[2].SerialNo, form.Fields[3].SerialNo})
+	assert.Equal(t, []string{"Compact", "Premium", "Family"}, form.Fields[1].Values())
+
+	data, err := form.FormData(FormDataArray{
+		{FieldName: "bouquet", FieldValue: "premium"},
+		{FieldName: "mobile number", FieldValue: " 0241234567 "},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, FormDataArray{
+		{FieldName: "MOBILE NUMBER", FieldValue: "0241234567"},
+		{FieldName: "Bouquet", FieldValue: "Premium"},
+		{FieldName: "Amount", FieldValue: "100"},
+	}, data)
+
+	testCases := []struct {
+		name    string
+		values  FormDataArray
+		wantErr string
+	}{
+		{
+			name:    "missing required field",
+			values:  FormDataArray{{FieldName: "Bouquet", FieldValue: "Family"}},
+			wantErr: `biller DSTV: invalid form data: field "MOBILE NUMBER" is required`,
+		},
+		{
+			name:    "not a number",
+			values:  FormDataArray{{FieldName: "MOBILE NUMBER", FieldValue: "024-123"}, {FieldName: "Bouquet", FieldValue: "Family"}},
+			wantErr: `biller DSTV: invalid form data: field "MOBILE NUMBER" must be a number, got "024-123"`,
+		},
+		{
+			name:    "too long",
+			values:  FormDataArray{{FieldName: "MOBILE NUMBER", FieldValue: "233241234567"}, {FieldName: "Bouquet", FieldValue: "Family"}},
+			wantErr: `biller DSTV: invalid form data: field "MOBILE NUMBER" is 12 characters long, the maximum is 10`,
+		},
+		{
+			name:    "not in list",
+			values:  FormDataArray{{FieldName: "MOBILE NUMBER", FieldValue: "0241234567"}, {FieldName: "Bouquet", FieldValue: "Basic"}},
+			wantErr: `biller DSTV: invalid form data: field "Bouquet" must be one of ["Compact" "Premium" "Family"], got "Basic"`,
+		},
+		{
+			name:   "invalid email and amount",
+			values: FormDataArray{{FieldName: "MOBILE NUMBER", FieldValue: "0241234567"}, {FieldName: "Bouquet", FieldValue: "Family"}, {FieldName: "Email", FieldValue: "kojo"}, {FieldName: "Amount", FieldValue: "ten"}},
+			wantErr: "biller DSTV: invalid form data: field \"Email\" must be an email address, got \"kojo\"\n" +
+				"biller DSTV: invalid form data: field \"Amount\" must be a decimal number, got \"ten\"",
+		},
+		{
+			name:   "unknown and repeated fields",
+			values: FormDataArray{{FieldName: "MOBILE NUMBER", FieldValue: "0241234567"}, {FieldName: "Bouquet", FieldValue: "Family"}, {FieldName: "bouquet", FieldValue: "Compact"}, {FieldName: "Phone", FieldValue: "1"}},
+			wantErr: "biller DSTV: invalid form data: field \"Bouquet\" is given more than once\n" +
+				"biller DSTV: invalid form data: unknown field \"Phone\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := form.Validate(tc.values)
+			assert.ErrorIs(t, err, ErrInvalidFormData)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Package calendar provides public holidays and business day arithmetic for the countries
+// Ecobank affiliates operate in.
+//
+// Countries are identified either by their ISO 3166-1 alpha-2 code, e.g. "GH", or by the
+// affiliate code used by the Ecobank API, e.g. "EGH". Countries without holiday data only
+// have Saturdays and Sundays off.
+//
+// Islamic holidays follow the lunar calendar and are only known in advance as estimates, so
+// governments may declare them a day earlier or later. Use AddHoliday to record such changes
+// and any holidays declared at short notice.
+package calendar
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Holiday is a public holiday.
+type Holiday struct {
+	// Date is midnight UTC of the day of the holiday.
+	Date time.Time
+	Name string
+}
+
+// Calendar tells the business days of affiliates using the holiday data of this package.
+// It satisfies the BusinessCalendar interface of the ecobank package.
+type Calendar struct{}
+
+// IsBusinessDay reports whether t falls on a business day of the affiliate.
+func (Calendar) IsBusinessDay(affiliateCode string, t time.Time) bool {
+	return IsBusinessDay(affiliateCode, t)
+}
+
+var (
+	mu    sync.RWMutex
+	extra = make(map[string][]Holiday) // holidays added with AddHoliday, by country
+)
+
+// AddHoliday adds a holiday to a country, such as a holiday declared at short notice
+// or the confirmed date of an Islamic holiday. It is safe for concurrent use.
+func AddHoliday(code string, date time.Time, name string) {
+	country := Country(code)
+
+	mu.Lock()
+	defer mu.Unlock()
+	extra[country] = append(extra[country], Holiday{Date: day(date), Name: name})
+}
+
+// affiliateCountries are the ISO 3166-1 alpha-2 codes of the countries of the Ecobank affiliates, by affiliate code.
+var affiliateCountries = map[string]string{
+	"EBF": "BF", "EBJ": "BJ", "EBI": "BI", "ECD": "CD", "ECF": "CF", "ECG": "CG", "ECI": "CI", "ECM": "CM",
+	"ECV": "CV", "EGA": "GA", "EGH": "GH", "EGM": "GM", "EGN": "GN", "EGQ": "GQ", "EGW": "GW", "EKE": "KE",
+	"ELR": "LR", "EML": "ML", "EMW": "MW", "EMZ": "MZ", "ENE": "NE", "ENG": "NG", "ERW": "RW", "ESL": "SL",
+	"ESN": "SN", "ESS": "SS", "EST": "ST", "ETD": "TD", "ETG": "TG", "ETZ": "TZ", "EUG": "UG", "EZM": "ZM",
+	"EZW": "ZW",
+}
+
+// Country returns the ISO 3166-1 alpha-2 country code for an affiliate or country code.
+// Other codes, such as unknown affiliate codes, are returned upper-cased.
+func Country(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if country, ok := affiliateCountries[code]; ok {
+		return country
+	}
+	return code
+}
+
+// Countries returns the codes of the countries with holiday data, sorted.
+func Countries() []string {
+	codes := make([]string, 0, len(countries))
+	for code := range countries {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Holidays returns the public holidays of a country in a year, sorted by date.
+//
+// Holidays which fall on a weekend and are observed on the next working day in the country
+// are included on both days.
+func Holidays(code string, year int) []Holiday {
+	country := Country(code)
+
+	var holidays []Holiday
+	if c, ok := countries[country]; ok {
+		holidays = c.holidays(year)
+	}
+
+	mu.RLock()
+	for _, h := range extra[country] {
+		if h.Date.Year() == year {
+			holidays = append(holidays, h)
+		}
+	}
+	mu.RUnlock()
+
+	sort.SliceStable(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+
+	return holidays
+}
+
+// IsHoliday reports whether t falls on a public holiday of the country.
+func IsHoliday(code string, t time.Time) bool {
+	d := day(t)
+	for _, h := range Holidays(code, d.Year()) {
+		if h.Date.Equal(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWeekend reports whether t falls on a Saturday or Sunday.
+func IsWeekend(t time.Time) bool {
+	wd := t.Weekday()
+	return wd == time.Saturday || wd == time.Sunday
+}
+
+// IsBusinessDay reports whether t falls on a day which is neither a weekend nor a public holiday of the country.
+func IsBusinessDay(code string, t time.Time) bool {
+	return !IsWeekend(t) && !IsHoliday(code, t)
+}
+
+// AddBusinessDays returns t moved by n business days of the country, keeping its time of day.
+// A negative n moves t backwards. If n is zero, t is returned unchanged.
+func AddBusinessDays(code string, t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if IsBusinessDay(code, t) {
+			n--
+		}
+	}
+
+	return t
+}
+
+// day returns midnight UTC of the calendar day of t in its location.
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
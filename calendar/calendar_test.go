@@ -0,0 +1,81 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCountry(t *testing.T) {
+	assert.Equal(t, "GH", Country("EGH"))
+	assert.Equal(t, "GH", Country("gh"))
+	assert.Equal(t, "NG", Country(" eng "))
+	assert.Equal(t, "TD", Country("ETD"))
+	assert.Equal(t, "ETH", Country("eth"), "codes which are not affiliate codes are kept")
+	assert.Equal(t, "ETI", Country("ETI"))
+}
+
+func TestEasterSunday(t *testing.T) {
+	assert.Equal(t, date(2024, time.March, 31), easterSunday(2024))
+	assert.Equal(t, date(2025, time.April, 20), easterSunday(2025))
+	assert.Equal(t, date(2026, time.April, 5), easterSunday(2026))
+}
+
+func TestIsHoliday(t *testing.T) {
+	testCases := []struct {
+		name string
+		code string
+		date time.Time
+		want bool
+	}{
+		{name: "fixed", code: "EGH", date: date(2025, time.March, 6), want: true},
+		{name: "fixed in another country", code: "ENG", date: date(2025, time.March, 6)},
+		{name: "easter", code: "GH", date: date(2025, time.April, 18), want: true},
+		{name: "nth weekday", code: "GH", date: date(2025, time.December, 5), want: true},
+		{name: "weekday with offset", code: "ZM", date: date(2025, time.July, 8), want: true},
+		{name: "lunar", code: "NG", date: date(2025, time.April, 1), want: true},
+		{name: "substituted to monday", code: "KE", date: date(2027, time.December, 13), want: true},
+		{name: "substituted past another holiday", code: "GH", date: date(2027, time.December, 28), want: true},
+		{name: "not substituted", code: "TG", date: date(2027, time.December, 27)},
+		{name: "time of day", code: "GH", date: time.Date(2025, time.March, 6, 23, 59, 0, 0, time.UTC), want: true},
+		{name: "unknown country", code: "XX", date: date(2025, time.January, 1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsHoliday(tc.code, tc.date))
+		})
+	}
+}
+
+func TestAddHoliday(t *testing.T) {
+	d := date(2031, time.July, 2)
+	assert.False(t, IsHoliday("EGH", d))
+
+	AddHoliday("EGH", d, "Special Holiday")
+
+	assert.True(t, IsHoliday("GH", d))
+	assert.False(t, IsHoliday("NG", d))
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	// Thursday before Easter
+	start := time.Date(2025, time.April, 17, 10, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, time.April, 22, 10, 30, 0, 0, time.UTC), AddBusinessDays("GH", start, 1))
+	assert.Equal(t, time.Date(2025, time.April, 18, 10, 30, 0, 0, time.UTC), AddBusinessDays("XX", start, 1))
+	assert.Equal(t, time.Date(2025, time.April, 16, 10, 30, 0, 0, time.UTC), AddBusinessDays("GH", start, -1))
+	assert.Equal(t, start, AddBusinessDays("GH", start, 0))
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	assert.True(t, IsBusinessDay("GH", date(2025, time.March, 7)))
+	assert.False(t, IsBusinessDay("GH", date(2025, time.March, 6)))
+	assert.False(t, IsBusinessDay("GH", date(2025, time.March, 8)))
+	assert.True(t, Calendar{}.IsBusinessDay("EGH", date(2025, time.March, 7)))
+}
@@ -0,0 +1,377 @@
+package calendar
+
+import "time"
+
+// country describes the public holidays of a country.
+type country struct {
+	// fixed holidays fall on the same date every year.
+	fixed []fixedHoliday
+	// easter holidays are offset from Easter Sunday.
+	easter []easterHoliday
+	// weekday holidays fall on the nth weekday of a month.
+	weekday []weekdayHoliday
+	// lunar holidays follow the Islamic calendar.
+	lunar []lunarHoliday
+	// substitute are the days of the week on which a holiday is moved to the next working day.
+	substitute []time.Weekday
+}
+
+type fixedHoliday struct {
+	month time.Month
+	day   int
+	name  string
+}
+
+type easterHoliday struct {
+	offset int
+	name   string
+}
+
+type weekdayHoliday struct {
+	month   time.Month
+	weekday time.Weekday
+	n       int // 1 for the first such weekday of the month
+	offset  int // days after that weekday
+	name    string
+}
+
+type lunarHoliday struct {
+	dates map[int]fixedHoliday // by year
+	name  string
+	days  int // number of consecutive days
+}
+
+func (c *country) holidays(year int) []Holiday {
+	var holidays []Holiday
+	add := func(date time.Time, name string) {
+		holidays = append(holidays, Holiday{Date: date, Name: name})
+	}
+
+	for _, h := range c.fixed {
+		add(time.Date(year, h.month, h.day, 0, 0, 0, 0, time.UTC), h.name)
+	}
+
+	sunday := easterSunday(year)
+	for _, h := range c.easter {
+		add(sunday.AddDate(0, 0, h.offset), h.name)
+	}
+
+	for _, h := range c.weekday {
+		first := time.Date(year, h.month, 1, 0, 0, 0, 0, time.UTC)
+		offset := (int(h.weekday) - int(first.Weekday()) + 7) % 7
+		add(first.AddDate(0, 0, offset+7*(h.n-1)+h.offset), h.name)
+	}
+
+	for _, h := range c.lunar {
+		date, ok := h.dates[year]
+		if !ok {
+			continue
+		}
+		start := time.Date(year, date.month, date.day, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < max(h.days, 1); i++ {
+			add(start.AddDate(0, 0, i), h.name)
+		}
+	}
+
+	return c.substituted(holidays)
+}
+
+// substituted adds the days on which holidays falling on a substitute weekday are observed.
+func (c *country) substituted(holidays []Holiday) []Holiday {
+	if len(c.substitute) == 0 {
+		return holidays
+	}
+
+	taken := make(map[time.Time]bool, len(holidays))
+	for _, h := range holidays {
+		taken[h.Date] = true
+	}
+
+	n := len(holidays)
+	for i := 0; i < n; i++ {
+		h := holidays[i]
+		if !c.substitutes(h.Date.Weekday()) {
+			continue
+		}
+
+		date := h.Date.AddDate(0, 0, 1)
+		for IsWeekend(date) || taken[date] {
+			date = date.AddDate(0, 0, 1)
+		}
+		taken[date] = true
+		holidays = append(holidays, Holiday{Date: date, Name: h.Name + " (observed)"})
+	}
+
+	return holidays
+}
+
+func (c *country) substitutes(wd time.Weekday) bool {
+	for _, s := range c.substitute {
+		if s == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// easterSunday returns the date of Easter Sunday in the Gregorian calendar.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Estimated dates of the Islamic holidays.
+var (
+	eidAlFitr = map[int]fixedHoliday{
+		2024: {month: time.April, day: 10},
+		2025: {month: time.March, day: 31},
+		2026: {month: time.March, day: 20},
+		2027: {month: time.March, day: 10},
+	}
+	eidAlAdha = map[int]fixedHoliday{
+		2024: {month: time.June, day: 16},
+		2025: {month: time.June, day: 6},
+		2026: {month: time.May, day: 27},
+		2027: {month: time.May, day: 16},
+	}
+	mawlid = map[int]fixedHoliday{
+		2024: {month: time.September, day: 15},
+		2025: {month: time.September, day: 4},
+		2026: {month: time.August, day: 25},
+		2027: {month: time.August, day: 14},
+	}
+)
+
+var (
+	newYear      = fixedHoliday{time.January, 1, "New Year's Day"}
+	labourDay    = fixedHoliday{time.May, 1, "Labour Day"}
+	assumption   = fixedHoliday{time.August, 15, "Assumption Day"}
+	allSaints    = fixedHoliday{time.November, 1, "All Saints' Day"}
+	christmas    = fixedHoliday{time.December, 25, "Christmas Day"}
+	boxingDay    = fixedHoliday{time.December, 26, "Boxing Day"}
+	goodFriday   = easterHoliday{-2, "Good Friday"}
+	holySaturday = easterHoliday{-1, "Holy Saturday"}
+	easterMonday = easterHoliday{1, "Easter Monday"}
+	ascension    = easterHoliday{39, "Ascension Day"}
+	whitMonday   = easterHoliday{50, "Whit Monday"}
+	fitr         = lunarHoliday{dates: eidAlFitr, name: "Eid al-Fitr", days: 1}
+	adha         = lunarHoliday{dates: eidAlAdha, name: "Eid al-Adha", days: 1}
+	prophetBirth = lunarHoliday{dates: mawlid, name: "Prophet's Birthday", days: 1}
+	weekends     = []time.Weekday{time.Saturday, time.Sunday}
+	sundays      = []time.Weekday{time.Sunday}
+)
+
+var countries = map[string]*country{
+	// Cameroon
+	"CM": {
+		fixed: []fixedHoliday{
+			newYear,
+			{time.February, 11, "Youth Day"},
+			labourDay,
+			{time.May, 20, "National Day"},
+			assumption,
+			christmas,
+		},
+		easter: []easterHoliday{goodFriday, ascension},
+		lunar:  []lunarHoliday{fitr, adha},
+	},
+	// Côte d'Ivoire
+	"CI": {
+		fixed: []fixedHoliday{
+			newYear,
+			labourDay,
+			{time.August, 7, "Independence Day"},
+			assumption,
+			allSaints,
+			{time.November, 15, "National Peace Day"},
+			christmas,
+		},
+		easter: []easterHoliday{easterMonday, ascension, whitMonday},
+		lunar:  []lunarHoliday{fitr, adha, prophetBirth},
+	},
+	// Ghana
+	"GH": {
+		fixed: []fixedHoliday{
+			newYear,
+			{time.January, 7, "Constitution Day"},
+			{time.March, 6, "Independence Day"},
+			labourDay,
+			{time.July, 1, "Republic Day"},
+			{time.August, 4, "Founders' Day"},
+			{time.September, 21, "Kwame Nkrumah Memorial Day"},
+			christmas,
+			boxingDay,
+		},
+		easter:     []easterHoliday{goodFriday, easterMonday},
+		weekday:    []weekdayHoliday{{month: time.December, weekday: time.Friday, n: 1, name: "Farmers' Day"}},
+		lunar:      []lunarHoliday{fitr, adha},
+		substitute: weekends,
+	},
+	// Kenya
+	"KE": {
+		fixed: []fixedHoliday{
+			newYear,
+			labourDay,
+			{time.June, 1, "Madaraka Day"},
+			{time.October, 10, "Mazingira Day"},
+			{time.October, 20, "Mashujaa Day"},
+			{time.December, 12, "Jamhuri Day"},
+			christmas,
+			boxingDay,
+		},
+		easter:     []easterHoliday{goodFriday, easterMonday},
+		lunar:      []lunarHoliday{fitr},
+		substitute: sundays,
+	},
+	// Nigeria
+	"NG": {
+		fixed: []fixedHoliday{
+			newYear,
+			labourDay,
+			{time.June, 12, "Democracy Day"},
+			{time.October, 1, "Independence Day"},
+			christmas,
+			boxingDay,
+		},
+		easter: []easterHoliday{goodFriday, easterMonday},
+		lunar: []lunarHoliday{
+			{dates: eidAlFitr, name: "Eid al-Fitr", days: 2},
+			{dates: eidAlAdha, name: "Eid al-Adha", days: 2},
+			prophetBirth,
+		},
+		substitute: weekends,
+	},
+	// Rwanda
+	"RW": {
+		fixed: []fixedHoliday{
+			newYear,
+			{time.January, 2, "New Year Holiday"},
+			{time.February, 1, "National Heroes' Day"},
+			{time.April, 7, "Genocide against the Tutsi Memorial Day"},
+			labourDay,
+			{time.July, 1, "Independence Day"},
+			{time.July, 4, "Liberation Day"},
+			assumption,
+			christmas,
+			boxingDay,
+		},
+		easter:  []easterHoliday{goodFriday, easterMonday},
+		weekday: []weekdayHoliday{{month: time.August, weekday: time.Friday, n: 1, name: "Umuganura Day"}},
+		lunar:   []lunarHoliday{fitr, adha},
+	},
+	// Senegal
+	"SN": {
+		fixed: []fixedHoliday{
+			newYear,
+			{time.April, 4, "Independence Day"},
+			labourDay,
+			assumption,
+			allSaints,
+			christmas,
+		},
+		easter: []easterHoliday{easterMonday, ascension, whitMonday},
+		lunar:  []lunarHoliday{fitr, adha, prophetBirth},
+	},
+	// Tanzania
+	"TZ": {
+		fixed: []fixedHoliday{
+			newYear,
+			{time.January, 12, "Zanzibar Revolution Day"},
+			{time.April, 7, "Karume Day"},
+			{time.April, 26, "Union Day"},
+			labourDay,
+			{time.July, 7, "Saba Saba Day"},
+			{time.August, 8, "Nane Nane Day"},
+			{time.October, 14, "Nyerere Day"},
+			{time.December, 9, "Independence Day"},
+			christmas,
+			boxingDay,
+		},
+		easter: []easterHoliday{goodFriday, easterMonday},
+		lunar:  []lunarHoliday{{dates: eidAlFitr, name: "Eid al-Fitr", days: 2}, adha, prophetBirth},
+	},
+	// Togo
+	"TG": {
+		fixed: []fixedHoliday{
+			newYear,
+			{time.January, 13, "Liberation Day"},
+			{time.April, 27, "Independence Day"},
+			labourDay,
+			{time.June, 21, "Martyrs' Day"},
+			assumption,
+			allSaints,
+			christmas,
+		},
+		easter: []easterHoliday{easterMonday, ascension, whitMonday},
+		lunar:  []lunarHoliday{fitr, adha},
+	},
+	// Uganda
+	"UG": {
+		fixed: []fixedHoliday{
+			newYear,
+			{time.January, 26, "Liberation Day"},
+			{time.February, 16, "Archbishop Janani Luwum Day"},
+			{time.March, 8, "International Women's Day"},
+			labourDay,
+			{time.June, 3, "Martyrs' Day"},
+			{time.June, 9, "National Heroes' Day"},
+			{time.October, 9, "Independence Day"},
+			christmas,
+			boxingDay,
+		},
+		easter: []easterHoliday{goodFriday, easterMonday},
+		lunar:  []lunarHoliday{fitr, adha},
+	},
+	// Zambia
+	"ZM": {
+		fixed: []fixedHoliday{
+			newYear,
+			{time.March, 8, "International Women's Day"},
+			{time.March, 12, "Youth Day"},
+			labourDay,
+			{time.May, 25, "Africa Day"},
+			{time.October, 18, "National Day of Prayer"},
+			{time.October, 24, "Independence Day"},
+			christmas,
+		},
+		easter: []easterHoliday{goodFriday, holySaturday, easterMonday},
+		weekday: []weekdayHoliday{
+			{month: time.July, weekday: time.Monday, n: 1, name: "Heroes' Day"},
+			{month: time.July, weekday: time.Monday, n: 1, offset: 1, name: "Unity Day"},
+			{month: time.August, weekday: time.Monday, n: 1, name: "Farmers' Day"},
+		},
+		substitute: sundays,
+	},
+	// Zimbabwe
+	"ZW": {
+		fixed: []fixedHoliday{
+			newYear,
+			{time.February, 21, "National Youth Day"},
+			{time.April, 18, "Independence Day"},
+			labourDay,
+			{time.May, 25, "Africa Day"},
+			{time.December, 22, "Unity Day"},
+			christmas,
+			boxingDay,
+		},
+		easter: []easterHoliday{goodFriday, holySaturday, easterMonday},
+		weekday: []weekdayHoliday{
+			{month: time.August, weekday: time.Monday, n: 2, name: "Heroes' Day"},
+			{month: time.August, weekday: time.Monday, n: 2, offset: 1, name: "Defence Forces Day"},
+		},
+		substitute: sundays,
+	},
+}
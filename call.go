@@ -0,0 +1,56 @@
+package ecobank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// SecureHashOptions can be embedded in the request options of endpoints the library does not model,
+// so that their secure hash is generated like for the options of the library:
+//
+//	type LoanOptions struct {
+//		RequestID     string `json:"requestId"`
+//		AffiliateCode string `json:"affiliateCode"`
+//		ecobank.SecureHashOptions
+//	}
+type SecureHashOptions struct {
+	SecureHash string `json:"secureHash" securehash:"ignore" redact:"secret"` // optional: generated if not provided
+}
+
+// SetHash sets the secure hash.
+func (opt *SecureHashOptions) SetHash(hash string) {
+	opt.SecureHash = hash
+}
+
+// GetHash returns the secure hash.
+func (opt *SecureHashOptions) GetHash() string {
+	return opt.SecureHash
+}
+
+// Call sends a request to an endpoint of the corporate API the library does not model yet, e.g.
+// "merchant/loanenquiry" relative to the base URL, and decodes the response content into out,
+// which must be a non-nil pointer or nil to discard the content.
+//
+// The request is sent like the ones of the services: it is authorized, retried, and the response
+// envelope is parsed, with its errors returned as a *ResponseError. The secure hash of opts is
+// generated if they are a pointer to a struct embedding SecureHashOptions.
+//
+//	var loan Loan
+//	_, err := client.Call(ctx, http.MethodPost, "merchant/loanenquiry", &LoanOptions{...}, &loan)
+//
+// DoRequest does the same, returning the content as a new value of its type parameter.
+func (c *Client) Call(ctx context.Context, method, path string, opts, out any, options ...RequestOptionFunc) (*Response, error) {
+	if out == nil {
+		out = new(json.RawMessage)
+	} else if v := reflect.ValueOf(out); v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil, fmt.Errorf("cannot decode response into %T: must be a non-nil pointer", out)
+	}
+
+	req, err := c.NewRequest(ctx, method, path, opts, options...)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req, out)
+}
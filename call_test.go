@@ -0,0 +1,78 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loanOptions struct {
+	RequestID     string `json:"requestId"`
+	AffiliateCode string `json:"affiliateCode"`
+	LoanID        string `json:"loanId"`
+	SecureHashOptions
+}
+
+type loan struct {
+	LoanID  string          `json:"loanId"`
+	Balance FlexibleDecimal `json:"balance"`
+}
+
+func TestClient_Call(t *testing.T) {
+	var path string
+	var body map[string]any
+
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			path = req.URL.Path
+			b, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(b, &body); err != nil {
+				return nil, err
+			}
+
+			resp := httptest.NewRecorder()
+			_, err = resp.WriteString(`{"response_code": 200, "response_message": "success", "response_content": {"loanId": "L-1", "balance": "150.25"}}`)
+			return resp.Result(), err
+		},
+	}
+
+	opt := &loanOptions{RequestID: "14232436312", AffiliateCode: "EGH", LoanID: "L-1"}
+
+	var out loan
+	resp, err := client.Call(t.Context(), http.MethodPost, "merchant/loanenquiry", opt, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.Code)
+	assert.Equal(t, "L-1", out.LoanID)
+	assert.Equal(t, "150.25", out.Balance.String())
+
+	assert.Equal(t, "/corporateapi/merchant/loanenquiry", path)
+	assert.Equal(t, generateSecureHash("14232436312EGHL-1", "mock-lab-key"), body["secureHash"])
+
+	t.Run("no content", func(t *testing.T) {
+		resp, err := client.Call(t.Context(), http.MethodPost, "merchant/loanenquiry", &loanOptions{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.Code)
+	})
+
+	t.Run("not a pointer", func(t *testing.T) {
+		_, err := client.Call(t.Context(), http.MethodPost, "merchant/loanenquiry", &loanOptions{}, out)
+		assert.EqualError(t, err, "cannot decode response into ecobank.loan: must be a non-nil pointer")
+	})
+
+	t.Run("API errors", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 400, "response_message": "failed", "errors": ["invalid loan"]}`, http.StatusOK)
+
+		_, err := client.Call(t.Context(), http.MethodPost, "merchant/loanenquiry", &loanOptions{}, &out)
+		var respErr *ResponseError
+		assert.ErrorAs(t, err, &respErr)
+	})
+}
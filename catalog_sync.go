@@ -0,0 +1,273 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrBillerNotFound is returned by CatalogSync lookups when the biller is not in the synced catalog.
+var ErrBillerNotFound = errors.New("biller not found")
+
+const defaultCatalogSyncInterval = 6 * time.Hour
+
+// CatalogBiller is a biller stored by a CatalogSync.
+type CatalogBiller struct {
+	BillerInfo
+	// Details of the biller, if CatalogSync.Details is set and they could be fetched.
+	Details *BillerDetails `json:"details,omitempty"`
+	// SyncedAt is when the biller was last fetched from the API.
+	SyncedAt time.Time `json:"syncedAt"`
+}
+
+// CatalogStore stores the billers synced by a CatalogSync.
+// Implementations must be safe for concurrent use. Use a shared store such as Redis or a database
+// to share the catalog across processes, or to have it available right after a restart.
+type CatalogStore interface {
+	// SaveCatalog replaces the billers of the affiliate.
+	SaveCatalog(ctx context.Context, affiliateCode string, billers []CatalogBiller) error
+	// LoadCatalog returns the billers of the affiliate, or none if it has not been synced.
+	LoadCatalog(ctx context.Context, affiliateCode string) ([]CatalogBiller, error)
+	// LoadBiller returns the biller of the affiliate with the given code, or nil if it is not stored.
+	LoadBiller(ctx context.Context, affiliateCode, billerCode string) (*CatalogBiller, error)
+}
+
+// CatalogSync keeps a local copy of the billers of a set of affiliates, so that billers can be looked up
+// and bill payments validated without calling PaymentService.GetBillerList on every payment.
+//
+// Call Run in a goroutine to sync the catalog periodically, or Sync to sync it once.
+type CatalogSync struct {
+	client *Client
+
+	// Store stores the synced billers.
+	Store CatalogStore
+	// RequestID identifies the corporate, as in GetBillerListOptions.
+	RequestID string
+	// AffiliateCodes are the affiliates whose billers are synced, e.g. "EGH".
+	AffiliateCodes []string
+	// Interval is the time between syncs made by Run. It defaults to 6 hours.
+	Interval time.Duration
+	// Details makes the sync also fetch the details of every biller with PaymentService.GetBillerDetails,
+	// which are needed to validate bill amounts against the biller products.
+	Details bool
+	// OnError, if set, is called by Run with the errors of each sync.
+	OnError func(err error)
+}
+
+// NewCatalogSync returns a CatalogSync which syncs the billers of the affiliates into memory.
+func NewCatalogSync(client *Client, requestID string, affiliateCodes ...string) *CatalogSync {
+	return &CatalogSync{
+		client:         client,
+		Store:          NewMemoryCatalogStore(),
+		RequestID:      requestID,
+		AffiliateCodes: affiliateCodes,
+	}
+}
+
+// Run syncs the catalog right away and then every Interval, shifted by a random jitter, until ctx is done.
+// Errors are passed to OnError and do not stop the sync. Run returns the context's error,
+// or ErrClientClosed if the client is closed first.
+func (s *CatalogSync) Run(ctx context.Context, options ...RequestOptionFunc) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultCatalogSyncInterval
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.client.closed():
+			return ErrClientClosed
+		case <-timer.C:
+		}
+
+		if err := s.Sync(ctx, options...); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, ErrClientClosed) {
+				return err
+			}
+			if s.OnError != nil {
+				s.OnError(err)
+			}
+		}
+
+		timer.Reset(jitter(interval))
+	}
+}
+
+// Sync fetches the billers of all the affiliates and saves them to the store. The catalog of an
+// affiliate whose biller list cannot be fetched is left as is. If the details of a biller cannot be
+// fetched, its previously synced details are kept. All errors are joined into the returned error.
+func (s *CatalogSync) Sync(ctx context.Context, options ...RequestOptionFunc) error {
+	var errs []error
+	for _, affiliateCode := range s.AffiliateCodes {
+		if err := s.syncAffiliate(ctx, affiliateCode, options...); err != nil {
+			errs = append(errs, fmt.Errorf("syncing billers of %s: %w", affiliateCode, err))
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *CatalogSync) syncAffiliate(ctx context.Context, affiliateCode string, options ...RequestOptionFunc) error {
+	var billers []CatalogBiller
+	_, err := s.client.Payment.ForEachBiller(ctx, &GetBillerListOptions{
+		RequestID:     s.RequestID,
+		AffiliateCode: affiliateCode,
+	}, func(b *BillerInfo) error {
+		billers = append(billers, CatalogBiller{BillerInfo: *b, SyncedAt: s.client.now()})
+		return nil
+	}, options...)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if s.Details {
+		previous, err := s.Store.LoadCatalog(ctx, affiliateCode)
+		if err != nil {
+			return err
+		}
+
+		for i := range billers {
+			b := &billers[i]
+			b.Details, _, err = s.client.Payment.GetBillerDetails(ctx, &GetBillerDetailsOptions{
+				RequestID:     s.RequestID,
+				AffiliateCode: affiliateCode,
+				BillerCode:    b.BillerCode,
+			}, options...)
+			if err == nil {
+				continue
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			errs = append(errs, fmt.Errorf("getting details of biller %s: %w", b.BillerCode, err))
+			if j := slices.IndexFunc(previous, func(p CatalogBiller) bool { return p.BillerCode == b.BillerCode }); j >= 0 {
+				b.Details = previous[j].Details
+			}
+		}
+	}
+
+	if err := s.Store.SaveCatalog(ctx, affiliateCode, billers); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// Biller returns the synced biller of the affiliate with the given code.
+// It returns an error wrapping ErrBillerNotFound if the biller is not in the catalog.
+func (s *CatalogSync) Biller(ctx context.Context, affiliateCode, billerCode string) (*CatalogBiller, error) {
+	b, err := s.Store.LoadBiller(ctx, affiliateCode, billerCode)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fmt.Errorf("%w: %s in %s", ErrBillerNotFound, billerCode, affiliateCode)
+	}
+	return b, nil
+}
+
+// Billers returns the synced billers of the affiliate.
+func (s *CatalogSync) Billers(ctx context.Context, affiliateCode string) ([]CatalogBiller, error) {
+	return s.Store.LoadCatalog(ctx, affiliateCode)
+}
+
+// ValidateBillExtension is like PaymentService.ValidateBillExtension, but uses the synced details of the biller
+// and also checks the amount against the denominations of the biller, if it has any. The biller details are only
+// fetched from the API if they have not been synced.
+func (s *CatalogSync) ValidateBillExtension(ctx context.Context, affiliateCode string, ext PaymentExtension, options ...RequestOptionFunc) error {
+	billerCode, productCode, err := billProduct(ext)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.Biller(ctx, affiliateCode, billerCode)
+	if err != nil {
+		return fmt.Errorf("extension %s: %w", ext.RequestID, err)
+	}
+
+	details := b.Details
+	if details == nil {
+		details, _, err = s.client.Payment.GetBillerDetails(ctx, &GetBillerDetailsOptions{
+			RequestID:     s.RequestID,
+			AffiliateCode: affiliateCode,
+			BillerCode:    billerCode,
+		}, options...)
+		if err != nil {
+			return fmt.Errorf("getting details of biller %s: %w", billerCode, err)
+		}
+	}
+
+	denominations, err := b.Denominations()
+	if err != nil {
+		return fmt.Errorf("extension %s: %w", ext.RequestID, err)
+	}
+
+	if err := details.ValidateAmount(productCode, ext.Amount, denominations...); err != nil {
+		return fmt.Errorf("extension %s: %w", ext.RequestID, err)
+	}
+	return nil
+}
+
+// MemoryCatalogStore is an in-memory CatalogStore.
+type MemoryCatalogStore struct {
+	mu      sync.RWMutex
+	billers map[string]map[string]CatalogBiller
+	lists   map[string][]CatalogBiller
+}
+
+// NewMemoryCatalogStore returns a new, empty MemoryCatalogStore.
+func NewMemoryCatalogStore() *MemoryCatalogStore {
+	return &MemoryCatalogStore{
+		billers: make(map[string]map[string]CatalogBiller),
+		lists:   make(map[string][]CatalogBiller),
+	}
+}
+
+// SaveCatalog implements CatalogStore.
+func (s *MemoryCatalogStore) SaveCatalog(_ context.Context, affiliateCode string, billers []CatalogBiller) error {
+	byCode := make(map[string]CatalogBiller, len(billers))
+	for _, b := range billers {
+		byCode[b.BillerCode] = b
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.billers[affiliateCode] = byCode
+	s.lists[affiliateCode] = slices.Clone(billers)
+	return nil
+}
+
+// LoadCatalog implements CatalogStore.
+func (s *MemoryCatalogStore) LoadCatalog(_ context.Context, affiliateCode string) ([]CatalogBiller, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.lists[affiliateCode]), nil
+}
+
+// LoadBiller implements CatalogStore.
+func (s *MemoryCatalogStore) LoadBiller(_ context.Context, affiliateCode, billerCode string) (*CatalogBiller, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.billers[affiliateCode][billerCode]
+	if !ok {
+		return nil, nil
+	}
+	return &b, nil
+}
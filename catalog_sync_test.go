@@ -0,0 +1,147 @@
+package ecobank
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/profclems/go-ecobank/ecobanktest"
+)
+
+const catalogBillerList = `{
+	"response_code": 200,
+	"response_message": "success",
+	"response_content": {
+		"billerInfo": [
+			{"billerCode": "MTNPTU", "billerName": "MTN AIRTIME", "amountDenominations": "10, 20, 50"},
+			{"billerCode": "GHWATER", "billerName": "GHANA WATER"}
+		]
+	}
+}`
+
+func newCatalogServer(t *testing.T, detailsStatus *atomic.Int32) (*Client, *atomic.Int32) {
+	t.Helper()
+
+	var listCalls atomic.Int32
+	details := ecobanktest.MustLoad(t, "merchant/getbillerdetails", "success")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/payment/getbillerlist", func(w http.ResponseWriter, r *http.Request) {
+		listCalls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(catalogBillerList))
+	})
+	mux.HandleFunc("/merchant/getbillerdetails", func(w http.ResponseWriter, r *http.Request) {
+		if status := detailsStatus.Load(); status != 0 {
+			w.WriteHeader(int(status))
+			_, _ = w.Write([]byte(`{"response_code": 500, "response_message": "error", "errors": ["unavailable"]}`))
+			return
+		}
+		details.ServeHTTP(w, r)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient("user", "password", "lab-key",
+		WithBaseURL(srv.URL),
+		WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+		WithDisableRetries(),
+	)
+	require.NoError(t, err)
+
+	return client, &listCalls
+}
+
+func TestCatalogSync_Sync(t *testing.T) {
+	var detailsStatus atomic.Int32
+	client, listCalls := newCatalogServer(t, &detailsStatus)
+
+	s := NewCatalogSync(client, "ECO2112134346", "EGH")
+	s.Details = true
+	require.NoError(t, s.Sync(t.Context()))
+	assert.EqualValues(t, 1, listCalls.Load())
+
+	billers, err := s.Billers(t.Context(), "EGH")
+	require.NoError(t, err)
+	require.Len(t, billers, 2)
+	assert.Equal(t, "MTNPTU", billers[0].BillerCode)
+	assert.NotNil(t, billers[0].Details)
+	assert.False(t, billers[0].SyncedAt.IsZero())
+
+	b, err := s.Biller(t.Context(), "EGH", "GHWATER")
+	require.NoError(t, err)
+	assert.Equal(t, "GHANA WATER", b.BillerName)
+
+	_, err = s.Biller(t.Context(), "ENG", "GHWATER")
+	assert.ErrorIs(t, err, ErrBillerNotFound)
+
+	t.Run("details error keeps previous details", func(t *testing.T) {
+		detailsStatus.Store(http.StatusServiceUnavailable)
+		t.Cleanup(func() { detailsStatus.Store(0) })
+
+		err := s.Sync(t.Context())
+		assert.ErrorContains(t, err, "syncing billers of EGH: getting details of biller MTNPTU")
+
+		b, err := s.Biller(t.Context(), "EGH", "MTNPTU")
+		require.NoError(t, err)
+		assert.NotNil(t, b.Details)
+	})
+}
+
+func TestCatalogSync_ValidateBillExtension(t *testing.T) {
+	var detailsStatus atomic.Int32
+	client, listCalls := newCatalogServer(t, &detailsStatus)
+
+	s := NewCatalogSync(client, "ECO2112134346", "EGH")
+	s.Details = true
+	require.NoError(t, s.Sync(t.Context()))
+
+	// the catalog is used from now on
+	detailsStatus.Store(http.StatusServiceUnavailable)
+
+	ext := PaymentExtension{
+		RequestID:   "4321",
+		RequestType: AIRTIMETOPUP,
+		ParamList:   NewPaymentParams(AirtimeTopupParams{BillerCode: "MTNPTU", ProductCode: "02"}),
+		Amount:      decimal.NewFromInt(20),
+	}
+	assert.NoError(t, s.ValidateBillExtension(t.Context(), "EGH", ext))
+
+	ext.Amount = decimal.NewFromInt(15)
+	err := s.ValidateBillExtension(t.Context(), "EGH", ext)
+	assert.ErrorIs(t, err, ErrInvalidBillAmount)
+	assert.ErrorContains(t, err, "not one of the denominations 10, 20, 50")
+
+	ext.ParamList = NewPaymentParams(AirtimeTopupParams{BillerCode: "UNKNOWN"})
+	assert.ErrorIs(t, s.ValidateBillExtension(t.Context(), "EGH", ext), ErrBillerNotFound)
+
+	assert.EqualValues(t, 1, listCalls.Load())
+}
+
+func TestCatalogSync_Run(t *testing.T) {
+	var detailsStatus atomic.Int32
+	client, listCalls := newCatalogServer(t, &detailsStatus)
+
+	s := NewCatalogSync(client, "ECO2112134346", "EGH", "ENG")
+	s.Interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	assert.Eventually(t, func() bool { return listCalls.Load() >= 4 }, time.Second, 5*time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	billers, err := s.Billers(t.Context(), "ENG")
+	require.NoError(t, err)
+	assert.Len(t, billers, 2)
+}
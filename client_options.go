@@ -1,10 +1,17 @@
 package ecobank
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/profclems/go-ecobank/calendar"
 )
 
 // ClientOptionFunc is a function that configures a Client.
@@ -43,6 +50,122 @@ func WithHTTPClient(httpClient *http.Client) ClientOptionFunc {
 	}
 }
 
+// WithTransportTuning tunes the connection pool of the HTTP transport for clients making many requests
+// to the gateway. maxIdleConns is the number of idle connections kept open, which all go to the gateway
+// host, and idleTimeout is how long they are kept. enableHTTP2 makes the client negotiate HTTP/2 with the
+// gateway, which multiplexes requests over a single connection; otherwise only HTTP/1.1 is used.
+//
+// It tunes the transport of the HTTP client set so far, which must be an *http.Transport,
+// so it should come after WithHTTPClient or WithRetryableClient. The transport is copied, not modified.
+func WithTransportTuning(maxIdleConns int, idleTimeout time.Duration, enableHTTP2 bool) ClientOptionFunc {
+	return func(c *Client) error {
+		if maxIdleConns < 0 {
+			return errors.New("max idle connections must not be negative")
+		}
+		if idleTimeout < 0 {
+			return errors.New("idle timeout must not be negative")
+		}
+
+		return updateTransport(c, "tune", func(transport *http.Transport) error {
+			transport.MaxIdleConns = maxIdleConns
+			transport.MaxIdleConnsPerHost = maxIdleConns
+			transport.IdleConnTimeout = idleTimeout
+			transport.ForceAttemptHTTP2 = enableHTTP2
+			if enableHTTP2 {
+				transport.TLSNextProto = nil
+			} else {
+				// a non-nil empty map disables HTTP/2
+				transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+			}
+			return nil
+		})
+	}
+}
+
+// WithInsecureSkipTLSVerify disables the verification of the TLS certificate of the gateway.
+//
+// DANGER: this exposes credentials, tokens and payments to anyone able to intercept the connection.
+// It is only meant for bank-provided UAT environments using self-signed certificates, and should never
+// be used in production. Prefer WithCustomCA, which trusts the certificate of the test gateway only.
+//
+// Like WithTransportTuning, it applies to the transport of the HTTP client set so far, which is copied,
+// so only this client is affected.
+func WithInsecureSkipTLSVerify() ClientOptionFunc {
+	return func(c *Client) error {
+		return updateTransport(c, "configure TLS of", func(transport *http.Transport) error {
+			tlsConfig(transport).InsecureSkipVerify = true //nolint:gosec // explicitly requested for test gateways
+			return nil
+		})
+	}
+}
+
+// WithCustomCA trusts the PEM encoded CA certificates in addition to the system roots when verifying the
+// TLS certificate of the gateway, e.g. for UAT environments using certificates issued by the bank's own CA.
+//
+// Like WithTransportTuning, it applies to the transport of the HTTP client set so far, which is copied,
+// so only this client is affected. An error is returned if pem contains no certificate.
+func WithCustomCA(pem []byte) ClientOptionFunc {
+	return func(c *Client) error {
+		return updateTransport(c, "configure TLS of", func(transport *http.Transport) error {
+			config := tlsConfig(transport)
+			pool := config.RootCAs
+			if pool == nil {
+				systemPool, err := x509.SystemCertPool()
+				if err != nil {
+					systemPool = x509.NewCertPool()
+				}
+				pool = systemPool
+			} else {
+				pool = pool.Clone()
+			}
+
+			if !pool.AppendCertsFromPEM(pem) {
+				return errors.New("no CA certificate found in PEM data")
+			}
+			config.RootCAs = pool
+			return nil
+		})
+	}
+}
+
+// tlsConfig returns the TLS config of the transport, setting one if it has none.
+// The config of a cloned transport is already a copy.
+func tlsConfig(transport *http.Transport) *tls.Config {
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return transport.TLSClientConfig
+}
+
+// updateTransport calls fn with a copy of the transport of the HTTP client of c, which must be an
+// *http.Transport, and sets it on a copy of the HTTP client.
+// action describes the update in the error returned for other transports.
+func updateTransport(c *Client, action string, fn func(*http.Transport) error) error {
+	httpClient := c.client.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	var transport *http.Transport
+	switch rt := httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = rt.Clone()
+	default:
+		return fmt.Errorf("cannot %s transport of type %T: must be *http.Transport", action, rt)
+	}
+
+	if err := fn(transport); err != nil {
+		return err
+	}
+
+	updated := *httpClient
+	updated.Transport = transport
+	c.client.HTTPClient = &updated
+	return nil
+}
+
 // WithRetryableClient sets the retryable client for the client.
 func WithRetryableClient(client *retryablehttp.Client) ClientOptionFunc {
 	return func(c *Client) error {
@@ -75,10 +198,119 @@ func WithRetryPolicy(retry retryablehttp.CheckRetry) ClientOptionFunc {
 	}
 }
 
-// WithBackoff sets the backoff strategy for the client.
+// WithBackoff sets the backoff strategy for the client. See WithBackoffPreset for predefined strategies.
 func WithBackoff(backoff retryablehttp.Backoff) ClientOptionFunc {
 	return func(c *Client) error {
 		c.client.Backoff = backoff
 		return nil
 	}
 }
+
+// WithDuplicateGuard sets a DuplicateGuard which checks every payment submitted with
+// PaymentService.Pay for duplicates before it is sent to the API.
+func WithDuplicateGuard(guard *DuplicateGuard) ClientOptionFunc {
+	return func(c *Client) error {
+		c.duplicateGuard = guard
+		return nil
+	}
+}
+
+// WithTagHeaders sends the tags set on the request context with WithTag as request headers,
+// named by prefixing the tag key with prefix, e.g. "X-Tag-". Tags are not sent unless this option is set.
+func WithTagHeaders(prefix string) ClientOptionFunc {
+	return func(c *Client) error {
+		c.tagHeaderPrefix = prefix
+		return nil
+	}
+}
+
+// WithDefaultHeaders sets headers which are sent with every request, including token requests,
+// e.g. for routing through an API gateway. Use WithHeader to set a header on a single request.
+func WithDefaultHeaders(headers map[string]string) ClientOptionFunc {
+	return func(c *Client) error {
+		if c.defaultHeaders == nil {
+			c.defaultHeaders = make(map[string]string, len(headers))
+		}
+		for key, value := range headers {
+			c.defaultHeaders[key] = value
+		}
+		return nil
+	}
+}
+
+// WithClock sets the clock used for token expiry checks and default timestamps such as
+// the execution date of payments. It defaults to the system clock.
+func WithClock(clock Clock) ClientOptionFunc {
+	return func(c *Client) error {
+		if clock == nil {
+			return errors.New("clock must not be nil")
+		}
+		c.clock = clock
+		return nil
+	}
+}
+
+// WithTokenExpirySkew sets how long before its expiry a token is treated as expired and renewed, to avoid
+// requests failing with 401 when the clock of the client is behind the one of the gateway. It defaults to 60s.
+//
+// The skew only applies to clients which can log in again. Clients without credentials, e.g. given a token
+// with WithTokenAndExpiry, use their token until it expires.
+func WithTokenExpirySkew(d time.Duration) ClientOptionFunc {
+	return func(c *Client) error {
+		if d < 0 {
+			return errors.New("token expiry skew must not be negative")
+		}
+		c.tokenExpirySkew = d
+		return nil
+	}
+}
+
+// WithBusinessCalendar sets the calendar used to schedule and validate future dated payments.
+// It defaults to calendar.Calendar, which knows the public holidays of the affiliate countries.
+func WithBusinessCalendar(calendar BusinessCalendar) ClientOptionFunc {
+	return func(c *Client) error {
+		c.calendar = calendar
+		return nil
+	}
+}
+
+// WithTimeFormats adds layouts to try when parsing the timestamps in responses, after the default
+// formats, e.g. for affiliates that return timestamps in a different format.
+// The formats only apply to the client, so clients with different formats can be used concurrently.
+func WithTimeFormats(layouts ...string) ClientOptionFunc {
+	return func(c *Client) error {
+		c.timeFormats = append(slices.Clip(c.timeFormats), layouts...)
+		return nil
+	}
+}
+
+// WithJSONCodec sets the functions encoding request bodies and decoding responses, e.g. the ones of
+// json-iterator or segmentio/encoding for performance, or wrappers adding custom decimal handling.
+// They must behave like json.Marshal and json.Unmarshal, in particular call the MarshalJSON and
+// UnmarshalJSON methods of the types and return their errors. Either may be nil to keep encoding/json.
+// Streamed responses read with DoStream are always decoded with encoding/json.
+func WithJSONCodec(marshal func(v any) ([]byte, error), unmarshal func(data []byte, v any) error) ClientOptionFunc {
+	return func(c *Client) error {
+		c.jsonMarshal = marshal
+		c.jsonUnmarshal = unmarshal
+		return nil
+	}
+}
+
+// WithPhoneFormat makes PaymentService.Pay normalize the phone numbers in the params of payments to format,
+// for the given affiliates or countries, or all of them if none are given. Payments with phone numbers that
+// cannot be normalized fail with ErrInvalidPhoneNumber before they are sent.
+func WithPhoneFormat(format PhoneFormat, affiliateCodes ...string) ClientOptionFunc {
+	return func(c *Client) error {
+		if c.phoneFormats == nil {
+			c.phoneFormats = make(map[string]PhoneFormat)
+		}
+		if len(affiliateCodes) == 0 {
+			c.phoneFormats[""] = format
+		}
+		for _, code := range affiliateCodes {
+			c.phoneFormats[calendar.Country(code)] = format
+		}
+		return nil
+	}
+}
@@ -0,0 +1,22 @@
+package ecobank
+
+import "time"
+
+// Clock tells the current time. It is used by the client for token expiry checks and
+// default timestamps, and can be replaced with WithClock for deterministic tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock used by default, which reads the system time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// now returns the current time according to the clock of the client.
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
@@ -0,0 +1,104 @@
+package ecobank
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time { return c.now }
+
+func TestWithClock(t *testing.T) {
+	expiresAt := time.Date(2025, 3, 16, 12, 0, 0, 0, time.UTC)
+	clock := &fixedClock{now: expiresAt.Add(-time.Minute)}
+
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithClock(clock)(client))
+	client.tokenExpiresAt = expiresAt
+
+	var logins int
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			resp := httptest.NewRecorder()
+			body := `{"response_code": 200, "response_content": "success"}`
+			if req.URL.Path == "/corporateapi/user/token" {
+				logins++
+				body = `{"username": "mock-client-id", "token": "new-token"}`
+			}
+			_, err := resp.WriteString(body)
+			return resp.Result(), err
+		},
+	}
+
+	opt := &PaymentOptions{}
+	_, _, err := client.Payment.Pay(t.Context(), opt)
+	require.NoError(t, err)
+	assert.Zero(t, logins, "token has not expired yet")
+	assert.Equal(t, clock.now, opt.PaymentHeader.ExecutionDate.GetTime())
+
+	clock.now = expiresAt.Add(time.Minute)
+	_, _, err = client.Payment.Pay(t.Context(), &PaymentOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, logins, "token has expired")
+
+	// the token has no expiry claim, so it expires after the default expiry of the clock's time
+	_, expiry := client.getToken()
+	assert.Equal(t, clock.now.Add(defaultTokenExpiry), expiry)
+}
+
+func TestWithTokenExpirySkew(t *testing.T) {
+	expiresAt := time.Date(2025, 3, 16, 12, 0, 0, 0, time.UTC)
+	clock := &fixedClock{now: expiresAt.Add(-30 * time.Second)}
+
+	var logins int
+	newClient := func(opts ...ClientOptionFunc) *Client {
+		client := newMockClient(t, "", http.StatusOK)
+		require.NoError(t, WithClock(clock)(client))
+		for _, opt := range opts {
+			require.NoError(t, opt(client))
+		}
+		client.tokenExpiresAt = expiresAt
+		client.client.HTTPClient.Transport = &mockHTTPClient{
+			requestHandler: func(req *http.Request) (*http.Response, error) {
+				resp := httptest.NewRecorder()
+				body := `{"response_code": 200, "response_content": "success"}`
+				if req.URL.Path == "/corporateapi/user/token" {
+					logins++
+					body = `{"username": "mock-client-id", "token": "new-token"}`
+				}
+				_, err := resp.WriteString(body)
+				return resp.Result(), err
+			},
+		}
+		return client
+	}
+
+	_, _, err := newClient().Payment.Pay(t.Context(), &PaymentOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, logins, "token expires within the default skew")
+
+	_, _, err = newClient(WithTokenExpirySkew(10*time.Second)).Payment.Pay(t.Context(), &PaymentOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, logins, "token does not expire within the skew")
+
+	client := newClient()
+	client.username, client.password = "", ""
+	_, _, err = client.Payment.Pay(t.Context(), &PaymentOptions{})
+	require.NoError(t, err, "clients which cannot log in again use their token until it expires")
+	assert.Equal(t, 1, logins)
+
+	clock.now = expiresAt.Add(time.Second)
+	_, _, err = client.Payment.Pay(t.Context(), &PaymentOptions{})
+	assert.ErrorContains(t, err, "token expired")
+
+	_, err = NewClient("", "", "", WithTokenExpirySkew(-time.Second))
+	assert.EqualError(t, err, "token expiry skew must not be negative")
+}
@@ -0,0 +1,66 @@
+package ecobank
+
+import "errors"
+
+// ErrClientClosed is returned by requests made with a client after Close, and by its background loops
+// such as AccountService.WatchBalance, CatalogSync.Run and Outbox.Run when Close stops them.
+var ErrClientClosed = errors.New("ecobank: client closed")
+
+// Close shuts the client down, so that services embedding it can stop cleanly.
+//
+// It stops the background loops of the client, waits for the payment hooks and hash audit hooks which are
+// running to return, and closes the idle connections of the HTTP client. Requests made after Close fail
+// with ErrClientClosed, while requests in flight complete; cancel their context to abort them.
+// Close is safe to call more than once.
+func (c *Client) Close() error {
+	c.closeMu.Lock()
+	if !c.isClosed {
+		c.isClosed = true
+		close(c.closedChan())
+	}
+	c.closeMu.Unlock()
+
+	c.hooks.Wait()
+
+	if c.client.HTTPClient != nil {
+		c.client.HTTPClient.CloseIdleConnections()
+	}
+	return nil
+}
+
+// closed returns a channel which is closed when the client is closed.
+func (c *Client) closed() <-chan struct{} {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+	return c.closedChan()
+}
+
+// closedChan returns the channel closed by Close, creating it if needed. c.closeMu must be held.
+func (c *Client) closedChan() chan struct{} {
+	if c.closedCh == nil {
+		c.closedCh = make(chan struct{})
+	}
+	return c.closedCh
+}
+
+// checkClosed returns ErrClientClosed if the client is closed.
+func (c *Client) checkClosed() error {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+	if c.isClosed {
+		return ErrClientClosed
+	}
+	return nil
+}
+
+// trackHook registers a hook call which Close waits for. The returned function must be called when the hook
+// returns. Hooks called after Close are not waited for.
+func (c *Client) trackHook() func() {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+	if c.isClosed {
+		return func() {}
+	}
+	c.hooks.Add(1)
+	return c.hooks.Done
+}
@@ -0,0 +1,103 @@
+package ecobank
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Close(t *testing.T) {
+	client := newMockClient(t, `{"response_code": 200, "response_content": {"availableBalance": 10}}`, http.StatusOK)
+
+	_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close())
+	require.NoError(t, client.Close(), "Close is idempotent")
+
+	_, _, err = client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	assert.ErrorIs(t, err, ErrClientClosed)
+
+	_, err = client.Payment.ForEachBiller(t.Context(), &GetBillerListOptions{}, func(*BillerInfo) error { return nil })
+	assert.ErrorIs(t, err, ErrClientClosed)
+}
+
+func TestClient_Close_StopsLoops(t *testing.T) {
+	client := newMockClient(t, `{"response_code": 200, "response_content": {"availableBalance": 10}}`, http.StatusOK)
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- client.Account.WatchBalance(t.Context(), &AccountBalanceOptions{}, time.Hour, decimal.NewFromInt(50),
+			func(*AccountBalance, error) {})
+	}()
+	go func() {
+		errs <- NewOutbox(client, NewMemoryOutboxStore()).Run(t.Context())
+	}()
+
+	require.NoError(t, client.Close())
+
+	for range 2 {
+		select {
+		case err := <-errs:
+			assert.ErrorIs(t, err, ErrClientClosed)
+		case <-time.After(5 * time.Second):
+			t.Fatal("loop did not stop after Close")
+		}
+	}
+}
+
+func TestClient_Close_DrainsHooks(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	client := newMockClient(t, `{"response_code": 200, "response_content": {"availableBalance": 10}}`, http.StatusOK)
+	require.NoError(t, WithHashAudit(func(context.Context, HashAuditRecord) {
+		close(started)
+		<-release
+	})(client))
+
+	go func() {
+		_, _, _ = client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	}()
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		_ = client.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("Close returned while a hook was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return after the hook returned")
+	}
+}
+
+func TestClient_ContextCancellation(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, _, err := client.Account.GetBalance(ctx, &AccountBalanceOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,258 @@
+// Command ecobank is a small command line client for the Ecobank API, useful for debugging
+// against the sandbox and for operational runbooks.
+//
+// Usage:
+//
+//	ecobank [flags] <command> [command flags]
+//
+// The commands are login, balance, enquiry, statement, pay and status. Credentials are read
+// from the ECOBANK_USERNAME, ECOBANK_PASSWORD and ECOBANK_LAB_KEY environment variables
+// unless set with flags. Results are printed as JSON.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/profclems/go-ecobank"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, client *ecobank.Client, args []string) (any, error)
+}
+
+var commands = []command{
+	{"login", "request an access token", runLogin},
+	{"balance", "get the balance of an account", runBalance},
+	{"enquiry", "get the details of an account", runEnquiry},
+	{"statement", "generate an account statement", runStatement},
+	{"pay", "submit a payment read as JSON from a file or stdin", runPay},
+	{"status", "get the status of a transaction", runStatus},
+}
+
+// username and password are the API credentials, set from the flags or the environment.
+var username, password string
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "ecobank:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("ecobank", flag.ContinueOnError)
+	fs.StringVar(&username, "username", os.Getenv("ECOBANK_USERNAME"), "API username (env ECOBANK_USERNAME)")
+	fs.StringVar(&password, "password", os.Getenv("ECOBANK_PASSWORD"), "API password (env ECOBANK_PASSWORD)")
+	labKey := fs.String("lab-key", os.Getenv("ECOBANK_LAB_KEY"), "lab key used to sign requests (env ECOBANK_LAB_KEY)")
+	baseURL := fs.String("base-url", os.Getenv("ECOBANK_BASE_URL"), "API base URL (env ECOBANK_BASE_URL)")
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintln(out, "Usage: ecobank [flags] <command> [command flags]")
+		fmt.Fprintln(out, "\nCommands:")
+		for _, cmd := range commands {
+			fmt.Fprintf(out, "  %-10s %s\n", cmd.name, cmd.usage)
+		}
+		fmt.Fprintln(out, "\nFlags:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no command given")
+	}
+
+	name := fs.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		var opts []ecobank.ClientOptionFunc
+		if *baseURL != "" {
+			opts = append(opts, ecobank.WithBaseURL(*baseURL))
+		}
+
+		client, err := ecobank.NewClient(username, password, *labKey, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to initiate client: %w", err)
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		result, err := cmd.run(ctx, client, fs.Args()[1:])
+		if err != nil {
+			return err
+		}
+
+		return printJSON(os.Stdout, result)
+	}
+
+	fs.Usage()
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func runLogin(ctx context.Context, client *ecobank.Client, args []string) (any, error) {
+	fs := flag.NewFlagSet("login", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	token, _, err := client.Auth.GetAccessToken(ctx, &ecobank.AccessTokenOptions{
+		UserID:   username,
+		Password: password,
+	})
+	return token, err
+}
+
+// accountFlags registers the flags shared by the account commands.
+type accountFlags struct {
+	requestID, affiliateCode, accountNo, clientID, companyName *string
+}
+
+func newAccountFlags(fs *flag.FlagSet) accountFlags {
+	return accountFlags{
+		requestID:     fs.String("request-id", newRequestID(), "unique request ID"),
+		affiliateCode: fs.String("affiliate", "EGH", "affiliate code"),
+		accountNo:     fs.String("account", "", "account number"),
+		clientID:      fs.String("client-id", "", "client ID"),
+		companyName:   fs.String("company", "", "company name"),
+	}
+}
+
+func runBalance(ctx context.Context, client *ecobank.Client, args []string) (any, error) {
+	fs := flag.NewFlagSet("balance", flag.ContinueOnError)
+	f := newAccountFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	balance, _, err := client.Account.GetBalance(ctx, &ecobank.AccountBalanceOptions{
+		RequestID:     *f.requestID,
+		AffiliateCode: *f.affiliateCode,
+		AccountNo:     *f.accountNo,
+		ClientID:      *f.clientID,
+		CompanyName:   *f.companyName,
+	})
+	return balance, err
+}
+
+func runEnquiry(ctx context.Context, client *ecobank.Client, args []string) (any, error) {
+	fs := flag.NewFlagSet("enquiry", flag.ContinueOnError)
+	f := newAccountFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	account, _, err := client.Account.Enquiry(ctx, &ecobank.AccountEnquiryOptions{
+		RequestID:     *f.requestID,
+		AffiliateCode: *f.affiliateCode,
+		AccountNo:     *f.accountNo,
+		ClientID:      *f.clientID,
+		CompanyName:   *f.companyName,
+	})
+	return account, err
+}
+
+func runStatement(ctx context.Context, client *ecobank.Client, args []string) (any, error) {
+	fs := flag.NewFlagSet("statement", flag.ContinueOnError)
+	f := newAccountFlags(fs)
+	corporateID := fs.String("corporate-id", "", "corporate ID")
+	start := fs.String("start", time.Now().AddDate(0, -1, 0).Format(time.DateOnly), "start date (YYYY-MM-DD)")
+	end := fs.String("end", time.Now().Format(time.DateOnly), "end date (YYYY-MM-DD)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	startDate, err := time.Parse(time.DateOnly, *start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+	endDate, err := time.Parse(time.DateOnly, *end)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+
+	txns, _, err := client.Account.GenerateStatement(ctx, &ecobank.GenerateStatementOptions{
+		CorporateID:   *corporateID,
+		RequestID:     *f.requestID,
+		ClientID:      *f.clientID,
+		AffiliateCode: *f.affiliateCode,
+		AccountNumber: *f.accountNo,
+		StartDate:     ecobank.NewDate(startDate),
+		EndDate:       ecobank.NewDate(endDate),
+	})
+	return txns, err
+}
+
+func runPay(ctx context.Context, client *ecobank.Client, args []string) (any, error) {
+	fs := flag.NewFlagSet("pay", flag.ContinueOnError)
+	file := fs.String("file", "-", "file containing the payment as JSON, - for stdin")
+	wait := fs.Bool("wait", false, "wait for the final status of the transactions")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var opt ecobank.PaymentOptions
+	if err := json.NewDecoder(r).Decode(&opt); err != nil {
+		return nil, fmt.Errorf("failed to read payment: %w", err)
+	}
+	opt.WaitForStatus = *wait
+
+	ack, _, err := client.Payment.Pay(ctx, &opt)
+	return ack, err
+}
+
+func runStatus(ctx context.Context, client *ecobank.Client, args []string) (any, error) {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	clientID := fs.String("client-id", "", "client ID")
+	requestID := fs.String("request-id", "", "request ID of the transaction")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *requestID == "" {
+		return nil, errors.New("-request-id is required")
+	}
+
+	status, _, err := client.Status.GetTransactionStatus(ctx, &ecobank.StatusOptions{
+		ClientID:  *clientID,
+		RequestID: *requestID,
+	})
+	return status, err
+}
+
+func newRequestID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
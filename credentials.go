@@ -0,0 +1,63 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Credentials are the credentials the client logs in with.
+type Credentials struct {
+	Username string
+	Password string `redact:"secret"`
+}
+
+// CredentialProvider provides the credentials of the client, e.g. from a secret manager.
+//
+// The packages under credentials implement it for HashiCorp Vault and AWS Secrets Manager.
+type CredentialProvider interface {
+	// Credentials returns the current credentials. It is called for every login.
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialProviderFunc is a function implementing CredentialProvider.
+type CredentialProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials implements CredentialProvider.
+func (f CredentialProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// WithCredentialProvider makes the client log in with the credentials of provider instead of the username
+// and password given to NewClient. The provider is consulted lazily, at every login, so that rotated secrets
+// are picked up without restarting, and they are never stored by the client.
+//
+// The lab key is still the one given to NewClient, since the secure hash of requests is generated before
+// the client logs in.
+func WithCredentialProvider(provider CredentialProvider) ClientOptionFunc {
+	return func(c *Client) error {
+		if provider == nil {
+			return errors.New("credential provider must not be nil")
+		}
+		c.credentialProvider = provider
+		return nil
+	}
+}
+
+// credentials returns the credentials to log in with, from the credential provider if the client has one.
+func (c *Client) credentials(ctx context.Context) (Credentials, error) {
+	if c.credentialProvider == nil {
+		return Credentials{Username: c.username, Password: c.password}, nil
+	}
+
+	creds, err := c.credentialProvider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to get credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// hasCredentials reports whether the client can log in.
+func (c *Client) hasCredentials() bool {
+	return c.credentialProvider != nil || c.username != "" || c.password != ""
+}
@@ -0,0 +1,71 @@
+// Package awssecrets provides the credentials of an Ecobank client from a secret of AWS Secrets Manager,
+// read at every login. It is a separate module, so that the AWS SDK is only a dependency of the applications
+// using it.
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	provider := &awssecrets.Provider{
+//		Client:   secretsmanager.NewFromConfig(cfg),
+//		SecretID: "payments/ecobank",
+//	}
+//	client, err := ecobank.NewClient("", "", labKey, ecobank.WithCredentialProvider(provider))
+package awssecrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"github.com/profclems/go-ecobank"
+	"github.com/profclems/go-ecobank/credentials/internal/secret"
+)
+
+// GetSecretValueAPI is the method of the Secrets Manager client used by Provider.
+type GetSecretValueAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// Provider is an ecobank.CredentialProvider reading the credentials from a Secrets Manager secret,
+// whose string value is a JSON object such as {"username": "...", "password": "..."}.
+type Provider struct {
+	// Client is the Secrets Manager client, usually a *secretsmanager.Client.
+	Client GetSecretValueAPI
+	// SecretID is the name or ARN of the secret.
+	SecretID string
+	// VersionStage is the staging label of the version read. It defaults to AWSCURRENT.
+	VersionStage string
+	// UsernameKey and PasswordKey are the keys of the credentials in the secret.
+	// They default to "username" and "password".
+	UsernameKey string
+	PasswordKey string
+}
+
+var _ ecobank.CredentialProvider = (*Provider)(nil)
+
+// Credentials implements ecobank.CredentialProvider.
+func (p *Provider) Credentials(ctx context.Context) (ecobank.Credentials, error) {
+	input := &secretsmanager.GetSecretValueInput{SecretId: &p.SecretID}
+	if p.VersionStage != "" {
+		input.VersionStage = &p.VersionStage
+	}
+
+	out, err := p.Client.GetSecretValue(ctx, input)
+	if err != nil {
+		return ecobank.Credentials{}, fmt.Errorf("reading secret %s: %w", p.SecretID, err)
+	}
+	if out.SecretString == nil {
+		return ecobank.Credentials{}, fmt.Errorf("reading secret %s: secret has no string value", p.SecretID)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(*out.SecretString), &data); err != nil {
+		return ecobank.Credentials{}, fmt.Errorf("decoding secret %s: %w", p.SecretID, err)
+	}
+
+	creds, err := secret.Credentials(data, p.UsernameKey, p.PasswordKey)
+	if err != nil {
+		return ecobank.Credentials{}, fmt.Errorf("reading secret %s: %w", p.SecretID, err)
+	}
+	return creds, nil
+}
@@ -0,0 +1,53 @@
+package awssecrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/profclems/go-ecobank"
+)
+
+type mockSecrets map[string]string
+
+func (m mockSecrets) GetSecretValue(_ context.Context, params *secretsmanager.GetSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	id := aws.ToString(params.SecretId)
+	if stage := aws.ToString(params.VersionStage); stage != "" {
+		id += ":" + stage
+	}
+	value, ok := m[id]
+	if !ok {
+		return nil, errors.New("secret not found")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(value)}, nil
+}
+
+func TestProvider_Credentials(t *testing.T) {
+	secrets := mockSecrets{
+		"payments/ecobank":             `{"username": "user", "password": "pass"}`,
+		"payments/ecobank:AWSPREVIOUS": `{"user": "old-user", "pass": "old-pass"}`,
+	}
+
+	provider := &Provider{Client: secrets, SecretID: "payments/ecobank"}
+	creds, err := provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, ecobank.Credentials{Username: "user", Password: "pass"}, creds)
+
+	provider = &Provider{Client: secrets, SecretID: "payments/ecobank", VersionStage: "AWSPREVIOUS", UsernameKey: "user", PasswordKey: "pass"}
+	creds, err = provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, ecobank.Credentials{Username: "old-user", Password: "old-pass"}, creds)
+
+	provider = &Provider{Client: secrets, SecretID: "payments/ecobank", PasswordKey: "secret"}
+	_, err = provider.Credentials(t.Context())
+	assert.EqualError(t, err, `reading secret payments/ecobank: no key "secret" in secret`)
+
+	provider = &Provider{Client: secrets, SecretID: "payments/unknown"}
+	_, err = provider.Credentials(t.Context())
+	assert.EqualError(t, err, "reading secret payments/unknown: secret not found")
+}
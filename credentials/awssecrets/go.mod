@@ -0,0 +1,25 @@
+module github.com/profclems/go-ecobank/credentials/awssecrets
+
+go 1.24.0
+
+// To build against a local checkout of the library, create a workspace at the root of the repository:
+// go work init . ./credentials/awssecrets
+require (
+	github.com/aws/aws-sdk-go-v2 v1.36.3
+	github.com/aws/aws-sdk-go-v2/service/secretsmanager v1.35.4
+	github.com/profclems/go-ecobank v0.0.0-20261016081458-3d2085aa31d1
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/aws/aws-sdk-go-v2/internal/configsources v1.3.34 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.6.34 // indirect
+	github.com/aws/smithy-go v1.22.2 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
+	github.com/hashicorp/go-retryablehttp v0.7.7 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/shopspring/decimal v1.4.0 // indirect
+	golang.org/x/oauth2 v0.34.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
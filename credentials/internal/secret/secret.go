@@ -0,0 +1,32 @@
+// Package secret reads the credentials stored in a secret, for the credential providers under credentials.
+package secret
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/profclems/go-ecobank"
+)
+
+// Credentials returns the credentials stored under the keys of the secret data.
+// The keys default to "username" and "password".
+func Credentials(data map[string]any, usernameKey, passwordKey string) (ecobank.Credentials, error) {
+	username, err := String(data, cmp.Or(usernameKey, "username"))
+	if err != nil {
+		return ecobank.Credentials{}, err
+	}
+	password, err := String(data, cmp.Or(passwordKey, "password"))
+	if err != nil {
+		return ecobank.Credentials{}, err
+	}
+	return ecobank.Credentials{Username: username, Password: password}, nil
+}
+
+// String returns the non-empty string stored under key in the secret data.
+func String(data map[string]any, key string) (string, error) {
+	v, ok := data[key].(string)
+	if !ok || v == "" {
+		return "", fmt.Errorf("no key %q in secret", key)
+	}
+	return v, nil
+}
@@ -0,0 +1,89 @@
+// Package vault provides the credentials of an Ecobank client from a secret of the KV version 2 secrets
+// engine of HashiCorp Vault, read over its HTTP API at every login:
+//
+//	provider := &vault.Provider{
+//		Address: "https://vault.example.com:8200",
+//		Token:   vaultToken,
+//		Path:    "payments/ecobank",
+//	}
+//	client, err := ecobank.NewClient("", "", labKey, ecobank.WithCredentialProvider(provider))
+package vault
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/profclems/go-ecobank"
+	"github.com/profclems/go-ecobank/credentials/internal/secret"
+)
+
+// Provider is an ecobank.CredentialProvider reading the credentials from a Vault KV version 2 secret.
+type Provider struct {
+	// Address is the address of the Vault server, e.g. https://vault.example.com:8200.
+	Address string
+	// Token is the Vault token the secret is read with.
+	Token string
+	// Mount is the path the KV secrets engine is mounted at. It defaults to "secret".
+	Mount string
+	// Path is the path of the secret in the secrets engine.
+	Path string
+	// UsernameKey and PasswordKey are the keys of the credentials in the secret.
+	// They default to "username" and "password".
+	UsernameKey string
+	PasswordKey string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ ecobank.CredentialProvider = (*Provider)(nil)
+
+// Credentials implements ecobank.CredentialProvider, reading the latest version of the secret.
+func (p *Provider) Credentials(ctx context.Context) (ecobank.Credentials, error) {
+	mount := cmp.Or(p.Mount, "secret")
+	u, err := url.JoinPath(p.Address, "v1", mount, "data", p.Path)
+	if err != nil {
+		return ecobank.Credentials{}, fmt.Errorf("invalid Vault address: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return ecobank.Credentials{}, err
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return ecobank.Credentials{}, fmt.Errorf("reading Vault secret %s: %w", p.Path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return ecobank.Credentials{}, fmt.Errorf("reading Vault secret %s: %s: %s", p.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var data struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return ecobank.Credentials{}, fmt.Errorf("decoding Vault secret %s: %w", p.Path, err)
+	}
+
+	creds, err := secret.Credentials(data.Data.Data, p.UsernameKey, p.PasswordKey)
+	if err != nil {
+		return ecobank.Credentials{}, fmt.Errorf("reading Vault secret %s: %w", p.Path, err)
+	}
+	return creds, nil
+}
@@ -0,0 +1,48 @@
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/profclems/go-ecobank"
+)
+
+func TestProvider_Credentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/payments/ecobank":
+			_, _ = w.Write([]byte(`{"data": {"data": {"username": "user", "password": "pass"}, "metadata": {"version": 3}}}`))
+		case "/v1/kv/data/payments/ecobank":
+			_, _ = w.Write([]byte(`{"data": {"data": {"user": "kv-user", "pass": "kv-pass"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	provider := &Provider{Address: srv.URL, Token: "vault-token", Path: "payments/ecobank"}
+	creds, err := provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, ecobank.Credentials{Username: "user", Password: "pass"}, creds)
+
+	provider = &Provider{Address: srv.URL, Token: "vault-token", Mount: "kv", Path: "payments/ecobank", UsernameKey: "user", PasswordKey: "pass"}
+	creds, err = provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, ecobank.Credentials{Username: "kv-user", Password: "kv-pass"}, creds)
+
+	provider = &Provider{Address: srv.URL, Token: "vault-token", Path: "payments/ecobank", PasswordKey: "secret"}
+	_, err = provider.Credentials(t.Context())
+	assert.EqualError(t, err, "reading Vault secret payments/ecobank: no key \"secret\" in secret")
+
+	provider = &Provider{Address: srv.URL, Token: "revoked", Path: "payments/ecobank"}
+	_, err = provider.Credentials(t.Context())
+	assert.ErrorContains(t, err, "403 Forbidden")
+}
@@ -0,0 +1,74 @@
+package ecobank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCredentialProvider(t *testing.T) {
+	var calls int
+	password := "first-pass"
+	provider := CredentialProviderFunc(func(ctx context.Context) (Credentials, error) {
+		calls++
+		return Credentials{Username: "vault-user", Password: password}, nil
+	})
+
+	client, err := NewClient("", "", "mock-lab-key", WithCredentialProvider(provider))
+	require.NoError(t, err)
+	assert.Zero(t, calls, "credentials are read lazily")
+
+	var logins []AccessTokenOptions
+	client.client.HTTPClient = &http.Client{Transport: &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			resp := httptest.NewRecorder()
+			body := `{"response_code": 200, "response_content": "success"}`
+			if req.URL.Path == "/corporateapi/user/token" {
+				var opt AccessTokenOptions
+				if err := json.NewDecoder(req.Body).Decode(&opt); err != nil {
+					return nil, err
+				}
+				logins = append(logins, opt)
+				body = `{"username": "vault-user", "token": "new-token"}`
+			}
+			_, err := resp.WriteString(body)
+			return resp.Result(), err
+		},
+	}}
+
+	require.NoError(t, client.Login(t.Context()))
+	password = "rotated-pass"
+	require.NoError(t, client.Login(t.Context()))
+
+	assert.Equal(t, 2, calls)
+	require.Len(t, logins, 2)
+	assert.Equal(t, "vault-user", logins[0].UserID)
+	assert.Equal(t, "first-pass", logins[0].Password)
+	assert.Equal(t, "rotated-pass", logins[1].Password, "rotated secrets are picked up at the next login")
+
+	// the client logs in with the provider when it has no token
+	client.setToken("", client.now())
+	_, _, err = client.Payment.Pay(t.Context(), &PaymentOptions{})
+	require.NoError(t, err)
+	assert.Len(t, logins, 3)
+}
+
+func TestWithCredentialProvider_Error(t *testing.T) {
+	_, err := NewClient("", "", "mock-lab-key", WithCredentialProvider(nil))
+	assert.EqualError(t, err, "credential provider must not be nil")
+
+	provider := CredentialProviderFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{}, errors.New("vault sealed")
+	})
+	client, err := NewClient("", "", "mock-lab-key", WithCredentialProvider(provider))
+	require.NoError(t, err)
+
+	err = client.Login(t.Context())
+	assert.EqualError(t, err, "failed to get credentials: vault sealed")
+}
@@ -0,0 +1,195 @@
+package ecobank
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDuplicatePayment is returned by PaymentService.Pay when the DuplicateGuard rejects a payment
+// that matches one submitted within the guard's window.
+var ErrDuplicatePayment = errors.New("duplicate payment")
+
+const defaultDuplicateWindow = 24 * time.Hour
+
+// DuplicateStore records payment fingerprints for a DuplicateGuard.
+// Implementations must be safe for concurrent use. Use a shared store such as Redis or a database
+// to detect duplicates across processes.
+type DuplicateStore interface {
+	// Add records key until ttl elapses. It reports false if key is already recorded and has not expired.
+	Add(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Remove forgets key.
+	Remove(ctx context.Context, key string) error
+}
+
+// DuplicateGuard detects payments that are submitted more than once within a time window.
+//
+// Each payment extension is fingerprinted from its request type, amount, currency and params,
+// which include the beneficiary account and references. Set the guard on a client with WithDuplicateGuard.
+type DuplicateGuard struct {
+	// Store records the fingerprints of submitted payments.
+	Store DuplicateStore
+	// Window is how long a payment is remembered. It defaults to 24 hours.
+	Window time.Duration
+	// OnDuplicate, if set, is called for every duplicate extension and the payment is submitted anyway.
+	// Otherwise, duplicates are rejected with ErrDuplicatePayment.
+	OnDuplicate func(ext *PaymentExtension)
+}
+
+// NewDuplicateGuard returns a DuplicateGuard which remembers payments in memory for the given window
+// and rejects duplicates.
+func NewDuplicateGuard(window time.Duration) *DuplicateGuard {
+	return &DuplicateGuard{
+		Store:  NewMemoryDuplicateStore(),
+		Window: window,
+	}
+}
+
+// check records the fingerprints of all the extensions of the payment. It returns the recorded keys,
+// so they can be released if the submission fails, or an ErrDuplicatePayment error.
+func (g *DuplicateGuard) check(ctx context.Context, opt *PaymentOptions) ([]string, error) {
+	window := g.Window
+	if window <= 0 {
+		window = defaultDuplicateWindow
+	}
+
+	var keys []string
+	for i := range opt.Extension {
+		ext := &opt.Extension[i]
+
+		key, err := paymentFingerprint(ext)
+		if err != nil {
+			return keys, err
+		}
+
+		added, err := g.Store.Add(ctx, key, window)
+		if err != nil {
+			return keys, err
+		}
+
+		if added {
+			keys = append(keys, key)
+			continue
+		}
+
+		if g.OnDuplicate == nil {
+			return keys, fmt.Errorf("%w: extension %s matches a payment submitted within the last %s", ErrDuplicatePayment, ext.RequestID, window)
+		}
+		g.OnDuplicate(ext)
+	}
+
+	return keys, nil
+}
+
+// release forgets the given keys so a failed payment can be retried.
+func (g *DuplicateGuard) release(keys []string) error {
+	var errs []error
+	for _, key := range keys {
+		// use a fresh context since the request context may already be done
+		errs = append(errs, g.Store.Remove(context.Background(), key))
+	}
+	return errors.Join(errs...)
+}
+
+// forget forgets the extensions of the payment, so that a payment the API does not know can be submitted again.
+func (g *DuplicateGuard) forget(opt *PaymentOptions) error {
+	keys := make([]string, 0, len(opt.Extension))
+	for i := range opt.Extension {
+		key, err := paymentFingerprint(&opt.Extension[i])
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	return g.release(keys)
+}
+
+// paymentFingerprint returns a key identifying the payment made by the extension.
+// The RequestID is left out since retried payments usually get a new one.
+func paymentFingerprint(ext *PaymentExtension) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(ext.RequestType))
+	h.Write([]byte{0})
+	h.Write([]byte(ext.Currency))
+	h.Write([]byte{0})
+	h.Write([]byte(ext.Amount.String()))
+	h.Write([]byte{0})
+
+	if ext.ParamList != nil {
+		params, err := ext.ParamList.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+		h.Write(params)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// MemoryDuplicateStore is an in-memory DuplicateStore. It only detects duplicates within a single process.
+type MemoryDuplicateStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	sweeps  sweepSchedule
+}
+
+// NewMemoryDuplicateStore returns a new, empty MemoryDuplicateStore.
+func NewMemoryDuplicateStore() *MemoryDuplicateStore {
+	return &MemoryDuplicateStore{entries: make(map[string]time.Time)}
+}
+
+// Add implements DuplicateStore.
+func (s *MemoryDuplicateStore) Add(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := s.entries[key]; ok && !now.After(expiresAt) {
+		return false, nil
+	}
+
+	// drop expired entries so the store does not grow unbounded
+	if s.sweeps.due(len(s.entries)) {
+		for k, expiresAt := range s.entries {
+			if now.After(expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.sweeps.swept(len(s.entries))
+	}
+
+	s.entries[key] = now.Add(ttl)
+	return true, nil
+}
+
+// Remove implements DuplicateStore.
+func (s *MemoryDuplicateStore) Remove(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// minSweepEntries is the number of entries below which in-memory stores do not drop their expired entries.
+const minSweepEntries = 64
+
+// sweepSchedule schedules the sweeps of the expired entries of an in-memory store. A sweep is due once
+// the store has doubled in size since the last one, so that sweeping takes amortized constant time.
+type sweepSchedule struct {
+	at int
+}
+
+// due reports whether a store of n entries should be swept.
+func (s *sweepSchedule) due(n int) bool {
+	return n >= max(s.at, minSweepEntries)
+}
+
+// swept records that a store was swept down to n entries.
+func (s *sweepSchedule) swept(n int) {
+	s.at = 2 * n
+}
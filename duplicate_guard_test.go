@@ -0,0 +1,123 @@
+package ecobank
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDuplicatePayment(requestID string) *PaymentOptions {
+	return &PaymentOptions{
+		PaymentHeader: PaymentHeader{BatchID: "EG1593490"},
+		Extension: []PaymentExtension{
+			{
+				RequestID:   requestID,
+				RequestType: INTERBANK,
+				ParamList: NewPaymentParams(InterbankTransferParams{
+					BeneficiaryAccountNo: "110424812001",
+					TransferReferenceNo:  "QWE345Y4",
+					Amount:               decimal.NewFromInt(10),
+					Currency:             "GHS",
+				}),
+				Amount:   decimal.NewFromInt(10),
+				Currency: "GHS",
+			},
+		},
+	}
+}
+
+func TestDuplicateGuard_Reject(t *testing.T) {
+	client := newMockClient(t, `{"response_code": 200, "response_content": "success"}`, http.StatusOK)
+	require.NoError(t, WithDuplicateGuard(NewDuplicateGuard(time.Hour))(client))
+
+	_, _, err := client.Payment.Pay(t.Context(), newDuplicatePayment("1"))
+	require.NoError(t, err)
+
+	// same payment with a new request ID is still a duplicate
+	_, _, err = client.Payment.Pay(t.Context(), newDuplicatePayment("2"))
+	assert.ErrorIs(t, err, ErrDuplicatePayment)
+	assert.ErrorContains(t, err, "extension 2")
+
+	// a different amount is not a duplicate
+	opt := newDuplicatePayment("3")
+	opt.Extension[0].Amount = decimal.NewFromInt(20)
+	_, _, err = client.Payment.Pay(t.Context(), opt)
+	assert.NoError(t, err)
+}
+
+func TestDuplicateGuard_Warn(t *testing.T) {
+	client := newMockClient(t, `{"response_code": 200, "response_content": "success"}`, http.StatusOK)
+
+	var duplicates []string
+	guard := NewDuplicateGuard(time.Hour)
+	guard.OnDuplicate = func(ext *PaymentExtension) {
+		duplicates = append(duplicates, ext.RequestID)
+	}
+	require.NoError(t, WithDuplicateGuard(guard)(client))
+
+	for _, id := range []string{"1", "2", "3"} {
+		_, _, err := client.Payment.Pay(t.Context(), newDuplicatePayment(id))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"2", "3"}, duplicates)
+}
+
+func TestDuplicateGuard_ReleaseOnRejection(t *testing.T) {
+	client := newMockClient(t, `{"response_code": 400, "errors": ["invalid secure hash"]}`, http.StatusBadRequest)
+	require.NoError(t, WithDuplicateGuard(NewDuplicateGuard(time.Hour))(client))
+
+	_, _, err := client.Payment.Pay(t.Context(), newDuplicatePayment("1"))
+	require.ErrorContains(t, err, "invalid secure hash")
+
+	// the rejected payment can be resubmitted
+	_, _, err = client.Payment.Pay(t.Context(), newDuplicatePayment("2"))
+	assert.NotErrorIs(t, err, ErrDuplicatePayment)
+}
+
+func TestMemoryDuplicateStore(t *testing.T) {
+	store := NewMemoryDuplicateStore()
+
+	added, err := store.Add(t.Context(), "key", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = store.Add(t.Context(), "key", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	require.NoError(t, store.Remove(t.Context(), "key"))
+	added, err = store.Add(t.Context(), "key", -time.Second)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// expired entries are forgotten
+	added, err = store.Add(t.Context(), "key", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, added)
+}
+
+func TestMemoryDuplicateStore_Sweep(t *testing.T) {
+	store := NewMemoryDuplicateStore()
+
+	for i := range minSweepEntries {
+		_, err := store.Add(t.Context(), fmt.Sprint("expired", i), -time.Second)
+		require.NoError(t, err)
+	}
+	assert.Len(t, store.entries, minSweepEntries, "expired entries are kept until the store is swept")
+
+	_, err := store.Add(t.Context(), "key", time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, store.entries, 1)
+
+	for i := range minSweepEntries - 2 {
+		_, err := store.Add(t.Context(), fmt.Sprint("live", i), time.Hour)
+		require.NoError(t, err)
+	}
+	assert.Len(t, store.entries, minSweepEntries-1, "the store is not swept before it reaches the minimum size")
+}
@@ -12,11 +12,15 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/shopspring/decimal"
+	"golang.org/x/oauth2"
 )
 
 const (
@@ -27,6 +31,9 @@ const (
 
 	// by default, token expires in 2 hours
 	defaultTokenExpiry = 7200 * time.Second
+
+	// tokens are refreshed a minute before they expire, to allow for clock skew with the gateway
+	defaultTokenExpirySkew = 60 * time.Second
 )
 
 // Client manages communication with the Ecobank API.
@@ -43,12 +50,110 @@ type Client struct {
 	token          string
 	tokenExpiresAt time.Time
 
+	// tokenExpirySkew is how long before its expiry a token is treated as expired.
+	tokenExpirySkew time.Duration
+
 	// Credentials for requesting a token.
 	username, password, labKey string
 
+	// credentialProvider, if set, provides the credentials instead of username and password.
+	credentialProvider CredentialProvider
+
+	// tokenSource, if set, provides the tokens instead of the credentials.
+	tokenSource oauth2.TokenSource
+
 	// UserAgent is set in the User-Agent header of all requests.
 	UserAgent string
 
+	// defaultHeaders are set on every request, including token requests.
+	defaultHeaders map[string]string
+
+	// tagHeaderPrefix, if set, sends the context tags as request headers with this prefix.
+	tagHeaderPrefix string
+
+	// clock tells the time for token expiry checks and default timestamps.
+	clock Clock
+
+	// timeFormats are additional layouts for parsing the timestamps of responses.
+	timeFormats []string
+
+	// jsonMarshal and jsonUnmarshal, if set, replace encoding/json for request bodies and responses.
+	jsonMarshal   func(v any) ([]byte, error)
+	jsonUnmarshal func(data []byte, v any) error
+
+	// phoneFormats are the formats phone numbers in payment params are normalized to, by country.
+	// The format for all other countries is stored under "".
+	phoneFormats map[string]PhoneFormat
+
+	// calendar tells the business days of affiliates for scheduling payments.
+	calendar BusinessCalendar
+
+	// duplicateGuard, if set, rejects duplicate payments before submission.
+	duplicateGuard *DuplicateGuard
+
+	// billerDetailsCache, if set, caches the responses of PaymentService.GetBillerDetails.
+	billerDetailsCache *billerDetailsCache
+
+	// endpointOverrides are the paths of the API operations overridden with WithEndpointOverride.
+	endpointOverrides map[endpoint]string
+
+	// apiVersion is the version of the API operations set with WithAPIVersion, if any.
+	apiVersion string
+
+	// endpointVersions are the versions of the operations set with WithEndpointVersion.
+	endpointVersions map[endpoint]string
+
+	// deprecationFunc, if set, is called with the deprecated operations used, which are recorded in
+	// deprecationsWarned so they are only reported once. deprecatedVersions holds the first Deprecation
+	// announced by the API for each pinned version.
+	deprecationFunc    DeprecationFunc
+	deprecationsWarned sync.Map
+	deprecatedVersions sync.Map
+
+	// hashFields are the fields the secure hash of API operations is generated from, set with WithSecureHashFields.
+	hashFields map[endpoint][]string
+
+	// retryClassifier, if set, decides which API errors are retried, up to retryClassifierMax times.
+	retryClassifier    RetryClassifier
+	retryClassifierMax int
+
+	// hashAudit, if set, is called with every generated secure hash.
+	hashAudit HashAuditFunc
+
+	// hashVerification is how strictly the secure hash of responses is verified.
+	hashVerification HashVerification
+
+	// strictDecoding rejects responses with fields which are not decoded, and unknownFields, if set,
+	// is called with them.
+	strictDecoding bool
+	unknownFields  UnknownFieldsFunc
+
+	// fieldEncrypter, if set, encrypts the sensitive params of payments.
+	fieldEncrypter FieldEncrypter
+
+	// paymentHooks are called at every state transition of a payment.
+	paymentHooks PaymentHooks
+
+	// stats, if set, collects the success rate and latency of the API operations.
+	stats *statsCollector
+
+	// maxResponseBytes, if positive, limits the size of the response bodies read.
+	maxResponseBytes int64
+
+	// amountFormat, if set, is the format of the amounts sent to the API. It defaults to the
+	// format set with SetAmountFormat.
+	amountFormat *AmountFormat
+
+	// recorder, if set, records or replays the interactions with the API.
+	recorder *recorder
+
+	// closedCh is closed by Close to stop the background loops of the client.
+	closeMu  sync.Mutex
+	isClosed bool
+	closedCh chan struct{}
+	// hooks tracks the hooks in flight, which Close waits for.
+	hooks sync.WaitGroup
+
 	Auth       *AuthService
 	Account    *AccountService
 	Payment    *PaymentService
@@ -64,6 +169,16 @@ func (c *Client) getToken() (string, time.Time) {
 	return c.token, c.tokenExpiresAt
 }
 
+// TokenClaims returns the claims of the current access token.
+// It returns an error if the client has no token yet.
+func (c *Client) TokenClaims() (*TokenClaims, error) {
+	token, _ := c.getToken()
+	if token == "" {
+		return nil, errors.New("no access token")
+	}
+	return ParseToken(token)
+}
+
 // setToken sets the token and expiry time.
 // It is safe for concurrent access since it obtains a lock before writing.
 func (c *Client) setToken(token string, expiresAt time.Time) {
@@ -79,6 +194,9 @@ func NewClient(username, password, labKey string, opts ...ClientOptionFunc) (*Cl
 		password:  password,
 		labKey:    labKey,
 		UserAgent: userAgent,
+		clock:     systemClock{},
+
+		tokenExpirySkew: defaultTokenExpirySkew,
 	}
 
 	c.client = retryablehttp.NewClient()
@@ -96,6 +214,8 @@ func NewClient(username, password, labKey string, opts ...ClientOptionFunc) (*Cl
 	c.Auth = &AuthService{client: c}
 	c.Account = &AccountService{client: c}
 	c.Payment = &PaymentService{client: c}
+	c.Remittance = &RemittanceService{client: c}
+	c.Status = &StatusService{client: c}
 
 	for _, opt := range opts {
 		if err := opt(c); err != nil {
@@ -103,6 +223,11 @@ func NewClient(username, password, labKey string, opts ...ClientOptionFunc) (*Cl
 		}
 	}
 
+	if err := c.installRecorder(); err != nil {
+		return nil, err
+	}
+	c.installLogHooks()
+
 	return c, nil
 }
 
@@ -118,6 +243,7 @@ func NewClient(username, password, labKey string, opts ...ClientOptionFunc) (*Cl
 // - `method` (string): The HTTP method (e.g., "GET", "POST", "PUT", "DELETE").
 // - `path` (string): The endpoint path for the API request.
 // - `opt` (any): The request body or query parameters (can be nil).
+// - `options` (...RequestOptionFunc): Options applied to the request, such as extra headers.
 //
 // Returns:
 // - `*T`: A pointer to the parsed response body, unmarshaled into the expected type `T`.
@@ -149,8 +275,8 @@ func NewClient(username, password, labKey string, opts ...ClientOptionFunc) (*Cl
 //
 // fmt.Printf("User: %+v, Status: %d\n", user, resp.StatusCode)
 // ```
-func DoRequest[T any](ctx context.Context, client *Client, method, path string, opt any) (*T, *Response, error) {
-	req, err := client.NewRequest(ctx, method, path, opt)
+func DoRequest[T any](ctx context.Context, client *Client, method, path string, opt any, options ...RequestOptionFunc) (*T, *Response, error) {
+	req, err := client.NewRequest(ctx, method, path, opt, options...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -167,29 +293,41 @@ func DoRequest[T any](ctx context.Context, client *Client, method, path string,
 
 // Login authenticates the client and stores the access token in the client.
 func (c *Client) Login(ctx context.Context) error {
+	_, err := c.login(ctx)
+	return err
+}
+
+// login requests an access token with the credentials of the client and sets it, returning the response
+// of the token request.
+func (c *Client) login(ctx context.Context) (*Response, error) {
+	creds, err := c.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	req := &AccessTokenOptions{
-		UserID:   c.username,
-		Password: c.password,
+		UserID:   creds.Username,
+		Password: creds.Password,
 	}
 
 	token, resp, err := c.Auth.GetAccessToken(ctx, req)
 	if err != nil {
-		return err
+		return resp, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New(resp.Status)
+		return resp, errors.New(resp.Status)
 	}
 
-	expiry, err := getTokenExpiry(c.token)
-	if err != nil {
-		// set a default expiry time
-		expiry = time.Now().Add(defaultTokenExpiry)
+	// set a default expiry time if the token does not have one
+	expiry := c.now().Add(defaultTokenExpiry)
+	if claims, err := ParseToken(token.Token); err == nil && !claims.ExpiresAt.IsZero() {
+		expiry = claims.ExpiresAt
 	}
 
 	c.setToken(token.Token, expiry)
 
-	return nil
+	return resp, nil
 }
 
 // setBaseURL sets the base URL for API requests to a custom endpoint.
@@ -217,7 +355,14 @@ func (c *Client) BaseURL() *url.URL {
 }
 
 // NewRequest creates an API request.
-func (c *Client) NewRequest(ctx context.Context, method, path string, opts any) (*retryablehttp.Request, error) {
+//
+// For GET requests, opts are encoded in the URL query using the `url` struct tags of its fields,
+// falling back to the `json` tags. For all other methods, opts are sent as the JSON request body,
+// or as a multipart/form-data body if they have FormFile fields. Bodies are buffered, so that retries resend
+// them byte for byte, with the secure hash generated once for the request.
+//
+// Request options are applied after the default headers of the client, so they can override them.
+func (c *Client) NewRequest(ctx context.Context, method, path string, opts any, options ...RequestOptionFunc) (*retryablehttp.Request, error) {
 	u := *c.baseURL
 
 	unescaped, err := url.PathUnescape(path)
@@ -234,17 +379,52 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, opts any)
 		headers.Set("User-Agent", userAgent)
 	}
 
-	headers.Set("Content-Type", contentType)
+	if method != http.MethodGet {
+		headers.Set("Content-Type", contentType)
+	}
 	headers.Set("Accept", contentType)
 	headers.Set("Origin", origin)
 
+	for key, value := range c.defaultHeaders {
+		headers.Set(key, value)
+	}
+
+	// the request ID is set once, so that retries of the request share it
+	headers.Set(RequestIDHeader, requestID(ctx))
+
+	if c.tagHeaderPrefix != "" {
+		setTagHeaders(ctx, headers, c.tagHeaderPrefix)
+	}
+
+	// the body is a []byte, which the retryable client rewinds for every attempt
 	var body any
 
 	if opts != nil {
-		c.ensureSecureHash(opts)
-		body, err = json.Marshal(opts)
-		if err != nil {
-			return nil, err
+		c.ensureSecureHash(ctx, method, path, opts)
+
+		if method == http.MethodGet {
+			q, err := encodeQuery(opts, c.amounts())
+			if err != nil {
+				return nil, err
+			}
+			u.RawQuery = q.Encode()
+		} else if hasFormFiles(opts) {
+			var multipartType string
+			body, multipartType, err = encodeMultipart(opts, c.amounts())
+			if err != nil {
+				return nil, err
+			}
+			headers.Set("Content-Type", multipartType)
+		} else if payment, ok := opts.(*PaymentOptions); ok {
+			body, err = c.encodePayment(payment)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			body, err = c.marshalJSON(opts)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -257,6 +437,15 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, opts any)
 		req.Header[key] = values
 	}
 
+	for _, fn := range options {
+		if fn == nil {
+			continue
+		}
+		if err := fn(req); err != nil {
+			return nil, err
+		}
+	}
+
 	return req, nil
 }
 
@@ -277,7 +466,9 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, opts any)
 // and `response_timestamp` are stored in the returned *Response alongside the underlying HTTP response.
 //
 // If the API response contains an `errors` field, it is returned as an error of type ResponseError.
-// Use `errors.As(err, &ResponseError)` to extract the error details.
+// Use `errors.As(err, &ResponseError)` to extract the error details, or errors.Is with one of
+// ErrInvalidHash, ErrDuplicateRequest and ErrInsufficientBalance to check for common failures. If the gateway responds with
+// something other than JSON, such as an HTML maintenance page, a *GatewayError is returned instead.
 //
 // Example:
 //
@@ -292,61 +483,147 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, opts any)
 //		}
 //	}
 func (c *Client) Do(req *retryablehttp.Request, v any) (*Response, error) {
-	token, expiry := c.getToken()
-	// authenticate if token is not set or has expired
-	if token == "" || (!expiry.IsZero() && time.Now().After(expiry)) {
-		if c.username == "" && c.password == "" {
-			return nil, errors.New("token expired")
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.doRequest(req, v)
+		if err == nil {
+			return resp, nil
 		}
-		if err := c.Login(req.Context()); err != nil {
-			return nil, fmt.Errorf("failed to re-authenticate: %w", err)
+
+		if !c.retryResponse(attempt, resp, err) {
+			// the response is returned with API errors so the payload can be inspected
+			return resp, err
 		}
 
-		token, _ = c.getToken()
+		if err := c.waitRetry(req.Context(), attempt, resp); err != nil {
+			return resp, err
+		}
+	}
+}
+
+// authorize adds the bearer token to the request, logging in first if the token
+// is not set or has expired.
+func (c *Client) authorize(req *retryablehttp.Request) error {
+	token, _, err := c.validToken(req.Context())
+	if err != nil {
+		return err
 	}
 
 	req.Header.Add("Authorization", "Bearer "+token)
 
-	resp, err := c.doRequest(req, v)
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// validToken returns the access token and its expiry time, logging in first if the token is not set or
+// has expired. If the client has a token source, the token is taken from it instead.
+func (c *Client) validToken(ctx context.Context) (string, time.Time, error) {
+	if c.tokenSource != nil {
+		token, err := c.tokenSource.Token()
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to get token from token source: %w", err)
+		}
+		return token.AccessToken, token.Expiry, nil
 	}
 
-	// TODO: Handle rate limiting
+	token, expiry := c.getToken()
+	if c.tokenExpired(token, expiry) {
+		if !c.hasCredentials() {
+			return "", time.Time{}, errors.New("token expired")
+		}
+		if err := c.Login(ctx); err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to re-authenticate: %w", err)
+		}
 
-	return resp, nil
+		token, expiry = c.getToken()
+	}
+	return token, expiry, nil
+}
+
+// tokenExpired reports whether the token must be renewed because it is not set or expires within the skew.
+// The skew only applies to clients which can log in again; the others use their token until it expires.
+func (c *Client) tokenExpired(token string, expiry time.Time) bool {
+	if token == "" {
+		return true
+	}
+	if expiry.IsZero() {
+		return false
+	}
+
+	skew := c.tokenExpirySkew
+	if !c.hasCredentials() {
+		skew = 0
+	}
+	return c.now().Add(skew).After(expiry)
 }
 
 func (c *Client) doRequest(req *retryablehttp.Request, v any) (*Response, error) {
+	start := time.Now()
+	resp, err := c.sendRequest(req, v)
+	c.recordStats(c.relativePath(req.URL.Path), start, err)
+	return resp, err
+}
+
+func (c *Client) sendRequest(req *retryablehttp.Request, v any) (*Response, error) {
+	req, stats := withAttemptStats(req)
+
+	c.warnDeprecatedVersion(req.URL.Path)
+
+	markSent(req.Context())
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
 	r := newResponse(resp)
+	r.RequestID = req.Header.Get(RequestIDHeader)
+	stats.fill(r)
+	c.checkDeprecationHeaders(resp)
 
 	if v != nil {
+		rawBody := resp.Body
 		defer func() {
-			err = errors.Join(err, resp.Body.Close())
-		}()
-		defer func() {
-			err = errors.Join(err, checkErr1(io.Copy(io.Discard, resp.Body)))
+			err = errors.Join(err, drainBody(rawBody))
 		}()
 
+		if err := c.limitBody(req.Request, resp); err != nil {
+			return r, err
+		}
+		if err := checkGatewayResponse(resp); err != nil {
+			return r, err
+		}
+
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return r, readErr
+		}
+
 		if _, ok := v.(*BearerToken); ok {
-			err = json.NewDecoder(resp.Body).Decode(v)
+			err = c.unmarshalJSON(body, v)
 		} else {
 			var respData responseData
-			err = json.NewDecoder(resp.Body).Decode(&respData)
+			err = c.unmarshal(body, &respData)
 			if err == nil {
 				r.Code = respData.ResponseCode
 				r.Message = respData.ResponseMessage
 				r.Time = respData.ResponseTime
+				r.Pagination = parsePagination(respData.paginationFields, unquoteContent(respData.ResponseContent))
 
-				if respData.Errors != nil {
-					return r, &respData.Errors
+				if err := decodeResponseErrors(respData.Errors, r); err != nil {
+					return r, err
+				}
+				if err := c.verifyResponseHash(unquoteContent(respData.ResponseContent)); err != nil {
+					return r, err
+				}
+				err = c.unmarshalResponse(v, &respData)
+				if err == nil {
+					err = c.checkUnknownFields(req.URL.Path, unquoteContent(respData.ResponseContent), v)
 				}
-				err = unmarshalResponse(v, &respData)
 			}
 		}
 	}
@@ -369,51 +646,210 @@ func (c *Client) retryHTTPCheck(ctx context.Context, resp *http.Response, err er
 	return false, nil
 }
 
-func (c *Client) ensureSecureHash(opt any) {
-	if sh, ok := opt.(secureHasher); ok && sh.GetHash() == "" {
-		sh.SetHash(generateSecureHashFrom(opt, c.labKey))
+// ensureSecureHash generates the secure hash of the request options of the given method and path,
+// unless one is already set, and reports it to the hash audit hook.
+func (c *Client) ensureSecureHash(ctx context.Context, method, path string, opt any) {
+	sh, ok := opt.(secureHasher)
+	if !ok || sh.GetHash() != "" {
+		return
+	}
+
+	source := secureHashSource(opt, c.amounts())
+	if keys, ok := c.hashFieldsFor(path); ok {
+		source = secureHashSourceFor(opt, keys, c.amounts())
+	}
+	hash := generateSecureHash(source, c.labKey)
+	sh.SetHash(hash)
+
+	if c.hashAudit != nil {
+		defer c.trackHook()()
+		c.hashAudit(ctx, HashAuditRecord{
+			Method: method,
+			Path:   path,
+			Source: source,
+			Hash:   hash,
+			Time:   c.now(),
+		})
 	}
 }
 
 // generateSecureHashFrom generates a secure hash for the given struct.
 func generateSecureHashFrom(v any, key string) string {
+	return generateSecureHash(secureHashSource(v, globalAmountFormat()), key)
+}
+
+// secureHashSource returns the concatenated field values of the given struct which are signed by the secure hash,
+// with amounts in the given format.
+// The values are concatenated in struct order, except for the fields positioned with securehash:"pos=N".
+func secureHashSource(v any, format AmountFormat) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	b := *buf
+	for _, field := range orderHashFields(collectHashFields(v, format)) {
+		b = append(b, field.value...)
+	}
+	*buf = b
+
+	return string(b)
+}
+
+// hashField is a field of request options which is part of the secure hash.
+type hashField struct {
+	// key is the JSON key of the field.
+	key string
+	// pos is the position of the field set with securehash:"pos=N", or zero.
+	pos   int
+	value string
+}
+
+// collectHashFields returns the fields of the struct v which are part of the secure hash, in struct order.
+// For payments, the secure hash is generated from the PaymentHeader struct, so its fields are returned.
+// Amounts are formatted with format.
+func collectHashFields(v any, format AmountFormat) []hashField {
 	val := reflect.ValueOf(v)
-	typ := reflect.TypeOf(v)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-		typ = typ.Elem()
+	fields := make([]hashField, 0, 16)
+	for {
+		header, ok := appendHashFields(&fields, val, format)
+		if !ok {
+			return fields
+		}
+		fields, val = fields[:0], header
 	}
+}
 
-	var b strings.Builder
+// hashStructField describes how a field of a struct type is part of the secure hash.
+type hashStructField struct {
+	index int
+	// header is set for the payment header of payments, which is hashed instead of the struct.
+	header bool
+	// embedded is set for exported embedded structs, whose fields are hashed in place as they are
+	// promoted in the JSON payload.
+	embedded  bool
+	key       string
+	pos       int
+	omitEmpty bool
+}
+
+// hashStructFieldsCache caches the hashed fields of request option types by type.
+var hashStructFieldsCache sync.Map
+
+// hashStructFields returns the fields of the struct type typ which are part of the secure hash, in struct order.
+// Unexported and anonymous fields, fields with a securehash tag set to ignore, fields with a json tag set
+// to "-", the secure hash itself and files are left out.
+func hashStructFields(typ reflect.Type) []hashStructField {
+	if fields, ok := hashStructFieldsCache.Load(typ); ok {
+		return fields.([]hashStructField)
+	}
+
+	var fields []hashStructField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
 
-	for i := 0; i < val.NumField(); i++ {
-		fieldType := typ.Field(i)
-		fieldValue := val.Field(i)
-		// check if it's a struct and has the name PaymentHeader
-		// For payment, the secure hash is generated from the PaymentHeader struct
-		if typ.Kind() == reflect.Struct && fieldType.Tag.Get("json") == "paymentHeader" {
-			return generateSecureHashFrom(fieldValue.Interface(), key)
+		switch {
+		case tag == "paymentHeader":
+			fields = append(fields, hashStructField{index: i, header: true})
+			continue
+		case field.Anonymous && field.IsExported() && tag == "":
+			fields = append(fields, hashStructField{index: i, embedded: true})
+			continue
+		}
+
+		key, opts, _ := strings.Cut(tag, ",")
+		if !field.IsExported() ||
+			field.Anonymous ||
+			field.Tag.Get("securehash") == "ignore" ||
+			key == "-" ||
+			key == "secureHash" ||
+			isFormFile(field.Type) {
+			continue
 		}
 
-		// skip unexported fields, anonymous fields, fields with securehash tag set to ignore, and fields with json tag set to "-"
-		if !fieldValue.CanInterface() ||
-			fieldType.Anonymous ||
-			fieldType.Tag.Get("securehash") == "ignore" ||
-			fieldType.Tag.Get("json") == "-" ||
-			fieldType.Tag.Get("json") == "secureHash" {
+		if key == "" {
+			key = field.Name
+		}
+		fields = append(fields, hashStructField{
+			index:     i,
+			key:       key,
+			pos:       hashFieldPos(field.Tag.Get("securehash")),
+			omitEmpty: strings.Contains(opts, "omitempty"),
+		})
+	}
+
+	hashStructFieldsCache.Store(typ, fields)
+	return fields
+}
+
+// appendHashFields appends the fields of the struct val which are part of the secure hash to fields.
+// If the struct has a payment header, it is returned instead. Values other than structs have no fields.
+func appendHashFields(fields *[]hashField, val reflect.Value, format AmountFormat) (reflect.Value, bool) {
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return reflect.Value{}, false
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	for _, field := range hashStructFields(val.Type()) {
+		fieldValue := val.Field(field.index)
+
+		switch {
+		case field.header:
+			return fieldValue, true
+		case field.embedded:
+			if header, ok := appendHashFields(fields, fieldValue, format); ok {
+				return header, true
+			}
+			continue
+		case field.omitEmpty && fieldValue.IsZero():
+			// empty optional fields are not sent
 			continue
 		}
 
-		b.WriteString(formatToStr(fieldValue.Interface()))
+		*fields = append(*fields, hashField{
+			key:   field.key,
+			pos:   field.pos,
+			value: formatHashValue(fieldValue, format),
+		})
 	}
 
-	return generateSecureHash(b.String(), key)
+	return reflect.Value{}, false
 }
 
+// formatHashValue formats the value of a hashed field like formatValue. The common types are not boxed
+// into an interface, which allocates.
+func formatHashValue(fv reflect.Value, format AmountFormat) string {
+	switch fv.Type() {
+	case stringType:
+		return fv.String()
+	case intType:
+		return strconv.FormatInt(fv.Int(), 10)
+	case decimalType:
+		if fv.CanAddr() {
+			return format.format(*fv.Addr().Interface().(*decimal.Decimal))
+		}
+	}
+	return formatValue(fv.Interface(), format)
+}
+
+var intType = reflect.TypeFor[int]()
+
 // generateSecureHash generates a secure hash for the given data.
 func generateSecureHash(data, key string) string {
-	hash := sha512.Sum512([]byte(data + key))
-	return hex.EncodeToString(hash[:])
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	b := append(append(*buf, data...), key...)
+	*buf = b
+
+	hash := sha512.Sum512(b)
+	var encoded [sha512.Size * 2]byte
+	hex.Encode(encoded[:], hash[:])
+	return string(encoded[:])
 }
 
 type responseData struct {
@@ -421,16 +857,162 @@ type responseData struct {
 	ResponseMessage string          `json:"response_message"`
 	ResponseContent json.RawMessage `json:"response_content"`
 	ResponseTime    Time            `json:"response_timestamp"`
-	Errors          ResponseError   `json:"errors"`
+	Errors          json.RawMessage `json:"errors"`
+
+	// the paging fields of paged endpoints returned in the envelope
+	paginationFields
 }
 
 var emptyResponseContent = []byte{0x22, 0x22}
 
-func unmarshalResponse(resp any, data *responseData) error {
+// decodeResponseErrors returns the errors of a response as a *ResponseError, or nil if there are none.
+// The raw errors are stored in r for diagnostics.
+func decodeResponseErrors(raw json.RawMessage, r *Response) error {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	r.RawErrors = raw
+
+	var respErr ResponseError
+	if err := json.Unmarshal(raw, &respErr); err != nil {
+		return err
+	}
+	if respErr == nil {
+		return nil
+	}
+	return &respErr
+}
+
+func (c *Client) unmarshalResponse(resp any, data *responseData) error {
 	if data.ResponseContent == nil || bytes.Equal(data.ResponseContent, emptyResponseContent) {
 		return nil
 	}
-	return json.Unmarshal(data.ResponseContent, resp)
+	return c.unmarshal(unquoteContent(data.ResponseContent), resp)
+}
+
+// unquoteContent returns the JSON object or array encoded in the response content, if some endpoint
+// returned it double-encoded as a JSON string. Other content is returned as is.
+func unquoteContent(content json.RawMessage) json.RawMessage {
+	if len(content) == 0 || content[0] != '"' {
+		return content
+	}
+
+	var s string
+	if err := json.Unmarshal(content, &s); err != nil {
+		return content
+	}
+
+	inner := bytes.TrimSpace([]byte(s))
+	if len(inner) == 0 || (inner[0] != '{' && inner[0] != '[') || !json.Valid(inner) {
+		return content
+	}
+	return inner
+}
+
+// unmarshal decodes data into v like json.Unmarshal, additionally parsing timestamps with the
+// time formats of the client.
+//
+// Time values cannot reach the client while they are decoded, so if a timestamp fails to parse with
+// the default formats, data is decoded again after rewriting as RFC 3339, in a single pass, the values of
+// the Time and Date fields of v which only parse with the client's formats. Other values are left as is.
+func (c *Client) unmarshal(data []byte, v any) error {
+	err := c.unmarshalJSON(data, v)
+
+	var parseErr *TimeParseError
+	if err == nil || len(c.timeFormats) == 0 || !errors.As(err, &parseErr) {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree any
+	if dec.Decode(&tree) != nil {
+		return err
+	}
+	rewritten, marshalErr := json.Marshal(rewriteTimes(tree, reflect.TypeOf(v), c.timeFormats))
+	if marshalErr != nil {
+		return err
+	}
+	return c.unmarshalJSON(rewritten, v)
+}
+
+// marshalJSON encodes v with the JSON codec of the client.
+func (c *Client) marshalJSON(v any) ([]byte, error) {
+	if c.jsonMarshal != nil {
+		return c.jsonMarshal(v)
+	}
+	return json.Marshal(v)
+}
+
+// unmarshalJSON decodes data into v with the JSON codec of the client.
+func (c *Client) unmarshalJSON(data []byte, v any) error {
+	if c.jsonUnmarshal != nil {
+		return c.jsonUnmarshal(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+var (
+	timeType = reflect.TypeFor[Time]()
+	dateType = reflect.TypeFor[Date]()
+)
+
+// rewriteTimes rewrites as RFC 3339 the timestamps of the decoded JSON node which are decoded into Time
+// or Date values of type typ, and only parse with the given layouts. Struct fields are matched by their
+// json tags, case-insensitively like encoding/json.
+func rewriteTimes(node any, typ reflect.Type, layouts []string) any {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch n := node.(type) {
+	case string:
+		if typ != timeType && typ != dateType {
+			return n
+		}
+		quoted, _ := json.Marshal(n)
+		if reflect.New(typ).Interface().(json.Unmarshaler).UnmarshalJSON(quoted) == nil {
+			return n
+		}
+		if t, ok := parseTimeFormats(n, layouts); ok {
+			return t.Format(time.RFC3339Nano)
+		}
+	case map[string]any:
+		switch typ.Kind() {
+		case reflect.Map:
+			for key, value := range n {
+				n[key] = rewriteTimes(value, typ.Elem(), layouts)
+			}
+		case reflect.Struct:
+			fields := paramFields(typ)
+			for key, value := range n {
+				i := slices.IndexFunc(fields, func(f paramField) bool { return f.key == key })
+				if i < 0 {
+					i = slices.IndexFunc(fields, func(f paramField) bool { return strings.EqualFold(f.key, key) })
+				}
+				if i >= 0 && fields[i].IsExported() {
+					n[key] = rewriteTimes(value, fields[i].Type, layouts)
+				}
+			}
+		}
+	case []any:
+		if typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+			for i, value := range n {
+				n[i] = rewriteTimes(value, typ.Elem(), layouts)
+			}
+		}
+	}
+	return node
+}
+
+// parseTimeFormats parses s with the first matching layout.
+func parseTimeFormats(s string, layouts []string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
 }
 
 type Response struct {
@@ -443,6 +1025,21 @@ type Response struct {
 	Message string
 	// Time is the response_timestamp returned by the API as part of the response payload.
 	Time Time
+	// RawErrors holds the errors field of the response payload as returned by the API, for diagnostics.
+	// It is decoded into the ResponseError returned with the response.
+	RawErrors json.RawMessage
+	// Pagination is the paging of the response of paged endpoints, or nil if the response is not paged.
+	Pagination *Pagination
+
+	// Attempts is the number of times the request was sent, including retries.
+	Attempts int
+	// RetryWait is the total time spent waiting between attempts.
+	RetryWait time.Duration
+	// Latency is the duration of the final attempt, until the response headers were received.
+	Latency time.Duration
+
+	// RequestID is the ID sent in the X-Request-ID header of the request, set with WithRequestID or generated.
+	RequestID string
 }
 
 func newResponse(r *http.Response) *Response {
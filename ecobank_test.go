@@ -1,13 +1,22 @@
 package ecobank
 
 import (
+	"context"
 	"crypto/sha512"
 	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -35,7 +44,7 @@ func (m *mockHTTPClient) RoundTrip(req *http.Request) (*http.Response, error) {
 }
 
 // newMockClient creates a Client with a mock HTTP client.
-func newMockClient(t *testing.T, response string, statusCode int) *Client {
+func newMockClient(t testing.TB, response string, statusCode int) *Client {
 	t.Helper()
 	mockTransport := &mockHTTPClient{
 		mockResponse: response,
@@ -78,6 +87,33 @@ func TestGenerateSecureHashFrom(t *testing.T) {
 		OtherField    string `json:"otherField"`
 	}
 
+	type TestStructWithPointers struct {
+		RequestID     *string          `json:"requestId"`
+		AffiliateCode *string          `json:"affiliateCode"`
+		Amount        *decimal.Decimal `json:"amount"`
+	}
+
+	type TestStructEmbedded struct {
+		TestStructWithoutPaymentHeader
+		AccountNo string `json:"accountNo"`
+
+		secureHashOption
+	}
+
+	type TestStructOptional struct {
+		RequestID string  `json:"requestId"`
+		CbaRefNo  *string `json:"cbaRefNo,omitempty"`
+		Retries   int     `json:"retries,omitempty"`
+		Flag      bool    `json:"flag"`
+	}
+
+	type TestStructWithPaymentHeaderPointer struct {
+		RequestID     string         `json:"requestId"`
+		PaymentHeader *PaymentHeader `json:"paymentHeader"`
+	}
+
+	requestID, amount := "REQ123", decimal.NewFromInt(10)
+
 	testCases := []struct {
 		name     string
 		input    any
@@ -119,6 +155,51 @@ func TestGenerateSecureHashFrom(t *testing.T) {
 			key:      "testKey",
 			expected: generateSecureHash("", "testKey"),
 		},
+		{
+			name:     "PointerFields",
+			input:    &TestStructWithPointers{RequestID: &requestID, Amount: &amount},
+			key:      "testKey",
+			expected: generateSecureHash("REQ123"+globalAmountFormat().format(amount), "testKey"),
+		},
+		{
+			name: "EmbeddedStruct",
+			input: TestStructEmbedded{
+				TestStructWithoutPaymentHeader: TestStructWithoutPaymentHeader{RequestID: "REQ123", AffiliateCode: "AFF"},
+				AccountNo:                      "1441000574000",
+			},
+			key:      "testKey",
+			expected: generateSecureHash("REQ123AFF1441000574000", "testKey"),
+		},
+		{
+			name:     "NilPaymentHeader",
+			input:    TestStructWithPaymentHeaderPointer{RequestID: "REQ123"},
+			key:      "testKey",
+			expected: generateSecureHash("", "testKey"),
+		},
+		{
+			name:     "OptionalFieldsUnset",
+			input:    TestStructOptional{RequestID: "REQ123"},
+			key:      "testKey",
+			expected: generateSecureHash("REQ123false", "testKey"),
+		},
+		{
+			name:     "OptionalFieldsSet",
+			input:    TestStructOptional{RequestID: "REQ123", CbaRefNo: &requestID, Retries: 2, Flag: true},
+			key:      "testKey",
+			expected: generateSecureHash("REQ123REQ1232true", "testKey"),
+		},
+		{
+			name:     "NilPointer",
+			input:    (*TestStructWithoutPaymentHeader)(nil),
+			key:      "testKey",
+			expected: generateSecureHash("", "testKey"),
+		},
+		{
+			name:     "NotAStruct",
+			input:    "REQ123",
+			key:      "testKey",
+			expected: generateSecureHash("", "testKey"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -140,3 +221,395 @@ func TestGenerateSecureHash(t *testing.T) {
 	actual := generateSecureHash(data, key)
 	assert.Equal(t, expected, actual)
 }
+
+func BenchmarkGenerateSecureHashFrom(b *testing.B) {
+	opt := &PaymentOptions{
+		PaymentHeader: PaymentHeader{
+			ClientID:          "EGHTelc000043",
+			BatchSequence:     "1",
+			BatchAmount:       decimal.NewFromInt(520),
+			TransactionAmount: decimal.NewFromInt(520),
+			BatchID:           "EG1593490",
+			TransactionCount:  6,
+			BatchCount:        6,
+			TransactionID:     "E12T443308",
+			DebitType:         "Multiple",
+			AffiliateCode:     "EGH",
+			TotalBatches:      "1",
+		},
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = generateSecureHashFrom(opt, "lab-key")
+	}
+}
+
+func BenchmarkDoRequest_Decode(b *testing.B) {
+	client := newMockClient(b, `{
+		"response_code": 200,
+		"response_message": "success",
+		"response_content": {
+			"hostHeaderInfo": {"sourceCode": "CORPORATEAPI", "requestId": "14232436312", "affiliateCode": "EGH"},
+			"accountNo": "1441000574000",
+			"accountName": "TEST USER",
+			"ccy": "GHS",
+			"availableBalance": 15.92,
+			"currentBalance": 15.92,
+			"accountStatus": "ACTIVE"
+		},
+		"response_timestamp": "2022-04-19T19:46:57.557"
+	}`, http.StatusOK)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, _, err := client.Account.GetBalance(b.Context(), &AccountBalanceOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func FuzzGenerateSecureHashFrom(f *testing.F) {
+	f.Add("14232436312", "EGH", "1441000574000", "lab-key")
+	f.Add("", "", "", "")
+	f.Add("\"\\", "\x00", "\xff", "ключ")
+
+	f.Fuzz(func(t *testing.T, requestID, affiliateCode, accountNo, key string) {
+		opt := &AccountBalanceOptions{
+			RequestID:     requestID,
+			AffiliateCode: affiliateCode,
+			AccountNo:     accountNo,
+		}
+
+		hash := generateSecureHashFrom(opt, key)
+		assert.Len(t, hash, sha512.Size*2)
+		assert.Equal(t, generateSecureHash(requestID+affiliateCode+accountNo, key), hash)
+
+		// the hash itself is not part of the hash
+		opt.SetHash(hash)
+		assert.Equal(t, hash, generateSecureHashFrom(opt, key))
+	})
+}
+
+func TestResponseAttempts(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+
+	calls := 0
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			calls++
+			resp := httptest.NewRecorder()
+			if calls < 3 {
+				resp.WriteHeader(http.StatusServiceUnavailable)
+				return resp.Result(), nil
+			}
+			resp.WriteHeader(http.StatusOK)
+			_, err := resp.WriteString(`{"response_code": 0, "response_message": "success"}`)
+			return resp.Result(), err
+		},
+	}
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "merchant/accountbalance", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req, &struct{}{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, resp.Attempts)
+	// two retries with a minimum wait of 100ms each
+	assert.GreaterOrEqual(t, resp.RetryWait, 200*time.Millisecond)
+	assert.Positive(t, resp.Latency)
+	assert.Less(t, resp.Latency, resp.RetryWait)
+}
+
+func TestRetry_IdenticalPayloads(t *testing.T) {
+	newPayment := func() *PaymentOptions {
+		opt := newOutboxPayment("2323", "2324")
+		opt.PaymentHeader.ExecutionDate = NewTime(time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))
+		return opt
+	}
+
+	// recordBodies records the bodies of the requests, and fails the first two with fail.
+	recordBodies := func(client *Client, fail func() (*http.Response, error)) *[][]byte {
+		var bodies [][]byte
+		client.client.HTTPClient.Transport = &mockHTTPClient{
+			requestHandler: func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				bodies = append(bodies, body)
+				if len(bodies) < 3 {
+					return fail()
+				}
+
+				resp := httptest.NewRecorder()
+				_, err = resp.WriteString(`{"response_code": 200, "response_content": "success"}`)
+				return resp.Result(), err
+			},
+		}
+		return &bodies
+	}
+
+	assertIdentical := func(t *testing.T, bodies [][]byte, hash string) {
+		t.Helper()
+		require.Len(t, bodies, 3)
+		for _, body := range bodies {
+			assert.Equal(t, string(bodies[0]), string(body))
+			assert.Contains(t, string(body), `"secureHash":"`+hash+`"`)
+		}
+	}
+
+	t.Run("connection reset", func(t *testing.T) {
+		client := newMockClient(t, "", http.StatusOK)
+		require.NoError(t, WithRetryPolicy(retryablehttp.DefaultRetryPolicy)(client))
+		client.client.RetryWaitMin, client.client.RetryWaitMax = time.Millisecond, time.Millisecond
+
+		bodies := recordBodies(client, func() (*http.Response, error) {
+			return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+		})
+
+		opt := newPayment()
+		_, resp, err := client.Payment.Pay(t.Context(), opt)
+		require.NoError(t, err)
+		assert.Equal(t, 3, resp.Attempts)
+		assertIdentical(t, *bodies, opt.GetHash())
+	})
+
+	t.Run("retry classifier", func(t *testing.T) {
+		client := newMockClient(t, "", http.StatusOK)
+		require.NoError(t, WithRetryClassifier(func(*Response, error) bool { return true }, 2)(client))
+		client.client.RetryWaitMin, client.client.RetryWaitMax = time.Millisecond, time.Millisecond
+
+		bodies := recordBodies(client, func() (*http.Response, error) {
+			resp := httptest.NewRecorder()
+			_, err := resp.WriteString(`{"response_code": 503, "response_message": "error", "errors": ["temporary failure"]}`)
+			return resp.Result(), err
+		})
+
+		opt := newPayment()
+		_, _, err := client.Payment.Pay(t.Context(), opt)
+		require.NoError(t, err)
+		assertIdentical(t, *bodies, opt.GetHash())
+	})
+}
+
+func TestNewRequestQuery(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+
+	type listOptions struct {
+		secureHashOption
+		AccountNo string   `url:"accountNo"`
+		Currency  string   `json:"ccy,omitempty"`
+		Page      int      `url:"page,omitempty"`
+		Status    []string `url:"status"`
+		StartDate Date     `url:"startDate"`
+		Internal  string   `url:"-"`
+	}
+
+	opt := &listOptions{
+		AccountNo: "6500184371",
+		Status:    []string{"PENDING", "SUCCESS"},
+		StartDate: NewDate(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)),
+		Internal:  "ignored",
+	}
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "merchant/list", opt)
+	require.NoError(t, err)
+
+	assert.Nil(t, req.Body)
+	assert.Empty(t, req.Header.Get("Content-Type"))
+
+	q := req.URL.Query()
+	assert.Equal(t, "6500184371", q.Get("accountNo"))
+	assert.Equal(t, []string{"PENDING", "SUCCESS"}, q["status"])
+	assert.Equal(t, "20250301", q.Get("startDate"))
+	assert.Equal(t, opt.SecureHash, q.Get("secureHash"))
+	assert.NotEmpty(t, opt.SecureHash)
+	assert.NotContains(t, q, "ccy")
+	assert.NotContains(t, q, "page")
+	assert.NotContains(t, q, "Internal")
+}
+
+func TestRequestHeaders(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithDefaultHeaders(map[string]string{
+		"X-Tenant":  "acme",
+		"X-Gateway": "default",
+	})(client))
+	client.token = ""
+
+	headers := make(map[string]http.Header)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			headers[req.URL.Path] = req.Header
+			resp := httptest.NewRecorder()
+			body := `{"response_code": 200, "response_content": {}}`
+			if req.URL.Path == "/corporateapi/user/token" {
+				body = `{"username": "mock-client-id", "token": "mock-token"}`
+			}
+			_, err := resp.WriteString(body)
+			return resp.Result(), err
+		},
+	}
+
+	_, _, err := client.Account.GetBalance(context.Background(), &AccountBalanceOptions{}, WithHeader("X-Gateway", "eu-west"))
+	require.NoError(t, err)
+
+	token := headers["/corporateapi/user/token"]
+	require.NotNil(t, token)
+	assert.Equal(t, "acme", token.Get("X-Tenant"))
+	assert.Equal(t, "default", token.Get("X-Gateway"))
+
+	balance := headers["/corporateapi/merchant/accountbalance"]
+	require.NotNil(t, balance)
+	assert.Equal(t, "acme", balance.Get("X-Tenant"))
+	assert.Equal(t, "eu-west", balance.Get("X-Gateway"))
+}
+
+func TestWithTransportTuning(t *testing.T) {
+	client, err := NewClient("user", "password", "lab-key", WithTransportTuning(100, time.Minute, true))
+	require.NoError(t, err)
+
+	transport, ok := client.client.HTTPClient.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 100, transport.MaxIdleConns)
+	assert.Equal(t, 100, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.Nil(t, transport.TLSNextProto)
+
+	client, err = NewClient("user", "password", "lab-key", WithTransportTuning(10, 0, false))
+	require.NoError(t, err)
+	transport = client.client.HTTPClient.Transport.(*http.Transport)
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.NotNil(t, transport.TLSNextProto, "HTTP/2 is disabled")
+
+	_, err = NewClient("user", "password", "lab-key",
+		WithHTTPClient(&http.Client{Transport: &mockHTTPClient{}}),
+		WithTransportTuning(10, time.Minute, true),
+	)
+	assert.EqualError(t, err, "cannot tune transport of type *ecobank.mockHTTPClient: must be *http.Transport")
+
+	_, err = NewClient("user", "password", "lab-key", WithTransportTuning(-1, time.Minute, true))
+	assert.EqualError(t, err, "max idle connections must not be negative")
+}
+
+func TestWithInsecureSkipTLSVerify_WithCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"response_code": 200, "response_content": {"accountName": "TEST USER"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	getBalance := func(options ...ClientOptionFunc) error {
+		t.Helper()
+		client, err := NewClient("user", "password", "lab-key", append([]ClientOptionFunc{
+			WithBaseURL(srv.URL),
+			WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+			WithDisableRetries(),
+		}, options...)...)
+		require.NoError(t, err)
+
+		_, _, err = client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		return err
+	}
+
+	assert.ErrorContains(t, getBalance(), "certificate", "self-signed certificates are rejected by default")
+	assert.NoError(t, getBalance(WithInsecureSkipTLSVerify()))
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	assert.NoError(t, getBalance(WithCustomCA(caPEM)))
+
+	_, err := NewClient("user", "password", "lab-key", WithCustomCA([]byte("not a certificate")))
+	assert.EqualError(t, err, "no CA certificate found in PEM data")
+
+	_, err = NewClient("user", "password", "lab-key",
+		WithHTTPClient(&http.Client{Transport: &mockHTTPClient{}}),
+		WithInsecureSkipTLSVerify(),
+	)
+	assert.EqualError(t, err, "cannot configure TLS of transport of type *ecobank.mockHTTPClient: must be *http.Transport")
+
+	client, err := NewClient("user", "password", "lab-key", WithInsecureSkipTLSVerify())
+	require.NoError(t, err)
+	assert.True(t, client.client.HTTPClient.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestWithJSONCodec(t *testing.T) {
+	var marshaled, unmarshaled int
+	var body []byte
+
+	client := newMockClient(t, `{"response_code": 200, "response_content": {"accountNo": "1441000574000", "availableBalance": "15.92"}}`, http.StatusOK)
+	require.NoError(t, WithJSONCodec(
+		func(v any) ([]byte, error) {
+			marshaled++
+			return json.Marshal(v)
+		},
+		func(data []byte, v any) error {
+			unmarshaled++
+			return json.Unmarshal(data, v)
+		},
+	)(client))
+
+	transport := client.client.HTTPClient.Transport.(*mockHTTPClient)
+	transport.requestHandler = func(req *http.Request) (*http.Response, error) {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp := httptest.NewRecorder()
+		_, err = resp.WriteString(transport.mockResponse)
+		return resp.Result(), err
+	}
+
+	balance, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{AccountNo: "1441000574000"})
+	require.NoError(t, err)
+	assert.Equal(t, "15.92", balance.AvailableBalance.String())
+	assert.Contains(t, string(body), `"accountNo":"1441000574000"`)
+
+	assert.Equal(t, 1, marshaled)
+	// the envelope and the content
+	assert.Equal(t, 2, unmarshaled)
+
+	t.Run("errors", func(t *testing.T) {
+		codecErr := errors.New("codec error")
+		client := newMockClient(t, `{"response_code": 200}`, http.StatusOK)
+		require.NoError(t, WithJSONCodec(nil, func([]byte, any) error { return codecErr })(client))
+
+		_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		assert.ErrorIs(t, err, codecErr)
+	})
+}
+
+func TestDoRequest_DoubleEncodedContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"object", `{"accountNo": "1441000574000", "availableBalance": "15.92"}`},
+		{"quoted object", `"{\"accountNo\": \"1441000574000\", \"availableBalance\": \"15.92\"}"`},
+		{"quoted object with spaces", `" {\"accountNo\": \"1441000574000\", \"availableBalance\": 15.92}\n"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(t, `{"response_code": 200, "response_content": `+tt.content+`}`, http.StatusOK)
+
+			balance, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, "1441000574000", balance.AccountNo)
+			assert.Equal(t, "15.92", balance.AvailableBalance.String())
+		})
+	}
+
+	t.Run("plain string", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 200, "response_content": "{not json"}`, http.StatusOK)
+
+		req, err := client.NewRequest(t.Context(), http.MethodPost, "merchant/test", nil)
+		require.NoError(t, err)
+
+		var content string
+		_, err = client.Do(req, &content)
+		require.NoError(t, err)
+		assert.Equal(t, "{not json", content)
+	})
+}
@@ -0,0 +1,171 @@
+// Package ecobanktest provides utilities for testing code that uses the Ecobank API client.
+//
+// It ships realistic golden responses for every endpoint supported by the client, covering
+// documented successes, API errors and non-JSON gateway pages, so tests don't have to
+// hand-write response payloads.
+package ecobanktest
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// GatewayEndpoint is the pseudo endpoint under which the HTML pages served by the gateway
+// in front of the API, such as maintenance and firewall pages, are stored.
+const GatewayEndpoint = "gateway"
+
+//go:embed testdata
+var testdata embed.FS
+
+// Fixture is a golden response of the Ecobank API.
+//
+// Fixtures are stored as testdata/<endpoint>/<name>_<status>.<ext>, where endpoint is the API
+// path relative to the base URL, e.g. "merchant/accountbalance".
+type Fixture struct {
+	// Endpoint is the API path the fixture is a response for.
+	Endpoint string
+	// Name describes the scenario, e.g. "success" or "invalid_secure_hash".
+	Name string
+	// StatusCode is the HTTP status code the API responds with.
+	StatusCode int
+	// ContentType is the Content-Type header the API responds with.
+	ContentType string
+	// Body is the response body.
+	Body []byte
+}
+
+// ServeHTTP writes the fixture as the response, so a fixture can be used as an http.Handler.
+func (f *Fixture) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", f.ContentType)
+	w.WriteHeader(f.StatusCode)
+	_, _ = w.Write(f.Body)
+}
+
+// Load returns the fixture with the given name for an endpoint.
+func Load(endpoint, name string) (*Fixture, error) {
+	fixtures, err := Fixtures(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range fixtures {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+
+	return nil, fmt.Errorf("ecobanktest: no fixture %q for endpoint %q", name, endpoint)
+}
+
+// MustLoad is like Load but fails the test if the fixture does not exist.
+func MustLoad(tb testing.TB, endpoint, name string) *Fixture {
+	tb.Helper()
+
+	f, err := Load(endpoint, name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return f
+}
+
+// Fixtures returns all the fixtures of an endpoint, sorted by name.
+func Fixtures(endpoint string) ([]*Fixture, error) {
+	endpoint = strings.Trim(endpoint, "/")
+
+	entries, err := fs.ReadDir(testdata, path.Join("testdata", endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("ecobanktest: no fixtures for endpoint %q", endpoint)
+	}
+
+	var fixtures []*Fixture
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		f, err := readFixture(endpoint, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, f)
+	}
+
+	sort.Slice(fixtures, func(i, j int) bool { return fixtures[i].Name < fixtures[j].Name })
+
+	return fixtures, nil
+}
+
+// Endpoints returns the endpoints which have fixtures, sorted, including GatewayEndpoint.
+func Endpoints() []string {
+	seen := make(map[string]bool)
+
+	_ = fs.WalkDir(testdata, "testdata", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		seen[strings.TrimPrefix(path.Dir(p), "testdata/")] = true
+		return nil
+	})
+
+	endpoints := make([]string, 0, len(seen))
+	for endpoint := range seen {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
+	return endpoints
+}
+
+// All returns the fixtures of every endpoint.
+func All() ([]*Fixture, error) {
+	var all []*Fixture
+	for _, endpoint := range Endpoints() {
+		fixtures, err := Fixtures(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, fixtures...)
+	}
+	return all, nil
+}
+
+func readFixture(endpoint, file string) (*Fixture, error) {
+	ext := path.Ext(file)
+	base := strings.TrimSuffix(file, ext)
+
+	i := strings.LastIndexByte(base, '_')
+	if i < 0 {
+		return nil, fmt.Errorf("ecobanktest: fixture %s/%s has no status code", endpoint, file)
+	}
+
+	status, err := strconv.Atoi(base[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("ecobanktest: fixture %s/%s has an invalid status code: %w", endpoint, file, err)
+	}
+
+	body, err := testdata.ReadFile(path.Join("testdata", endpoint, file))
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := "application/json"
+	if ext == ".html" {
+		contentType = "text/html; charset=utf-8"
+	}
+
+	return &Fixture{
+		Endpoint:    endpoint,
+		Name:        base[:i],
+		StatusCode:  status,
+		ContentType: contentType,
+		Body:        body,
+	}, nil
+}
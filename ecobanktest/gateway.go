@@ -0,0 +1,171 @@
+package ecobanktest
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Gateway is an http.Handler simulating the Ecobank gateway under load, with latency and errors, to
+// benchmark payout pipelines built on the client without hitting the sandbox:
+//
+//	gw := &ecobanktest.Gateway{Latency: 80 * time.Millisecond, Jitter: 40 * time.Millisecond, ErrorRate: 0.02}
+//	srv := ecobanktest.StartGateway(b, gw)
+//	client, _ := ecobank.NewClient("user", "password", "lab-key", ecobank.WithBaseURL(srv.URL))
+//
+// Every request is answered with the "success" fixture of its endpoint, found by the suffix of the
+// request path, unless a response is set in Responses. A fraction ErrorRate of the requests is answered
+// with Error instead.
+type Gateway struct {
+	// Latency is the mean time taken to respond.
+	Latency time.Duration
+	// Jitter is the maximum deviation from Latency, which is uniformly distributed.
+	Jitter time.Duration
+	// ErrorRate is the fraction of requests answered with Error, from 0 to 1.
+	ErrorRate float64
+	// Error is the response to failed requests. It defaults to the "maintenance" page of GatewayEndpoint.
+	Error http.Handler
+	// Responses are the responses by endpoint, e.g. "merchant/payment" without leading or trailing slashes,
+	// overriding the fixtures.
+	Responses map[string]http.Handler
+	// Seed seeds the random latencies and errors, so that runs are reproducible.
+	Seed uint64
+
+	once sync.Once
+	mu   sync.Mutex
+	rand *rand.Rand
+
+	// fixtures caches the success fixtures by endpoint.
+	fixtures sync.Map
+
+	requests atomic.Int64
+	errors   atomic.Int64
+}
+
+// GatewayStats counts the requests served by a Gateway.
+type GatewayStats struct {
+	// Requests is the number of requests served.
+	Requests int64
+	// Errors is the number of requests answered with the error response.
+	Errors int64
+}
+
+// StartGateway starts an HTTP server serving the gateway, which is closed when the test or benchmark ends.
+// Use its URL as the base URL of the client.
+func StartGateway(tb testing.TB, g *Gateway) *httptest.Server {
+	tb.Helper()
+
+	srv := httptest.NewServer(g)
+	tb.Cleanup(srv.Close)
+	return srv
+}
+
+// Stats returns the requests served so far.
+func (g *Gateway) Stats() GatewayStats {
+	return GatewayStats{
+		Requests: g.requests.Load(),
+		Errors:   g.errors.Load(),
+	}
+}
+
+// ServeHTTP answers the request after the simulated latency.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.requests.Add(1)
+	delay, fail := g.draw()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fail {
+		g.errors.Add(1)
+		g.errorHandler().ServeHTTP(w, r)
+		return
+	}
+
+	handler := g.handler(r.URL.Path)
+	if handler == nil {
+		http.NotFound(w, r)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// draw returns the latency of a request and whether it fails.
+func (g *Gateway) draw() (time.Duration, bool) {
+	g.once.Do(func() {
+		g.rand = rand.New(rand.NewPCG(g.Seed, g.Seed))
+	})
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delay := g.Latency
+	if g.Jitter > 0 {
+		delay += time.Duration(g.rand.Int64N(int64(2*g.Jitter)+1)) - g.Jitter
+	}
+	return max(delay, 0), g.ErrorRate > 0 && g.rand.Float64() < g.ErrorRate
+}
+
+func (g *Gateway) errorHandler() http.Handler {
+	if g.Error != nil {
+		return g.Error
+	}
+	f, err := Load(GatewayEndpoint, "maintenance")
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// handler returns the response for the endpoint with the longest path matching the end of the request path.
+func (g *Gateway) handler(requestPath string) http.Handler {
+	requestPath = strings.TrimSuffix(requestPath, "/")
+
+	var match string
+	for _, endpoint := range endpointPaths(g.Responses) {
+		if (requestPath == endpoint || strings.HasSuffix(requestPath, "/"+endpoint)) && len(endpoint) > len(match) {
+			match = endpoint
+		}
+	}
+	if match == "" {
+		return nil
+	}
+
+	if h, ok := g.Responses[match]; ok {
+		return h
+	}
+	if f, ok := g.fixtures.Load(match); ok {
+		return f.(*Fixture)
+	}
+	f, err := Load(match, "success")
+	if err != nil {
+		return nil
+	}
+	g.fixtures.Store(match, f)
+	return f
+}
+
+// fixtureEndpoints caches the endpoints with fixtures, which are embedded.
+var fixtureEndpoints = sync.OnceValue(Endpoints)
+
+// endpointPaths returns the endpoints with fixtures and the endpoints of responses.
+func endpointPaths(responses map[string]http.Handler) []string {
+	endpoints := slices.Clone(fixtureEndpoints())
+	for endpoint := range responses {
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
@@ -0,0 +1,69 @@
+package ecobanktest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+)
+
+//go:embed payments
+var payments embed.FS
+
+// PaymentTypes returns the payment types which have payment fixtures, sorted, e.g. "DOMESTIC".
+func PaymentTypes() []string {
+	entries, _ := fs.ReadDir(payments, "payments")
+
+	types := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		types = append(types, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// Payment returns the body of a payment request made of a single extension of the payment type,
+// e.g. "DOMESTIC", with sample values. There is a fixture for every payment type supported by the client.
+//
+// Payment fixtures are stored as payments/<payment type>.json. They have no execution date nor secure
+// hash, which are generated by the client when the payment is submitted.
+func Payment(paymentType string) ([]byte, error) {
+	body, err := payments.ReadFile(path.Join("payments", strings.ToUpper(paymentType)+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("ecobanktest: no payment fixture for payment type %q", paymentType)
+	}
+	return body, nil
+}
+
+// LoadPayment decodes the payment fixture of the payment type into v, usually an *ecobank.PaymentOptions
+// which gets typed params, so that it can be used as a template:
+//
+//	var opt ecobank.PaymentOptions
+//	if err := ecobanktest.LoadPayment(string(ecobank.DOMESTIC), &opt); err != nil { ... }
+//	opt.Extension[0].RequestID = "MY-REF-1"
+func LoadPayment(paymentType string, v any) error {
+	body, err := Payment(paymentType)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("ecobanktest: decoding payment fixture %s: %w", paymentType, err)
+	}
+
+	return nil
+}
+
+// MustLoadPayment is like LoadPayment but fails the test if the fixture does not exist or cannot be decoded.
+func MustLoadPayment(tb testing.TB, paymentType string, v any) {
+	tb.Helper()
+
+	if err := LoadPayment(paymentType, v); err != nil {
+		tb.Fatal(err)
+	}
+}
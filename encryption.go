@@ -0,0 +1,179 @@
+package ecobank
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// FieldEncrypter encrypts the value of a sensitive payment param, such as the secret code of a TOKEN payment.
+type FieldEncrypter func(plaintext string) (string, error)
+
+// WithEncryptionKey encrypts the sensitive params of payments with the public key provided by the bank,
+// using RSA-OAEP with SHA-256, and sends them base64 encoded. See WithFieldEncrypter for the encrypted params.
+func WithEncryptionKey(key *rsa.PublicKey) ClientOptionFunc {
+	return func(c *Client) error {
+		if key == nil {
+			return errors.New("encryption key must not be nil")
+		}
+		c.fieldEncrypter = RSAEncrypter(key)
+		return nil
+	}
+}
+
+// WithFieldEncrypter encrypts the sensitive params of payments with fn, for encryption schemes other than
+// the one of WithEncryptionKey.
+//
+// The params tagged with encrypt, such as the SecretCode of TokenTransferParams, are encrypted by
+// PaymentService.Pay when the payment is marshaled, along with the secretCode of params created from pairs.
+// The PaymentOptions passed to Pay are left unencrypted, so they can be resubmitted.
+func WithFieldEncrypter(fn FieldEncrypter) ClientOptionFunc {
+	return func(c *Client) error {
+		c.fieldEncrypter = fn
+		return nil
+	}
+}
+
+// RSAEncrypter returns a FieldEncrypter encrypting values with the public key using RSA-OAEP with SHA-256,
+// encoded with standard base64.
+func RSAEncrypter(key *rsa.PublicKey) FieldEncrypter {
+	return func(plaintext string) (string, error) {
+		ciphertext, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key, []byte(plaintext), nil)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(ciphertext), nil
+	}
+}
+
+// ParseRSAPublicKey parses a PEM encoded RSA public key, in PKIX or PKCS #1 form, as provided by the bank.
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded key found")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is a %T, not an RSA key", key)
+	}
+	return rsaKey, nil
+}
+
+// fieldEncrypter is implemented by payment params with fields to encrypt.
+type fieldEncrypter interface {
+	// encryptFields returns a copy of the params with the fields to encrypt encrypted.
+	encryptFields(encrypt FieldEncrypter) (PaymentParamInterface, error)
+}
+
+// encryptFields returns a copy of *PaymentParams with the fields tagged with encrypt encrypted.
+func (param *PaymentParams[T]) encryptFields(encrypt FieldEncrypter) (PaymentParamInterface, error) {
+	encrypted := *param
+	if err := encryptStructFields(reflect.ValueOf(&encrypted.param).Elem(), encrypt); err != nil {
+		return nil, err
+	}
+	return &encrypted, nil
+}
+
+// encryptFields returns a copy of custom params with the fields tagged with encrypt encrypted.
+func (param *customPaymentParams) encryptFields(encrypt FieldEncrypter) (PaymentParamInterface, error) {
+	v := reflect.New(reflect.TypeOf(param.param)).Elem()
+	v.Set(reflect.ValueOf(param.param))
+	if err := encryptStructFields(v, encrypt); err != nil {
+		return nil, err
+	}
+	return &customPaymentParams{param: v.Interface()}, nil
+}
+
+// encryptFields returns a copy of the pairs with the values of the keys encrypted in the typed params encrypted.
+func (pairs paymentParamPairs) encryptFields(encrypt FieldEncrypter) (PaymentParamInterface, error) {
+	encrypted := slices.Clone(pairs)
+	for i, pair := range encrypted {
+		if pair.Value == "" || !encryptedParamKeys()[strings.ToLower(pair.Key)] {
+			continue
+		}
+
+		value, err := encrypt(pair.Value)
+		if err != nil {
+			return nil, fmt.Errorf("encrypting %s: %w", pair.Key, err)
+		}
+		encrypted[i].Value = value
+	}
+	return encrypted, nil
+}
+
+// encryptStructFields encrypts the non-empty string fields of the param struct v tagged with encrypt.
+func encryptStructFields(v reflect.Value, encrypt FieldEncrypter) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for _, field := range paramFields(v.Type()) {
+		if _, ok := field.Tag.Lookup("encrypt"); !ok || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		// fields promoted through a nil embedded pointer are sent empty
+		fv, err := v.FieldByIndexErr(field.Index)
+		if err != nil || fv.String() == "" {
+			continue
+		}
+
+		value, err := encrypt(fv.String())
+		if err != nil {
+			return fmt.Errorf("encrypting %s: %w", field.key, err)
+		}
+		fv.SetString(value)
+	}
+	return nil
+}
+
+// encryptedParamKeys returns the keys of the typed payment params tagged with encrypt, in lower case,
+// which are used to encrypt raw param pairs.
+var encryptedParamKeys = sync.OnceValue(func() map[string]bool {
+	keys := make(map[string]bool)
+	for _, field := range paramFields(reflect.TypeFor[TokenTransferParams]()) {
+		if _, ok := field.Tag.Lookup("encrypt"); ok {
+			keys[strings.ToLower(field.key)] = true
+		}
+	}
+	return keys
+})
+
+// encryptPayment returns a copy of the payment with the sensitive params of its extensions encrypted.
+func encryptPayment(opt *PaymentOptions, encrypt FieldEncrypter) (*PaymentOptions, error) {
+	encrypted := *opt
+	encrypted.Extension = slices.Clone(opt.Extension)
+
+	for i, ext := range encrypted.Extension {
+		e, ok := ext.ParamList.(fieldEncrypter)
+		if !ok {
+			continue
+		}
+
+		params, err := e.encryptFields(encrypt)
+		if err != nil {
+			return nil, fmt.Errorf("extension %s: %w", ext.RequestID, err)
+		}
+		encrypted.Extension[i].ParamList = params
+	}
+	return &encrypted, nil
+}
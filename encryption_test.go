@@ -0,0 +1,110 @@
+package ecobank
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decryptField(t *testing.T, key *rsa.PrivateKey, value string) string {
+	t.Helper()
+
+	ciphertext, err := base64.StdEncoding.DecodeString(value)
+	require.NoError(t, err)
+	plaintext, err := rsa.DecryptOAEP(sha256.New(), nil, key, ciphertext, nil)
+	require.NoError(t, err)
+	return string(plaintext)
+}
+
+func TestWithEncryptionKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var payload []byte
+	client := newMockClient(t, `{"response_code": 200, "response_message": "success", "response_content": "success"}`, http.StatusOK)
+	require.NoError(t, WithEncryptionKey(&key.PublicKey)(client))
+	require.NoError(t, WithPaymentHooks(PaymentHooks{
+		OnSubmit: func(_ context.Context, event *PaymentEvent) error {
+			payload = event.Payload
+			return nil
+		},
+	})(client))
+
+	opt := &PaymentOptions{
+		PaymentHeader: PaymentHeader{BatchID: "EG1593490"},
+		Extension: []PaymentExtension{
+			{RequestID: "432", RequestType: TOKEN, ParamList: NewPaymentParams(TokenTransferParams{SecretCode: "123456", SourceAccount: "1441000574000"})},
+			{RequestID: "433", RequestType: TOKEN, ParamList: NewPaymentParamsFromPairs(PaymentParamPair{Key: "secretCode", Value: "654321"})},
+			{RequestID: "2323", RequestType: DOMESTIC, ParamList: NewPaymentParams(DomesticTransferParams{CreditAccountNo: "1441001996321"})},
+		},
+	}
+
+	_, _, err = client.Payment.Pay(t.Context(), opt)
+	require.NoError(t, err)
+
+	var sent PaymentOptions
+	require.NoError(t, json.Unmarshal(payload, &sent))
+
+	token := sent.Extension[0].ParamList.(*PaymentParams[TokenTransferParams]).Param()
+	assert.NotEqual(t, "123456", token.SecretCode)
+	assert.Equal(t, "123456", decryptField(t, key, token.SecretCode))
+	assert.Equal(t, "1441000574000", token.SourceAccount, "only the secret code is encrypted")
+
+	pairs, err := sent.Extension[1].ParamList.MarshalJSON()
+	require.NoError(t, err)
+	assert.NotContains(t, string(pairs), "654321")
+
+	domestic := sent.Extension[2].ParamList.(*PaymentParams[DomesticTransferParams]).Param()
+	assert.Equal(t, "1441001996321", domestic.CreditAccountNo)
+
+	original := opt.Extension[0].ParamList.(*PaymentParams[TokenTransferParams]).Param()
+	assert.Equal(t, "123456", original.SecretCode, "the options passed to Pay are not encrypted")
+}
+
+func TestEncryptStructFields_NilEmbeddedPointer(t *testing.T) {
+	type secret struct {
+		SecretCode string `json:"secretCode" encrypt:""`
+	}
+	type params struct {
+		*secret
+		SourceAccount string `json:"sourceAccount"`
+	}
+
+	param := &customPaymentParams{param: params{SourceAccount: "1441000574000"}}
+	encrypted, err := param.encryptFields(func(string) (string, error) {
+		t.Fatal("empty fields are not encrypted")
+		return "", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, params{SourceAccount: "1441000574000"}, encrypted.(*customPaymentParams).param)
+}
+
+func TestParseRSAPublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	for _, block := range []*pem.Block{
+		{Type: "PUBLIC KEY", Bytes: pkix},
+		{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)},
+	} {
+		parsed, err := ParseRSAPublicKey(pem.EncodeToMemory(block))
+		require.NoError(t, err, block.Type)
+		assert.True(t, key.PublicKey.Equal(parsed), block.Type)
+	}
+
+	_, err = ParseRSAPublicKey([]byte("not a key"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package ecobank
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// endpoint identifies an API operation by the client service and method name, e.g. Payment.GetBillerList.
+type endpoint struct {
+	service   string
+	operation string
+}
+
+func (e endpoint) String() string {
+	return e.service + "." + e.operation
+}
+
+// endpoints are the paths of the API operations, relative to the base URL.
+// Methods which stream the response of an operation, such as ForEachBiller, share its path.
+var endpoints = map[endpoint]string{
+	{"Auth", "GetAccessToken"}:         "user/token",
+	{"Account", "GetBalance"}:          "merchant/accountbalance",
+	{"Account", "Enquiry"}:             "merchant/accountinquiry",
+	{"Account", "EnquiryThirdParty"}:   "merchant/accountinquirythridpay",
+	{"Account", "GenerateStatement"}:   "merchant/statement",
+	{"Account", "CreateAccount"}:       "merchant/createexpressaccount",
+	{"Payment", "GetBillerList"}:       "payment/getbillerlist",
+	{"Payment", "GetBillerDetails"}:    "merchant/getbillerdetails",
+	{"Payment", "ValidateBiller"}:      "merchant/validatebiller",
+	{"Payment", "Pay"}:                 "merchant/payment",
+	{"Remittance", "ListInstitutions"}: "merchant/ecobankafrica/institutions",
+	{"Remittance", "GetAccount"}:       "merchant/ecobankafrica/account/enquiry",
+	{"Status", "GetTransactionStatus"}: "merchant/txns/status",
+	{"Status", "GetETokenStatus"}:      "merchant/etoken/status",
+}
+
+// WithEndpointOverride overrides the path of an API operation, identified by the client service and method
+// name, e.g. WithEndpointOverride("Payment", "GetBillerList", "merchant/getbillerlist"). It allows working
+// around endpoints moved by the gateway without waiting for a new release of the library.
+//
+// The path is relative to the base URL. Streaming methods share the path of the operation they stream,
+// e.g. ForEachBiller uses the path of GetBillerList. An error is returned for unknown operations.
+func WithEndpointOverride(service, operation, path string) ClientOptionFunc {
+	return func(c *Client) error {
+		e := endpoint{service, operation}
+		if _, ok := endpoints[e]; !ok {
+			return fmt.Errorf("unknown endpoint %s: must be one of %s", e, knownEndpoints())
+		}
+
+		path = strings.TrimPrefix(strings.TrimSpace(path), "/")
+		if path == "" {
+			return fmt.Errorf("path of endpoint %s must not be empty", e)
+		}
+
+		if c.endpointOverrides == nil {
+			c.endpointOverrides = make(map[endpoint]string)
+		}
+		c.endpointOverrides[e] = path
+		delete(c.endpointVersions, e)
+		return nil
+	}
+}
+
+// path returns the path of the operation of the service in the version set with WithAPIVersion, unless it
+// is overridden with WithEndpointOverride or WithEndpointVersion.
+func (c *Client) path(service, operation string) string {
+	e := endpoint{service, operation}
+	if _, ok := endpoints[e]; !ok {
+		panic("ecobank: unknown endpoint " + e.String())
+	}
+	return c.endpointPath(e)
+}
+
+// endpointPath returns the path of the known operation e.
+func (c *Client) endpointPath(e endpoint) string {
+	if path, ok := c.endpointOverrides[e]; ok {
+		return path
+	}
+	if c.apiVersion != "" && c.apiVersion != APIVersion1 {
+		return c.apiVersion + "/" + endpoints[e]
+	}
+	return endpoints[e]
+}
+
+// endpointsByPath returns the names of the operations of the client by their path.
+func (c *Client) endpointsByPath() map[string]string {
+	names := make(map[string]string, len(endpoints))
+	for e := range endpoints {
+		path, name := c.endpointPath(e), e.String()
+		// operations sharing a path are named after the first one, so that the name does not vary
+		if other, ok := names[path]; !ok || name < other {
+			names[path] = name
+		}
+	}
+	return names
+}
+
+// knownEndpoints returns the sorted names of the known endpoints.
+func knownEndpoints() string {
+	names := make([]string, 0, len(endpoints))
+	for e := range endpoints {
+		names = append(names, e.String())
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,48 @@
+package ecobank
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEndpointOverride(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithEndpointOverride("Payment", "GetBillerList", "/merchant/getbillerlist")(client))
+
+	var paths []string
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			paths = append(paths, req.URL.Path)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"response_code": 200, "response_message": "success", "response_content": {"billerInfo": []}}`)),
+			}, nil
+		},
+	}
+
+	_, _, err := client.Payment.GetBillerList(t.Context(), &GetBillerListOptions{})
+	require.NoError(t, err)
+	_, err = client.Payment.ForEachBiller(t.Context(), &GetBillerListOptions{}, func(*BillerInfo) error { return nil })
+	require.NoError(t, err)
+	_, _, err = client.Payment.GetBillerDetails(t.Context(), &GetBillerDetailsOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, paths, 3)
+	assert.True(t, strings.HasSuffix(paths[0], "/corporateapi/merchant/getbillerlist"), paths[0])
+	assert.Equal(t, paths[0], paths[1], "streaming methods share the path of the operation")
+	assert.True(t, strings.HasSuffix(paths[2], "/corporateapi/merchant/getbillerdetails"), paths[2])
+}
+
+func TestWithEndpointOverride_Invalid(t *testing.T) {
+	_, err := NewClient("user", "password", "lab-key", WithEndpointOverride("Payment", "GetBillers", "merchant/billers"))
+	assert.ErrorContains(t, err, "unknown endpoint Payment.GetBillers: must be one of Account.CreateAccount, ")
+
+	_, err = NewClient("user", "password", "lab-key", WithEndpointOverride("Payment", "Pay", " / "))
+	assert.EqualError(t, err, "path of endpoint Payment.Pay must not be empty")
+}
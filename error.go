@@ -1,6 +1,44 @@
 package ecobank
 
-import "strings"
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Sentinel errors matched by ResponseError, for use with errors.Is.
+var (
+	// ErrInvalidHash is returned when the API rejects the secure hash of a request.
+	ErrInvalidHash = errors.New("invalid secure hash")
+	// ErrDuplicateRequest is returned when the API rejects a request ID which was already used.
+	ErrDuplicateRequest = errors.New("duplicate request")
+	// ErrInsufficientBalance is returned when the account has insufficient funds for a transaction.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrTemporaryFailure is returned when the API reports a temporary failure of the host which did not
+	// process the request, so it can be retried. Timeouts are not temporary failures, since the request may
+	// have been processed. See DefaultRetryClassifier.
+	ErrTemporaryFailure = errors.New("temporary failure")
+)
+
+// sentinelMessages maps the sentinel errors to the lower case phrases the API uses for them.
+var sentinelMessages = map[error][]string{
+	ErrInvalidHash:         {"invalid secure hash", "invalid hash", "secure hash mismatch"},
+	ErrDuplicateRequest:    {"duplicate request", "duplicate transaction", "request id already exists", "requestid already exists"},
+	ErrInsufficientBalance: {"insufficient balance", "insufficient funds"},
+	ErrTemporaryFailure:    {"temporary failure", "temporarily unavailable", "host unavailable", "host not available", "try again later"},
+}
+
+// fieldErrorVerbs are the words that follow the field name in a field error, e.g. "accountNo is required".
+var fieldErrorVerbs = []string{"is", "are", "must", "should", "cannot", "can't", "may", "has", "does"}
 
 // ResponseError represents a collection of error messages.
 type ResponseError []string
@@ -36,3 +74,188 @@ func (e *ResponseError) Len() int {
 func (e *ResponseError) String() string {
 	return e.Error()
 }
+
+// UnmarshalJSON decodes the errors of an API response. The gateway returns them as a string, an array
+// or an object of field names to messages, which are all decoded into the list of messages. Object
+// entries are formatted as "<field>: <message>" so they are available from FieldErrors.
+//
+// Values which are not understood are kept as raw JSON instead of failing the decoding of the response.
+func (e *ResponseError) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		*e = ResponseError{string(b)}
+		return nil
+	}
+
+	switch v := v.(type) {
+	case []any:
+		// keep an empty array as an empty, non-nil list which is still reported as an error
+		*e = make(ResponseError, 0, len(v))
+		for _, item := range v {
+			e.addValue("", item)
+		}
+	default:
+		*e = nil
+		e.addValue("", v)
+	}
+	return nil
+}
+
+// addValue adds the messages of a decoded JSON value, prefixing them with field if set.
+func (e *ResponseError) addValue(field string, v any) {
+	switch v := v.(type) {
+	case nil:
+	case string:
+		if v == "" {
+			return
+		}
+		if field != "" {
+			v = field + ": " + v
+		}
+		e.Add(v)
+	case []any:
+		for _, item := range v {
+			e.addValue(field, item)
+		}
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			switch strings.ToLower(key) {
+			case "message", "msg", "error", "description", "detail":
+				// the message of an error object rather than a field name
+				e.addValue(field, v[key])
+			default:
+				name := key
+				if field != "" {
+					name = field + "." + key
+				}
+				e.addValue(name, v[key])
+			}
+		}
+	default:
+		b, _ := json.Marshal(v)
+		e.addValue(field, string(b))
+	}
+}
+
+// Is reports whether any of the error messages matches target, which is one of
+// ErrInvalidHash, ErrDuplicateRequest or ErrInsufficientBalance.
+func (e *ResponseError) Is(target error) bool {
+	phrases, ok := sentinelMessages[target]
+	if !ok {
+		return false
+	}
+
+	for _, msg := range e.All() {
+		msg = strings.ToLower(msg)
+		for _, phrase := range phrases {
+			if strings.Contains(msg, phrase) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FieldErrors returns the error messages which refer to a request field, such as "accountNo is required",
+// keyed by the field name. Messages which do not refer to a field are only available from All.
+func (e *ResponseError) FieldErrors() map[string][]string {
+	var fields map[string][]string
+	for _, msg := range e.All() {
+		field, ok := parseErrorField(msg)
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string][]string)
+		}
+		fields[field] = append(fields[field], msg)
+	}
+	return fields
+}
+
+// parseErrorField returns the field name an error message starts with.
+// It accepts messages of the form "<field>: <message>" and "<field> is required" and the like.
+func parseErrorField(msg string) (string, bool) {
+	first, rest, _ := strings.Cut(strings.TrimSpace(msg), " ")
+	if name, ok := strings.CutSuffix(first, ":"); ok && isFieldName(name) {
+		return name, true
+	}
+	if !isFieldName(first) {
+		return "", false
+	}
+
+	next, _, _ := strings.Cut(rest, " ")
+	for _, verb := range fieldErrorVerbs {
+		if strings.EqualFold(next, verb) {
+			return first, true
+		}
+	}
+	return "", false
+}
+
+// isFieldName reports whether s looks like a JSON field name of the API, e.g. accountNo or account.no.
+func isFieldName(s string) bool {
+	if s == "" || !unicode.IsLower(rune(s[0])) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// gatewaySnippetSize is the number of bytes of a non-JSON response body kept in a GatewayError.
+const gatewaySnippetSize = 512
+
+// GatewayError is returned when the gateway responds with something other than a JSON API response,
+// such as an HTML maintenance or firewall page.
+type GatewayError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// ContentType is the Content-Type header of the response.
+	ContentType string
+	// Snippet holds the start of the response body.
+	Snippet string
+}
+
+// Error implements the error interface.
+func (e *GatewayError) Error() string {
+	return fmt.Sprintf("unexpected gateway response (status %d, content type %q): %s", e.StatusCode, e.ContentType, e.Snippet)
+}
+
+// checkGatewayResponse returns a GatewayError if the response body is not JSON.
+//
+// The gateway does not always label JSON responses correctly, so unless the content type is JSON
+// the start of the body is inspected. The body of resp is replaced so it can still be read in full.
+func checkGatewayResponse(resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+
+	br := bufio.NewReaderSize(resp.Body, gatewaySnippetSize)
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, resp.Body}
+
+	peek, _ := br.Peek(gatewaySnippetSize)
+	trimmed := bytes.TrimSpace(peek)
+	if len(trimmed) == 0 || trimmed[0] == '{' || trimmed[0] == '[' {
+		return nil
+	}
+
+	return &GatewayError{
+		StatusCode:  resp.StatusCode,
+		ContentType: contentType,
+		Snippet:     string(trimmed),
+	}
+}
@@ -1,10 +1,16 @@
 package ecobank
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResponseError_Add(t *testing.T) {
@@ -122,3 +128,174 @@ func TestResponseError_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseError_Is(t *testing.T) {
+	testCases := []struct {
+		name   string
+		errors ResponseError
+		target error
+		want   bool
+	}{
+		{"invalid hash", ResponseError{"invalid secure hash"}, ErrInvalidHash, true},
+		{"duplicate request", ResponseError{"Account not found", "Duplicate transaction reference"}, ErrDuplicateRequest, true},
+		{"insufficient balance", ResponseError{"Insufficient funds in account"}, ErrInsufficientBalance, true},
+		{"other message", ResponseError{"Account not found"}, ErrInvalidHash, false},
+		{"unknown target", ResponseError{"invalid secure hash"}, ErrDuplicatePayment, false},
+		{"no errors", ResponseError{}, ErrInvalidHash, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error = &tc.errors
+			assert.Equal(t, tc.want, errors.Is(err, tc.target))
+			assert.Equal(t, tc.want, errors.Is(fmt.Errorf("wrapped: %w", err), tc.target))
+		})
+	}
+}
+
+func TestResponseError_FieldErrors(t *testing.T) {
+	err := ResponseError{
+		"accountNo is required",
+		"amount must be greater than zero",
+		"accountNo: must be 13 digits",
+		"invalid secure hash",
+		"Invalid username or password",
+		"ccy_code is not supported",
+	}
+
+	assert.Equal(t, map[string][]string{
+		"accountNo": {"accountNo is required", "accountNo: must be 13 digits"},
+		"amount":    {"amount must be greater than zero"},
+		"ccy_code":  {"ccy_code is not supported"},
+	}, err.FieldErrors())
+	assert.Len(t, err.All(), 6)
+
+	assert.Nil(t, (&ResponseError{"Account not found"}).FieldErrors())
+}
+
+func TestResponseError_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected ResponseError
+	}{
+		{"null", `null`, nil},
+		{"empty string", `""`, nil},
+		{"string", `"Account not found"`, ResponseError{"Account not found"}},
+		{"empty array", `[]`, ResponseError{}},
+		{"array", `["Error A", "Error B"]`, ResponseError{"Error A", "Error B"}},
+		{
+			name:     "object",
+			input:    `{"amount": "amount must be greater than zero", "accountNo": ["is required", "must be 13 digits"]}`,
+			expected: ResponseError{"accountNo: is required", "accountNo: must be 13 digits", "amount: amount must be greater than zero"},
+		},
+		{
+			name:     "array of objects",
+			input:    `[{"field": "accountNo", "message": "is required"}, {"message": "invalid secure hash"}]`,
+			expected: ResponseError{"field: accountNo", "is required", "invalid secure hash"},
+		},
+		{"nested object", `{"payment": {"amount": "is required"}}`, ResponseError{"payment.amount: is required"}},
+		{"number", `42`, ResponseError{"42"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err ResponseError
+			require.NoError(t, json.Unmarshal([]byte(tc.input), &err))
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+func TestDo_PolymorphicErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		errors   string
+		expected ResponseError
+	}{
+		{"string", `"invalid secure hash"`, ResponseError{"invalid secure hash"}},
+		{"array", `["invalid secure hash"]`, ResponseError{"invalid secure hash"}},
+		{"object", `{"accountNo": "is required"}`, ResponseError{"accountNo: is required"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newMockClient(t, `{"response_code": 400, "response_message": "error", "errors": `+tc.errors+`}`, http.StatusBadRequest)
+
+			req, err := client.NewRequest(context.Background(), http.MethodPost, "test", nil)
+			require.NoError(t, err)
+
+			var v map[string]any
+			resp, err := client.Do(req, &v)
+
+			var respErr *ResponseError
+			require.ErrorAs(t, err, &respErr)
+			assert.Equal(t, tc.expected, *respErr)
+			assert.JSONEq(t, tc.errors, string(resp.RawErrors))
+			assert.Equal(t, 400, resp.Code)
+		})
+	}
+
+	t.Run("empty string", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 200, "response_message": "success", "errors": ""}`, http.StatusOK)
+
+		req, err := client.NewRequest(context.Background(), http.MethodPost, "test", nil)
+		require.NoError(t, err)
+
+		var v map[string]any
+		_, err = client.Do(req, &v)
+		assert.NoError(t, err)
+	})
+}
+
+func TestGatewayError(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     bool
+	}{
+		{
+			name:        "json",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"response_code": 0, "response_content": {}}`,
+		},
+		{
+			name:        "mislabeled json",
+			contentType: "text/plain",
+			body:        ` {"response_code": 0, "response_content": {}}`,
+		},
+		{
+			name:        "html maintenance page",
+			contentType: "text/html",
+			body:        "<html><body>Service under maintenance</body></html>",
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newMockClient(t, "", http.StatusOK)
+			client.client.HTTPClient.Transport = &mockHTTPClient{
+				requestHandler: func(req *http.Request) (*http.Response, error) {
+					resp := httptest.NewRecorder()
+					resp.Header().Set("Content-Type", tc.contentType)
+					_, err := resp.WriteString(tc.body)
+					return resp.Result(), err
+				},
+			}
+
+			_, _, err := DoRequest[struct{}](context.Background(), client, http.MethodPost, "merchant/accountbalance", nil)
+			if !tc.wantErr {
+				require.NoError(t, err)
+				return
+			}
+
+			var gwErr *GatewayError
+			require.True(t, errors.As(err, &gwErr))
+			assert.Equal(t, http.StatusOK, gwErr.StatusCode)
+			assert.Equal(t, tc.contentType, gwErr.ContentType)
+			assert.Equal(t, tc.body, gwErr.Snippet)
+		})
+	}
+}
@@ -34,15 +34,15 @@ func main() {
 		Middlename:         "",
 		Lastname:           "Akinola",
 		MobileNo:           "2348089991325",
-		Gender:             "M",
+		Gender:             ecobank.GenderMale,
 		IdentityNo:         "198837383982",
-		IdentityType:       "MOBILE_WALLET_NO",
-		IDIssueDate:        "01072021",
-		IDExpiryDate:       "01072021",
+		IdentityType:       ecobank.IdentityTypeMobileWalletNo,
+		IDIssueDate:        ecobank.NewAccountDate(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)),
+		IDExpiryDate:       ecobank.NewAccountDate(time.Date(2031, 7, 1, 0, 0, 0, 0, time.UTC)),
 		Ccy:                "NGN",
 		Country:            "NGN",
 		BranchCode:         "ENG",
-		DateOfBirth:        "01072021",
+		DateOfBirth:        ecobank.NewAccountDate(time.Date(1990, 7, 1, 0, 0, 0, 0, time.UTC)),
 		CountryOfResidence: "NIGERIA",
 		Email:              "treknfreedom@yahoo.com",
 		Street:             "Labone",
@@ -51,7 +51,6 @@ func main() {
 		Image:              "oeyetweuiww8262822999999999",
 		Signature:          "orjerjeklellwewpw726527289292",
 	}
-	createOpts.SetHash("a43aa74662060b7b9c942dd7ace565a0919118db758bcd71a0f5c7cd7e349f6309b02866b6156ef9171a1b23119c71e77db2edd38cc89963d7f34b541d6dc461")
 	account, resp, err := client.Account.CreateAccount(ctx, createOpts)
 	checkErr(errors.Wrap(err, "failed to create account"))
 
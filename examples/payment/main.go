@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/pkg/errors"
 	"github.com/profclems/go-ecobank"
@@ -24,21 +23,15 @@ func main() {
 	err = client.Login(ctx)
 	checkErr(errors.Wrap(err, "failed to login"))
 
+	header, err := ecobank.NewPaymentHeader("EGHTelc000043", "EGH", "EG1593490", "E12T443308", decimal.NewFromInt(520), 6)
+	checkErr(errors.Wrap(err, "invalid payment header"))
+	header.DebitType = ecobank.DebitTypeMultiple
+	executionDate, err := client.Payment.NextBusinessDay("EGH")
+	checkErr(errors.Wrap(err, "failed to schedule payment"))
+	header.ExecutionDate = ecobank.NewTime(executionDate)
+
 	req := &ecobank.PaymentOptions{
-		PaymentHeader: ecobank.PaymentHeader{
-			ClientID:          "EGHTelc000043",
-			BatchSequence:     "1",
-			BatchAmount:       decimal.NewFromInt(520),
-			Transactionamount: decimal.NewFromInt(520),
-			BatchID:           "EG1593490",
-			TransactionCount:  6,
-			BatchCount:        6,
-			TransactionID:     "E12T443308",
-			DebitType:         "Multiple",
-			AffiliateCode:     "EGH",
-			TotalBatches:      "1",
-			ExecutionDate:     ecobank.NewTime(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)),
-		},
+		PaymentHeader: header,
 		Extension: []ecobank.PaymentExtension{
 			{
 				RequestID:   "2323",
@@ -72,7 +65,7 @@ func main() {
 					SenderID:               "QWE345Y4",
 					BeneficiaryName:        "Stephen Kojo",
 					BeneficiaryMobileNo:    "0233445566",
-					WithdrawalChannel:      "ATM",
+					WithdrawalChannel:      ecobank.WithdrawalChannelATM,
 				}),
 				Amount:   decimal.NewFromInt(40),
 				Currency: "GHS",
@@ -165,11 +158,15 @@ func main() {
 	req.SetHash("398d4f285cc33e12f035da19fa9d954be35afaf66816531c4f1a1aedd3c6f132a85c62b23ca12d7b9a99bf5a84fc69b66738289a70e8f8115e90ffaa060f4026")
 
 	// make payment
-	paymentStatus, resp, err := client.Payment.Pay(ctx, req)
+	ack, resp, err := client.Payment.Pay(ctx, req)
 
 	checkErr(errors.Wrap(err, "failed to make payment"))
 
-	fmt.Printf("Payment status: %v\n", *paymentStatus)
+	fmt.Printf("Payment accepted: %v\n", ack.Accepted())
+	fmt.Printf("Batch ID: %v\n", ack.BatchID)
+	for _, ext := range ack.Extensions {
+		fmt.Printf("Request %s (%s)\n", ext.RequestID, ext.RequestType)
+	}
 	fmt.Printf("Code: %+v\n", resp.Code)
 	fmt.Printf("Message: %+v\n", resp.Message)
 	fmt.Printf("HTTP Status: %+v\n", resp.Status)
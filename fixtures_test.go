@@ -0,0 +1,245 @@
+package ecobank_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/profclems/go-ecobank"
+	"github.com/profclems/go-ecobank/ecobanktest"
+)
+
+// fixtureCalls calls the client method for every endpoint with fixtures.
+var fixtureCalls = map[string]func(ctx context.Context, c *ecobank.Client) error{
+	"user/token": func(ctx context.Context, c *ecobank.Client) error {
+		return c.Login(ctx)
+	},
+	"merchant/accountbalance": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Account.GetBalance(ctx, &ecobank.AccountBalanceOptions{})
+		return err
+	},
+	"merchant/accountinquiry": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Account.Enquiry(ctx, &ecobank.AccountEnquiryOptions{})
+		return err
+	},
+	"merchant/accountinquirythridpay": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Account.EnquiryThirdParty(ctx, &ecobank.AccountEnquiryThirdPartyOptions{})
+		return err
+	},
+	"merchant/statement": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Account.GenerateStatement(ctx, &ecobank.GenerateStatementOptions{})
+		return err
+	},
+	"merchant/createexpressaccount": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Account.CreateAccount(ctx, &ecobank.CreateAccountOptions{
+			Gender:       ecobank.GenderFemale,
+			IdentityType: ecobank.IdentityTypeNationalID,
+			DateOfBirth:  ecobank.NewAccountDate(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)),
+		})
+		return err
+	},
+	"payment/getbillerlist": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Payment.GetBillerList(ctx, &ecobank.GetBillerListOptions{})
+		return err
+	},
+	"merchant/getbillerdetails": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Payment.GetBillerDetails(ctx, &ecobank.GetBillerDetailsOptions{})
+		return err
+	},
+	"merchant/validatebiller": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Payment.ValidateBiller(ctx, &ecobank.ValidateBillerOptions{})
+		return err
+	},
+	"merchant/payment": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Payment.Pay(ctx, &ecobank.PaymentOptions{})
+		return err
+	},
+	"merchant/ecobankafrica/institutions": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Remittance.ListInstitutions(ctx, &ecobank.ListInstitutionsOptions{})
+		return err
+	},
+	"merchant/ecobankafrica/account/enquiry": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Remittance.GetAccount(ctx, &ecobank.GetRemitteeAccountOptions{})
+		return err
+	},
+	"merchant/txns/status": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Status.GetTransactionStatus(ctx, &ecobank.StatusOptions{})
+		return err
+	},
+	"merchant/etoken/status": func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Status.GetETokenStatus(ctx, &ecobank.ETokenStatusOptions{})
+		return err
+	},
+	ecobanktest.GatewayEndpoint: func(ctx context.Context, c *ecobank.Client) error {
+		_, _, err := c.Account.GetBalance(ctx, &ecobank.AccountBalanceOptions{})
+		return err
+	},
+}
+
+func TestFixtures(t *testing.T) {
+	fixtures, err := ecobanktest.All()
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures)
+
+	for _, f := range fixtures {
+		t.Run(f.Endpoint+"/"+f.Name, func(t *testing.T) {
+			call, ok := fixtureCalls[f.Endpoint]
+			require.True(t, ok, "no client method for endpoint %s", f.Endpoint)
+
+			srv := httptest.NewServer(f)
+			t.Cleanup(srv.Close)
+
+			client, err := ecobank.NewClient("user", "password", "lab-key",
+				ecobank.WithBaseURL(srv.URL),
+				ecobank.WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+				ecobank.WithDisableRetries(),
+			)
+			require.NoError(t, err)
+
+			err = call(t.Context(), client)
+
+			var (
+				respErr    *ecobank.ResponseError
+				gatewayErr *ecobank.GatewayError
+			)
+			switch {
+			case f.Endpoint == ecobanktest.GatewayEndpoint:
+				assert.True(t, errors.As(err, &gatewayErr), "expected a GatewayError, got %v", err)
+			case f.StatusCode >= http.StatusBadRequest && f.Endpoint != "user/token":
+				assert.True(t, errors.As(err, &respErr), "expected a ResponseError, got %v", err)
+				if f.Name == "invalid_secure_hash" {
+					assert.ErrorIs(t, err, ecobank.ErrInvalidHash)
+				}
+			case f.StatusCode >= http.StatusBadRequest:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFixturesCoverEndpoints(t *testing.T) {
+	for endpoint := range fixtureCalls {
+		fixtures, err := ecobanktest.Fixtures(endpoint)
+		require.NoError(t, err)
+		assert.NotEmpty(t, fixtures, endpoint)
+	}
+}
+
+func TestPaymentFixtures(t *testing.T) {
+	paymentTypes := []ecobank.PaymentType{
+		ecobank.DOMESTIC, ecobank.TOKEN, ecobank.INTERBANK, ecobank.BILLPAYMENT, ecobank.AIRTIMETOPUP,
+		ecobank.MOMO, ecobank.TOKENIA, ecobank.INTERBANKIA, ecobank.MOMOIA,
+	}
+	assert.Len(t, ecobanktest.PaymentTypes(), len(paymentTypes))
+
+	srv := ecobanktest.StartGateway(t, &ecobanktest.Gateway{})
+	client, err := ecobank.NewClient("user", "password", "lab-key",
+		ecobank.WithBaseURL(srv.URL),
+		ecobank.WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+	)
+	require.NoError(t, err)
+
+	for _, paymentType := range paymentTypes {
+		t.Run(string(paymentType), func(t *testing.T) {
+			var opt ecobank.PaymentOptions
+			ecobanktest.MustLoadPayment(t, string(paymentType), &opt)
+
+			require.NoError(t, opt.Check())
+			require.Len(t, opt.Extension, 1)
+			assert.Equal(t, paymentType, opt.Extension[0].RequestType)
+
+			_, _, err := client.Payment.Pay(t.Context(), &opt)
+			assert.NoError(t, err)
+		})
+	}
+
+	err = ecobanktest.LoadPayment("CHEQUE", &ecobank.PaymentOptions{})
+	assert.EqualError(t, err, `ecobanktest: no payment fixture for payment type "CHEQUE"`)
+}
+
+func TestGateway(t *testing.T) {
+	gw := &ecobanktest.Gateway{Latency: time.Millisecond, Jitter: time.Millisecond, ErrorRate: 0.5, Seed: 1}
+	srv := ecobanktest.StartGateway(t, gw)
+
+	client, err := ecobank.NewClient("user", "password", "lab-key",
+		ecobank.WithBaseURL(srv.URL),
+		ecobank.WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+		ecobank.WithDisableRetries(),
+	)
+	require.NoError(t, err)
+
+	var failed int64
+	for range 20 {
+		_, _, err := client.Account.GetBalance(t.Context(), &ecobank.AccountBalanceOptions{})
+		if err != nil {
+			var gatewayErr *ecobank.GatewayError
+			require.ErrorAs(t, err, &gatewayErr)
+			failed++
+		}
+	}
+
+	assert.Positive(t, failed)
+	assert.Less(t, failed, int64(20))
+	assert.Equal(t, ecobanktest.GatewayStats{Requests: 20, Errors: failed}, gw.Stats())
+}
+
+func TestGateway_Responses(t *testing.T) {
+	gw := &ecobanktest.Gateway{
+		Responses: map[string]http.Handler{
+			"merchant/accountbalance": ecobanktest.MustLoad(t, "merchant/accountbalance", "invalid_secure_hash"),
+		},
+	}
+	srv := ecobanktest.StartGateway(t, gw)
+
+	client, err := ecobank.NewClient("user", "password", "lab-key",
+		ecobank.WithBaseURL(srv.URL),
+		ecobank.WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.Account.GetBalance(t.Context(), &ecobank.AccountBalanceOptions{})
+	assert.ErrorIs(t, err, ecobank.ErrInvalidHash)
+
+	_, _, err = client.Status.GetTransactionStatus(t.Context(), &ecobank.StatusOptions{})
+	assert.NoError(t, err)
+}
+
+// BenchmarkGateway_Pay measures the throughput of payments submitted concurrently to a gateway
+// responding in 5ms on average.
+func BenchmarkGateway_Pay(b *testing.B) {
+	gw := &ecobanktest.Gateway{Latency: 5 * time.Millisecond, Jitter: 2 * time.Millisecond}
+	srv := ecobanktest.StartGateway(b, gw)
+
+	client, err := ecobank.NewClient("user", "password", "lab-key",
+		ecobank.WithBaseURL(srv.URL),
+		ecobank.WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+		ecobank.WithTransportTuning(100, time.Minute, false),
+	)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			opt := &ecobank.PaymentOptions{
+				PaymentHeader: ecobank.PaymentHeader{BatchID: "EG1593490", ClientID: "EGHTelc000043"},
+				Extension: []ecobank.PaymentExtension{{
+					RequestID:   "2323",
+					RequestType: ecobank.DOMESTIC,
+					ParamList:   ecobank.NewPaymentParams(ecobank.DomesticTransferParams{CreditAccountNo: "1441000574000"}),
+				}},
+			}
+			if _, _, err := client.Payment.Pay(b.Context(), opt); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+	b.ReportMetric(float64(gw.Stats().Requests)/b.Elapsed().Seconds(), "req/s")
+}
@@ -0,0 +1,193 @@
+package ecobank
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sort"
+)
+
+// FormDataArray is the form data of a bill payment, a list of field names and values. A field name is
+// only given once: the helpers replace the value of a field which is already set, keeping its position,
+// and the array is marshaled without duplicates, so that payloads and their secure hashes only depend on
+// the fields and the order they are first set in.
+type FormDataArray []FormData
+
+// FormData represents a fieldName and fieldValue pair.
+type FormData struct {
+	FieldName  string `json:"fieldName"`
+	FieldValue string `json:"fieldValue"`
+}
+
+// NewFormDataArray returns form data with the given fields. A field given more than once is kept at its
+// first position, with its last value.
+func NewFormDataArray(fields ...FormData) FormDataArray {
+	var a FormDataArray
+	for _, f := range fields {
+		a.Set(f.FieldName, f.FieldValue)
+	}
+	return a
+}
+
+// FormDataFromMap returns form data with the values of the map. The fields listed in order come first,
+// in that order, followed by the other fields sorted by name, since maps are not ordered. The fields in
+// order which are not in the map are left out.
+func FormDataFromMap(values map[string]string, order ...string) FormDataArray {
+	a := make(FormDataArray, 0, len(values))
+	for _, name := range order {
+		if value, ok := values[name]; ok {
+			a.Set(name, value)
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if !slices.Contains(order, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		a = append(a, FormData{FieldName: name, FieldValue: values[name]})
+	}
+	return a
+}
+
+// Get returns the value of the field with the given name.
+func (a FormDataArray) Get(name string) (string, bool) {
+	if i := a.index(name); i >= 0 {
+		return a[i].FieldValue, true
+	}
+	return "", false
+}
+
+// Set sets the value of a field, replacing the value of the field if it is already set, or appending it.
+func (a *FormDataArray) Set(name, value string) {
+	if i := a.index(name); i >= 0 {
+		(*a)[i].FieldValue = value
+		return
+	}
+	*a = append(*a, FormData{FieldName: name, FieldValue: value})
+}
+
+// Merge returns the form data with the fields of other set on a copy of a: the fields of a keep their
+// positions, with the values of other if it sets them, followed by the other fields of other.
+func (a FormDataArray) Merge(other FormDataArray) FormDataArray {
+	merged := NewFormDataArray(a...)
+	for _, f := range other {
+		merged.Set(f.FieldName, f.FieldValue)
+	}
+	return merged
+}
+
+// FormFieldFunc builds form data, e.g. the FormFields of ValidateBillerOptions.
+type FormFieldFunc func(*FormDataArray) error
+
+// BuildFormData returns a copy of the form data with the fields built by fns, in order.
+func BuildFormData(a FormDataArray, fns ...FormFieldFunc) (FormDataArray, error) {
+	built := NewFormDataArray(a...)
+	for _, fn := range fns {
+		if err := fn(&built); err != nil {
+			return nil, err
+		}
+	}
+	return built, nil
+}
+
+// FormField sets the value of a field.
+func FormField(name, value string) FormFieldFunc {
+	return func(a *FormDataArray) error {
+		a.Set(name, value)
+		return nil
+	}
+}
+
+// FormFields sets the values of the map, as with FormDataFromMap.
+func FormFields(values map[string]string, order ...string) FormFieldFunc {
+	return func(a *FormDataArray) error {
+		*a = a.Merge(FormDataFromMap(values, order...))
+		return nil
+	}
+}
+
+// ValidForm validates the form data built so far against the form of a biller and replaces it with the
+// form data ready to be sent, see BillerForm.FormData.
+func ValidForm(form *BillerForm) FormFieldFunc {
+	return func(a *FormDataArray) error {
+		data, err := form.FormData(*a)
+		if err != nil {
+			return err
+		}
+		*a = data
+		return nil
+	}
+}
+
+func (a FormDataArray) index(name string) int {
+	return slices.IndexFunc(a, func(f FormData) bool { return f.FieldName == name })
+}
+
+// MarshalJSON implements the json.Marshaler interface for FormDataArray. The fields are marshaled in order,
+// and a field given more than once is marshaled at its first position with its last value, as with
+// NewFormDataArray.
+func (a FormDataArray) MarshalJSON() ([]byte, error) {
+	if a == nil {
+		return []byte("null"), nil
+	}
+	return a.appendJSON(nil), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for FormDataArray. Both a JSON array and the
+// stringified array sent in param lists are accepted.
+func (a *FormDataArray) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			*a = nil
+			return nil
+		}
+		b = []byte(s)
+	}
+
+	var fields []FormData
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	*a = fields
+	return nil
+}
+
+// appendJSON appends the fields to dst as a JSON array of objects with a fieldName and fieldValue,
+// which is how they are sent in param lists.
+func (a FormDataArray) appendJSON(dst []byte) []byte {
+	if a.hasDuplicates() {
+		a = NewFormDataArray(a...)
+	}
+
+	dst = append(dst, '[')
+	for i, fd := range a {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, `{"fieldName": `...)
+		dst = appendQuotedJSON(dst, fd.FieldName)
+		dst = append(dst, `, "fieldValue": `...)
+		dst = appendQuotedJSON(dst, fd.FieldValue)
+		dst = append(dst, '}')
+	}
+	return append(dst, ']')
+}
+
+func (a FormDataArray) hasDuplicates() bool {
+	for i := range a {
+		if a[:i].index(a[i].FieldName) >= 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormDataArray(t *testing.T) {
+	a := NewFormDataArray(
+		FormData{FieldName: "LastName", FieldValue: "Kojo"},
+		FormData{FieldName: "FirstName", FieldValue: "Kwame"},
+		FormData{FieldName: "LastName", FieldValue: "Mensah"},
+	)
+	assert.Equal(t, FormDataArray{{"LastName", "Mensah"}, {"FirstName", "Kwame"}}, a)
+
+	a.Set("Amount", "300")
+	a.Set("FirstName", "Ama")
+	assert.Equal(t, FormDataArray{{"LastName", "Mensah"}, {"FirstName", "Ama"}, {"Amount", "300"}}, a)
+
+	value, ok := a.Get("Amount")
+	assert.True(t, ok)
+	assert.Equal(t, "300", value)
+	_, ok = a.Get("amount")
+	assert.False(t, ok, "field names are case-sensitive")
+
+	merged := a.Merge(FormDataArray{{"Phone", "0241234567"}, {"LastName", "Owusu"}})
+	assert.Equal(t, FormDataArray{{"LastName", "Owusu"}, {"FirstName", "Ama"}, {"Amount", "300"}, {"Phone", "0241234567"}}, merged)
+	assert.Equal(t, "Mensah", a[0].FieldValue, "merging does not modify the form data")
+
+	var empty FormDataArray
+	empty.Set("Phone", "0241234567")
+	assert.Equal(t, FormDataArray{{"Phone", "0241234567"}}, empty)
+}
+
+func TestFormDataFromMap(t *testing.T) {
+	values := map[string]string{"Phone": "0241234567", "Email": "kojo@example.com", "Amount": "300", "LastName": "Kojo"}
+
+	for range 10 {
+		a := FormDataFromMap(values, "LastName", "Amount", "Reference")
+		assert.Equal(t, FormDataArray{{"LastName", "Kojo"}, {"Amount", "300"}, {"Email", "kojo@example.com"}, {"Phone", "0241234567"}}, a)
+	}
+}
+
+func TestFormDataArray_MarshalJSON(t *testing.T) {
+	a := FormDataArray{{"LastName", "Kojo"}, {"Amount", "300"}, {"LastName", "Mensah"}}
+
+	b, err := json.Marshal(a)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"fieldName": "LastName", "fieldValue": "Mensah"}, {"fieldName": "Amount", "fieldValue": "300"}]`, string(b))
+
+	b, err = json.Marshal(FormDataArray(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(b))
+
+	// duplicates are left out of param lists too, so the payload does not depend on how the form data was built
+	withDuplicates, err := NewPaymentParams(BillPaymentParams{BillerCode: "DSTV", FormDataValue: a}).MarshalJSON()
+	require.NoError(t, err)
+	withoutDuplicates, err := NewPaymentParams(BillPaymentParams{BillerCode: "DSTV", FormDataValue: NewFormDataArray(a...)}).MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, string(withoutDuplicates), string(withDuplicates))
+
+	var params PaymentParams[BillPaymentParams]
+	require.NoError(t, json.Unmarshal(withDuplicates, &params))
+	assert.Equal(t, FormDataArray{{"LastName", "Mensah"}, {"Amount", "300"}}, params.Param().FormDataValue)
+}
+
+func TestFormDataArray_UnmarshalJSON(t *testing.T) {
+	for _, input := range []string{
+		`[{"fieldName":"LastName","fieldValue":"Kojo"}]`,
+		`"[{\"fieldName\": \"LastName\", \"fieldValue\": \"Kojo\"}]"`,
+	} {
+		var a FormDataArray
+		require.NoError(t, json.Unmarshal([]byte(input), &a), input)
+		assert.Equal(t, FormDataArray{{"LastName", "Kojo"}}, a)
+	}
+
+	var a FormDataArray
+	require.NoError(t, json.Unmarshal([]byte(`""`), &a))
+	assert.Nil(t, a)
+}
+
+func TestBuildFormData(t *testing.T) {
+	base := FormDataArray{{"LastName", "Kojo"}}
+
+	a, err := BuildFormData(base,
+		FormField("FirstName", "Kwame"),
+		FormFields(map[string]string{"Phone": "0241234567", "LastName": "Mensah"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, FormDataArray{{"LastName", "Mensah"}, {"FirstName", "Kwame"}, {"Phone", "0241234567"}}, a)
+	assert.Equal(t, "Kojo", base[0].FieldValue, "the form data is copied")
+
+	form := &BillerForm{BillerCode: "DSTV", Fields: []BillFormData{{FieldName: "SmartCard", ValidateField: "Y"}}}
+	_, err = BuildFormData(base, ValidForm(form))
+	assert.ErrorIs(t, err, ErrInvalidFormData)
+}
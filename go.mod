@@ -4,9 +4,11 @@ go 1.24.0
 
 require (
 	github.com/hashicorp/go-retryablehttp v0.7.7
+	github.com/mattn/go-sqlite3 v1.14.32
 	github.com/pkg/errors v0.9.1
 	github.com/shopspring/decimal v1.4.0
 	github.com/stretchr/testify v1.10.0
+	golang.org/x/oauth2 v0.34.0
 )
 
 require (
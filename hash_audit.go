@@ -0,0 +1,34 @@
+package ecobank
+
+import (
+	"context"
+	"time"
+)
+
+// HashAuditRecord describes a secure hash generated for a request.
+type HashAuditRecord struct {
+	// Method and Path identify the request, e.g. POST merchant/payment.
+	Method string
+	Path   string
+	// Source is the concatenation of the signed field values, in the order they were hashed.
+	// The lab key, which is appended to it before hashing, is left out.
+	Source string
+	// Hash is the resulting secure hash, as sent in the request.
+	Hash string
+	// Time is when the hash was generated, according to the clock of the client.
+	Time time.Time
+}
+
+// HashAuditFunc is called with every secure hash generated by the client. ctx is the context of the request,
+// which carries the tags set with WithTag.
+type HashAuditFunc func(ctx context.Context, record HashAuditRecord)
+
+// WithHashAudit sets a hook which records every secure hash generated by the client along with the exact
+// string that was signed, so what was sent can be verified with Ecobank support after an incident.
+// Hashes set by the caller on the request options are not generated, so they are not recorded.
+func WithHashAudit(fn HashAuditFunc) ClientOptionFunc {
+	return func(c *Client) error {
+		c.hashAudit = fn
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package ecobank
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHashAudit(t *testing.T) {
+	client := newMockClient(t, `{"response_code": 200, "response_message": "success", "response_content": {}}`, http.StatusOK)
+
+	var records []HashAuditRecord
+	require.NoError(t, WithHashAudit(func(ctx context.Context, record HashAuditRecord) {
+		assert.Equal(t, []Tag{{Key: "incident", Value: "INC-1"}}, Tags(ctx))
+		records = append(records, record)
+	})(client))
+
+	ctx := WithTag(t.Context(), "incident", "INC-1")
+	opt := &AccountBalanceOptions{
+		RequestID:     "14232436312",
+		AffiliateCode: "EGH",
+		AccountNo:     "6500184371",
+		ClientID:      "ECO00184371123",
+		CompanyName:   "ECOBANK TEST CO",
+	}
+	_, _, err := client.Account.GetBalance(ctx, opt)
+	require.NoError(t, err)
+
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, http.MethodPost, record.Method)
+	assert.Equal(t, "merchant/accountbalance", record.Path)
+	assert.Equal(t, "14232436312EGH6500184371ECO00184371123ECOBANK TEST CO", record.Source)
+	assert.Equal(t, opt.SecureHash, record.Hash)
+	assert.Equal(t, generateSecureHash(record.Source, "mock-lab-key"), record.Hash)
+	assert.False(t, record.Time.IsZero())
+
+	// hashes set by the caller are not generated
+	_, _, err = client.Account.GetBalance(ctx, opt)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
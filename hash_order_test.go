@@ -0,0 +1,51 @@
+package ecobank
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecureHashSource_Positions(t *testing.T) {
+	type options struct {
+		Currency  string `json:"currency" securehash:"pos=3"`
+		Reference string `json:"reference"`
+		Amount    string `json:"amount" securehash:"pos=2"`
+		RequestID string `json:"requestId" securehash:"pos=1"`
+		Ignored   string `json:"ignored" securehash:"ignore"`
+		Narration string `json:"narration"`
+		secureHashOption
+	}
+
+	opt := options{Currency: "GHS", Reference: "REF", Amount: "10", RequestID: "ECO1", Ignored: "x", Narration: "rent"}
+	assert.Equal(t, "ECO110GHSREFrent", secureHashSource(opt, AmountFormatTrimZeros))
+	assert.Equal(t, "RENTGHS", secureHashSourceFor(options{Narration: "RENT", Currency: "GHS"}, []string{"narration", "missing", "currency"}, AmountFormatTrimZeros))
+}
+
+func TestWithSecureHashFields(t *testing.T) {
+	var records []HashAuditRecord
+	client := newMockClient(t, `{"response_code": 200, "response_content": {}}`, http.StatusOK)
+	require.NoError(t, WithHashAudit(func(_ context.Context, record HashAuditRecord) {
+		records = append(records, record)
+	})(client))
+	require.NoError(t, WithSecureHashFields("Account", "GetBalance", "accountNo", "requestId", "affiliateCode")(client))
+
+	opt := &AccountBalanceOptions{RequestID: "ECO76383823", AffiliateCode: "EGH", AccountNo: "1441000574000", ClientID: "ECO"}
+	_, _, err := client.Account.GetBalance(t.Context(), opt)
+	require.NoError(t, err)
+
+	_, _, err = client.Account.Enquiry(t.Context(), &AccountEnquiryOptions{RequestID: "ECO76383823", AffiliateCode: "EGH", AccountNo: "1441000574000"})
+	require.NoError(t, err)
+
+	require.Len(t, records, 2)
+	assert.Equal(t, "1441000574000ECO76383823EGH", records[0].Source)
+	assert.Equal(t, generateSecureHash("1441000574000ECO76383823EGH", "mock-lab-key"), opt.SecureHash)
+	assert.Equal(t, "ECO76383823EGH1441000574000", records[1].Source, "other endpoints hash in struct order")
+
+	err = WithSecureHashFields("Account", "Unknown", "accountNo")(client)
+	assert.ErrorContains(t, err, "unknown endpoint Account.Unknown")
+	assert.Error(t, WithSecureHashFields("Account", "GetBalance")(client))
+}
@@ -0,0 +1,69 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Health is the result of a health check of the gateway.
+type Health struct {
+	// Latency is how long the check took, including the round trip to the gateway.
+	Latency time.Duration
+	// StatusCode is the HTTP status code the gateway answered with. It is zero if the gateway did not answer,
+	// or if no new token was requested.
+	StatusCode int
+	// TokenExpiresAt is when the access token expires. It is zero if the token has no expiry.
+	TokenExpiresAt time.Time
+}
+
+// HealthCheck checks that the client holds a valid access token. If its token has expired, it checks that the
+// gateway is reachable and accepts the credentials of the client, by requesting a new access token which then
+// replaces the token of the client. A valid token is reused, so frequent probes do not log in every time.
+// If the client has a token source, the token is taken from it instead, which checks the source but not
+// the gateway.
+//
+// It is lightweight enough to be called from the readiness probes of services depending on the API:
+//
+//	http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+//		if _, err := client.HealthCheck(r.Context()); err != nil {
+//			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+//		}
+//	})
+//
+// The health is returned along with the error if the check fails, so that the latency and the status code
+// of the gateway can still be reported.
+func (c *Client) HealthCheck(ctx context.Context) (*Health, error) {
+	health := &Health{}
+	start := time.Now()
+
+	if c.tokenSource != nil {
+		_, expiry, err := c.validToken(ctx)
+		health.Latency = time.Since(start)
+		health.TokenExpiresAt = expiry
+		return health, err
+	}
+
+	if token, expiry := c.getToken(); !c.tokenExpired(token, expiry) {
+		health.Latency = time.Since(start)
+		health.TokenExpiresAt = expiry
+		return health, nil
+	}
+
+	if !c.hasCredentials() {
+		return health, errors.New("health check requires the credentials of the client")
+	}
+
+	resp, err := c.login(ctx)
+	health.Latency = time.Since(start)
+	if resp != nil && resp.Response != nil {
+		health.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		return health, fmt.Errorf("gateway is unhealthy: %w", err)
+	}
+
+	_, health.TokenExpiresAt = c.getToken()
+	return health, nil
+}
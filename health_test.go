@@ -0,0 +1,84 @@
+package ecobank
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestClient_HealthCheck(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	client.tokenExpiresAt = time.Now().Add(-time.Minute)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "/corporateapi/user/token", req.URL.Path)
+			resp := httptest.NewRecorder()
+			_, err := resp.WriteString(`{"username": "mock-client-id", "token": "new-token"}`)
+			return resp.Result(), err
+		},
+	}
+
+	health, err := client.HealthCheck(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, health.StatusCode)
+	assert.Positive(t, health.Latency)
+	assert.WithinDuration(t, time.Now().Add(defaultTokenExpiry), health.TokenExpiresAt, time.Minute)
+
+	token, _ := client.getToken()
+	assert.Equal(t, "new-token", token)
+
+	t.Run("valid token", func(t *testing.T) {
+		client := newMockClient(t, "", http.StatusOK)
+		client.client.HTTPClient.Transport = &mockHTTPClient{
+			requestHandler: func(req *http.Request) (*http.Response, error) {
+				t.Fatal("a valid token is reused")
+				return nil, nil
+			},
+		}
+
+		health, err := client.HealthCheck(t.Context())
+		require.NoError(t, err)
+		assert.Zero(t, health.StatusCode)
+		assert.Equal(t, client.tokenExpiresAt, health.TokenExpiresAt)
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 401, "response_message": "invalid credentials"}`, http.StatusUnauthorized)
+		client.token = ""
+
+		health, err := client.HealthCheck(t.Context())
+		require.ErrorContains(t, err, "gateway is unhealthy")
+		assert.Equal(t, http.StatusUnauthorized, health.StatusCode)
+		assert.Positive(t, health.Latency)
+		assert.Zero(t, health.TokenExpiresAt)
+	})
+
+	t.Run("token source", func(t *testing.T) {
+		expiry := time.Now().Add(time.Hour)
+		client := newMockClient(t, "", http.StatusOK)
+		require.NoError(t, WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "static", Expiry: expiry}))(client))
+
+		health, err := client.HealthCheck(t.Context())
+		require.NoError(t, err)
+		assert.Zero(t, health.StatusCode)
+		assert.Equal(t, expiry, health.TokenExpiresAt)
+
+		require.NoError(t, WithTokenSource(errTokenSource{errors.New("vault is sealed")})(client))
+		_, err = client.HealthCheck(t.Context())
+		assert.ErrorContains(t, err, "vault is sealed")
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		client, err := NewClient("", "", "mock-lab-key")
+		require.NoError(t, err)
+
+		_, err = client.HealthCheck(t.Context())
+		assert.EqualError(t, err, "health check requires the credentials of the client")
+	})
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -18,21 +20,11 @@ type secureHasher interface {
 	GetHash() string
 }
 
-type secureHashOption struct {
-	SecureHash string `json:"secureHash" securehash:"ignore"` // optional: generated if not provided
-}
-
-// SetHash sets the secure hash.
-func (opt *secureHashOption) SetHash(hash string) {
-	opt.SecureHash = hash
-}
+// secureHashOption is embedded in the request options of the library. It is unexported, so that the
+// options are not printed with their secure hash.
+type secureHashOption = SecureHashOptions
 
-// GetHash returns the secure hash.
-func (opt *secureHashOption) GetHash() string {
-	return opt.SecureHash
-}
-
-var _ secureHasher = (*secureHashOption)(nil)
+var _ secureHasher = (*SecureHashOptions)(nil)
 
 const (
 	timeFormat = "2006-01-02T15:04:05.999"
@@ -41,18 +33,47 @@ const (
 
 // This is the order in which we try to parse the timestamps.
 // The API is quite inconsistent with the timestamp formats it returns, so we have to try multiple formats.
-var formats = []string{
-	timeFormat,
-	time.DateTime,
-	time.RFC3339, // this is enough for both time.RFC3339 and time.RFC3339Nano layouts
-	time.DateOnly,
+//
+// The list is replaced rather than modified when formats are added, so it can be read without locking.
+var formats atomic.Pointer[[]string]
+
+func init() {
+	formats.Store(&[]string{
+		timeFormat,
+		time.DateTime,
+		time.RFC3339, // this is enough for both time.RFC3339 and time.RFC3339Nano layouts
+		time.DateOnly,
+	})
 }
 
-// AddTimeFormat adds the new time layouts to the list of formats to try when parsing timestamps.
+// AddTimeFormat adds the new time layouts to the list of formats to try when parsing timestamps,
+// for all clients. It is safe for concurrent use.
 //
-// This is not thread-safe and should be called before making any requests.
+// Deprecated: use WithTimeFormats to add formats to a single client.
 func AddTimeFormat(layouts ...string) {
-	formats = append(formats, layouts...)
+	for {
+		old := formats.Load()
+		updated := append(append(make([]string, 0, len(*old)+len(layouts)), *old...), layouts...)
+		if formats.CompareAndSwap(old, &updated) {
+			return
+		}
+	}
+}
+
+// TimeParseError is returned when a timestamp does not match any of the known formats.
+type TimeParseError struct {
+	Value string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *TimeParseError) Error() string {
+	return fmt.Sprintf("cannot parse time %q: %v", e.Value, e.Err)
+}
+
+// Unwrap returns the errors of the attempted formats.
+func (e *TimeParseError) Unwrap() error {
+	return e.Err
 }
 
 // Time is a wrapper around time.Time.
@@ -67,6 +88,7 @@ type Time struct {
 	time time.Time
 
 	layout string
+	loc    *time.Location
 }
 
 // NewTime returns a new Time.
@@ -80,36 +102,65 @@ func NewTimeWithLayout(time time.Time, layout string) Time {
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
+// A null or empty string leaves the time zero.
 func (t *Time) UnmarshalJSON(b []byte) (err error) {
 	s := string(b)
+	if s == "null" {
+		t.time = time.Time{}
+		return nil
+	}
 	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
 		s = s[1 : len(s)-1]
 	}
 
+	return t.parse(s)
+}
+
+// parse parses s with the layout of t, falling back to the known formats.
+// The time is interpreted in the location of t, which defaults to UTC.
+func (t *Time) parse(s string) (err error) {
+	if s == "" {
+		t.time = time.Time{}
+		return nil
+	}
+
+	loc := t.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+
 	if t.layout != "" {
 		// try first with the provided layout
-		t.time, err = time.Parse(t.layout, s)
+		t.time, err = time.ParseInLocation(t.layout, s, loc)
 		if err == nil {
 			return nil
 		}
 	}
 
-	for _, format := range formats {
-		nt, formatErr := time.Parse(format, s)
+	for _, format := range *formats.Load() {
+		nt, formatErr := time.ParseInLocation(format, s, loc)
 		if formatErr == nil {
 			t.time = nt
-			err = nil // found a format that works, reset the error
-			break
+			return nil
 		}
 		err = errors.Join(err, formatErr)
 	}
 
-	return err
+	return &TimeParseError{Value: s, Err: err}
 }
 
 // MarshalJSON implements the json.Marshaler interface.
+// The zero time is marshaled as an empty string.
 func (t Time) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf("%q", t.String())), nil
+	return []byte(strconv.Quote(t.text())), nil
+}
+
+// text returns the time as it is sent to the API, which is empty for the zero time.
+func (t Time) text() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.String()
 }
 
 // GetTime returns the time.Time value.
@@ -133,29 +184,49 @@ type Date struct {
 
 // NewDate returns a new Date with the default layout YYYYMMDD used by the API
 func NewDate(time time.Time) Date {
-	return Date{Time: NewTimeWithLayout(time, dateFormat)}
+	return NewDateWithLayout(time, dateFormat)
+}
+
+// NewDateWithLayout returns a new Date with the given layout.
+func NewDateWithLayout(time time.Time, layout string) Date {
+	return Date{Time: NewTimeWithLayout(time, layout)}
 }
 
 // MarshalJSON implements the json.Marshaler interface.
 func (date Date) MarshalJSON() ([]byte, error) {
-	return date.time.MarshalJSON()
+	return date.Time.MarshalJSON()
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (date *Date) UnmarshalJSON(b []byte) (err error) {
-	return date.time.UnmarshalJSON(b)
+	if date.layout == "" {
+		date.layout = dateFormat
+	}
+	return date.Time.UnmarshalJSON(b)
 }
 
-func checkErr1[A any](_ A, err error) error {
-	return err
+// formatToStr formats v as it is sent to the API, with amounts in the format set with SetAmountFormat.
+func formatToStr(v any) string {
+	return formatValue(v, globalAmountFormat())
 }
 
-func formatToStr(v any) string {
+// formatValue formats v as it is sent to the API, with amounts in the given format.
+func formatValue(v any, format AmountFormat) string {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		// format the value pointed to, unless only the pointer has a String method
+		if _, ok := v.(fmt.Stringer); !ok || rv.Elem().Type().Implements(stringerType) {
+			return formatValue(rv.Elem().Interface(), format)
+		}
+	}
+
 	switch s := v.(type) {
 	case string:
 		return s
 	case decimal.Decimal:
-		return s.String()
+		return format.format(s)
 	case bool:
 		return strconv.FormatBool(s)
 	case float64:
@@ -184,31 +255,71 @@ func formatToStr(v any) string {
 		return strconv.FormatUint(uint64(s), 10)
 	case json.Number:
 		return s.String()
+	case Time:
+		return s.text()
+	case Date:
+		return s.text()
 	case []byte:
 		return string(s)
 	case fmt.Stringer:
 		return s.String()
+	default:
+		return formatKindToStr(reflect.ValueOf(v))
+	}
+}
+
+// formatKindToStr formats values of named basic types such as Gender or PaymentType.
+func formatKindToStr(rv reflect.Value) string {
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
 	default:
 		return ""
 	}
 }
 
+// tokenClaims is the payload of the JWT access token.
 type tokenClaims struct {
-	Exp int64 `json:"exp"`
+	Exp           int64           `json:"exp"`
+	Iat           int64           `json:"iat,omitempty"`
+	Sub           string          `json:"sub,omitempty"`
+	Affiliate     string          `json:"affiliate,omitempty"`
+	AffiliateCode string          `json:"affiliateCode,omitempty"`
+	Scope         json.RawMessage `json:"scope,omitempty"`
+	Scopes        []string        `json:"scopes,omitempty"`
 }
 
-func getTokenExpiry(token string) (time.Time, error) {
+func decodeTokenPayload(token string) ([]byte, error) {
 	parts := strings.SplitN(token, ".", 3)
 	if len(parts) != 3 {
-		return time.Time{}, fmt.Errorf("invalid JWT format")
+		return nil, fmt.Errorf("invalid JWT format")
 	}
 
 	payload := parts[1]
 
 	// Decode base64 without manually adding padding
-	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(payload, "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64: %w", err)
+	}
+
+	return decoded, nil
+}
+
+func getTokenExpiry(token string) (time.Time, error) {
+	decoded, err := decodeTokenPayload(token)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to decode base64: %w", err)
+		return time.Time{}, err
 	}
 
 	var claims tokenClaims
@@ -7,6 +7,9 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func encodePayload(exp int64) string {
@@ -61,3 +64,74 @@ func BenchmarkGetTokenExpiry(b *testing.B) {
 		_, _ = getTokenExpiry(token)
 	}
 }
+
+func TestDate_MarshalJSON(t *testing.T) {
+	d := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	b, err := json.Marshal(NewDate(d))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != `"20200301"` {
+		t.Errorf("expected %q, got %s", "20200301", b)
+	}
+
+	b, err = json.Marshal(NewDateWithLayout(d, "02012006"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != `"01032020"` {
+		t.Errorf("expected %q, got %s", "01032020", b)
+	}
+
+	var parsed Date
+	if err := json.Unmarshal([]byte(`"20200301"`), &parsed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !parsed.GetTime().Equal(d) {
+		t.Errorf("expected %v, got %v", d, parsed.GetTime())
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	encode := func(claims map[string]any) string {
+		b, _ := json.Marshal(claims)
+		return "header." + base64.RawURLEncoding.EncodeToString(b) + ".signature"
+	}
+
+	t.Run("all claims", func(t *testing.T) {
+		claims, err := ParseToken(encode(map[string]any{
+			"sub":           "iamaunifieddev103",
+			"affiliateCode": "EGH",
+			"scope":         "payment statement",
+			"iat":           1650440180,
+			"exp":           1650447380,
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, "iamaunifieddev103", claims.Subject)
+		assert.Equal(t, "EGH", claims.Affiliate)
+		assert.Equal(t, []string{"payment", "statement"}, claims.Scopes)
+		assert.Equal(t, time.Unix(1650440180, 0), claims.IssuedAt)
+		assert.Equal(t, time.Unix(1650447380, 0), claims.ExpiresAt)
+		assert.Negative(t, claims.ExpiresIn())
+		assert.Equal(t, "iamaunifieddev103", claims.Raw["sub"])
+	})
+
+	t.Run("scope list and no expiry", func(t *testing.T) {
+		claims, err := ParseToken(encode(map[string]any{
+			"sub":   "iamaunifieddev103",
+			"scope": []string{"payment"},
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"payment"}, claims.Scopes)
+		assert.True(t, claims.ExpiresAt.IsZero())
+		assert.Zero(t, claims.ExpiresIn())
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := ParseToken("invalid.token")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,42 @@
+package ecobank
+
+import "fmt"
+
+// hostSuccessCode is the response code of the host header info of successful requests.
+const hostSuccessCode = "000"
+
+// HostHeaderInfo is the status reported by the host which processed a request, returned in the content of
+// the responses of many operations. The response envelope can be successful while the host failed.
+type HostHeaderInfo struct {
+	SourceCode      string `json:"sourceCode"`
+	RequestID       string `json:"requestId"`
+	AffiliateCode   string `json:"affiliateCode"`
+	ResponseCode    string `json:"responseCode"`
+	ResponseMessage string `json:"responseMessage"`
+}
+
+// Success reports whether the host processed the request successfully, with the response code 000.
+func (h HostHeaderInfo) Success() bool {
+	return h.ResponseCode == hostSuccessCode
+}
+
+// Error returns a *HostError if the host did not process the request successfully, or nil.
+func (h HostHeaderInfo) Error() error {
+	if h.Success() {
+		return nil
+	}
+	return &HostError{HostHeaderInfo: h}
+}
+
+// HostError is returned by HostHeaderInfo.Error when the host did not process a request successfully.
+type HostError struct {
+	HostHeaderInfo
+}
+
+// Error implements the error interface.
+func (e *HostError) Error() string {
+	if e.ResponseCode == "" {
+		return fmt.Sprintf("host %s of affiliate %s returned no response code for request %s", e.SourceCode, e.AffiliateCode, e.RequestID)
+	}
+	return fmt.Sprintf("host %s of affiliate %s failed request %s: %s %s", e.SourceCode, e.AffiliateCode, e.RequestID, e.ResponseCode, e.ResponseMessage)
+}
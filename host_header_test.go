@@ -0,0 +1,47 @@
+package ecobank
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostHeaderInfo(t *testing.T) {
+	h := HostHeaderInfo{SourceCode: "CORPORATEAPI", RequestID: "14232436312", AffiliateCode: "EGH", ResponseCode: "000", ResponseMessage: "SUCCESS"}
+	assert.True(t, h.Success())
+	assert.NoError(t, h.Error())
+
+	h.ResponseCode, h.ResponseMessage = "096", "System malfunction"
+	assert.False(t, h.Success())
+
+	var hostErr *HostError
+	require.ErrorAs(t, h.Error(), &hostErr)
+	assert.Equal(t, h, hostErr.HostHeaderInfo)
+	assert.EqualError(t, hostErr, "host CORPORATEAPI of affiliate EGH failed request 14232436312: 096 System malfunction")
+
+	assert.EqualError(t, HostHeaderInfo{SourceCode: "CORPORATEAPI", RequestID: "1", AffiliateCode: "EGH"}.Error(),
+		"host CORPORATEAPI of affiliate EGH returned no response code for request 1")
+}
+
+func TestHostHeaderInfo_Responses(t *testing.T) {
+	client := newMockClient(t, `{
+		"response_code": 200,
+		"response_message": "success",
+		"response_content": {
+			"hostHeaderInfo": {"sourceCode": "CORPORATEAPI", "requestId": "14232436312", "affiliateCode": "EGH", "responseCode": "E04", "responseMessage": "Invalid account"},
+			"billerInfo": []
+		}
+	}`, http.StatusOK)
+
+	balance, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	require.NoError(t, err)
+	billers, _, err := client.Payment.GetBillerList(t.Context(), &GetBillerListOptions{})
+	require.NoError(t, err)
+
+	for _, h := range []HostHeaderInfo{balance.HostHeaderInfo, billers.HostHeaderInfo} {
+		assert.False(t, h.Success())
+		assert.EqualError(t, h.Error(), "host CORPORATEAPI of affiliate EGH failed request 14232436312: E04 Invalid account")
+	}
+}
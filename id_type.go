@@ -0,0 +1,136 @@
+package ecobank
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/profclems/go-ecobank/calendar"
+)
+
+// IDType represents the type of identity document of the receiver of a cross-border transfer.
+// The ReceiverIDType of TokenIAParams and MomoIAParams is a plain string, so the ID types below,
+// which are untyped constants, and any other value the API accepts can be set.
+type IDType string
+
+// ID types of receivers.
+const (
+	IDTypePassport       = "PASSPORT"
+	IDTypeNationalID     = "NATIONAL_ID"
+	IDTypeDriversLicense = "DRIVERS_LICENSE"
+	IDTypeVoterID        = "VOTER_ID"
+)
+
+// idTypes are the known ID types, in the order they are listed in errors.
+var idTypes = []IDType{IDTypePassport, IDTypeNationalID, IDTypeDriversLicense, IDTypeVoterID}
+
+// idTypesByCountry lists the ID types accepted in a country. Countries which are not listed accept all ID types.
+var idTypesByCountry = map[string][]IDType{
+	"CI": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense},
+	"CM": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense},
+	"GH": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense, IDTypeVoterID},
+	"KE": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense},
+	"NG": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense, IDTypeVoterID},
+	"RW": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense},
+	"SN": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense},
+	"TG": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense, IDTypeVoterID},
+	"TZ": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense, IDTypeVoterID},
+	"UG": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense},
+	"ZM": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense},
+	"ZW": {IDTypePassport, IDTypeNationalID, IDTypeDriversLicense},
+}
+
+// idNumberLength is the allowed number of letters and digits of an ID number.
+type idNumberLength struct {
+	min, max int
+}
+
+// idNumberLengths are the lengths of the ID types, which apply unless there is one for the country.
+var idNumberLengths = map[IDType]idNumberLength{
+	IDTypePassport:       {6, 9},
+	IDTypeNationalID:     {5, 20},
+	IDTypeDriversLicense: {5, 20},
+	IDTypeVoterID:        {5, 20},
+}
+
+// idNumberLengthsByCountry are the lengths of national ID types, such as the 11 digit NIN of Nigeria.
+var idNumberLengthsByCountry = map[string]map[IDType]idNumberLength{
+	"GH": {IDTypeNationalID: {13, 13}, IDTypeVoterID: {10, 10}},
+	"KE": {IDTypeNationalID: {7, 8}},
+	"NG": {IDTypeNationalID: {11, 11}, IDTypeVoterID: {19, 19}},
+	"RW": {IDTypeNationalID: {16, 16}},
+	"TZ": {IDTypeNationalID: {20, 20}},
+	"UG": {IDTypeNationalID: {14, 14}},
+}
+
+// ValidIn reports whether the ID type is accepted in the country of the affiliate, e.g. "EGH".
+func (t IDType) ValidIn(affiliateCode string) bool {
+	if !slices.Contains(idTypes, t) {
+		return false
+	}
+
+	accepted, ok := idTypesByCountry[calendar.Country(affiliateCode)]
+	return !ok || slices.Contains(accepted, t)
+}
+
+// ValidateReceiverID checks that the ID type is accepted in the country of the affiliate and that the
+// ID number has a valid length for it. Spaces, dashes and slashes in the number are not counted,
+// so numbers such as GHA-123456789-0 can be passed as printed on the document.
+//
+// The accepted ID types and lengths are not part of the API collection: they follow the documents
+// commonly issued in each country, so the check is opt-in and PaymentService.Pay does not run it.
+func ValidateReceiverID(affiliateCode string, idType IDType, number string) error {
+	if !slices.Contains(idTypes, idType) {
+		return fmt.Errorf("unknown ID type %q: must be one of %q", idType, idTypes)
+	}
+	if !idType.ValidIn(affiliateCode) {
+		return fmt.Errorf("ID type %s is not accepted in %s", idType, calendar.Country(affiliateCode))
+	}
+
+	n := 0
+	for _, r := range number {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			n++
+		case r == ' ' || r == '-' || r == '/':
+		default:
+			return fmt.Errorf("invalid character %q in %s number", r, idType)
+		}
+	}
+
+	length := idNumberLengths[idType]
+	if l, ok := idNumberLengthsByCountry[calendar.Country(affiliateCode)][idType]; ok {
+		length = l
+	}
+
+	if n < length.min || n > length.max {
+		if length.min == length.max {
+			return fmt.Errorf("%s number must have %d letters or digits, got %d", idType, length.min, n)
+		}
+		return fmt.Errorf("%s number must have %d to %d letters or digits, got %d", idType, length.min, length.max, n)
+	}
+
+	return nil
+}
+
+// validateReceiverID validates the receiver ID of cross-border params, if it is set.
+func validateReceiverID(affiliateCode, idType, number string) error {
+	if idType == "" && strings.TrimSpace(number) == "" {
+		return nil
+	}
+	if err := ValidateReceiverID(affiliateCode, IDType(idType), number); err != nil {
+		return fmt.Errorf("invalid receiver ID: %w", err)
+	}
+	return nil
+}
+
+// ValidateReceiverID checks the receiver ID of the transfer with ValidateReceiverID if it is set.
+func (p TokenIAParams) ValidateReceiverID() error {
+	return validateReceiverID(p.DestinationAffiliate, p.ReceiverIDType, p.ReceiverIDNumber)
+}
+
+// ValidateReceiverID checks the receiver ID of the transfer with ValidateReceiverID if it is set.
+func (p MomoIAParams) ValidateReceiverID() error {
+	return validateReceiverID(p.DestinationAffiliate, p.ReceiverIDType, p.ReceiverIDNumber)
+}
@@ -0,0 +1,91 @@
+package ecobank
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDType_ValidIn(t *testing.T) {
+	assert.True(t, IDType(IDTypePassport).ValidIn("EKE"))
+	assert.True(t, IDType(IDTypeVoterID).ValidIn("EGH"))
+	assert.False(t, IDType(IDTypeVoterID).ValidIn("EKE"))
+	assert.True(t, IDType(IDTypeVoterID).ValidIn("EBJ"), "countries without a list accept all ID types")
+	assert.False(t, IDType("SSN").ValidIn("EGH"))
+}
+
+func TestValidateReceiverID(t *testing.T) {
+	testCases := []struct {
+		name      string
+		affiliate string
+		idType    IDType
+		number    string
+		wantErr   string
+	}{
+		{name: "passport", affiliate: "EGH", idType: IDTypePassport, number: "G1234567"},
+		{name: "ghana card", affiliate: "EGH", idType: IDTypeNationalID, number: "GHA-123456789-0"},
+		{name: "nigeria nin", affiliate: "ENG", idType: IDTypeNationalID, number: "12345678901"},
+		{name: "kenya national id", affiliate: "EKE", idType: IDTypeNationalID, number: "1234567"},
+		{name: "other country", affiliate: "EBJ", idType: IDTypeNationalID, number: "BJ123456"},
+		{name: "unknown type", affiliate: "EGH", idType: "SSN", number: "123456", wantErr: `unknown ID type "SSN"`},
+		{name: "not accepted", affiliate: "EKE", idType: IDTypeVoterID, number: "1234567890", wantErr: "VOTER_ID is not accepted in KE"},
+		{name: "nin too short", affiliate: "ENG", idType: IDTypeNationalID, number: "1234567890", wantErr: "must have 11 letters or digits, got 10"},
+		{name: "passport too long", affiliate: "EGH", idType: IDTypePassport, number: "G12345678901", wantErr: "must have 6 to 9 letters or digits, got 12"},
+		{name: "invalid character", affiliate: "EGH", idType: IDTypePassport, number: "G123456#", wantErr: `invalid character '#'`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateReceiverID(tc.affiliate, tc.idType, tc.number)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTokenIAParams_ValidateReceiverID(t *testing.T) {
+	assert.NoError(t, TokenIAParams{DestinationAffiliate: "EGH"}.ValidateReceiverID(), "the receiver ID is optional")
+	assert.NoError(t, MomoIAParams{DestinationAffiliate: "EGH", ReceiverIDType: IDTypePassport, ReceiverIDNumber: "G1234567"}.ValidateReceiverID())
+	assert.ErrorContains(t, TokenIAParams{DestinationAffiliate: "ENG", ReceiverIDNumber: "12345678901"}.ValidateReceiverID(), "invalid receiver ID: unknown ID type")
+}
+
+func TestPaymentService_Pay_ReceiverIDNotValidated(t *testing.T) {
+	var sent bool
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			sent = true
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"response_code": 200, "response_message": "success", "response_content": "success"}`)),
+				Request:    req,
+			}, nil
+		},
+	}
+
+	opt := &PaymentOptions{
+		PaymentHeader: PaymentHeader{AffiliateCode: "EGH"},
+		Extension: []PaymentExtension{{
+			RequestID:   "432",
+			RequestType: TOKENIA,
+			ParamList: NewPaymentParams(TokenIAParams{
+				DestinationAffiliate: "EKE",
+				ReceiverIDType:       "RESIDENCE_PERMIT",
+				ReceiverIDNumber:     "1234567890",
+			}),
+		}},
+	}
+
+	_, _, err := client.Payment.Pay(context.Background(), opt)
+	require.NoError(t, err)
+	assert.True(t, sent, "ID types unknown to ValidateReceiverID are sent as is")
+}
@@ -0,0 +1,92 @@
+package ecobank
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+// bufferPool holds the buffers the payment params and secure hashes are built in, so that sending many
+// payments does not allocate a buffer for each of them.
+var bufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 1024)
+		return &b
+	},
+}
+
+// maxPooledBuffer is the capacity above which buffers are not returned to the pool, so that a single
+// large payment does not keep memory allocated.
+const maxPooledBuffer = 64 << 10
+
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+func putBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBuffer {
+		return
+	}
+	*b = (*b)[:0]
+	bufferPool.Put(b)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendQuotedJSON appends s to dst as a JSON string, escaped like encoding/json does with HTML escaping
+// disabled: quotes, backslashes and control characters are escaped, invalid UTF-8 is replaced with
+// U+FFFD, and U+2028 and U+2029 are escaped for JavaScript.
+func appendQuotedJSON(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// quoteJSON returns s as a JSON string. Unlike json.Marshal, HTML characters are not escaped.
+func quoteJSON(s string) string {
+	return string(appendQuotedJSON(nil, s))
+}
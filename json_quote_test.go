@@ -0,0 +1,44 @@
+package ecobank
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// quoteJSONStd quotes s with encoding/json, which appendQuotedJSON must match.
+func quoteJSONStd(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return string(bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+}
+
+func TestQuoteJSON(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"Freeman Kay",
+		`"quoted" \ backslash`,
+		"<html> & friends",
+		"\x00\x01\b\f\n\r\t\x1f\x7f",
+		"  ",
+		"ключ 🔑",
+		"invalid \xff\xfe utf-8",
+		`[{"key": "secretCode", "value": "12345"}]`,
+	} {
+		assert.Equal(t, quoteJSONStd(s), quoteJSON(s), "%q", s)
+	}
+}
+
+func FuzzQuoteJSON(f *testing.F) {
+	f.Add("Pass_Bio_ECI")
+	f.Add(`"\`)
+	f.Add("\x00 \xff")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		assert.Equal(t, quoteJSONStd(s), quoteJSON(s))
+	})
+}
@@ -0,0 +1,120 @@
+package ecobank
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"reflect"
+	"strings"
+)
+
+// FormFile is a file uploaded as a part of a multipart/form-data request, such as a KYC document.
+//
+// Request options with FormFile fields are sent as multipart/form-data by Client.NewRequest, with a file part for
+// each file and a form field for each other field, named after their json tags. Files are not part of the secure
+// hash, which is computed from the form fields as for JSON requests.
+type FormFile struct {
+	// FileName is the name of the file sent to the API, e.g. passport.pdf.
+	FileName string
+	// ContentType is the media type of the file. It defaults to application/octet-stream.
+	ContentType string
+	// Content is read when the request is created, so the request can be retried.
+	Content io.Reader
+}
+
+var formFileType = reflect.TypeFor[FormFile]()
+
+// isFormFile reports whether the type is a FormFile or a pointer to one.
+func isFormFile(typ reflect.Type) bool {
+	return typ == formFileType || (typ.Kind() == reflect.Pointer && typ.Elem() == formFileType)
+}
+
+// hasFormFiles reports whether the request options have FormFile fields, which makes them a multipart request.
+func hasFormFiles(opts any) bool {
+	typ := reflect.TypeOf(opts)
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return false
+	}
+
+	for _, field := range paramFields(typ) {
+		if isFormFile(field.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeMultipart encodes the request options as a multipart/form-data body, with amounts in the given format,
+// returning the body and its content type.
+func encodeMultipart(opts any, format AmountFormat) ([]byte, string, error) {
+	v := reflect.ValueOf(opts)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	for _, field := range paramFields(v.Type()) {
+		fv, err := v.FieldByIndexErr(field.Index)
+		if err != nil || (field.omitEmpty && fv.IsZero()) {
+			continue
+		}
+
+		if !isFormFile(field.Type) {
+			if err := w.WriteField(field.key, formatValue(fv.Interface(), format)); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if err := writeFormFile(w, field.key, fv.Interface().(FormFile)); err != nil {
+			return nil, "", fmt.Errorf("writing file %s: %w", field.key, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return body.Bytes(), w.FormDataContentType(), nil
+}
+
+// writeFormFile writes the file as a part of the multipart body.
+func writeFormFile(w *multipart.Writer, name string, file FormFile) error {
+	if file.Content == nil {
+		return nil
+	}
+
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(name), escapeQuotes(file.FileName)))
+	header.Set("Content-Type", contentType)
+
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, file.Content)
+	return err
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,80 @@
+package ecobank
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type uploadDocumentOptions struct {
+	RequestID     string    `json:"requestId"`
+	AffiliateCode string    `json:"affiliateCode"`
+	DocumentType  string    `json:"documentType"`
+	Document      *FormFile `json:"document"`
+	Reference     string    `json:"reference,omitempty"`
+
+	secureHashOption
+}
+
+func TestNewRequest_Multipart(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+
+	opt := &uploadDocumentOptions{
+		RequestID:     "ECO76383823",
+		AffiliateCode: "EGH",
+		DocumentType:  "PASSPORT",
+		Document: &FormFile{
+			FileName:    `scan "front".pdf`,
+			ContentType: "application/pdf",
+			Content:     strings.NewReader("%PDF-1.4"),
+		},
+	}
+
+	req, err := client.NewRequest(t.Context(), http.MethodPost, "merchant/upload", opt)
+	require.NoError(t, err)
+
+	assert.Equal(t, generateSecureHash("ECO76383823EGHPASSPORT", "mock-lab-key"), opt.SecureHash, "files are not hashed")
+
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/form-data", mediaType)
+
+	// the body can be read again for retries
+	for range 2 {
+		body, err := req.BodyBytes()
+		require.NoError(t, err)
+
+		form, err := multipart.NewReader(strings.NewReader(string(body)), params["boundary"]).ReadForm(1 << 20)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"ECO76383823"}, form.Value["requestId"])
+		assert.Equal(t, []string{"PASSPORT"}, form.Value["documentType"])
+		assert.Equal(t, []string{opt.SecureHash}, form.Value["secureHash"])
+		assert.NotContains(t, form.Value, "reference", "empty optional fields are not sent")
+
+		require.Len(t, form.File["document"], 1)
+		file := form.File["document"][0]
+		assert.Equal(t, `scan "front".pdf`, file.Filename)
+		assert.Equal(t, "application/pdf", file.Header.Get("Content-Type"))
+
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, "%PDF-1.4", string(content))
+	}
+}
+
+func TestNewRequest_JSONWithoutFiles(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+
+	req, err := client.NewRequest(t.Context(), http.MethodPost, "merchant/accountbalance", &AccountBalanceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+}
@@ -0,0 +1,463 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// OutboxState is the state of a payment in an Outbox.
+type OutboxState string
+
+const (
+	// OutboxPending is a payment waiting to be submitted.
+	OutboxPending OutboxState = "PENDING"
+	// OutboxSubmitted is a payment accepted by the API, waiting for the final status of its extensions.
+	OutboxSubmitted OutboxState = "SUBMITTED"
+	// OutboxCompleted is a payment whose extensions all have a final status.
+	OutboxCompleted OutboxState = "COMPLETED"
+	// OutboxFailed is a payment rejected by the API, or given up on after too many attempts.
+	OutboxFailed OutboxState = "FAILED"
+)
+
+const (
+	defaultOutboxMaxAttempts  = 10
+	defaultOutboxPollInterval = 5 * time.Second
+	defaultOutboxBatchSize    = 50
+	defaultOutboxLease        = 5 * time.Minute
+	maxOutboxBackoff          = 5 * time.Minute
+)
+
+// OutboxEntry is a payment stored in an Outbox.
+type OutboxEntry struct {
+	// ID identifies the entry. It is the request ID of the first extension of the payment.
+	ID string `json:"id"`
+	// RequestIDs are the request IDs of the extensions of the payment.
+	RequestIDs []string `json:"requestIds"`
+	// Payment is the payment to submit.
+	Payment *PaymentOptions `json:"payment"`
+	// State is the state of the payment.
+	State OutboxState `json:"state"`
+	// Uncertain is set when the last submission failed without an answer from the API, in which case the
+	// payment may have been processed. Its status is checked before it is submitted again.
+	Uncertain bool `json:"uncertain,omitempty"`
+	// Attempts is the number of times the payment was submitted.
+	Attempts int `json:"attempts"`
+	// Polls is the number of times the status of the submitted payment was checked.
+	Polls int `json:"polls,omitempty"`
+	// NextAttemptAt is when the entry is processed next.
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+	// LastError is the error of the last attempt to submit the payment or check its status.
+	LastError string `json:"lastError,omitempty"`
+	// Statuses are the final statuses of the extensions, by request ID.
+	Statuses map[string]*TransactionStatus `json:"statuses,omitempty"`
+	// CreatedAt is when the payment was enqueued.
+	CreatedAt time.Time `json:"createdAt"`
+	// UpdatedAt is when the entry was last saved.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// OutboxStore stores the entries of an Outbox. Implementations must be safe for concurrent use.
+// Use a durable store such as SQLOutboxStore so that payments survive process restarts.
+type OutboxStore interface {
+	// Add stores a new entry. It returns an error wrapping ErrDuplicatePayment if one of its request IDs is already stored.
+	Add(ctx context.Context, entry *OutboxEntry) error
+	// Due claims and returns up to limit pending or submitted entries which are due at now, oldest first.
+	// The claim must be atomic: the returned entries are not returned by other calls to Due, e.g. from other
+	// processes sharing the store, until the lease elapses or they are updated.
+	Due(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OutboxEntry, error)
+	// Update saves the entry.
+	Update(ctx context.Context, entry *OutboxEntry) error
+	// Get returns the entry with the given ID, or nil if it is not stored.
+	Get(ctx context.Context, id string) (*OutboxEntry, error)
+}
+
+// Outbox is a durable queue of payments. Payments are enqueued, then submitted with PaymentService.Pay
+// and their statuses polled until final by Process, which Run calls periodically.
+//
+// A payment which fails before it is sent, e.g. because the client cannot log in, is submitted again,
+// and an invalid payment is failed. A payment which fails without an answer from the API, e.g. because
+// of a network error, may have been processed anyway. Its status is checked until the API knows it,
+// and it is only submitted again if NotFound reports that the API does not know it. Payments rejected
+// by the API are not retried.
+//
+// Several processes can share a store: the entries are claimed by the outbox processing them for
+// the Lease, so a payment is not submitted twice.
+type Outbox struct {
+	client *Client
+
+	// Store stores the payments.
+	Store OutboxStore
+	// MaxAttempts is the number of times a payment is submitted before it is given up on. It defaults to 10.
+	MaxAttempts int
+	// Backoff returns the time to wait after the given attempt before processing an entry again.
+	// It defaults to an exponential backoff from one second up to 5 minutes.
+	Backoff func(attempt int) time.Duration
+	// PollInterval is the time between calls to Process made by Run. It defaults to 5 seconds.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of entries processed by each call to Process. It defaults to 50.
+	BatchSize int
+	// Lease is how long the entries of a call to Process are claimed, after which other outboxes sharing
+	// the store may process them. It must be longer than it takes to process BatchSize entries.
+	// It defaults to 5 minutes.
+	Lease time.Duration
+	// NotFound reports whether the status check of a payment whose submission is uncertain failed because
+	// the API does not know the payment, in which case it is submitted again. The API does not document
+	// how unknown requests are reported, so it is nil by default and uncertain payments are not submitted
+	// again: their status is checked up to MaxAttempts times, after which they are failed, still marked
+	// Uncertain, to be reconciled by hand.
+	NotFound func(resp *Response, err error) bool
+	// OnError, if set, is called by Run with the errors of each call to Process.
+	OnError func(err error)
+}
+
+// NewOutbox returns an Outbox which submits payments with the client and stores them in store.
+func NewOutbox(client *Client, store OutboxStore) *Outbox {
+	return &Outbox{
+		client: client,
+		Store:  store,
+	}
+}
+
+// Enqueue adds a copy of the payment to the outbox. It is submitted by the next call to Process.
+// It returns an error wrapping ErrDuplicatePayment if a payment with one of the request IDs was already enqueued.
+func (o *Outbox) Enqueue(ctx context.Context, opt *PaymentOptions) (*OutboxEntry, error) {
+	if len(opt.Extension) == 0 {
+		return nil, errors.New("payment has no extensions")
+	}
+
+	requestIDs := make([]string, len(opt.Extension))
+	for i, ext := range opt.Extension {
+		if ext.RequestID == "" {
+			return nil, fmt.Errorf("extension %d has no request ID", i)
+		}
+		requestIDs[i] = ext.RequestID
+	}
+
+	// the outbox owns a copy of the payment, so that the caller can reuse opt
+	payment := *opt
+	payment.Extension = slices.Clone(opt.Extension)
+
+	now := o.client.now()
+	entry := &OutboxEntry{
+		ID:            requestIDs[0],
+		RequestIDs:    requestIDs,
+		Payment:       &payment,
+		State:         OutboxPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := o.Store.Add(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Get returns the entry with the given ID, or nil if it is not in the outbox.
+func (o *Outbox) Get(ctx context.Context, id string) (*OutboxEntry, error) {
+	return o.Store.Get(ctx, id)
+}
+
+// Run calls Process every PollInterval until ctx is done. Errors are passed to OnError and do not stop it.
+// Run returns the context's error, or ErrClientClosed if the client is closed first.
+func (o *Outbox) Run(ctx context.Context, options ...RequestOptionFunc) error {
+	interval := o.PollInterval
+	if interval <= 0 {
+		interval = defaultOutboxPollInterval
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-o.client.closed():
+			return ErrClientClosed
+		case <-timer.C:
+		}
+
+		if err := o.Process(ctx, options...); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, ErrClientClosed) {
+				return err
+			}
+			if o.OnError != nil {
+				o.OnError(err)
+			}
+		}
+
+		timer.Reset(interval)
+	}
+}
+
+// Process submits the pending payments which are due and checks the status of the submitted ones.
+// The errors of the entries are recorded in them and joined into the returned error.
+func (o *Outbox) Process(ctx context.Context, options ...RequestOptionFunc) error {
+	limit := o.BatchSize
+	if limit <= 0 {
+		limit = defaultOutboxBatchSize
+	}
+
+	lease := o.Lease
+	if lease <= 0 {
+		lease = defaultOutboxLease
+	}
+
+	entries, err := o.Store.Due(ctx, o.client.now(), lease, limit)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if err := o.process(ctx, entry, options...); err != nil {
+			errs = append(errs, fmt.Errorf("outbox entry %s: %w", entry.ID, err))
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// process moves the entry to its next state and saves it.
+func (o *Outbox) process(ctx context.Context, entry *OutboxEntry, options ...RequestOptionFunc) error {
+	var err error
+	switch {
+	case entry.State == OutboxSubmitted:
+		err = o.poll(ctx, entry, options...)
+	case entry.Uncertain:
+		err = o.reconcile(ctx, entry, options...)
+	default:
+		err = o.submit(ctx, entry, options...)
+	}
+
+	entry.LastError = ""
+	if err != nil {
+		entry.LastError = err.Error()
+	}
+
+	maxAttempts := o.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultOutboxMaxAttempts
+	}
+	if entry.State == OutboxPending && (entry.Attempts >= maxAttempts || (entry.Uncertain && entry.Polls >= maxAttempts)) {
+		entry.State = OutboxFailed
+	}
+
+	now := o.client.now()
+	entry.UpdatedAt = now
+	if entry.State == OutboxPending || entry.State == OutboxSubmitted {
+		entry.NextAttemptAt = now.Add(o.backoff(entry))
+	}
+
+	if updateErr := o.Store.Update(ctx, entry); updateErr != nil {
+		return errors.Join(err, updateErr)
+	}
+	return err
+}
+
+// submit sends the payment. It is failed if it is invalid or the API rejects it, and marked uncertain if
+// it was sent without an answer. Payments which failed before they were sent, e.g. because the context
+// was cancelled or the client could not log in, stay pending and are submitted again.
+func (o *Outbox) submit(ctx context.Context, entry *OutboxEntry, options ...RequestOptionFunc) error {
+	// the duplicate guard may still hold the payment of a previous submission, which the API does not know
+	if guard := o.client.duplicateGuard; guard != nil && entry.Attempts > 0 {
+		if err := guard.forget(entry.Payment); err != nil {
+			return fmt.Errorf("releasing payment from duplicate guard: %w", err)
+		}
+	}
+	entry.Attempts++
+
+	if err := o.client.Payment.validate(entry.Payment); err != nil {
+		entry.State, entry.Uncertain = OutboxFailed, false
+		return err
+	}
+
+	// Pay is given a copy, so that the execution date it defaults to the current day is not stored
+	// with the entry, and statuses are polled by the outbox, so that the wait survives restarts
+	payment := *entry.Payment
+	payment.WaitForStatus = false
+
+	ctx, sent := withSentFlag(ctx)
+	ack, _, err := o.client.Payment.Pay(ctx, &payment, options...)
+	var respErr *ResponseError
+	switch {
+	case err == nil, ack != nil:
+		// the payment is accepted even if a hook of the acknowledgement failed
+		entry.State, entry.Uncertain = OutboxSubmitted, false
+	case errors.As(err, &respErr), errors.Is(err, ErrDuplicatePayment):
+		entry.State, entry.Uncertain = OutboxFailed, false
+	case sent.Load():
+		entry.Uncertain = true
+	default:
+		entry.Uncertain = false
+	}
+	return err
+}
+
+// reconcile checks the status of a payment whose submission is uncertain. It is submitted again
+// only if NotFound reports that the API does not know any of its extensions.
+func (o *Outbox) reconcile(ctx context.Context, entry *OutboxEntry, options ...RequestOptionFunc) error {
+	entry.Polls++
+	for _, requestID := range entry.RequestIDs {
+		_, resp, err := o.client.Status.GetTransactionStatus(ctx, &StatusOptions{
+			ClientID:  entry.Payment.PaymentHeader.ClientID,
+			RequestID: requestID,
+		}, options...)
+		switch {
+		case err == nil:
+			entry.State, entry.Uncertain, entry.Polls = OutboxSubmitted, false, 0
+			return o.poll(ctx, entry, options...)
+		case o.NotFound == nil || !o.NotFound(resp, err):
+			return fmt.Errorf("checking status of uncertain payment: %w", err)
+		}
+	}
+
+	return o.submit(ctx, entry, options...)
+}
+
+// poll records the final statuses of the extensions of the payment, and completes it once all are final.
+func (o *Outbox) poll(ctx context.Context, entry *OutboxEntry, options ...RequestOptionFunc) error {
+	entry.Polls++
+	if entry.Statuses == nil {
+		entry.Statuses = make(map[string]*TransactionStatus)
+	}
+
+	var errs []error
+	for _, requestID := range entry.RequestIDs {
+		if _, ok := entry.Statuses[requestID]; ok {
+			continue
+		}
+
+		status, _, err := o.client.Status.GetTransactionStatus(ctx, &StatusOptions{
+			ClientID:  entry.Payment.PaymentHeader.ClientID,
+			RequestID: requestID,
+		}, options...)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("getting status of %s: %w", requestID, err))
+		case status.Status.IsTerminal():
+			entry.Statuses[requestID] = status
+		}
+	}
+
+	if len(entry.Statuses) == len(entry.RequestIDs) {
+		entry.State = OutboxCompleted
+	}
+	return errors.Join(errs...)
+}
+
+// backoff returns the time to wait before processing the entry again.
+func (o *Outbox) backoff(entry *OutboxEntry) time.Duration {
+	attempt := entry.Attempts
+	if entry.State == OutboxSubmitted {
+		attempt = entry.Polls
+	}
+	if o.Backoff != nil {
+		return o.Backoff(attempt)
+	}
+
+	d := time.Second
+	for i := 1; i < attempt && d < maxOutboxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxOutboxBackoff)
+}
+
+// MemoryOutboxStore is an in-memory OutboxStore. Its payments do not survive process restarts,
+// so it is mostly useful for tests.
+type MemoryOutboxStore struct {
+	mu         sync.Mutex
+	entries    map[string]*OutboxEntry
+	requestIDs map[string]string
+}
+
+// NewMemoryOutboxStore returns a new, empty MemoryOutboxStore.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{
+		entries:    make(map[string]*OutboxEntry),
+		requestIDs: make(map[string]string),
+	}
+}
+
+// Add implements OutboxStore.
+func (s *MemoryOutboxStore) Add(_ context.Context, entry *OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, requestID := range entry.RequestIDs {
+		if id, ok := s.requestIDs[requestID]; ok {
+			return fmt.Errorf("%w: request %s is already in outbox entry %s", ErrDuplicatePayment, requestID, id)
+		}
+	}
+
+	for _, requestID := range entry.RequestIDs {
+		s.requestIDs[requestID] = entry.ID
+	}
+	s.entries[entry.ID] = cloneOutboxEntry(entry)
+	return nil
+}
+
+// Due implements OutboxStore.
+func (s *MemoryOutboxStore) Due(_ context.Context, now time.Time, lease time.Duration, limit int) ([]*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*OutboxEntry
+	for _, entry := range s.entries {
+		if (entry.State == OutboxPending || entry.State == OutboxSubmitted) && !entry.NextAttemptAt.After(now) {
+			due = append(due, cloneOutboxEntry(entry))
+		}
+	}
+
+	slices.SortFunc(due, func(a, b *OutboxEntry) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for _, entry := range due {
+		s.entries[entry.ID].NextAttemptAt = now.Add(lease)
+	}
+	return due, nil
+}
+
+// Update implements OutboxStore.
+func (s *MemoryOutboxStore) Update(_ context.Context, entry *OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[entry.ID]; !ok {
+		return fmt.Errorf("outbox entry %s not found", entry.ID)
+	}
+	s.entries[entry.ID] = cloneOutboxEntry(entry)
+	return nil
+}
+
+// Get implements OutboxStore.
+func (s *MemoryOutboxStore) Get(_ context.Context, id string) (*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneOutboxEntry(entry), nil
+}
+
+// cloneOutboxEntry returns a copy of the entry which shares the payment but not the statuses.
+func cloneOutboxEntry(entry *OutboxEntry) *OutboxEntry {
+	c := *entry
+	c.RequestIDs = slices.Clone(entry.RequestIDs)
+	c.Statuses = maps.Clone(entry.Statuses)
+	return &c
+}
@@ -0,0 +1,206 @@
+package ecobank
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultOutboxTable = "ecobank_outbox"
+
+// SQLOutboxStore is an OutboxStore backed by a SQL database. It is a reference implementation which only
+// uses portable SQL, and stores each entry as JSON along with the columns needed to find the due entries.
+//
+// Create the tables with the statements returned by Schema before using the store.
+type SQLOutboxStore struct {
+	// DB is the database the entries are stored in.
+	DB *sql.DB
+	// Table is the name of the table of the entries. The request IDs are stored in a table with the
+	// same name suffixed with _requests. It defaults to ecobank_outbox.
+	Table string
+	// Placeholder returns the placeholder of the nth argument of a query, counting from 1.
+	// It defaults to ?, as used by MySQL and SQLite. Use DollarPlaceholder for PostgreSQL.
+	Placeholder func(n int) string
+}
+
+// DollarPlaceholder returns the PostgreSQL placeholder of the nth argument of a query, e.g. $1.
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// Schema returns the statements creating the tables of the store.
+func (s *SQLOutboxStore) Schema() []string {
+	table := s.table()
+	return []string{
+		`CREATE TABLE IF NOT EXISTS ` + table + ` (
+	id VARCHAR(64) PRIMARY KEY,
+	state VARCHAR(16) NOT NULL,
+	next_attempt_at BIGINT NOT NULL,
+	created_at BIGINT NOT NULL,
+	data TEXT NOT NULL
+)`,
+		`CREATE TABLE IF NOT EXISTS ` + table + `_requests (
+	request_id VARCHAR(64) PRIMARY KEY,
+	entry_id VARCHAR(64) NOT NULL
+)`,
+	}
+}
+
+// Add implements OutboxStore. The entry and its request IDs are inserted in a transaction.
+func (s *SQLOutboxStore) Add(ctx context.Context, entry *OutboxEntry) (err error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	for _, requestID := range entry.RequestIDs {
+		var id string
+		err = tx.QueryRowContext(ctx, s.query("SELECT entry_id FROM %s_requests WHERE request_id = %s", 1), requestID).Scan(&id)
+		switch {
+		case err == nil:
+			return fmt.Errorf("%w: request %s is already in outbox entry %s", ErrDuplicatePayment, requestID, id)
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		if _, err = tx.ExecContext(ctx, s.query("INSERT INTO %s_requests (request_id, entry_id) VALUES (%s, %s)", 2), requestID, entry.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, s.query("INSERT INTO %s (id, state, next_attempt_at, created_at, data) VALUES (%s, %s, %s, %s, %s)", 5),
+		entry.ID, string(entry.State), entry.NextAttemptAt.UnixMilli(), entry.CreatedAt.UnixMilli(), string(data))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Due implements OutboxStore. The due entries are claimed one by one by moving their next attempt past the
+// lease, with an update conditional on the next attempt read, so that an entry claimed by another process in
+// the meantime is skipped. This only needs portable SQL, unlike SELECT ... FOR UPDATE SKIP LOCKED.
+func (s *SQLOutboxStore) Due(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OutboxEntry, error) {
+	type candidate struct {
+		id            string
+		nextAttemptAt int64
+		data          string
+	}
+
+	rows, err := s.DB.QueryContext(ctx,
+		s.query("SELECT id, next_attempt_at, data FROM %s WHERE state IN (%s, %s) AND next_attempt_at <= %s ORDER BY created_at LIMIT %s", 4),
+		string(OutboxPending), string(OutboxSubmitted), now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []candidate
+	for rows.Next() {
+		var c candidate
+		if err := rows.Scan(&c.id, &c.nextAttemptAt, &c.data); err != nil {
+			return nil, errors.Join(err, rows.Close())
+		}
+		candidates = append(candidates, c)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, err
+	}
+
+	var due []*OutboxEntry
+	for _, c := range candidates {
+		res, err := s.DB.ExecContext(ctx, s.query("UPDATE %s SET next_attempt_at = %s WHERE id = %s AND next_attempt_at = %s", 3),
+			now.Add(lease).UnixMilli(), c.id, c.nextAttemptAt)
+		if err != nil {
+			return due, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return due, err
+		}
+		if n == 0 {
+			// claimed by another process
+			continue
+		}
+
+		entry := new(OutboxEntry)
+		if err := json.Unmarshal([]byte(c.data), entry); err != nil {
+			return due, err
+		}
+		due = append(due, entry)
+	}
+	return due, nil
+}
+
+// Update implements OutboxStore.
+func (s *SQLOutboxStore) Update(ctx context.Context, entry *OutboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.DB.ExecContext(ctx, s.query("UPDATE %s SET state = %s, next_attempt_at = %s, data = %s WHERE id = %s", 4),
+		string(entry.State), entry.NextAttemptAt.UnixMilli(), string(data), entry.ID)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("outbox entry %s not found", entry.ID)
+	}
+	return nil
+}
+
+// Get implements OutboxStore.
+func (s *SQLOutboxStore) Get(ctx context.Context, id string) (*OutboxEntry, error) {
+	var data string
+	err := s.DB.QueryRowContext(ctx, s.query("SELECT data FROM %s WHERE id = %s", 1), id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := new(OutboxEntry)
+	if err := json.Unmarshal([]byte(data), entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *SQLOutboxStore) table() string {
+	if s.Table == "" {
+		return defaultOutboxTable
+	}
+	return s.Table
+}
+
+// query formats the query with the table name and n placeholders.
+func (s *SQLOutboxStore) query(format string, n int) string {
+	placeholder := s.Placeholder
+	if placeholder == nil {
+		placeholder = func(int) string { return "?" }
+	}
+
+	args := make([]any, n+1)
+	args[0] = s.table()
+	for i := 1; i <= n; i++ {
+		args[i] = placeholder(i)
+	}
+	return strings.TrimSpace(fmt.Sprintf(format, args...))
+}
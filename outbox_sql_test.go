@@ -0,0 +1,72 @@
+//go:build cgo
+
+package ecobank
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteOutboxStore(t *testing.T) *SQLOutboxStore {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	store := &SQLOutboxStore{DB: db}
+	for _, stmt := range store.Schema() {
+		_, err := db.ExecContext(t.Context(), stmt)
+		require.NoError(t, err)
+	}
+	return store
+}
+
+func TestSQLOutboxStore(t *testing.T) {
+	store := newSQLiteOutboxStore(t)
+
+	entry := &OutboxEntry{ID: "1", RequestIDs: []string{"1", "2"}, Payment: newOutboxPayment("1", "2"), State: OutboxPending}
+	require.NoError(t, store.Add(t.Context(), entry))
+	assert.ErrorIs(t, store.Add(t.Context(), &OutboxEntry{ID: "3", RequestIDs: []string{"3", "2"}, Payment: newOutboxPayment("3", "2")}), ErrDuplicatePayment)
+
+	got, err := store.Get(t.Context(), "3")
+	require.NoError(t, err)
+	assert.Nil(t, got, "entries with duplicate request IDs are not added")
+
+	got, err = store.Get(t.Context(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, got.RequestIDs)
+	assert.Equal(t, "6500184371", got.Payment.Extension[1].ParamList.(*PaymentParams[DomesticTransferParams]).Param().CreditAccountNo)
+
+	got.State = OutboxSubmitted
+	require.NoError(t, store.Update(t.Context(), got))
+	got, err = store.Get(t.Context(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, OutboxSubmitted, got.State)
+
+	assert.Error(t, store.Update(t.Context(), &OutboxEntry{ID: "4"}))
+}
+
+func TestSQLOutboxStore_Due(t *testing.T) {
+	testOutboxStoreDue(t, newSQLiteOutboxStore(t))
+}
+
+func TestSQLOutboxStore_Outbox(t *testing.T) {
+	api := &outboxAPI{statuses: map[string]string{"1": "SUCCESSFUL"}}
+	o := newOutbox(t, api)
+	o.Store = newSQLiteOutboxStore(t)
+
+	_, err := o.Enqueue(t.Context(), newOutboxPayment("1"))
+	require.NoError(t, err)
+
+	require.NoError(t, o.Process(t.Context()))
+	require.NoError(t, o.Process(t.Context()))
+	entry, err := o.Get(t.Context(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, OutboxCompleted, entry.State)
+	assert.Equal(t, 1, api.payments)
+}
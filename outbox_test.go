@@ -0,0 +1,418 @@
+package ecobank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outboxAPI fakes the payment and status endpoints for outbox tests.
+type outboxAPI struct {
+	payments     int
+	paymentError error
+	paymentCode  int
+	statuses     map[string]string // missing request IDs are not found
+}
+
+func newOutbox(t *testing.T, api *outboxAPI) *Outbox {
+	t.Helper()
+
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.RetryMax = 0
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			if err := req.Context().Err(); err != nil {
+				return nil, err
+			}
+			rec := httptest.NewRecorder()
+			if strings.HasSuffix(req.URL.Path, "merchant/payment") {
+				api.payments++
+				if api.paymentError != nil {
+					return nil, api.paymentError
+				}
+				if api.paymentCode != 0 {
+					rec.WriteHeader(api.paymentCode)
+					_, _ = rec.WriteString(`{"response_code": 400, "response_message": "error", "errors": ["Invalid account"]}`)
+					return rec.Result(), nil
+				}
+				_, _ = rec.WriteString(`{"response_code": 200, "response_content": "success"}`)
+				return rec.Result(), nil
+			}
+
+			var opt StatusOptions
+			if err := json.NewDecoder(req.Body).Decode(&opt); err != nil {
+				return nil, err
+			}
+			status, ok := api.statuses[opt.RequestID]
+			if !ok {
+				rec.WriteHeader(http.StatusNotFound)
+				_, _ = rec.WriteString(`{"response_code": 404, "response_message": "failed", "errors": ["Transaction not found"]}`)
+				return rec.Result(), nil
+			}
+			_, _ = rec.WriteString(`{"response_code": 200, "response_content": {"status": "` + status + `"}}`)
+			return rec.Result(), nil
+		},
+	}
+
+	o := NewOutbox(client, NewMemoryOutboxStore())
+	o.Backoff = func(int) time.Duration { return 0 }
+	return o
+}
+
+// notFound is the NotFound of the outboxes of the tests, matching how outboxAPI reports unknown requests.
+func notFound(resp *Response, _ error) bool {
+	return resp != nil && resp.Code == http.StatusNotFound
+}
+
+func newOutboxPayment(requestIDs ...string) *PaymentOptions {
+	opt := &PaymentOptions{PaymentHeader: PaymentHeader{ClientID: "EGHTelc000043", AffiliateCode: "EGH"}}
+	for _, id := range requestIDs {
+		opt.Extension = append(opt.Extension, PaymentExtension{
+			RequestID:   id,
+			RequestType: DOMESTIC,
+			ParamList:   NewPaymentParams(DomesticTransferParams{CreditAccountNo: "6500184371"}),
+			Amount:      decimal.NewFromInt(100),
+			Currency:    "GHS",
+		})
+	}
+	return opt
+}
+
+func TestOutbox_Enqueue(t *testing.T) {
+	o := newOutbox(t, &outboxAPI{})
+
+	entry, err := o.Enqueue(t.Context(), newOutboxPayment("1", "2"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", entry.ID)
+	assert.Equal(t, OutboxPending, entry.State)
+
+	_, err = o.Enqueue(t.Context(), newOutboxPayment("3", "2"))
+	assert.ErrorIs(t, err, ErrDuplicatePayment)
+	assert.ErrorContains(t, err, "request 2 is already in outbox entry 1")
+
+	_, err = o.Enqueue(t.Context(), newOutboxPayment(""))
+	assert.EqualError(t, err, "extension 0 has no request ID")
+}
+
+func TestOutbox_Process(t *testing.T) {
+	t.Run("submitted and completed", func(t *testing.T) {
+		api := &outboxAPI{statuses: map[string]string{"1": "PENDING", "2": "SUCCESSFUL"}}
+		o := newOutbox(t, api)
+
+		_, err := o.Enqueue(t.Context(), newOutboxPayment("1", "2"))
+		require.NoError(t, err)
+
+		require.NoError(t, o.Process(t.Context()))
+		entry, err := o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxSubmitted, entry.State)
+		assert.Equal(t, 1, entry.Attempts)
+
+		require.NoError(t, o.Process(t.Context()))
+		entry, err = o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxSubmitted, entry.State)
+		assert.Contains(t, entry.Statuses, "2")
+
+		api.statuses["1"] = "FAILED"
+		require.NoError(t, o.Process(t.Context()))
+		entry, err = o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxCompleted, entry.State)
+		assert.Equal(t, StatusFailed, entry.Statuses["1"].Status)
+		assert.Equal(t, 1, api.payments)
+
+		require.NoError(t, o.Process(t.Context()))
+		assert.Equal(t, 1, api.payments, "completed payments are not processed")
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		api := &outboxAPI{paymentCode: http.StatusBadRequest}
+		o := newOutbox(t, api)
+
+		_, err := o.Enqueue(t.Context(), newOutboxPayment("1"))
+		require.NoError(t, err)
+
+		assert.ErrorContains(t, o.Process(t.Context()), "outbox entry 1: Invalid account")
+		entry, err := o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxFailed, entry.State)
+		assert.Equal(t, "Invalid account", entry.LastError)
+
+		require.NoError(t, o.Process(t.Context()))
+		assert.Equal(t, 1, api.payments)
+	})
+
+	t.Run("uncertain and not found is resubmitted", func(t *testing.T) {
+		api := &outboxAPI{paymentError: errors.New("connection reset"), statuses: map[string]string{}}
+		o := newOutbox(t, api)
+		o.NotFound = notFound
+
+		_, err := o.Enqueue(t.Context(), newOutboxPayment("1"))
+		require.NoError(t, err)
+
+		assert.Error(t, o.Process(t.Context()))
+		entry, err := o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxPending, entry.State)
+		assert.True(t, entry.Uncertain)
+
+		api.paymentError = nil
+		require.NoError(t, o.Process(t.Context()))
+		entry, err = o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxSubmitted, entry.State)
+		assert.False(t, entry.Uncertain)
+		assert.Equal(t, 2, entry.Attempts)
+		assert.Equal(t, 2, api.payments)
+	})
+
+	t.Run("uncertain and found is not resubmitted", func(t *testing.T) {
+		api := &outboxAPI{paymentError: errors.New("connection reset"), statuses: map[string]string{}}
+		o := newOutbox(t, api)
+
+		_, err := o.Enqueue(t.Context(), newOutboxPayment("1"))
+		require.NoError(t, err)
+		assert.Error(t, o.Process(t.Context()))
+
+		api.statuses["1"] = "SUCCESSFUL"
+		require.NoError(t, o.Process(t.Context()))
+		entry, err := o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxCompleted, entry.State)
+		assert.Equal(t, 1, api.payments)
+	})
+
+	t.Run("uncertain is not resubmitted without NotFound", func(t *testing.T) {
+		api := &outboxAPI{paymentError: errors.New("connection reset"), statuses: map[string]string{}}
+		o := newOutbox(t, api)
+		o.MaxAttempts = 2
+
+		_, err := o.Enqueue(t.Context(), newOutboxPayment("1"))
+		require.NoError(t, err)
+
+		assert.Error(t, o.Process(t.Context()))
+		api.paymentError = nil
+		assert.ErrorContains(t, o.Process(t.Context()), "checking status of uncertain payment")
+		assert.Error(t, o.Process(t.Context()))
+		require.NoError(t, o.Process(t.Context()))
+
+		entry, err := o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxFailed, entry.State)
+		assert.True(t, entry.Uncertain, "failed uncertain payments are left to be reconciled")
+		assert.Equal(t, 1, api.payments)
+	})
+
+	t.Run("resubmitted with duplicate guard", func(t *testing.T) {
+		api := &outboxAPI{paymentError: errors.New("connection reset"), statuses: map[string]string{}}
+		o := newOutbox(t, api)
+		o.NotFound = notFound
+		require.NoError(t, WithDuplicateGuard(NewDuplicateGuard(time.Hour))(o.client))
+
+		_, err := o.Enqueue(t.Context(), newOutboxPayment("1"))
+		require.NoError(t, err)
+		assert.Error(t, o.Process(t.Context()))
+
+		api.paymentError = nil
+		require.NoError(t, o.Process(t.Context()))
+		entry, err := o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxSubmitted, entry.State)
+		assert.Equal(t, 2, api.payments)
+	})
+
+	t.Run("not sent is resubmitted", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			setup func(t *testing.T, o *Outbox) context.Context
+		}{
+			{"hook error", func(t *testing.T, o *Outbox) context.Context {
+				hookErr := errors.New("audit log unavailable")
+				require.NoError(t, WithPaymentHooks(PaymentHooks{OnSubmit: func(context.Context, *PaymentEvent) error {
+					return hookErr
+				}})(o.client))
+				return t.Context()
+			}},
+			{"login failure", func(t *testing.T, o *Outbox) context.Context {
+				o.client.username, o.client.password = "", ""
+				o.client.tokenExpiresAt = time.Now().Add(-time.Minute)
+				return t.Context()
+			}},
+			{"client closed", func(t *testing.T, o *Outbox) context.Context {
+				require.NoError(t, o.client.Close())
+				return t.Context()
+			}},
+			{"context cancelled", func(t *testing.T, _ *Outbox) context.Context {
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				api := &outboxAPI{}
+				o := newOutbox(t, api)
+
+				_, err := o.Enqueue(t.Context(), newOutboxPayment("1"))
+				require.NoError(t, err)
+
+				assert.Error(t, o.Process(tt.setup(t, o)))
+				entry, err := o.Get(t.Context(), "1")
+				require.NoError(t, err)
+				assert.Equal(t, OutboxPending, entry.State)
+				assert.False(t, entry.Uncertain, "payments which were not sent are not uncertain")
+				assert.Equal(t, 1, entry.Attempts)
+				assert.Zero(t, api.payments)
+			})
+		}
+	})
+
+	t.Run("invalid is failed", func(t *testing.T) {
+		api := &outboxAPI{}
+		o := newOutbox(t, api)
+
+		opt := newOutboxPayment("1")
+		opt.PaymentHeader.ExecutionDate = NewTime(time.Now().AddDate(0, 0, -2))
+		_, err := o.Enqueue(t.Context(), opt)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, o.Process(t.Context()), ErrExecutionDateInPast)
+		entry, err := o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxFailed, entry.State)
+		assert.False(t, entry.Uncertain)
+		assert.Zero(t, api.payments)
+	})
+
+	t.Run("payment of the caller is not changed", func(t *testing.T) {
+		o := newOutbox(t, &outboxAPI{statuses: map[string]string{}})
+
+		opt := newOutboxPayment("1")
+		opt.WaitForStatus = true
+		_, err := o.Enqueue(t.Context(), opt)
+		require.NoError(t, err)
+		opt.Extension[0].Amount = decimal.NewFromInt(1)
+
+		require.NoError(t, o.Process(t.Context()))
+		assert.True(t, opt.WaitForStatus)
+		assert.True(t, opt.PaymentHeader.ExecutionDate.IsZero())
+
+		entry, err := o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxSubmitted, entry.State)
+		assert.Equal(t, "100", entry.Payment.Extension[0].Amount.String())
+		assert.True(t, entry.Payment.PaymentHeader.ExecutionDate.IsZero(), "the execution date is defaulted on every submission")
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		api := &outboxAPI{paymentError: errors.New("connection reset"), statuses: map[string]string{}}
+		o := newOutbox(t, api)
+		o.NotFound = notFound
+		o.MaxAttempts = 2
+
+		_, err := o.Enqueue(t.Context(), newOutboxPayment("1"))
+		require.NoError(t, err)
+
+		assert.Error(t, o.Process(t.Context()))
+		assert.Error(t, o.Process(t.Context()))
+		require.NoError(t, o.Process(t.Context()))
+
+		entry, err := o.Get(t.Context(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxFailed, entry.State)
+		assert.Equal(t, 2, api.payments)
+	})
+}
+
+func TestMemoryOutboxStore_Due(t *testing.T) {
+	testOutboxStoreDue(t, NewMemoryOutboxStore())
+}
+
+// testOutboxStoreDue checks that the entries returned by Due are claimed for the lease.
+func testOutboxStoreDue(t *testing.T, store OutboxStore) {
+	t.Helper()
+
+	now := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	for i, id := range []string{"1", "2", "3"} {
+		require.NoError(t, store.Add(t.Context(), &OutboxEntry{
+			ID:            id,
+			RequestIDs:    []string{id},
+			Payment:       newOutboxPayment(id),
+			State:         OutboxPending,
+			NextAttemptAt: now,
+			CreatedAt:     now.Add(time.Duration(i) * time.Second),
+		}))
+	}
+
+	due, err := store.Due(t.Context(), now, time.Minute, 2)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "1", due[0].ID)
+	assert.Equal(t, "2", due[1].ID)
+
+	due, err = store.Due(t.Context(), now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1, "claimed entries are not due")
+	assert.Equal(t, "3", due[0].ID)
+
+	due, err = store.Due(t.Context(), now.Add(time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Len(t, due, 3, "entries are due again when the lease elapses")
+
+	due[0].State, due[0].NextAttemptAt = OutboxCompleted, now
+	require.NoError(t, store.Update(t.Context(), due[0]))
+	entry, err := store.Get(t.Context(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, OutboxCompleted, entry.State)
+
+	due, err = store.Due(t.Context(), now.Add(time.Hour), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Len(t, due, 2, "completed entries are not due")
+}
+
+func TestOutbox_Backoff(t *testing.T) {
+	o := &Outbox{}
+	assert.Equal(t, time.Second, o.backoff(&OutboxEntry{State: OutboxPending, Attempts: 1}))
+	assert.Equal(t, 8*time.Second, o.backoff(&OutboxEntry{State: OutboxPending, Attempts: 4}))
+	assert.Equal(t, 4*time.Second, o.backoff(&OutboxEntry{State: OutboxSubmitted, Attempts: 1, Polls: 3}))
+	assert.Equal(t, 5*time.Minute, o.backoff(&OutboxEntry{State: OutboxPending, Attempts: 30}))
+}
+
+func TestOutboxEntry_JSON(t *testing.T) {
+	entry := &OutboxEntry{
+		ID:         "1",
+		RequestIDs: []string{"1"},
+		Payment:    newOutboxPayment("1"),
+		State:      OutboxSubmitted,
+		Statuses:   map[string]*TransactionStatus{"1": {Status: "SUCCESSFUL"}},
+	}
+
+	data, err := json.Marshal(entry)
+	require.NoError(t, err)
+
+	var got OutboxEntry
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, OutboxSubmitted, got.State)
+	require.Len(t, got.Payment.Extension, 1)
+	assert.Equal(t, "6500184371", got.Payment.Extension[0].ParamList.(*PaymentParams[DomesticTransferParams]).Param().CreditAccountNo)
+	assert.True(t, decimal.NewFromInt(100).Equal(got.Payment.Extension[0].Amount))
+}
+
+func TestSQLOutboxStore_query(t *testing.T) {
+	s := &SQLOutboxStore{}
+	assert.Equal(t, "SELECT data FROM ecobank_outbox WHERE id = ?", s.query("SELECT data FROM %s WHERE id = %s", 1))
+
+	s = &SQLOutboxStore{Table: "payouts", Placeholder: DollarPlaceholder}
+	assert.Equal(t, "UPDATE payouts SET state = $1, data = $2 WHERE id = $3", s.query("UPDATE %s SET state = %s, data = %s WHERE id = %s", 3))
+	assert.Contains(t, s.Schema()[1], "CREATE TABLE IF NOT EXISTS payouts_requests")
+}
@@ -0,0 +1,155 @@
+package ecobank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const defaultMaxPages = 1000
+
+// Pagination is the paging of a response which returns one page of a list.
+// It is parsed into Response.Pagination from the response envelope, or from the response content if the
+// API nests it there. Response.Pagination is nil for responses which are not paged.
+type Pagination struct {
+	// Page is the number of the returned page, starting at 1.
+	Page int
+	// PageSize is the maximum number of records in a page.
+	PageSize int
+	// TotalRecords is the number of records in all pages.
+	TotalRecords int
+}
+
+// TotalPages returns the number of pages, or zero if the API did not return the page size.
+func (p *Pagination) TotalPages() int {
+	if p.PageSize <= 0 {
+		return 0
+	}
+	return (p.TotalRecords + p.PageSize - 1) / p.PageSize
+}
+
+// HasNext reports whether there are pages after the returned one.
+func (p *Pagination) HasNext() bool {
+	return p.Page < p.TotalPages()
+}
+
+// paginationFields are the paging fields returned by paged endpoints.
+type paginationFields struct {
+	Page         *pageNumber `json:"page"`
+	PageSize     *pageNumber `json:"pageSize"`
+	TotalRecords *pageNumber `json:"totalRecords"`
+}
+
+// pageNumber is a paging field, which the API returns as a number or a string.
+type pageNumber int
+
+func (n *pageNumber) UnmarshalJSON(b []byte) error {
+	s := string(bytes.Trim(b, `"`))
+	if s == "" || s == "null" {
+		return nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	*n = pageNumber(v)
+	return nil
+}
+
+// parsePagination returns the pagination of a response, from the paging fields decoded from its envelope or
+// else from its content, or nil if neither is paged.
+func parsePagination(envelope paginationFields, content []byte) *Pagination {
+	if p := envelope.pagination(); p != nil {
+		return p
+	}
+
+	// content which is not an object, e.g. a list, is not paged
+	if content = bytes.TrimSpace(content); len(content) == 0 || content[0] != '{' {
+		return nil
+	}
+	var fields paginationFields
+	if json.Unmarshal(content, &fields) != nil {
+		return nil
+	}
+	return fields.pagination()
+}
+
+// pagination returns the pagination of the fields, or nil if they are not paged.
+func (fields paginationFields) pagination() *Pagination {
+	if fields.TotalRecords == nil {
+		return nil
+	}
+
+	p := &Pagination{TotalRecords: int(*fields.TotalRecords)}
+	if fields.Page != nil {
+		p.Page = int(*fields.Page)
+	}
+	if fields.PageSize != nil {
+		p.PageSize = int(*fields.PageSize)
+	}
+	return p
+}
+
+// ListOptions are the paging options of requests to paged endpoints. Embed them in the request options and
+// use ListAll to fetch every page.
+type ListOptions struct {
+	// Page is the number of the page to return, starting at 1.
+	Page int `json:"page,omitempty"`
+	// PageSize is the maximum number of records to return.
+	PageSize int `json:"pageSize,omitempty"`
+}
+
+// ListAll fetches all the pages of a paged endpoint and returns their records.
+//
+// fetch is called with the options of every page in turn, starting at the page of opt or the first one,
+// and must return the records of the page along with the response. Pages are fetched until the
+// Response.Pagination of a page tells it is the last one, a page is empty, or the response is not paged.
+// Typically, fetch copies the paging options into the ListOptions of the request options and calls a service method.
+func ListAll[T any](ctx context.Context, opt *ListOptions, fetch func(ctx context.Context, opt *ListOptions) ([]T, *Response, error)) ([]T, error) {
+	var all []T
+	err := ForEachPage(ctx, opt, func(ctx context.Context, opt *ListOptions) ([]T, *Response, error) {
+		records, resp, err := fetch(ctx, opt)
+		all = append(all, records...)
+		return records, resp, err
+	})
+	return all, err
+}
+
+// ForEachPage calls fetch for every page of a paged endpoint like ListAll, without collecting the records,
+// so that large lists can be processed a page at a time.
+func ForEachPage[T any](ctx context.Context, opt *ListOptions, fetch func(ctx context.Context, opt *ListOptions) ([]T, *Response, error)) error {
+	page := ListOptions{Page: 1}
+	if opt != nil {
+		page = *opt
+		page.Page = max(page.Page, 1)
+	}
+
+	for range defaultMaxPages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		current := page
+		records, resp, err := fetch(ctx, &current)
+		if err != nil {
+			return err
+		}
+
+		if len(records) == 0 || resp == nil || resp.Pagination == nil || !resp.Pagination.HasNext() {
+			return nil
+		}
+
+		page.Page = current.Page + 1
+		if resp.Pagination.Page > 0 {
+			page.Page = resp.Pagination.Page + 1
+		}
+		if page.PageSize == 0 {
+			page.PageSize = resp.Pagination.PageSize
+		}
+	}
+
+	return fmt.Errorf("paged list has more than %d pages", defaultMaxPages)
+}
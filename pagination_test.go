@@ -0,0 +1,103 @@
+package ecobank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePagination(t *testing.T) {
+	parse := func(envelope, content string) *Pagination {
+		t.Helper()
+		var data responseData
+		require.NoError(t, json.Unmarshal([]byte(envelope), &data))
+		return parsePagination(data.paginationFields, []byte(content))
+	}
+
+	p := parse(`{"response_code": 200, "page": 2, "pageSize": "10", "totalRecords": 25}`, `[]`)
+	require.NotNil(t, p)
+	assert.Equal(t, Pagination{Page: 2, PageSize: 10, TotalRecords: 25}, *p)
+	assert.Equal(t, 3, p.TotalPages())
+	assert.True(t, p.HasNext())
+
+	p = parse(`{"response_code": 200}`, ` {"records": [], "page": "3", "pageSize": 10, "totalRecords": "25"}`)
+	require.NotNil(t, p)
+	assert.Equal(t, Pagination{Page: 3, PageSize: 10, TotalRecords: 25}, *p)
+	assert.False(t, p.HasNext())
+
+	assert.Nil(t, parse(`{"response_code": 200}`, `{"records": []}`))
+	assert.Nil(t, parse(`{"response_code": 200}`, `[{"totalRecords": 1}]`))
+	assert.Nil(t, parse(`{"response_code": 200}`, ``))
+}
+
+type testPagedOptions struct {
+	ListOptions
+	AffiliateCode string `json:"affiliateCode"`
+	secureHashOption
+}
+
+func TestListAll(t *testing.T) {
+	records := []string{"a", "b", "c", "d", "e"}
+
+	var pages []int
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			var opt testPagedOptions
+			body, _ := io.ReadAll(req.Body)
+			require.NoError(t, json.Unmarshal(body, &opt))
+			pages = append(pages, opt.Page)
+
+			start := min((opt.Page-1)*opt.PageSize, len(records))
+			end := min(start+opt.PageSize, len(records))
+			content, _ := json.Marshal(records[start:end])
+
+			resp := httptest.NewRecorder()
+			fmt.Fprintf(resp, `{"response_code": 200, "response_content": %s, "page": %d, "pageSize": %d, "totalRecords": %d}`,
+				content, opt.Page, opt.PageSize, len(records))
+			return resp.Result(), nil
+		},
+	}
+
+	all, err := ListAll(t.Context(), &ListOptions{PageSize: 2}, func(ctx context.Context, page *ListOptions) ([]string, *Response, error) {
+		opt := &testPagedOptions{ListOptions: *page, AffiliateCode: "EGH"}
+		list, resp, err := DoRequest[[]string](ctx, client, http.MethodPost, "merchant/list", opt)
+		if err != nil {
+			return nil, resp, err
+		}
+		return *list, resp, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, records, all)
+	assert.Equal(t, []int{1, 2, 3}, pages)
+}
+
+func TestForEachPage_NotPaged(t *testing.T) {
+	calls := 0
+	err := ForEachPage(t.Context(), nil, func(_ context.Context, opt *ListOptions) ([]int, *Response, error) {
+		calls++
+		assert.Equal(t, 1, opt.Page)
+		return []int{1, 2}, &Response{}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDecodeResponseStream_Pagination(t *testing.T) {
+	client := newMockClient(t, `{"response_code": 200, "page": 1, "pageSize": 50, "totalRecords": "120", "response_content": []}`, http.StatusOK)
+
+	req, err := client.NewRequest(t.Context(), http.MethodPost, "merchant/list", nil)
+	require.NoError(t, err)
+
+	resp, err := client.DoStream(req, nil)
+	require.NoError(t, err)
+	require.NotNil(t, resp.Pagination)
+	assert.Equal(t, Pagination{Page: 1, PageSize: 50, TotalRecords: 120}, *resp.Pagination)
+}
@@ -0,0 +1,79 @@
+package ecobank
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// EncodeParamList encodes key/value pairs in the format of the param_list of payment extensions, in order.
+//
+// The API expects the param_list as a JSON array of objects with a key and a value, which is itself
+// stringified, so the quotes of the array are escaped with backslashes:
+//
+//	"[{\"key\": \"billerCode\", \"value\": \"Pass_Bio_ECI\"},{\"key\": \"billRefNo\", \"value\": \"239729\"}]"
+//
+// Values are always strings. Nested values, such as the form data of bill payments, are stringified once
+// more, so their quotes are escaped twice. All the code depending on this format goes through
+// EncodeParamList and DecodeParamList.
+func EncodeParamList(pairs []PaymentParamPair) []byte {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	list := append(*buf, '[')
+	for i, pair := range pairs {
+		list = appendParamPair(list, i, pair.Key, pair.Value)
+	}
+	list = append(list, ']')
+	*buf = list
+
+	return quoteParamList(list)
+}
+
+// DecodeParamList decodes a param_list in the format described in EncodeParamList, e.g. the param_list
+// of an extension echoed by the API or of a webhook payload. Both the stringified array sent to the API and
+// a plain JSON array are accepted. The values are not unescaped further, so nested values are returned as
+// JSON.
+func DecodeParamList(b []byte) ([]PaymentParamPair, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return nil, fmt.Errorf("invalid param_list: %w", err)
+		}
+		b = []byte(s)
+	}
+
+	var entries []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("invalid param_list: %w", err)
+	}
+
+	pairs := make([]PaymentParamPair, len(entries))
+	for i, e := range entries {
+		pairs[i] = PaymentParamPair{Key: e.Key, Value: e.Value}
+	}
+
+	return pairs, nil
+}
+
+// appendParamPair appends the i-th key-value pair of a param list to dst.
+func appendParamPair(dst []byte, i int, key, value string) []byte {
+	if i > 0 {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, `{"key": `...)
+	dst = appendQuotedJSON(dst, key)
+	dst = append(dst, `, "value": `...)
+	dst = appendQuotedJSON(dst, value)
+	return append(dst, '}')
+}
+
+// quoteParamList returns the param list as a single quoted string, which is how it is sent.
+func quoteParamList(list []byte) []byte {
+	// escaping the quotes of the list makes it about a fifth longer
+	return appendQuotedJSON(make([]byte, 0, len(list)+len(list)/4+2), string(list))
+}
@@ -0,0 +1,155 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeParamList(t *testing.T) {
+	testCases := []struct {
+		name  string
+		pairs []PaymentParamPair
+		want  string
+		// lossy is set if the pairs are not decoded as they were given
+		lossy bool
+	}{
+		{
+			name: "empty",
+			want: `"[]"`,
+		},
+		{
+			name:  "pairs in order",
+			pairs: []PaymentParamPair{{"billerCode", "Pass_Bio_ECI"}, {"billRefNo", "239729"}},
+			want:  `"[{\"key\": \"billerCode\", \"value\": \"Pass_Bio_ECI\"},{\"key\": \"billRefNo\", \"value\": \"239729\"}]"`,
+		},
+		{
+			name:  "quotes and backslashes",
+			pairs: []PaymentParamPair{{"narration", `say "hi" \o/`}},
+			want:  `"[{\"key\": \"narration\", \"value\": \"say \\\"hi\\\" \\\\o/\"}]"`,
+		},
+		{
+			name:  "control characters",
+			pairs: []PaymentParamPair{{"address", "line 1\nline 2\t\x00"}},
+			want:  `"[{\"key\": \"address\", \"value\": \"line 1\\nline 2\\t\\u0000\"}]"`,
+		},
+		{
+			name:  "HTML is not escaped",
+			pairs: []PaymentParamPair{{"customerName", "Kay & Sons <Ltd>"}},
+			want:  `"[{\"key\": \"customerName\", \"value\": \"Kay & Sons <Ltd>\"}]"`,
+		},
+		{
+			name:  "unicode",
+			pairs: []PaymentParamPair{{"beneficiaryName", "Adéwálé\u2028"}},
+			want:  `"[{\"key\": \"beneficiaryName\", \"value\": \"Adéwálé\\u2028\"}]"`,
+		},
+		{
+			name:  "invalid UTF-8",
+			pairs: []PaymentParamPair{{"senderName", "Kay\xff"}},
+			want:  `"[{\"key\": \"senderName\", \"value\": \"Kay` + "\ufffd" + `\"}]"`,
+			lossy: true,
+		},
+		{
+			name:  "nested form data",
+			pairs: []PaymentParamPair{{"formDataValue", string(FormDataArray{{"LastName", "Kojo"}}.appendJSON(nil))}},
+			want:  `"[{\"key\": \"formDataValue\", \"value\": \"[{\\\"fieldName\\\": \\\"LastName\\\", \\\"fieldValue\\\": \\\"Kojo\\\"}]\"}]"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := EncodeParamList(tc.pairs)
+			assert.Equal(t, tc.want, string(b))
+			assert.True(t, json.Valid(b))
+
+			pairs, err := DecodeParamList(b)
+			require.NoError(t, err)
+			if !tc.lossy {
+				assert.Equal(t, append([]PaymentParamPair{}, tc.pairs...), pairs)
+			}
+		})
+	}
+}
+
+func TestDecodeParamList(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    []PaymentParamPair
+		wantErr string
+	}{
+		{
+			name:  "stringified array",
+			input: `"[{\"key\": \"ccy\", \"value\": \"GHS\"}]"`,
+			want:  []PaymentParamPair{{"ccy", "GHS"}},
+		},
+		{
+			name:  "plain array",
+			input: `[{"key": "ccy", "value": "GHS"}, {"key": "amount", "value": "10"}]`,
+			want:  []PaymentParamPair{{"ccy", "GHS"}, {"amount", "10"}},
+		},
+		{
+			name:  "surrounding whitespace",
+			input: "\n  \"[{\\\"key\\\": \\\"ccy\\\", \\\"value\\\": \\\"GHS\\\"}]\"  \n",
+			want:  []PaymentParamPair{{"ccy", "GHS"}},
+		},
+		{
+			name:  "unknown members and missing value",
+			input: `[{"key": "ccy", "type": "string"}]`,
+			want:  []PaymentParamPair{{"ccy", ""}},
+		},
+		{
+			name:  "empty",
+			input: `"[]"`,
+			want:  []PaymentParamPair{},
+		},
+		{
+			name:    "unterminated string",
+			input:   `"[{\"key\": \"ccy\"`,
+			wantErr: "invalid param_list: unexpected end of JSON input",
+		},
+		{
+			name:    "not an array",
+			input:   `{"ccy": "GHS"}`,
+			wantErr: "invalid param_list: json: cannot unmarshal object",
+		},
+		{
+			name:    "non-string value",
+			input:   `[{"key": "amount", "value": 10}]`,
+			wantErr: "invalid param_list: json: cannot unmarshal number",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pairs, err := DecodeParamList([]byte(tc.input))
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, pairs)
+		})
+	}
+}
+
+func FuzzParamList(f *testing.F) {
+	f.Add("billerCode", "Pass_Bio_ECI")
+	f.Add("", "")
+	f.Add(`"`, `\"}]`)
+	f.Add(" ", "<&>\n")
+
+	f.Fuzz(func(t *testing.T, key, value string) {
+		if !utf8.ValidString(key + value) {
+			t.Skip("invalid UTF-8 is replaced when encoding")
+		}
+
+		pairs := []PaymentParamPair{{Key: key, Value: value}, {Key: "ccy", Value: "GHS"}}
+		got, err := DecodeParamList(EncodeParamList(pairs))
+		require.NoError(t, err)
+		assert.Equal(t, pairs, got)
+	})
+}
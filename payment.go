@@ -1,9 +1,16 @@
 package ecobank
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/shopspring/decimal"
 )
 
@@ -26,7 +33,7 @@ type BillerInfo struct {
 	BillerCategory      string          `json:"billerCategory"`
 	BillerLogo          string          `json:"billerLogo"`
 	BillAmountType      string          `json:"billAmountType"`
-	BillAmount          decimal.Decimal `json:"billAmount"`
+	BillAmount          FlexibleDecimal `json:"billAmount"`
 	Currency            string          `json:"ccy"`
 	CollectionAccountNo string          `json:"collectionAccountNo"`
 	AggregatorName      string          `json:"aggregatorName"`
@@ -49,22 +56,37 @@ type GetBillerListOptions struct {
 // GetBillerList fetches the list of billers from the Ecobank API.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#eec6e30d-de2b-4565-89a1-cded3a7a8284
-func (p *PaymentService) GetBillerList(ctx context.Context, req *GetBillerListOptions) (*BillerList, *Response, error) {
-	return DoRequest[BillerList](ctx, p.client, http.MethodPost, "payment/getbillerlist", req)
+func (p *PaymentService) GetBillerList(ctx context.Context, req *GetBillerListOptions, options ...RequestOptionFunc) (*BillerList, *Response, error) {
+	return DoRequest[BillerList](ctx, p.client, http.MethodPost, p.client.path("Payment", "GetBillerList"), req, options...)
+}
+
+// ForEachBiller streams the list of billers from the Ecobank API, calling fn for each biller as it is decoded.
+// Unlike GetBillerList, the whole list is never held in memory, which makes it suitable for affiliates with
+// thousands of billers. Returning an error from fn stops the iteration and the error is returned.
+//
+// API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#eec6e30d-de2b-4565-89a1-cded3a7a8284
+func (p *PaymentService) ForEachBiller(ctx context.Context, opt *GetBillerListOptions, fn func(*BillerInfo) error, options ...RequestOptionFunc) (*Response, error) {
+	req, err := p.client.NewRequest(ctx, http.MethodPost, p.client.path("Payment", "GetBillerList"), opt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.client.DoStream(req, func(dec *json.Decoder) error {
+		return decodeObject(dec, func(key string) error {
+			if key == "billerInfo" {
+				return decodeArray(dec, fn)
+			}
+			return skipValue(dec)
+		})
+	})
 }
 
 // BillerList is the response payload for getting the biller list.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#eec6e30d-de2b-4565-89a1-cded3a7a8284
 type BillerList struct {
-	BillerInfo     []BillerInfo `json:"billerInfo"`
-	HostHeaderInfo struct {
-		SourceCode      string `json:"sourceCode"`
-		RequestID       string `json:"requestId"`
-		AffiliateCode   string `json:"affiliateCode"`
-		ResponseCode    int    `json:"responseCode"`
-		ResponseMessage string `json:"responseMessage"`
-	} `json:"hostHeaderInfo"`
+	BillerInfo     []BillerInfo   `json:"billerInfo"`
+	HostHeaderInfo HostHeaderInfo `json:"hostHeaderInfo"`
 }
 
 // GetBillerDetailsOptions represents the request payload for retrieving biller details.
@@ -76,27 +98,36 @@ type GetBillerDetailsOptions struct {
 	secureHashOption
 }
 
-// GetBillerDetails fetches details of a specific biller.
+// GetBillerDetails fetches details of a specific biller. The details are cached if the client was
+// created with WithBillerDetailsCache.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#22c57a29-be69-4ca6-8274-896defa6b2f9
-func (p *PaymentService) GetBillerDetails(ctx context.Context, opt *GetBillerDetailsOptions) (*BillerDetails, *Response, error) {
-	return DoRequest[BillerDetails](ctx, p.client, http.MethodPost, "/merchant/getbillerdetails", opt)
+func (p *PaymentService) GetBillerDetails(ctx context.Context, opt *GetBillerDetailsOptions, options ...RequestOptionFunc) (*BillerDetails, *Response, error) {
+	send := func(options ...RequestOptionFunc) (*BillerDetails, *Response, error) {
+		return DoRequest[BillerDetails](ctx, p.client, http.MethodPost, p.client.path("Payment", "GetBillerDetails"), opt, options...)
+	}
+
+	if cache := p.client.billerDetailsCache; cache != nil {
+		return p.getBillerDetails(cache, opt, send, options...)
+	}
+	return send(options...)
 }
 
 // ValidateBillerOptions represents the request payload for validating a biller.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#575a20cc-d7d1-4627-9665-1211622e1523
 type ValidateBillerOptions struct {
-	RequestID     string `json:"requestId"`
-	AffiliateCode string `json:"affiliateCode"`
-	BillerCode    string `json:"billerCode"`
-	ProductCode   string `json:"productCode"`
-	MobileNumber  string `json:"mobileNnumber"`
-	CustomerName  string `json:"customerName"`
-	FormDataValue []struct {
-		FieldName  string `json:"fieldName"`
-		FieldValue string `json:"fieldValue"`
-	} `json:"formDataValue"`
+	RequestID     string        `json:"requestId"`
+	AffiliateCode string        `json:"affiliateCode"`
+	BillerCode    string        `json:"billerCode"`
+	ProductCode   string        `json:"productCode"`
+	MobileNumber  string        `json:"mobileNnumber"`
+	CustomerName  string        `json:"customerName"`
+	FormDataValue FormDataArray `json:"formDataValue"`
+
+	// FormFields build the form data sent on top of FormDataValue, in order, e.g. with FormField.
+	// They are applied by ValidateBiller and not sent to the API.
+	FormFields []FormFieldFunc `json:"-"`
 
 	secureHashOption
 }
@@ -105,17 +136,11 @@ type ValidateBillerOptions struct {
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#575a20cc-d7d1-4627-9665-1211622e1523
 type ValidateBillerResponse struct {
-	HostHeaderInfo struct {
-		SourceCode      string `json:"sourceCode"`
-		RequestID       string `json:"requestId"`
-		AffiliateCode   string `json:"affiliateCode"`
-		ResponseCode    int    `json:"responseCode"`
-		ResponseMessage string `json:"responseMessage"`
-	} `json:"hostHeaderInfo"`
+	HostHeaderInfo     HostHeaderInfo  `json:"hostHeaderInfo"`
 	BillerCode         string          `json:"billerCode"`
 	BillRefNo          string          `json:"billRefNo"`
 	CustomerName       string          `json:"customerName"`
-	Amount             decimal.Decimal `json:"amount"`
+	Amount             FlexibleDecimal `json:"amount"`
 	PaymentDescription string          `json:"paymentDescription"`
 	ProductCode        string          `json:"productCode"`
 	ResponseValues     string          `json:"responseValues"`
@@ -129,11 +154,21 @@ type ValidateBillerResponse struct {
 	} `json:"formDataValue"`
 }
 
-// ValidateBiller validates a biller.
+// ValidateBiller validates a biller. The form data is built with the FormFields of opt, if any, before it is sent.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#575a20cc-d7d1-4627-9665-1211622e1523
-func (p *PaymentService) ValidateBiller(ctx context.Context, opt *ValidateBillerOptions) (*ValidateBillerResponse, *Response, error) {
-	return DoRequest[ValidateBillerResponse](ctx, p.client, http.MethodPost, "/merchant/validatebiller", opt)
+func (p *PaymentService) ValidateBiller(ctx context.Context, opt *ValidateBillerOptions, options ...RequestOptionFunc) (*ValidateBillerResponse, *Response, error) {
+	if len(opt.FormFields) > 0 {
+		formData, err := BuildFormData(opt.FormDataValue, opt.FormFields...)
+		if err != nil {
+			return nil, nil, err
+		}
+		built := *opt
+		built.FormDataValue, built.FormFields = formData, nil
+		opt = &built
+	}
+
+	return DoRequest[ValidateBillerResponse](ctx, p.client, http.MethodPost, p.client.path("Payment", "ValidateBiller"), opt, options...)
 }
 
 // PaymentOptions represents a request to make a payment.
@@ -143,25 +178,86 @@ type PaymentOptions struct {
 	PaymentHeader PaymentHeader      `json:"paymentHeader"`
 	Extension     []PaymentExtension `json:"extension"`
 
+	// WaitForStatus makes Pay poll the status of every extension after a successful submission
+	// until it is final, and record it in PaymentExtensionAck.FinalStatus. It is not sent to the API.
+	WaitForStatus bool `json:"-"`
+	// WaitTimeout limits how long Pay waits for the final statuses when WaitForStatus is set.
+	// It defaults to 2 minutes.
+	WaitTimeout time.Duration `json:"-"`
+
 	secureHashOption
 }
 
+const defaultStatusWaitTimeout = 2 * time.Minute
+
+// statusPollInterval is the interval between transaction status checks when waiting for final statuses.
+var statusPollInterval = 2 * time.Second
+
 // PaymentHeader is the main payload for a payment request.
 type PaymentHeader struct {
 	BatchSequence     string          `json:"batchsequence"`
 	BatchAmount       decimal.Decimal `json:"batchamount"`
-	Transactionamount decimal.Decimal `json:"transactionamount"`
+	TransactionAmount decimal.Decimal `json:"transactionamount"`
 	BatchID           string          `json:"batchid"`
 	TransactionCount  int             `json:"transactioncount"`
 	BatchCount        int             `json:"batchcount"`
 	TransactionID     string          `json:"transactionid"`
-	DebitType         string          `json:"debittype"`
+	DebitType         DebitType       `json:"debittype"`
 	AffiliateCode     string          `json:"affiliateCode"`
 	TotalBatches      string          `json:"totalbatches"`
-	ExecutionDate     Time            `json:"execution_date"`
+	ExecutionDate     Time            `json:"execution_date"` // defaults to the current time
 	ClientID          string          `json:"clientid"`
 }
 
+// NewPaymentHeader returns the header of a payment made of a single batch of transactionCount transactions
+// totaling amount, and validates it. The execution date defaults to the time of submission, and the debit
+// type can be set on the returned header.
+func NewPaymentHeader(clientID, affiliateCode, batchID, transactionID string, amount decimal.Decimal, transactionCount int) (PaymentHeader, error) {
+	h := PaymentHeader{
+		ClientID:          clientID,
+		AffiliateCode:     affiliateCode,
+		BatchID:           batchID,
+		TransactionID:     transactionID,
+		BatchSequence:     "1",
+		TotalBatches:      "1",
+		BatchAmount:       amount,
+		TransactionAmount: amount,
+		BatchCount:        transactionCount,
+		TransactionCount:  transactionCount,
+	}
+	return h, h.Validate()
+}
+
+// Validate checks that the fields required by the API are set: the client ID, affiliate code, batch ID
+// and transaction ID, a positive transaction count and a positive batch amount. The transaction amount,
+// if set, must equal the batch amount. All violations are returned joined together.
+func (h PaymentHeader) Validate() error {
+	var errs []error
+
+	for _, field := range []struct{ name, value string }{
+		{"client ID", h.ClientID},
+		{"affiliate code", h.AffiliateCode},
+		{"batch ID", h.BatchID},
+		{"transaction ID", h.TransactionID},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			errs = append(errs, fmt.Errorf("%s is required", field.name))
+		}
+	}
+
+	if h.TransactionCount <= 0 {
+		errs = append(errs, fmt.Errorf("transaction count %d must be positive", h.TransactionCount))
+	}
+	if !h.BatchAmount.IsPositive() {
+		errs = append(errs, fmt.Errorf("batch amount %s must be positive", h.BatchAmount))
+	}
+	if !h.TransactionAmount.IsZero() && !h.TransactionAmount.Equal(h.BatchAmount) {
+		errs = append(errs, fmt.Errorf("transaction amount %s does not match the batch amount %s", h.TransactionAmount, h.BatchAmount))
+	}
+
+	return errors.Join(errs...)
+}
+
 // PaymentExtension contains additional information for a payment request.
 type PaymentExtension struct {
 	RequestID   string                `json:"request_id"`
@@ -173,9 +269,273 @@ type PaymentExtension struct {
 	RateType    string                `json:"rate_type"`
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface for PaymentExtension.
+// The param_list is decoded into the typed params matching the RequestType, e.g. a DOMESTIC
+// extension gets a *PaymentParams[DomesticTransferParams]. Params of unknown request types
+// are decoded as raw key-value pairs.
+func (ext *PaymentExtension) UnmarshalJSON(b []byte) error {
+	type extension PaymentExtension
+	var raw struct {
+		extension
+		ParamList json.RawMessage `json:"param_list"`
+	}
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*ext = PaymentExtension(raw.extension)
+
+	if len(raw.ParamList) == 0 || string(raw.ParamList) == "null" {
+		return nil
+	}
+
+	params := paymentParamsFor(ext.RequestType)
+	if err := json.Unmarshal(raw.ParamList, params); err != nil {
+		return err
+	}
+	ext.ParamList = params
+
+	return nil
+}
+
+// PaymentAck is the acknowledgment of a payment submission.
+//
+// The API documents the response_content of payments as a plain message (e.g. "success"), which is
+// parsed into Message. Any other content is only kept in Raw. The batch and request IDs are filled in
+// from the submitted PaymentOptions so they are always available.
+//
+// API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#fca97841-db96-4828-bc1b-525e973efe91
+type PaymentAck struct {
+	BatchID       string
+	TransactionID string
+	// Code is the response_code of the acknowledgment.
+	Code int
+	// Message is the acknowledgment message when the API returns a plain text response.
+	Message    string
+	Extensions []PaymentExtensionAck
+	// Raw is the response_content as returned by the API.
+	Raw json.RawMessage
+}
+
+// PaymentExtensionAck is a single payment extension within an acknowledged batch.
+type PaymentExtensionAck struct {
+	RequestID   string
+	RequestType PaymentType
+
+	// FinalStatus is the final status of the transaction when PaymentOptions.WaitForStatus is set.
+	FinalStatus *TransactionStatus
+}
+
+// Accepted reports whether the API acknowledged the payment as successfully submitted, with a response
+// code of 000 or 200. Submission does not mean the payment has completed; use
+// StatusService.GetTransactionStatus for that.
+func (ack *PaymentAck) Accepted() bool {
+	return ack.Code == 0 || ack.Code == http.StatusOK
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for PaymentAck.
+func (ack *PaymentAck) UnmarshalJSON(b []byte) error {
+	ack.Raw = append(json.RawMessage(nil), b...)
+
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || b[0] != '"' {
+		return nil
+	}
+	return json.Unmarshal(b, &ack.Message)
+}
+
+// fillFrom fills in the batch and request IDs from the submitted payment options, and the response
+// code from the response acknowledging them.
+func (ack *PaymentAck) fillFrom(opt *PaymentOptions, resp *Response) {
+	ack.BatchID = opt.PaymentHeader.BatchID
+	ack.TransactionID = opt.PaymentHeader.TransactionID
+	ack.Extensions = make([]PaymentExtensionAck, len(opt.Extension))
+	for i, ext := range opt.Extension {
+		ack.Extensions[i] = PaymentExtensionAck{RequestID: ext.RequestID, RequestType: ext.RequestType}
+	}
+	if resp != nil {
+		ack.Code = resp.Code
+	}
+}
+
 // Pay sends a payment request to the Ecobank API.
 //
+// If the client has a DuplicateGuard, the payment is checked for duplicates before it is sent.
+// A payment rejected by the API is forgotten by the guard so it can be corrected and resubmitted,
+// but one that fails for any other reason is remembered since it may have been processed.
+//
+// If the execution date of the payment is not set, it is set to the current time of the client's clock.
+// Otherwise, it is checked with ValidateExecutionDate before the payment is sent. Params with a Validate
+// method, such as the withdrawal channel of TokenTransferParams, are validated too. If the client
+// has phone formats set with WithPhoneFormat, the phone numbers of the params are normalized in the
+// payment sent, without changing opt.
+//
+// The PaymentHooks set with WithPaymentHooks are called as the payment is submitted, acknowledged and,
+// with WaitForStatus, as the extensions reach their final status.
+//
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#fca97841-db96-4828-bc1b-525e973efe91
-func (p *PaymentService) Pay(ctx context.Context, opt *PaymentOptions) (*string, *Response, error) {
-	return DoRequest[string](ctx, p.client, http.MethodPost, "merchant/payment", opt)
+func (p *PaymentService) Pay(ctx context.Context, opt *PaymentOptions, options ...RequestOptionFunc) (*PaymentAck, *Response, error) {
+	if err := p.validate(opt); err != nil {
+		return nil, nil, err
+	}
+
+	if opt.PaymentHeader.ExecutionDate.GetTime().IsZero() {
+		opt.PaymentHeader.ExecutionDate = NewTime(p.client.now())
+	}
+
+	if len(p.client.phoneFormats) > 0 {
+		normalized, err := p.normalizePhoneNumbers(opt)
+		if err != nil {
+			return nil, nil, err
+		}
+		opt = normalized
+	}
+
+	guard := p.client.duplicateGuard
+
+	var guardKeys []string
+	if guard != nil {
+		keys, err := guard.check(ctx, opt)
+		if err != nil {
+			return nil, nil, errors.Join(err, guard.release(keys))
+		}
+		guardKeys = keys
+	}
+
+	hooks := p.client.paymentHooks
+
+	req, payload, err := p.newPaymentRequest(ctx, opt, options...)
+	if err == nil {
+		err = p.client.callPaymentHook(ctx, "OnSubmit", hooks.OnSubmit, &PaymentEvent{Options: opt, Payload: payload})
+	}
+	if err != nil {
+		// the payment was not sent, so it can be resubmitted
+		if guard != nil {
+			err = errors.Join(err, guard.release(guardKeys))
+		}
+		return nil, nil, err
+	}
+
+	ack := new(PaymentAck)
+	resp, err := p.client.Do(req, ack)
+	if err != nil {
+		var respErr *ResponseError
+		if guard != nil && errors.As(err, &respErr) {
+			err = errors.Join(err, guard.release(guardKeys))
+		}
+		event := &PaymentEvent{Options: opt, Payload: payload, Response: resp, Err: err}
+		return nil, resp, errors.Join(err, p.client.callPaymentHook(ctx, "OnAck", hooks.OnAck, event))
+	}
+
+	ack.fillFrom(opt, resp)
+
+	event := &PaymentEvent{Options: opt, Payload: payload, Ack: ack, Response: resp}
+	if err := p.client.callPaymentHook(ctx, "OnAck", hooks.OnAck, event); err != nil {
+		return ack, resp, err
+	}
+
+	if opt.WaitForStatus {
+		if err := p.waitForStatus(ctx, opt, ack, payload, options...); err != nil {
+			return ack, resp, err
+		}
+	}
+
+	return ack, resp, nil
+}
+
+// validate checks the execution date and the params of the payment before it is submitted.
+func (p *PaymentService) validate(opt *PaymentOptions) error {
+	if err := p.ValidateExecutionDate(opt); err != nil {
+		return err
+	}
+
+	for _, ext := range opt.Extension {
+		if v, ok := ext.ParamList.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return fmt.Errorf("extension %s: %w", ext.RequestID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// newPaymentRequest returns the request submitting the payment and its body.
+// The sensitive params of the payment are encrypted in the body if the client has a FieldEncrypter.
+func (p *PaymentService) newPaymentRequest(ctx context.Context, opt *PaymentOptions, options ...RequestOptionFunc) (*retryablehttp.Request, []byte, error) {
+	if p.client.fieldEncrypter != nil {
+		encrypted, err := encryptPayment(opt, p.client.fieldEncrypter)
+		if err != nil {
+			return nil, nil, err
+		}
+		opt = encrypted
+	}
+
+	req, err := p.client.NewRequest(ctx, http.MethodPost, p.client.path("Payment", "Pay"), opt, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload, err := req.BodyBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, payload, nil
+}
+
+// waitForStatus polls the status of every extension in the batch until all of them are final
+// or the wait timeout elapses, recording the statuses in ack.
+// The OnFinalStatus hook of the client is called for every final status, with the payload of the payment.
+func (p *PaymentService) waitForStatus(ctx context.Context, opt *PaymentOptions, ack *PaymentAck, payload []byte, options ...RequestOptionFunc) error {
+	timeout := opt.WaitTimeout
+	if timeout <= 0 {
+		timeout = defaultStatusWaitTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(statusPollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		pending := 0
+		for i := range ack.Extensions {
+			ext := &ack.Extensions[i]
+			if ext.FinalStatus != nil {
+				continue
+			}
+
+			status, _, err := p.client.Status.GetTransactionStatus(ctx, &StatusOptions{
+				ClientID:  opt.PaymentHeader.ClientID,
+				RequestID: ext.RequestID,
+			}, options...)
+			switch {
+			case err != nil:
+				lastErr = err
+				pending++
+			case status.Status.IsTerminal():
+				ext.FinalStatus = status
+				event := &PaymentEvent{Options: opt, Payload: payload, Ack: ack, Extension: ext}
+				if err := p.client.callPaymentHook(ctx, "OnFinalStatus", p.client.paymentHooks.OnFinalStatus, event); err != nil {
+					return err
+				}
+			default:
+				pending++
+			}
+		}
+
+		if pending == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(fmt.Errorf("waiting for final payment status: %w", ctx.Err()), lastErr)
+		case <-p.client.closed():
+			return errors.Join(fmt.Errorf("waiting for final payment status: %w", ErrClientClosed), lastErr)
+		case <-ticker.C:
+		}
+	}
 }
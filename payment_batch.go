@@ -0,0 +1,89 @@
+package ecobank
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DebitType is how the amount of a payment is debited, set in its header.
+type DebitType string
+
+const (
+	// DebitTypeSingle debits the batch amount once, so all the extensions must be in the same currency and
+	// debit the same account. Extensions cannot set their own debit account.
+	DebitTypeSingle DebitType = "Single"
+	// DebitTypeMultiple debits every extension separately, from the debit account set in its params if any.
+	// All the extensions must still be in the same currency.
+	DebitTypeMultiple DebitType = "Multiple"
+)
+
+// SplitBatches splits the payment into batches the API accepts: one batch per currency, and with
+// DebitTypeSingle one batch per source account of the extensions, in the order the currencies and accounts
+// first appear. The batches are checked with Check, and all violations are returned.
+//
+// If the payment does not need splitting, it is returned as is. Otherwise, the batches make up a multi-batch
+// payment which can be submitted with PaymentService.PayBatches: every batch gets a copy of the header with
+// the same batch ID, its BatchSequence and the TotalBatches, the transaction ID suffixed with the number of
+// the batch, e.g. E12T443308-2, and the amounts and counts of its extensions. The secure hash of the payment
+// is not copied, so it is generated for every batch.
+func (opt *PaymentOptions) SplitBatches() ([]*PaymentOptions, error) {
+	single := strings.EqualFold(string(opt.PaymentHeader.DebitType), string(DebitTypeSingle))
+
+	var keys []string
+	groups := make(map[string][]PaymentExtension)
+	for _, ext := range opt.Extension {
+		key := strings.ToUpper(ext.Currency)
+		if single {
+			key += "/" + paramString(ext.ParamList, "sourceAccount")
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], ext)
+	}
+
+	if len(keys) <= 1 {
+		return []*PaymentOptions{opt}, opt.Check()
+	}
+
+	batches := make([]*PaymentOptions, len(keys))
+	var errs []error
+	for i, key := range keys {
+		batch := &PaymentOptions{
+			PaymentHeader: opt.PaymentHeader,
+			Extension:     groups[key],
+			WaitForStatus: opt.WaitForStatus,
+			WaitTimeout:   opt.WaitTimeout,
+		}
+
+		sum := decimal.Zero
+		for _, ext := range batch.Extension {
+			sum = sum.Add(ext.Amount)
+		}
+
+		header := &batch.PaymentHeader
+		header.BatchSequence = strconv.Itoa(i + 1)
+		header.TotalBatches = strconv.Itoa(len(keys))
+		if header.TransactionID != "" {
+			header.TransactionID = fmt.Sprintf("%s-%d", header.TransactionID, i+1)
+		}
+		header.BatchAmount = sum
+		if !header.TransactionAmount.IsZero() {
+			header.TransactionAmount = sum
+		}
+		header.TransactionCount = len(batch.Extension)
+		if header.BatchCount != 0 {
+			header.BatchCount = len(batch.Extension)
+		}
+
+		if err := batch.Check(); err != nil {
+			errs = append(errs, fmt.Errorf("batch %d of %s: %w", i+1, header.BatchID, err))
+		}
+		batches[i] = batch
+	}
+	return batches, errors.Join(errs...)
+}
@@ -0,0 +1,132 @@
+package ecobank
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentOptions_SplitBatches(t *testing.T) {
+	t.Run("single currency", func(t *testing.T) {
+		opt := validTestBatch()
+
+		batches, err := opt.SplitBatches()
+		require.NoError(t, err)
+		require.Len(t, batches, 1)
+		assert.Same(t, opt, batches[0])
+	})
+
+	t.Run("by currency", func(t *testing.T) {
+		opt := validTestBatch()
+		opt.SetHash("stale")
+		opt.PaymentHeader.BatchCount = 3
+		opt.PaymentHeader.TransactionCount = 3
+		opt.PaymentHeader.BatchAmount = decimal.RequireFromString("620.50")
+		opt.PaymentHeader.TransactionAmount = decimal.RequireFromString("620.50")
+		opt.Extension = append(opt.Extension, PaymentExtension{
+			RequestID:   "2325",
+			RequestType: DOMESTIC,
+			Amount:      decimal.NewFromInt(100),
+			Currency:    "usd",
+			ParamList:   NewPaymentParams(DomesticTransferParams{Amount: decimal.NewFromInt(100), Currency: "USD"}),
+		})
+		require.ErrorContains(t, opt.Check(), "use SplitBatches")
+
+		batches, err := opt.SplitBatches()
+		require.NoError(t, err)
+		require.Len(t, batches, 2)
+
+		ghs, usd := batches[0], batches[1]
+		assert.Equal(t, "EG1593490", ghs.PaymentHeader.BatchID)
+		assert.Equal(t, "1", ghs.PaymentHeader.BatchSequence)
+		assert.Equal(t, "2", ghs.PaymentHeader.TotalBatches)
+		assert.Equal(t, "E12T443308-1", ghs.PaymentHeader.TransactionID)
+		assert.Equal(t, "520.5", ghs.PaymentHeader.BatchAmount.String())
+		assert.Equal(t, "520.5", ghs.PaymentHeader.TransactionAmount.String())
+		assert.Equal(t, 2, ghs.PaymentHeader.TransactionCount)
+		assert.Equal(t, 2, ghs.PaymentHeader.BatchCount)
+		assert.Len(t, ghs.Extension, 2)
+		assert.Empty(t, ghs.GetHash())
+
+		assert.Equal(t, "EG1593490", usd.PaymentHeader.BatchID)
+		assert.Equal(t, "2", usd.PaymentHeader.BatchSequence)
+		assert.Equal(t, "E12T443308-2", usd.PaymentHeader.TransactionID)
+		assert.Equal(t, "100", usd.PaymentHeader.BatchAmount.String())
+		assert.Equal(t, 1, usd.PaymentHeader.TransactionCount)
+		assert.Equal(t, "2325", usd.Extension[0].RequestID)
+
+		assert.Equal(t, "E12T443308", opt.PaymentHeader.TransactionID, "the payment is not modified")
+	})
+
+	t.Run("by source account", func(t *testing.T) {
+		token := func(requestID, account string) PaymentExtension {
+			return PaymentExtension{
+				RequestID:   requestID,
+				RequestType: TOKEN,
+				Amount:      decimal.NewFromInt(10),
+				Currency:    "GHS",
+				ParamList:   NewPaymentParams(TokenTransferParams{SourceAccount: account, Amount: decimal.NewFromInt(10), Currency: "GHS"}),
+			}
+		}
+		opt := &PaymentOptions{
+			PaymentHeader: PaymentHeader{
+				BatchID:          "EG1593490",
+				DebitType:        DebitTypeSingle,
+				BatchAmount:      decimal.NewFromInt(30),
+				TransactionCount: 3,
+			},
+			Extension: []PaymentExtension{token("1", "6500184371"), token("2", "6500184372"), token("3", "6500184371")},
+		}
+		require.ErrorContains(t, opt.Check(), "source account of extension 2 does not match the account debited once")
+
+		batches, err := opt.SplitBatches()
+		require.NoError(t, err)
+		require.Len(t, batches, 2)
+		assert.Equal(t, []string{"1", "3"}, []string{batches[0].Extension[0].RequestID, batches[0].Extension[1].RequestID})
+		assert.Equal(t, "2", batches[1].Extension[0].RequestID)
+
+		opt.PaymentHeader.DebitType = DebitTypeMultiple
+		require.NoError(t, opt.Check())
+		batches, err = opt.SplitBatches()
+		require.NoError(t, err)
+		assert.Len(t, batches, 1)
+	})
+
+	t.Run("invalid batches", func(t *testing.T) {
+		opt := validTestBatch()
+		opt.PaymentHeader.DebitType = "Once"
+		opt.Extension[1].Currency = "USD"
+
+		_, err := opt.SplitBatches()
+		require.ErrorIs(t, err, ErrInvalidBatch)
+		assert.ErrorContains(t, err, `batch 1 of EG1593490: invalid payment batch: debit type "Once" must be Single or Multiple`)
+		assert.ErrorContains(t, err, "batch 2 of EG1593490: invalid payment batch: debit type")
+		assert.NotContains(t, err.Error(), "currency")
+	})
+}
+
+func TestPaymentOptions_Check_DebitAccount(t *testing.T) {
+	opt := validTestBatch()
+	opt.PaymentHeader.DebitType = DebitTypeMultiple
+	opt.Extension[0].ParamList = NewPaymentParams(DomesticTransferParams{
+		DebitAccountNo: "6500184371",
+		Amount:         decimal.NewFromInt(500),
+		Currency:       "GHS",
+	})
+	require.NoError(t, opt.Check())
+
+	b, err := opt.Extension[0].ParamList.MarshalJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `debitAccountNo\", \"value\": \"6500184371`)
+
+	b, err = NewPaymentParams(DomesticTransferParams{}).MarshalJSON()
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "debitAccountNo", "the debit account is optional")
+
+	opt.PaymentHeader.DebitType = DebitTypeSingle
+	err = opt.Check()
+	require.ErrorIs(t, err, ErrInvalidBatch)
+	assert.EqualError(t, err, "invalid payment batch: extension 2323 has its own debit account, which requires debit type Multiple")
+}
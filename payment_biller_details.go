@@ -1,6 +1,17 @@
 package ecobank
 
-import "github.com/shopspring/decimal"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidBillAmount is returned when the amount of a bill payment is not accepted by the biller product.
+var ErrInvalidBillAmount = errors.New("invalid bill amount")
 
 // BillFormData represents input fields required for billing.
 //
@@ -26,10 +37,10 @@ type BillerProductInfo struct {
 	ProductDescription string          `json:"productDescription"`
 	ProductCategory    string          `json:"productCategory"`
 	AmountType         string          `json:"amountType"`
-	MinAmount          decimal.Decimal `json:"minAmount"`
-	MaxAmount          decimal.Decimal `json:"maxAmount"`
+	MinAmount          FlexibleDecimal `json:"minAmount"`
+	MaxAmount          FlexibleDecimal `json:"maxAmount"`
 	Currency           string          `json:"ccy"`
-	ExchangeRate       decimal.Decimal `json:"exchRate"`
+	ExchangeRate       FlexibleDecimal `json:"exchRate"`
 }
 
 // BillerDetails represents the response payload for retrieving biller details.
@@ -47,7 +58,7 @@ type BillerDetails struct {
 		BillerSite                string          `json:"billerSite"`
 		BillerLogo                string          `json:"billerLogo"`
 		BillAmountType            string          `json:"billAmountType"`
-		BillAmount                decimal.Decimal `json:"billAmount"`
+		BillAmount                FlexibleDecimal `json:"billAmount"`
 		CollectionAccountNo       string          `json:"collectionAccountNo"`
 		CollectionAccountName     string          `json:"collectionAccountName"`
 		CollectionAccountBankCode string          `json:"collectionAccountBankCode"`
@@ -59,11 +70,120 @@ type BillerDetails struct {
 	BillFormData []BillFormData `json:"billFormData"`
 
 	BillerProductInfo []BillerProductInfo `json:"billerProductInfo"`
-	HostHeaderInfo    struct {
-		SourceCode      string `json:"sourceCode"`
-		RequestID       string `json:"requestId"`
-		AffiliateCode   string `json:"affiliateCode"`
-		ResponseCode    string `json:"responseCode"`
-		ResponseMessage string `json:"responseMessage"`
-	} `json:"hostHeaderInfo"`
+	HostHeaderInfo    HostHeaderInfo      `json:"hostHeaderInfo"`
+}
+
+// Product returns the product of the biller with the given code. If code is empty and the biller
+// has a single product, that product is returned.
+func (d *BillerDetails) Product(code string) (*BillerProductInfo, bool) {
+	if code == "" && len(d.BillerProductInfo) == 1 {
+		return &d.BillerProductInfo[0], true
+	}
+	for i := range d.BillerProductInfo {
+		if d.BillerProductInfo[i].ProductCode == code {
+			return &d.BillerProductInfo[i], true
+		}
+	}
+	return nil, false
+}
+
+// ValidateAmount checks the amount against the minimum and maximum amount of the biller product,
+// and against the denominations of the biller if any are given. Use BillerInfo.Denominations to get
+// the denominations, which are only listed by PaymentService.GetBillerList.
+// The returned error wraps ErrInvalidBillAmount.
+func (d *BillerDetails) ValidateAmount(productCode string, amount decimal.Decimal, denominations ...decimal.Decimal) error {
+	product, ok := d.Product(productCode)
+	if !ok {
+		return fmt.Errorf("biller %s: %w: no product %q", d.BillerInfo.BillerCode, ErrInvalidBillAmount, productCode)
+	}
+
+	if err := product.ValidateAmount(amount); err != nil {
+		return fmt.Errorf("biller %s: %w", d.BillerInfo.BillerCode, err)
+	}
+
+	if len(denominations) > 0 && !slices.ContainsFunc(denominations, amount.Equal) {
+		return fmt.Errorf("biller %s: %w: amount %s is not one of the denominations %s",
+			d.BillerInfo.BillerCode, ErrInvalidBillAmount, amount, joinAmounts(denominations))
+	}
+
+	return nil
+}
+
+// ValidateAmount checks the amount against the minimum and maximum amount of the product.
+// A zero minimum or maximum is not checked. The returned error wraps ErrInvalidBillAmount.
+func (p *BillerProductInfo) ValidateAmount(amount decimal.Decimal) error {
+	if !amount.IsPositive() {
+		return fmt.Errorf("%w: amount %s of product %s must be positive", ErrInvalidBillAmount, amount, p.ProductCode)
+	}
+	if p.MinAmount.IsPositive() && amount.LessThan(p.MinAmount.Decimal) {
+		return fmt.Errorf("%w: amount %s is below the minimum %s %s of product %s",
+			ErrInvalidBillAmount, amount, p.MinAmount, p.Currency, p.ProductCode)
+	}
+	if p.MaxAmount.IsPositive() && amount.GreaterThan(p.MaxAmount.Decimal) {
+		return fmt.Errorf("%w: amount %s is above the maximum %s %s of product %s",
+			ErrInvalidBillAmount, amount, p.MaxAmount, p.Currency, p.ProductCode)
+	}
+	return nil
+}
+
+// Denominations parses the amount denominations of the biller, if it only accepts fixed amounts.
+// The denominations are separated by commas, semicolons, pipes or spaces.
+func (b *BillerInfo) Denominations() ([]decimal.Decimal, error) {
+	fields := strings.FieldsFunc(b.AmountDenominations, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|' || r == ' '
+	})
+
+	denominations := make([]decimal.Decimal, 0, len(fields))
+	for _, field := range fields {
+		d, err := decimal.NewFromString(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid denomination %q of biller %s: %w", field, b.BillerCode, err)
+		}
+		denominations = append(denominations, d)
+	}
+	return denominations, nil
+}
+
+func joinAmounts(amounts []decimal.Decimal) string {
+	s := make([]string, len(amounts))
+	for i, a := range amounts {
+		s[i] = a.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+// ValidateBillExtension checks the amount of a BILLPAYMENT or AIRTIMETOPUP extension against the product of
+// the biller, fetching the biller details with GetBillerDetails. requestID identifies the corporate, as in
+// GetBillerDetailsOptions. Call it when building extensions to fail fast, before the payment is sent.
+func (p *PaymentService) ValidateBillExtension(ctx context.Context, requestID, affiliateCode string, ext PaymentExtension, options ...RequestOptionFunc) error {
+	billerCode, productCode, err := billProduct(ext)
+	if err != nil {
+		return err
+	}
+
+	details, _, err := p.GetBillerDetails(ctx, &GetBillerDetailsOptions{
+		RequestID:     requestID,
+		AffiliateCode: affiliateCode,
+		BillerCode:    billerCode,
+	}, options...)
+	if err != nil {
+		return fmt.Errorf("getting details of biller %s: %w", billerCode, err)
+	}
+
+	if err := details.ValidateAmount(productCode, ext.Amount); err != nil {
+		return fmt.Errorf("extension %s: %w", ext.RequestID, err)
+	}
+	return nil
+}
+
+// billProduct returns the biller and product codes of a BILLPAYMENT or AIRTIMETOPUP extension.
+func billProduct(ext PaymentExtension) (billerCode, productCode string, err error) {
+	switch params := ext.ParamList.(type) {
+	case *PaymentParams[BillPaymentParams]:
+		return params.Param().BillerCode, params.Param().ProductCode, nil
+	case *PaymentParams[AirtimeTopupParams]:
+		return params.Param().BillerCode, params.Param().ProductCode, nil
+	default:
+		return "", "", fmt.Errorf("extension %s of type %s is not a bill payment", ext.RequestID, ext.RequestType)
+	}
 }
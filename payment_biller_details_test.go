@@ -0,0 +1,102 @@
+package ecobank
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/profclems/go-ecobank/ecobanktest"
+)
+
+func TestBillerDetails_ValidateAmount(t *testing.T) {
+	details := &BillerDetails{
+		BillerProductInfo: []BillerProductInfo{
+			{ProductCode: "02", MinAmount: NewFlexibleDecimal(decimal.NewFromInt(1)), MaxAmount: NewFlexibleDecimal(decimal.NewFromInt(1000)), Currency: "GHS"},
+			{ProductCode: "03"},
+		},
+	}
+	details.BillerInfo.BillerCode = "MTNPTU"
+
+	testCases := []struct {
+		name          string
+		product       string
+		amount        string
+		denominations []decimal.Decimal
+		wantErr       string
+	}{
+		{name: "in range", product: "02", amount: "10.50"},
+		{name: "minimum", product: "02", amount: "1"},
+		{name: "maximum", product: "02", amount: "1000"},
+		{name: "below minimum", product: "02", amount: "0.99", wantErr: "biller MTNPTU: invalid bill amount: amount 0.99 is below the minimum 1 GHS of product 02"},
+		{name: "above maximum", product: "02", amount: "1000.01", wantErr: "biller MTNPTU: invalid bill amount: amount 1000.01 is above the maximum 1000 GHS of product 02"},
+		{name: "no bounds", product: "03", amount: "5000"},
+		{name: "zero", product: "03", amount: "0", wantErr: "biller MTNPTU: invalid bill amount: amount 0 of product 03 must be positive"},
+		{name: "unknown product", product: "04", amount: "10", wantErr: `biller MTNPTU: invalid bill amount: no product "04"`},
+		{name: "denomination", product: "02", amount: "20.00", denominations: []decimal.Decimal{decimal.NewFromInt(10), decimal.NewFromInt(20)}},
+		{name: "not a denomination", product: "02", amount: "15", denominations: []decimal.Decimal{decimal.NewFromInt(10), decimal.NewFromInt(20)}, wantErr: "biller MTNPTU: invalid bill amount: amount 15 is not one of the denominations 10, 20"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := details.ValidateAmount(tc.product, decimal.RequireFromString(tc.amount), tc.denominations...)
+			if tc.wantErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidBillAmount)
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBillerInfo_Denominations(t *testing.T) {
+	denominations, err := (&BillerInfo{AmountDenominations: "5, 10|20;50.5"}).Denominations()
+	require.NoError(t, err)
+	assert.Equal(t, "5, 10, 20, 50.5", joinAmounts(denominations))
+
+	denominations, err = (&BillerInfo{}).Denominations()
+	require.NoError(t, err)
+	assert.Empty(t, denominations)
+
+	_, err = (&BillerInfo{BillerCode: "DSTV", AmountDenominations: "5,ten"}).Denominations()
+	assert.ErrorContains(t, err, `invalid denomination "ten" of biller DSTV`)
+}
+
+func TestPaymentService_ValidateBillExtension(t *testing.T) {
+	srv := httptest.NewServer(ecobanktest.MustLoad(t, "merchant/getbillerdetails", "success"))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient("user", "password", "lab-key",
+		WithBaseURL(srv.URL),
+		WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+	)
+	require.NoError(t, err)
+
+	ext := PaymentExtension{
+		RequestID:   "4321",
+		RequestType: AIRTIMETOPUP,
+		ParamList:   NewPaymentParams(AirtimeTopupParams{BillerCode: "MTNPTU", ProductCode: "02"}),
+		Amount:      decimal.NewFromInt(10),
+	}
+	assert.NoError(t, client.Payment.ValidateBillExtension(t.Context(), "ECO2112134346", "EGH", ext))
+
+	ext.Amount = decimal.NewFromInt(2000)
+	err = client.Payment.ValidateBillExtension(t.Context(), "ECO2112134346", "EGH", ext)
+	assert.ErrorIs(t, err, ErrInvalidBillAmount)
+	assert.ErrorContains(t, err, "extension 4321: biller MTNPTU")
+
+	ext.ParamList = NewPaymentParams(DomesticTransferParams{})
+	ext.RequestType = DOMESTIC
+	assert.EqualError(t, client.Payment.ValidateBillExtension(t.Context(), "ECO2112134346", "EGH", ext), "extension 4321 of type DOMESTIC is not a bill payment")
+
+	t.Run("api error", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 400, "response_message": "error", "errors": ["Biller not found"]}`, http.StatusBadRequest)
+		ext := PaymentExtension{RequestID: "4321", ParamList: NewPaymentParams(BillPaymentParams{BillerCode: "NOPE"})}
+		assert.ErrorContains(t, client.Payment.ValidateBillExtension(t.Context(), "ECO2112134346", "EGH", ext), "getting details of biller NOPE: Biller not found")
+	})
+}
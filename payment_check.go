@@ -0,0 +1,187 @@
+package ecobank
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidBatch is wrapped by the violations returned by PaymentOptions.Check.
+var ErrInvalidBatch = errors.New("invalid payment batch")
+
+// MaxPaymentIDLength is the maximum length of the batch, transaction, client and request IDs of a payment
+// checked by PaymentOptions.Check.
+var MaxPaymentIDLength = 50
+
+// Check verifies the integrity of the batch before it is submitted, so that it is not rejected by the API
+// after being partially processed. It checks that:
+//
+//   - the batch has extensions, and the transaction count matches their number
+//   - the batch amount, and the transaction amount if set, equal the sum of the extension amounts
+//   - all extensions are in the same currency, which matches the currency and amount of their params if set
+//   - the debit type is Single or Multiple, and with Single all extensions debit the same source account
+//     and none sets its own debit account
+//   - the batch has a batch ID, every extension has a unique request ID, and no ID is longer than MaxPaymentIDLength
+//
+// All violations are returned joined with errors.Join, each wrapping ErrInvalidBatch. Check returns nil
+// if the batch is valid.
+func (opt *PaymentOptions) Check() error {
+	var errs []error
+	violation := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidBatch, fmt.Sprintf(format, args...)))
+	}
+
+	header := opt.PaymentHeader
+
+	if len(opt.Extension) == 0 {
+		violation("batch has no extensions")
+	}
+	if header.TransactionCount != len(opt.Extension) {
+		violation("transaction count %d does not match the %d extensions", header.TransactionCount, len(opt.Extension))
+	}
+
+	checkID := func(name, id string) {
+		if n := utf8.RuneCountInString(id); n > MaxPaymentIDLength {
+			violation("%s %q is %d characters long, the maximum is %d", name, id, n, MaxPaymentIDLength)
+		}
+	}
+	if header.BatchID == "" {
+		violation("batch ID is empty")
+	}
+	checkID("batch ID", header.BatchID)
+	checkID("transaction ID", header.TransactionID)
+	checkID("client ID", header.ClientID)
+
+	single := strings.EqualFold(string(header.DebitType), string(DebitTypeSingle))
+	if header.DebitType != "" && !single && !strings.EqualFold(string(header.DebitType), string(DebitTypeMultiple)) {
+		violation("debit type %q must be %s or %s", header.DebitType, DebitTypeSingle, DebitTypeMultiple)
+	}
+
+	sum := decimal.Zero
+	requestIDs := make(map[string]bool, len(opt.Extension))
+	var currency, sourceAccount string
+
+	for i, ext := range opt.Extension {
+		sum = sum.Add(ext.Amount)
+
+		switch {
+		case ext.RequestID == "":
+			violation("extension %d has no request ID", i)
+		case requestIDs[ext.RequestID]:
+			violation("request ID %s is used by more than one extension", ext.RequestID)
+		default:
+			checkID("request ID", ext.RequestID)
+		}
+		requestIDs[ext.RequestID] = true
+
+		if !ext.Amount.IsPositive() {
+			violation("amount %s of extension %s must be positive", ext.Amount, ext.RequestID)
+		}
+
+		switch {
+		case ext.Currency == "":
+			violation("extension %s has no currency", ext.RequestID)
+		case currency == "":
+			currency = ext.Currency
+		case !strings.EqualFold(ext.Currency, currency):
+			violation("currency %s of extension %s does not match the currency %s of the batch, use SplitBatches to pay them in separate batches", ext.Currency, ext.RequestID, currency)
+		}
+
+		if single {
+			if paramString(ext.ParamList, "debitAccountNo") != "" {
+				violation("extension %s has its own debit account, which requires debit type %s", ext.RequestID, DebitTypeMultiple)
+			}
+			switch account := paramString(ext.ParamList, "sourceAccount"); {
+			case account == "":
+			case sourceAccount == "":
+				sourceAccount = account
+			case account != sourceAccount:
+				violation("source account of extension %s does not match the account debited once for the batch with debit type %s", ext.RequestID, DebitTypeSingle)
+			}
+		}
+
+		paramAmount, paramCurrency := paramAmountAndCurrency(ext.ParamList)
+		if paramCurrency != "" && ext.Currency != "" && !strings.EqualFold(paramCurrency, ext.Currency) {
+			violation("params currency %s of extension %s does not match its currency %s", paramCurrency, ext.RequestID, ext.Currency)
+		}
+		if !paramAmount.IsZero() && !paramAmount.Equal(ext.Amount) {
+			violation("params amount %s of extension %s does not match its amount %s", paramAmount, ext.RequestID, ext.Amount)
+		}
+	}
+
+	if !header.BatchAmount.Equal(sum) {
+		violation("batch amount %s does not match the sum %s of the extension amounts", header.BatchAmount, sum)
+	}
+	if !header.TransactionAmount.IsZero() && !header.TransactionAmount.Equal(sum) {
+		violation("transaction amount %s does not match the sum %s of the extension amounts", header.TransactionAmount, sum)
+	}
+
+	return errors.Join(errs...)
+}
+
+var decimalType = reflect.TypeFor[decimal.Decimal]()
+
+// paramAmountAndCurrency returns the amount and currency of typed params, from their amount and ccy fields.
+// Params without them, such as bill payments, return zero values.
+func paramAmountAndCurrency(params PaymentParamInterface) (amount decimal.Decimal, currency string) {
+	v, ok := paramStruct(params)
+	if !ok {
+		return amount, currency
+	}
+
+	for _, field := range paramFields(v.Type()) {
+		// fields promoted through a nil embedded pointer are empty
+		fv, err := v.FieldByIndexErr(field.Index)
+		if err != nil {
+			continue
+		}
+		switch {
+		case field.key == "amount" && field.Type == decimalType:
+			amount = fv.Interface().(decimal.Decimal)
+		case field.key == "ccy" && field.Type.Kind() == reflect.String:
+			currency = fv.String()
+		}
+	}
+	return amount, currency
+}
+
+// paramString returns the value of the string field of typed params with the given JSON key, such as the
+// sourceAccount of token transfers. Params without the field return an empty string.
+func paramString(params PaymentParamInterface, key string) string {
+	v, ok := paramStruct(params)
+	if !ok {
+		return ""
+	}
+	for _, field := range paramFields(v.Type()) {
+		if field.key == key && field.Type.Kind() == reflect.String {
+			if fv, err := v.FieldByIndexErr(field.Index); err == nil {
+				return fv.String()
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// paramStruct returns the struct of typed params.
+func paramStruct(params PaymentParamInterface) (reflect.Value, bool) {
+	var v reflect.Value
+	switch p := params.(type) {
+	case *customPaymentParams:
+		v = reflect.ValueOf(p.param)
+	case interface{ paramValue() any }:
+		v = reflect.ValueOf(p.paramValue())
+	default:
+		return v, false
+	}
+	return v, v.Kind() == reflect.Struct
+}
+
+// paramValue returns the underlying param struct.
+func (param *PaymentParams[T]) paramValue() any {
+	return param.param
+}
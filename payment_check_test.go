@@ -0,0 +1,91 @@
+package ecobank
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validTestBatch() *PaymentOptions {
+	return &PaymentOptions{
+		PaymentHeader: PaymentHeader{
+			BatchID:           "EG1593490",
+			TransactionID:     "E12T443308",
+			BatchAmount:       decimal.RequireFromString("520.50"),
+			TransactionAmount: decimal.RequireFromString("520.50"),
+			TransactionCount:  2,
+		},
+		Extension: []PaymentExtension{
+			{
+				RequestID:   "2323",
+				RequestType: DOMESTIC,
+				Amount:      decimal.NewFromInt(500),
+				Currency:    "GHS",
+				ParamList:   NewPaymentParams(DomesticTransferParams{Amount: decimal.NewFromInt(500), Currency: "GHS"}),
+			},
+			{
+				RequestID:   "2324",
+				RequestType: BILLPAYMENT,
+				Amount:      decimal.RequireFromString("20.5"),
+				Currency:    "GHS",
+				ParamList:   NewPaymentParams(BillPaymentParams{BillerCode: "ECG"}),
+			},
+		},
+	}
+}
+
+func TestPaymentOptions_Check(t *testing.T) {
+	require.NoError(t, validTestBatch().Check())
+
+	opt := validTestBatch()
+	opt.PaymentHeader.TransactionCount = 3
+	opt.PaymentHeader.TransactionID = strings.Repeat("X", MaxPaymentIDLength+1)
+	opt.Extension[1].RequestID = "2323"
+	opt.Extension[1].Currency = "USD"
+	opt.Extension[0].ParamList = NewPaymentParams(DomesticTransferParams{Amount: decimal.NewFromInt(50), Currency: "NGN"})
+
+	err := opt.Check()
+	require.ErrorIs(t, err, ErrInvalidBatch)
+
+	violations := strings.Split(err.Error(), "\n")
+	assert.Len(t, violations, 6)
+	for _, want := range []string{
+		"transaction count 3 does not match the 2 extensions",
+		"transaction ID",
+		"request ID 2323 is used by more than one extension",
+		"currency USD of extension 2323 does not match the currency GHS of the batch",
+		"params currency NGN of extension 2323 does not match its currency GHS",
+		"params amount 50 of extension 2323 does not match its amount 500",
+	} {
+		assert.ErrorContains(t, err, want)
+	}
+	assert.NotContains(t, err.Error(), "batch amount", "the batch amount matches the sum")
+}
+
+func TestPaymentOptions_Check_Empty(t *testing.T) {
+	err := (&PaymentOptions{}).Check()
+	assert.ErrorContains(t, err, "batch has no extensions")
+	assert.ErrorContains(t, err, "batch ID is empty")
+}
+
+func TestParamAmountAndCurrency_NilEmbeddedPointer(t *testing.T) {
+	type transfer struct {
+		Amount        decimal.Decimal `json:"amount"`
+		Currency      string          `json:"ccy"`
+		SourceAccount string          `json:"sourceAccount"`
+	}
+	type params struct {
+		*transfer
+		Narration string `json:"narration"`
+	}
+
+	param := &customPaymentParams{param: params{Narration: "salary"}}
+	amount, currency := paramAmountAndCurrency(param)
+	assert.True(t, amount.IsZero())
+	assert.Empty(t, currency)
+	assert.Empty(t, paramString(param, "sourceAccount"))
+	assert.Equal(t, "salary", paramString(param, "narration"))
+}
@@ -0,0 +1,61 @@
+package ecobank
+
+import (
+	"context"
+	"fmt"
+)
+
+// PaymentEvent describes a state transition of a payment submitted with PaymentService.Pay.
+type PaymentEvent struct {
+	// Options is the submitted payment.
+	Options *PaymentOptions
+	// Payload is the request body sent to the API, including the secure hash.
+	Payload []byte
+	// Ack is the acknowledgment of the payment. It is nil for OnSubmit and for rejected payments.
+	Ack *PaymentAck
+	// Response is the response of the API. It is nil for OnSubmit and OnFinalStatus.
+	Response *Response
+	// Err is the error the payment was rejected or failed with, for OnAck.
+	Err error
+	// Extension is the extension whose final status is reported, for OnFinalStatus.
+	Extension *PaymentExtensionAck
+}
+
+// PaymentHooks are called by PaymentService.Pay as a payment goes through its lifecycle, so that
+// applications can journal every state transition in their own database, e.g. in the same transaction
+// as the ledger entries of the payment.
+//
+// The hooks of a payment are called in order, on the goroutine calling Pay: OnSubmit, then OnAck,
+// then OnFinalStatus once for each extension. A hook is never called before the previous one returned.
+// Set the hooks on a client with WithPaymentHooks.
+type PaymentHooks struct {
+	// OnSubmit is called right before the payment is sent. Returning an error aborts the payment.
+	OnSubmit func(ctx context.Context, event *PaymentEvent) error
+	// OnAck is called once the API has answered the submission. If the payment was rejected or could
+	// not be sent, Err is set and Ack is nil. The error it returns is returned by Pay, along with the ack.
+	OnAck func(ctx context.Context, event *PaymentEvent) error
+	// OnFinalStatus is called for every extension whose final status is found while waiting for it,
+	// when PaymentOptions.WaitForStatus is set. Returning an error stops the wait.
+	OnFinalStatus func(ctx context.Context, event *PaymentEvent) error
+}
+
+// WithPaymentHooks sets the hooks called by PaymentService.Pay at every state transition of a payment.
+func WithPaymentHooks(hooks PaymentHooks) ClientOptionFunc {
+	return func(c *Client) error {
+		c.paymentHooks = hooks
+		return nil
+	}
+}
+
+// callPaymentHook calls the hook, if set, wrapping its error with the hook name.
+func (c *Client) callPaymentHook(ctx context.Context, name string, hook func(context.Context, *PaymentEvent) error, event *PaymentEvent) error {
+	if hook == nil {
+		return nil
+	}
+	defer c.trackHook()()
+
+	if err := hook(ctx, event); err != nil {
+		return fmt.Errorf("payment hook %s: %w", name, err)
+	}
+	return nil
+}
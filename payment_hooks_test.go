@@ -0,0 +1,141 @@
+package ecobank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentService_Pay_Hooks(t *testing.T) {
+	defer func(interval time.Duration) { statusPollInterval = interval }(statusPollInterval)
+	statusPollInterval = time.Millisecond
+
+	newOptions := func() *PaymentOptions {
+		return &PaymentOptions{
+			PaymentHeader: PaymentHeader{BatchID: "EG1593490", ClientID: "EGHTelc000043"},
+			Extension: []PaymentExtension{
+				{RequestID: "2323", RequestType: DOMESTIC, ParamList: NewPaymentParams(DomesticTransferParams{})},
+				{RequestID: "432", RequestType: TOKEN, ParamList: NewPaymentParams(TokenTransferParams{})},
+			},
+			WaitForStatus: true,
+		}
+	}
+
+	newClient := func(t *testing.T, paymentStatus int, hooks PaymentHooks) (*Client, *int) {
+		client := newMockClient(t, "", http.StatusOK)
+		require.NoError(t, WithPaymentHooks(hooks)(client))
+
+		payments := 0
+		client.client.HTTPClient.Transport = &mockHTTPClient{
+			requestHandler: func(req *http.Request) (*http.Response, error) {
+				rec := httptest.NewRecorder()
+				if strings.HasSuffix(req.URL.Path, "merchant/payment") {
+					payments++
+					rec.WriteHeader(paymentStatus)
+					if paymentStatus != http.StatusOK {
+						_, _ = rec.WriteString(`{"response_code": 400, "response_message": "error", "errors": ["Insufficient balance"]}`)
+						return rec.Result(), nil
+					}
+					_, _ = rec.WriteString(`{"response_code": 200, "response_content": "success"}`)
+					return rec.Result(), nil
+				}
+
+				var opt StatusOptions
+				if err := json.NewDecoder(req.Body).Decode(&opt); err != nil {
+					return nil, err
+				}
+				_, _ = fmt.Fprintf(rec, `{"response_code": 200, "response_content": {"status": "SUCCESSFUL"}}`)
+				return rec.Result(), nil
+			},
+		}
+		return client, &payments
+	}
+
+	t.Run("lifecycle", func(t *testing.T) {
+		var calls []string
+		client, _ := newClient(t, http.StatusOK, PaymentHooks{
+			OnSubmit: func(_ context.Context, e *PaymentEvent) error {
+				assert.Nil(t, e.Ack)
+				assert.Contains(t, string(e.Payload), `"secureHash"`)
+				calls = append(calls, "submit")
+				return nil
+			},
+			OnAck: func(_ context.Context, e *PaymentEvent) error {
+				require.NotNil(t, e.Ack)
+				assert.True(t, e.Ack.Accepted())
+				assert.NotNil(t, e.Response)
+				assert.Contains(t, string(e.Payload), `"batchid":"EG1593490"`)
+				calls = append(calls, "ack")
+				return nil
+			},
+			OnFinalStatus: func(_ context.Context, e *PaymentEvent) error {
+				assert.Equal(t, StatusSuccessful, e.Extension.FinalStatus.Status)
+				calls = append(calls, "final "+e.Extension.RequestID)
+				return nil
+			},
+		})
+
+		_, _, err := client.Payment.Pay(t.Context(), newOptions())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"submit", "ack", "final 2323", "final 432"}, calls)
+	})
+
+	t.Run("submit error aborts the payment", func(t *testing.T) {
+		errJournal := errors.New("journal unavailable")
+		client, payments := newClient(t, http.StatusOK, PaymentHooks{
+			OnSubmit: func(context.Context, *PaymentEvent) error { return errJournal },
+			OnAck: func(context.Context, *PaymentEvent) error {
+				t.Error("OnAck must not be called")
+				return nil
+			},
+		})
+
+		ack, _, err := client.Payment.Pay(t.Context(), newOptions())
+		assert.ErrorIs(t, err, errJournal)
+		assert.EqualError(t, err, "payment hook OnSubmit: journal unavailable")
+		assert.Nil(t, ack)
+		assert.Zero(t, *payments)
+	})
+
+	t.Run("rejected payment", func(t *testing.T) {
+		var ackErr error
+		client, _ := newClient(t, http.StatusBadRequest, PaymentHooks{
+			OnAck: func(_ context.Context, e *PaymentEvent) error {
+				assert.Nil(t, e.Ack)
+				ackErr = e.Err
+				return nil
+			},
+			OnFinalStatus: func(context.Context, *PaymentEvent) error {
+				t.Error("OnFinalStatus must not be called")
+				return nil
+			},
+		})
+
+		_, _, err := client.Payment.Pay(t.Context(), newOptions())
+		assert.ErrorIs(t, err, ErrInsufficientBalance)
+		assert.ErrorIs(t, ackErr, ErrInsufficientBalance)
+	})
+
+	t.Run("ack error stops the wait", func(t *testing.T) {
+		client, _ := newClient(t, http.StatusOK, PaymentHooks{
+			OnAck: func(context.Context, *PaymentEvent) error { return errors.New("conflict") },
+			OnFinalStatus: func(context.Context, *PaymentEvent) error {
+				t.Error("OnFinalStatus must not be called")
+				return nil
+			},
+		})
+
+		ack, _, err := client.Payment.Pay(t.Context(), newOptions())
+		assert.EqualError(t, err, "payment hook OnAck: conflict")
+		assert.NotNil(t, ack, "the payment was submitted")
+	})
+}
@@ -1,10 +1,19 @@
 package ecobank
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPaymentService_GetBillerList(t *testing.T) {
@@ -73,7 +82,7 @@ func TestPaymentService_GetBillerList(t *testing.T) {
 	assert.Equal(t, "METHODIST COLLECTION", resp.BillerInfo[0].BillerName)
 	assert.Equal(t, "/usr/app/Alert/ecobank_banner.jpg", resp.BillerInfo[0].BillerLogo)
 	assert.Equal(t, "NEWESB", resp.BillerInfo[0].AggregatorName)
-	assert.Equal(t, 0, resp.BillerInfo[0].BillAmount)
+	assert.Equal(t, decimal.NewFromInt(0), resp.BillerInfo[0].BillAmount.Decimal)
 
 	// Validate second biller
 	assert.Equal(t, "GHWATER", resp.BillerInfo[1].BillerCode)
@@ -82,7 +91,7 @@ func TestPaymentService_GetBillerList(t *testing.T) {
 	assert.Equal(t, "ECOBANK", resp.BillerInfo[1].BillerCategory)
 	assert.Equal(t, "/usr/app/Alert/ecobank_banner.jpg", resp.BillerInfo[1].BillerLogo)
 	assert.Equal(t, "GHANA WATER", resp.BillerInfo[1].AggregatorName)
-	assert.Equal(t, 1, resp.BillerInfo[1].BillAmount)
+	assert.Equal(t, decimal.NewFromInt(1), resp.BillerInfo[1].BillAmount.Decimal)
 	assert.Equal(t, "GHS", resp.BillerInfo[1].Currency)
 
 	// Validate host header info
@@ -167,13 +176,7 @@ func TestPaymentService_ValidateBiller(t *testing.T) {
 		ProductCode:   "02",
 		MobileNumber:  "0254875943",
 		CustomerName:  "Edu",
-		FormDataValue: []struct {
-			FieldName  string `json:"fieldName"`
-			FieldValue string `json:"fieldValue"`
-		}{{
-			FieldName:  "METER NUMBER",
-			FieldValue: "54140081982",
-		}},
+		FormDataValue: FormDataArray{{FieldName: "METER NUMBER", FieldValue: "54140081982"}},
 	}
 
 	resp, _, err := client.Payment.ValidateBiller(t.Context(), opt)
@@ -183,7 +186,7 @@ func TestPaymentService_ValidateBiller(t *testing.T) {
 	assert.Equal(t, "MTNPTU", resp.BillerCode)
 	assert.Equal(t, "46356262", resp.BillRefNo)
 	assert.Equal(t, "Benson", resp.CustomerName)
-	assert.Equal(t, 0, resp.Amount)
+	assert.Equal(t, decimal.NewFromInt(0), resp.Amount.Decimal)
 	assert.Equal(t, "", resp.PaymentDescription)
 	assert.Equal(t, "", resp.ProductCode)
 	assert.Equal(t, "", resp.ResponseValues)
@@ -208,3 +211,277 @@ func TestPaymentService_ValidateBiller(t *testing.T) {
 	assert.Equal(t, "000", resp.HostHeaderInfo.ResponseCode)
 	assert.Equal(t, "Success", resp.HostHeaderInfo.ResponseMessage)
 }
+
+func TestPaymentService_ValidateBiller_FormFields(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+
+	var sent struct {
+		FormDataValue []map[string]string `json:"formDataValue"`
+	}
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&sent); err != nil {
+				return nil, err
+			}
+			resp := httptest.NewRecorder()
+			_, err := resp.WriteString(`{"response_code": 200, "response_content": {"billerCode": "MTNPTU"}}`)
+			return resp.Result(), err
+		},
+	}
+
+	form := &BillerForm{BillerCode: "MTNPTU", Fields: []BillFormData{
+		{SerialNo: 1, FieldName: "MOBILE NUMBER", DataType: "NUMBER", ValidateField: "Y", MaxFieldLength: 10},
+		{SerialNo: 2, FieldName: "Network", ListOfValues: "MTN|Vodafone", DefaultValue: "MTN"},
+	}}
+	opt := &ValidateBillerOptions{
+		RequestID:     "EC12O2134521",
+		AffiliateCode: "EGH",
+		BillerCode:    "MTNPTU",
+		FormDataValue: FormDataArray{{FieldName: "MOBILE NUMBER", FieldValue: "0254875943"}},
+		FormFields:    []FormFieldFunc{FormField("MOBILE NUMBER", "0241234567"), FormField("network", "vodafone"), ValidForm(form)},
+	}
+
+	_, _, err := client.Payment.ValidateBiller(t.Context(), opt)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"fieldName": "MOBILE NUMBER", "fieldValue": "0241234567"},
+		{"fieldName": "Network", "fieldValue": "Vodafone"},
+	}, sent.FormDataValue)
+	assert.Len(t, opt.FormDataValue, 1, "the options are not modified")
+
+	opt.FormFields = []FormFieldFunc{FormField("MOBILE NUMBER", "233241234567"), ValidForm(form)}
+	_, _, err = client.Payment.ValidateBiller(t.Context(), opt)
+	assert.ErrorIs(t, err, ErrInvalidFormData)
+}
+
+func TestPaymentService_ForEachBiller(t *testing.T) {
+	mockResponse := `{
+		"response_code": 200,
+		"response_message": "success",
+		"response_content": {
+			"hostHeaderInfo": {
+				"sourceCode": "ECOBANKMOBILEAPP",
+				"requestId": "ECO2112134345",
+				"affiliateCode": "EGH",
+				"responseCode": "000",
+				"responseMessage": "Success"
+			},
+			"billerInfo": [
+				{"billerCode": "MGC", "billerID": 77427, "billerName": "METHODIST COLLECTION"},
+				{"billerCode": "GHWATER", "billerID": 76758, "billerName": "GHANA WATER", "billAmount": 1, "ccy": "GHS"},
+				{"billerCode": "ECG", "billerID": 76759, "billerName": "ECG POSTPAID"}
+			]
+		},
+		"response_timestamp": "2022-09-23T17:04:43.506"
+	}`
+
+	opt := &GetBillerListOptions{
+		RequestID:     "ECO2112134345",
+		AffiliateCode: "EGH",
+	}
+
+	t.Run("all billers", func(t *testing.T) {
+		client := newMockClient(t, mockResponse, http.StatusOK)
+
+		var codes []string
+		resp, err := client.Payment.ForEachBiller(t.Context(), opt, func(b *BillerInfo) error {
+			codes = append(codes, b.BillerCode)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.Code)
+		assert.Equal(t, "success", resp.Message)
+		assert.Equal(t, []string{"MGC", "GHWATER", "ECG"}, codes)
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		client := newMockClient(t, mockResponse, http.StatusOK)
+		errStop := errors.New("stop")
+
+		var count int
+		_, err := client.Payment.ForEachBiller(t.Context(), opt, func(b *BillerInfo) error {
+			count++
+			if b.BillerCode == "GHWATER" {
+				return errStop
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 200, "response_message": "success", "response_content": ""}`, http.StatusOK)
+
+		_, err := client.Payment.ForEachBiller(t.Context(), opt, func(b *BillerInfo) error {
+			t.Fatal("unexpected biller")
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("response errors", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 400, "errors": ["invalid secure hash"]}`, http.StatusBadRequest)
+
+		_, err := client.Payment.ForEachBiller(t.Context(), opt, func(b *BillerInfo) error {
+			return nil
+		})
+
+		var respErr *ResponseError
+		assert.ErrorAs(t, err, &respErr)
+		assert.Equal(t, []string{"invalid secure hash"}, respErr.All())
+	})
+}
+
+func TestPaymentService_Pay(t *testing.T) {
+	opt := &PaymentOptions{
+		PaymentHeader: PaymentHeader{
+			BatchID:       "EG1593490",
+			TransactionID: "E12T443308",
+		},
+		Extension: []PaymentExtension{
+			{RequestID: "2323", RequestType: DOMESTIC, ParamList: NewPaymentParams(DomesticTransferParams{})},
+			{RequestID: "432", RequestType: TOKEN, ParamList: NewPaymentParams(TokenTransferParams{})},
+		},
+	}
+
+	t.Run("plain message", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 200, "response_message": "success", "response_content": "success"}`, http.StatusOK)
+
+		ack, resp, err := client.Payment.Pay(t.Context(), opt)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.Code)
+		assert.True(t, ack.Accepted())
+		assert.Equal(t, "EG1593490", ack.BatchID)
+		assert.Equal(t, "E12T443308", ack.TransactionID)
+		assert.Equal(t, []PaymentExtensionAck{
+			{RequestID: "2323", RequestType: DOMESTIC},
+			{RequestID: "432", RequestType: TOKEN},
+		}, ack.Extensions)
+		assert.Equal(t, `"success"`, string(ack.Raw))
+	})
+
+	t.Run("undocumented content", func(t *testing.T) {
+		client := newMockClient(t, `{
+			"response_code": 200,
+			"response_message": "success",
+			"response_content": {"paymentHeader": {"batchid": "OTHER"}, "responseMessage": "Success"}
+		}`, http.StatusOK)
+
+		ack, _, err := client.Payment.Pay(t.Context(), opt)
+		assert.NoError(t, err)
+		assert.True(t, ack.Accepted())
+		assert.Empty(t, ack.Message)
+		assert.Equal(t, "EG1593490", ack.BatchID, "only documented fields are decoded")
+		assert.JSONEq(t, `{"paymentHeader": {"batchid": "OTHER"}, "responseMessage": "Success"}`, string(ack.Raw))
+	})
+
+	t.Run("not accepted", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 202, "response_message": "queued", "response_content": "success"}`, http.StatusOK)
+
+		ack, _, err := client.Payment.Pay(t.Context(), opt)
+		assert.NoError(t, err)
+		assert.Equal(t, "success", ack.Message)
+		assert.False(t, ack.Accepted(), "the response code is not successful")
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		client := newMockClient(t, `{"response_code": 400, "response_message": "failed", "errors": ["invalid secure hash"]}`, http.StatusBadRequest)
+
+		ack, _, err := client.Payment.Pay(t.Context(), opt)
+		assert.ErrorContains(t, err, "invalid secure hash")
+		assert.Nil(t, ack)
+	})
+}
+
+func TestPaymentService_Pay_WaitForStatus(t *testing.T) {
+	defer func(interval time.Duration) { statusPollInterval = interval }(statusPollInterval)
+	statusPollInterval = time.Millisecond
+
+	newClient := func(t *testing.T, statuses map[string][]string) *Client {
+		client := newMockClient(t, "", http.StatusOK)
+		client.client.HTTPClient.Transport = &mockHTTPClient{
+			requestHandler: func(req *http.Request) (*http.Response, error) {
+				rec := httptest.NewRecorder()
+				if strings.HasSuffix(req.URL.Path, "merchant/payment") {
+					_, _ = rec.WriteString(`{"response_code": 200, "response_content": "success"}`)
+					return rec.Result(), nil
+				}
+
+				var opt StatusOptions
+				if err := json.NewDecoder(req.Body).Decode(&opt); err != nil {
+					return nil, err
+				}
+				status := statuses[opt.RequestID][0]
+				if len(statuses[opt.RequestID]) > 1 {
+					statuses[opt.RequestID] = statuses[opt.RequestID][1:]
+				}
+				_, _ = fmt.Fprintf(rec, `{"response_code": 200, "response_content": {"transactionRefNo": "REF-%s", "status": %q}}`, opt.RequestID, status)
+				return rec.Result(), nil
+			},
+		}
+		return client
+	}
+
+	opt := &PaymentOptions{
+		PaymentHeader: PaymentHeader{BatchID: "EG1593490", ClientID: "EGHTelc000043"},
+		Extension: []PaymentExtension{
+			{RequestID: "2323", RequestType: DOMESTIC, ParamList: NewPaymentParams(DomesticTransferParams{})},
+			{RequestID: "432", RequestType: TOKEN, ParamList: NewPaymentParams(TokenTransferParams{})},
+		},
+		WaitForStatus: true,
+	}
+
+	t.Run("final statuses", func(t *testing.T) {
+		client := newClient(t, map[string][]string{
+			"2323": {"PENDING", "PENDING", "SUCCESSFUL"},
+			"432":  {"FAILED"},
+		})
+
+		ack, _, err := client.Payment.Pay(t.Context(), opt)
+		require.NoError(t, err)
+		require.Len(t, ack.Extensions, 2)
+		assert.Equal(t, StatusSuccessful, ack.Extensions[0].FinalStatus.Status)
+		assert.Equal(t, "REF-2323", ack.Extensions[0].FinalStatus.TransactionRefNo)
+		assert.Equal(t, StatusFailed, ack.Extensions[1].FinalStatus.Status)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client := newClient(t, map[string][]string{
+			"2323": {"PENDING"},
+			"432":  {"SUCCESSFUL"},
+		})
+
+		opt := *opt
+		opt.WaitTimeout = 20 * time.Millisecond
+
+		ack, _, err := client.Payment.Pay(t.Context(), &opt)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NotNil(t, ack)
+		assert.Nil(t, ack.Extensions[0].FinalStatus)
+		assert.Equal(t, StatusSuccessful, ack.Extensions[1].FinalStatus.Status)
+	})
+}
+
+func TestNewPaymentHeader(t *testing.T) {
+	h, err := NewPaymentHeader("EGHTelc000043", "EGH", "EG1593490", "E12T443308", decimal.NewFromInt(520), 6)
+	require.NoError(t, err)
+	assert.Equal(t, PaymentHeader{
+		ClientID:          "EGHTelc000043",
+		AffiliateCode:     "EGH",
+		BatchID:           "EG1593490",
+		TransactionID:     "E12T443308",
+		BatchSequence:     "1",
+		TotalBatches:      "1",
+		BatchAmount:       decimal.NewFromInt(520),
+		TransactionAmount: decimal.NewFromInt(520),
+		BatchCount:        6,
+		TransactionCount:  6,
+	}, h)
+
+	_, err = NewPaymentHeader("", "EGH", " ", "E12T443308", decimal.Zero, 0)
+	assert.EqualError(t, err, "client ID is required\nbatch ID is required\ntransaction count 0 must be positive\nbatch amount 0 must be positive")
+
+	h.TransactionAmount = decimal.NewFromInt(500)
+	assert.EqualError(t, h.Validate(), "transaction amount 500 does not match the batch amount 520")
+}
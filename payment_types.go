@@ -2,8 +2,13 @@ package ecobank
 
 import (
 	"encoding/json"
+	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/shopspring/decimal"
 )
@@ -65,10 +70,11 @@ type PaymentParamInterface interface {
 
 // SupportedPaymentParamTypes is a type constraint for generics in PaymentParams.
 // It ensures that only predefined payment parameter structs can be used.
+// User-defined param structs can be registered with RegisterPaymentParamType.
 type SupportedPaymentParamTypes interface {
 	DomesticTransferParams | TokenTransferParams | InterbankTransferParams |
 		BillPaymentParams | AirtimeTopupParams | MomoParams |
-		TokenIAParams | InterbankIAParams
+		TokenIAParams | InterbankIAParams | MomoIAParams
 }
 
 // PaymentParams represents the parameters for a payment.
@@ -81,91 +87,424 @@ func NewPaymentParams[T SupportedPaymentParamTypes](param T) PaymentParamInterfa
 	return &PaymentParams[T]{param: param}
 }
 
-// MarshalJSON implements the json.Marshaler interface for PaymentParams.
-// It serializes the struct fields into a JSON-encoded string that follows
-// a specific key-value format required by the ecobank API.
-//
-// **Format:**
-// The output is a JSON array of objects, where each object represents a field
-// with two properties:
-//   - "key": The JSON field name of the struct.
-//   - "value": The corresponding value from the struct.
-//
-// Example Output:
-// ```json
-// "[
+// Param returns the underlying payment parameter struct.
+func (param *PaymentParams[T]) Param() T {
+	return param.param
+}
+
+// Validate validates the params if the param struct has a Validate method, such as TokenIAParams.
+func (param *PaymentParams[T]) Validate() error {
+	if v, ok := any(param.param).(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for PaymentParams.
+// It parses the key-value format produced by MarshalJSON back into the typed struct,
+// so stored request payloads can be decoded for auditing and replay.
+// Keys that do not match a field of the struct are ignored.
+func (param *PaymentParams[T]) UnmarshalJSON(b []byte) error {
+	return unmarshalParamList(b, &param.param)
+}
+
+// paymentParamsFor returns empty params of the typed struct matching the payment type.
+// Params of unknown payment types are decoded as raw key-value pairs.
+func paymentParamsFor(paymentType PaymentType) interface {
+	PaymentParamInterface
+	json.Unmarshaler
+} {
+	switch paymentType {
+	case DOMESTIC:
+		return &PaymentParams[DomesticTransferParams]{}
+	case TOKEN:
+		return &PaymentParams[TokenTransferParams]{}
+	case INTERBANK:
+		return &PaymentParams[InterbankTransferParams]{}
+	case BILLPAYMENT:
+		return &PaymentParams[BillPaymentParams]{}
+	case AIRTIMETOPUP:
+		return &PaymentParams[AirtimeTopupParams]{}
+	case MOMO:
+		return &PaymentParams[MomoParams]{}
+	case TOKENIA:
+		return &PaymentParams[TokenIAParams]{}
+	case INTERBANKIA:
+		return &PaymentParams[InterbankIAParams]{}
+	case MOMOIA:
+		return &PaymentParams[MomoIAParams]{}
+	default:
+		return &paymentParamPairs{}
+	}
+}
+
+// registeredParamTypes holds the user-defined param struct types registered with RegisterPaymentParamType.
+var registeredParamTypes sync.Map
+
+// RegisterPaymentParamType registers the user-defined struct T so that it can be used as payment params
+// with NewCustomPaymentParams. This allows sending payment types the bank rolls out before they are
+// supported by this package.
 //
-//	{\"key\": \"billerCode\", \"value\": \"Pass_Bio_ECI\"},
-//	{\"key\": \"billRefNo\", \"value\": \"239729\"},
-//	{\"key\": \"customerName\", \"value\": \"Freeman Kay\"},
-//	{\"key\": \"formDataValue\", \"value\": \"[{\\\"fieldName\\\": \\\"LastName\\\", \\\"fieldValue\\\": \\\"Kojo\\\"}]\"}
+// T must be a struct. Every field with a json tag must be exported and of a type that can be formatted
+// as a param value: strings, numbers, booleans, decimal.Decimal, fmt.Stringer or FormDataArray.
+// Optional fields may be pointers to these types; with the omitempty option of the json tag, nil
+// and zero values are left out of the param_list. Fields without a json tag are ignored.
 //
-// ]"
-// ```
+// It is safe to call RegisterPaymentParamType concurrently.
+func RegisterPaymentParamType[T any]() error {
+	typ := reflect.TypeFor[T]()
+	if err := checkParamType(typ); err != nil {
+		return err
+	}
+
+	registeredParamTypes.Store(typ, struct{}{})
+	return nil
+}
+
+// NewCustomPaymentParams creates payment params from a struct registered with RegisterPaymentParamType.
+func NewCustomPaymentParams[T any](param T) (PaymentParamInterface, error) {
+	if _, ok := registeredParamTypes.Load(reflect.TypeFor[T]()); !ok {
+		return nil, fmt.Errorf("payment param type %s is not registered", reflect.TypeFor[T]())
+	}
+	return &customPaymentParams{param: param}, nil
+}
+
+// customPaymentParams holds a user-defined param struct registered with RegisterPaymentParamType.
+type customPaymentParams struct {
+	param any
+}
+
+// MarshalJSON implements the json.Marshaler interface using the same key-value format as PaymentParams.
+func (param *customPaymentParams) MarshalJSON() ([]byte, error) {
+	return marshalParamList(param.param, globalAmountFormat())
+}
+
+func (param *customPaymentParams) marshalParamList(format AmountFormat) ([]byte, error) {
+	return marshalParamList(param.param, format)
+}
+
+var (
+	formDataArrayType = reflect.TypeFor[FormDataArray]()
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+)
+
+// checkParamType reports whether typ satisfies the marshaling contract of marshalParamList.
+func checkParamType(typ reflect.Type) error {
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("payment param type %s must be a struct", typ)
+	}
+
+	for _, field := range paramFields(typ) {
+		if !field.IsExported() {
+			return fmt.Errorf("payment param field %s.%s must be exported", typ, field.Name)
+		}
+
+		if !isParamValueType(field.Type) {
+			return fmt.Errorf("payment param field %s.%s has unsupported type %s", typ, field.Name, field.Type)
+		}
+	}
+
+	return nil
+}
+
+// isParamValueType reports whether values of typ, or of the type it points to, can be formatted as a param value.
+func isParamValueType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer && !typ.Implements(stringerType) {
+		typ = typ.Elem()
+	}
+	if typ == formDataArrayType || typ.Implements(stringerType) {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// PaymentParamPair is a single key/value entry of a payment param_list.
+type PaymentParamPair struct {
+	Key   string
+	Value string
+}
+
+// NewPaymentParamsFromPairs creates payment params from raw key/value pairs which are sent in the given order.
+// It allows sending param_list fields that are not yet modeled by the typed param structs.
+func NewPaymentParamsFromPairs(pairs ...PaymentParamPair) PaymentParamInterface {
+	return paymentParamPairs(pairs)
+}
+
+// NewPaymentParamsFromMap creates payment params from a map of keys to values.
+// It allows sending param_list fields that are not yet modeled by the typed param structs.
 //
-// **Special Handling:**
-//   - The `formDataValue` field is itself a JSON-encoded array of objects, each
-//     containing a `fieldName` and `fieldValue`. This nested structure is stringified
-//     to maintain consistency with the external API's expected format.
+// Since maps are unordered, the params are sorted by key so that the payload is deterministic.
+// Use NewPaymentParamsFromPairs if the order of the params matters.
+func NewPaymentParamsFromMap(params map[string]string) PaymentParamInterface {
+	pairs := make(paymentParamPairs, 0, len(params))
+	for _, key := range slices.Sorted(maps.Keys(params)) {
+		pairs = append(pairs, PaymentParamPair{Key: key, Value: params[key]})
+	}
+	return pairs
+}
+
+type paymentParamPairs []PaymentParamPair
+
+// UnmarshalJSON implements the json.Unmarshaler interface for the key-value format produced by MarshalJSON.
+func (pairs *paymentParamPairs) UnmarshalJSON(b []byte) (err error) {
+	*pairs, err = DecodeParamList(b)
+	return err
+}
+
+// MarshalJSON implements the json.Marshaler interface using the same key-value format as PaymentParams.
+func (pairs paymentParamPairs) MarshalJSON() ([]byte, error) {
+	return EncodeParamList(pairs), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for PaymentParams.
+// It serializes the struct fields, keyed by their JSON field names, into the param_list format
+// required by the ecobank API, which is described in EncodeParamList. FormDataArray fields are
+// themselves stringified, see FormDataArray.MarshalJSON.
 func (param *PaymentParams[T]) MarshalJSON() ([]byte, error) {
-	val := reflect.ValueOf(param.param)
-	typ := reflect.TypeOf(param.param)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-		typ = typ.Elem()
+	return marshalParamList(param.param, globalAmountFormat())
+}
+
+func (param *PaymentParams[T]) marshalParamList(format AmountFormat) ([]byte, error) {
+	return marshalParamList(param.param, format)
+}
+
+// paramListMarshaler is implemented by the payment params whose amounts are formatted with an AmountFormat.
+type paramListMarshaler interface {
+	marshalParamList(format AmountFormat) ([]byte, error)
+}
+
+// marshalParamList serializes the fields of the param struct v into the param_list format
+// described in EncodeParamList, formatting amounts with format.
+func marshalParamList(v any, format AmountFormat) ([]byte, error) {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("payment params must be a struct, got %T", v)
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	list := append(*buf, '[')
+	n := 0
+	for _, field := range paramFields(val.Type()) {
+		if !field.IsExported() {
+			continue
+		}
+
+		// fields promoted through a nil embedded pointer are sent empty
+		fv, err := val.FieldByIndexErr(field.Index)
+		if field.omitEmpty && (err != nil || fv.IsZero()) {
+			continue
+		}
+
+		var value string
+		if err == nil {
+			value = formatParamValue(fv, format)
+		}
+		list = appendParamPair(list, n, field.key, value)
+		n++
+	}
+	list = append(list, ']')
+	*buf = list
+
+	return quoteParamList(list), nil
+}
+
+// formatParamValue formats the value of a param field. FormDataArray values are stringified
+// JSON arrays of objects with a fieldName and fieldValue, see FormDataArray.MarshalJSON.
+func formatParamValue(fv reflect.Value, format AmountFormat) string {
+	if fv.Kind() == reflect.Pointer && fv.Type().Elem() == formDataArrayType {
+		if fv.IsNil() {
+			return ""
+		}
+		fv = fv.Elem()
+	}
+
+	if fv.Type() == stringType {
+		// plain strings are not boxed into an interface, which allocates
+		return fv.String()
+	}
+
+	formData, ok := fv.Interface().(FormDataArray)
+	if !ok {
+		return formatValue(fv.Interface(), format)
+	}
+
+	return string(formData.appendJSON(nil))
+}
+
+var stringType = reflect.TypeFor[string]()
+
+// paramField is a field of a param struct with the key it is sent as.
+type paramField struct {
+	reflect.StructField
+	key string
+	// omitEmpty is set by the omitempty option of the json tag, leaving out zero values and nil pointers.
+	omitEmpty bool
+}
+
+// paramFieldsCache caches the fields of param types by type, as they are looked up for every payment.
+var paramFieldsCache sync.Map
+
+// paramFields returns the fields of the struct type typ which have a json tag, in struct order.
+// As with encoding/json, the fields of embedded structs are promoted.
+// The fields are cached and shared, so they must not be modified.
+func paramFields(typ reflect.Type) []paramField {
+	if fields, ok := paramFieldsCache.Load(typ); ok {
+		return fields.([]paramField)
+	}
+
+	var fields []paramField
+	for _, field := range reflect.VisibleFields(typ) {
+		key, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous || key == "" || key == "-" {
+			continue
+		}
+		fields = append(fields, paramField{
+			StructField: field,
+			key:         key,
+			omitEmpty:   slices.Contains(strings.Split(opts, ","), "omitempty"),
+		})
 	}
 
-	var b strings.Builder
-	b.WriteString(`"[`)
+	paramFieldsCache.Store(typ, fields)
+	return fields
+}
+
+// unmarshalParamList parses the param_list format described in EncodeParamList
+// into the fields of the struct pointed to by v, matching keys against the json tags.
+func unmarshalParamList(b []byte, v any) error {
+	pairs, err := DecodeParamList(b)
+	if err != nil {
+		return err
+	}
+
+	val := reflect.ValueOf(v).Elem()
 
-	for i := 0; i < val.NumField(); i++ {
-		fieldType := typ.Field(i)
-		fieldValue := val.Field(i)
+	fields := make(map[string][]int)
+	for _, field := range paramFields(val.Type()) {
+		if field.IsExported() {
+			fields[field.key] = field.Index
+		}
+	}
+
+	for _, pair := range pairs {
+		index, ok := fields[pair.Key]
+		if !ok {
+			continue
+		}
 
-		// get the json tag of the field
-		jsonTag := fieldType.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
+		fv, ok := fieldByIndexAlloc(val, index)
+		if !ok {
 			continue
 		}
+		if err := setParamValue(fv, pair.Value); err != nil {
+			return fmt.Errorf("invalid value for param %s: %w", pair.Key, err)
+		}
+	}
+
+	return nil
+}
 
-		// now we need to set the {"key": "jsonTag", "value": "value"}
-		b.WriteString(`{\"key\": \"`)
-		b.WriteString(jsonTag)
-		b.WriteString(`\", \"value\": \"`)
-		// check if the field is FormDataArray
-		if jsonTag == "formDataValue" {
-			formData := fieldValue.Interface().(FormDataArray)
-			b.WriteString(`[`)
-			for i, fd := range formData {
-				b.WriteString(`{\\\"fieldName\\\": \\\"`)
-				b.WriteString(fd.FieldName)
-				b.WriteString(`\\\", \\\"fieldValue\\\": \\\"`)
-				b.WriteString(fd.FieldValue)
-				b.WriteString(`\\\"}`)
-				if i < len(formData)-1 {
-					b.WriteString(`,`)
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex, but allocates nil embedded struct pointers on the way.
+// It reports false if the field cannot be reached.
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
 				}
+				v.Set(reflect.New(v.Type().Elem()))
 			}
-			b.WriteString(`]`)
-		} else {
-			b.WriteString(formatToStr(fieldValue.Interface()))
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
 
+// setParamValue parses the param value s into the field fv.
+func setParamValue(fv reflect.Value, s string) error {
+	// optional fields are left nil if the value is empty
+	if fv.Kind() == reflect.Pointer {
+		if s == "" {
+			return nil
 		}
-		b.WriteString(`\"}`)
-		if i < val.NumField()-1 {
-			b.WriteString(`,`)
+		elem := reflect.New(fv.Type().Elem())
+		if err := setParamValue(elem.Elem(), s); err != nil {
+			return err
 		}
+		fv.Set(elem)
+		return nil
 	}
 
-	b.WriteString(`]"`)
+	if fv.Type() == formDataArrayType {
+		if s == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(s), fv.Addr().Interface())
+	}
+
+	if s == "" {
+		return nil
+	}
+
+	if u, ok := fv.Addr().Interface().(json.Unmarshaler); ok {
+		quoted, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		return u.UnmarshalJSON(quoted)
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
 
-	// converted to a single quoted string
-	return []byte(b.String()), nil
+	return nil
 }
 
 // DomesticTransferParams represents the parameters for DOMESTIC payment type.
 type DomesticTransferParams struct {
-	CreditAccountNo     string          `json:"creditAccountNo"`
+	CreditAccountNo     string          `json:"creditAccountNo" redact:"partial"`
+	DebitAccountNo      string          `json:"debitAccountNo,omitempty" redact:"partial"` // optional: with debit type Multiple only
 	DebitAccountBranch  string          `json:"debitAccountBranch"`
 	DebitAccountType    string          `json:"debitAccountType"`
 	CreditAccountBranch string          `json:"creditAccountBranch"`
@@ -176,36 +515,36 @@ type DomesticTransferParams struct {
 
 // TokenTransferParams represents the parameters TOKEN payment type.
 type TokenTransferParams struct {
-	TransactionDescription string          `json:"transactionDescription"`
-	SecretCode             string          `json:"secretCode"`
-	SourceAccount          string          `json:"sourceAccount"`
-	SourceAccountCurrency  string          `json:"sourceAccountCurrency"`
-	SourceAccountType      string          `json:"sourceAccountType"`
-	SenderName             string          `json:"senderName"`
-	Currency               string          `json:"ccy"`
-	SenderMobileNo         string          `json:"senderMobileNo"`
-	Amount                 decimal.Decimal `json:"amount"`
-	SenderID               string          `json:"senderId"`
-	BeneficiaryName        string          `json:"beneficiaryName"`
-	BeneficiaryMobileNo    string          `json:"beneficiaryMobileNo"`
-	WithdrawalChannel      string          `json:"withdrawalChannel"`
+	TransactionDescription string            `json:"transactionDescription"`
+	SecretCode             string            `json:"secretCode" redact:"secret" encrypt:"true"`
+	SourceAccount          string            `json:"sourceAccount" redact:"partial"`
+	SourceAccountCurrency  string            `json:"sourceAccountCurrency"`
+	SourceAccountType      string            `json:"sourceAccountType"`
+	SenderName             string            `json:"senderName"`
+	Currency               string            `json:"ccy"`
+	SenderMobileNo         string            `json:"senderMobileNo" phone:"header" redact:"partial"`
+	Amount                 decimal.Decimal   `json:"amount"`
+	SenderID               string            `json:"senderId"`
+	BeneficiaryName        string            `json:"beneficiaryName"`
+	BeneficiaryMobileNo    string            `json:"beneficiaryMobileNo" phone:"header" redact:"partial"`
+	WithdrawalChannel      WithdrawalChannel `json:"withdrawalChannel"`
 }
 
 // TokenIAParams represents the parameters for TOKENIA payment type.
 type TokenIAParams struct {
 	DestinationAffiliate   string          `json:"destAffiliate"`
 	DestinationCurrency    string          `json:"destCrncy"`
-	DestinationAccount     string          `json:"destinationAccount"`
+	DestinationAccount     string          `json:"destinationAccount" redact:"partial"`
 	DestinationAccountName string          `json:"destinationAccountName"`
 	ReceiverFirstName      string          `json:"receiveFirstName"`
 	ReceiverLastName       string          `json:"receiveLastName"`
-	ReceiverPhoneNumber    string          `json:"receiverPhoneNumber"`
+	ReceiverPhoneNumber    string          `json:"receiverPhoneNumber" phone:"destAffiliate" redact:"partial"`
 	ReceiverEmailAddress   string          `json:"receiveEmailAddress"`
 	ReceiverIDType         string          `json:"receiveIdType"`
-	ReceiverIDNumber       string          `json:"receiveIdNumber"`
+	ReceiverIDNumber       string          `json:"receiveIdNumber" redact:"partial"`
 	SourceAmount           decimal.Decimal `json:"sourceAmount"`
 	TestQuestion           string          `json:"testQuestion"`
-	TestAnswer             string          `json:"testAnswer"`
+	TestAnswer             string          `json:"testAnswer" redact:"secret"`
 	Narration              string          `json:"narration"`
 	PurposeOfTransfer      string          `json:"purposeOfTransfer"`
 	SendExternalRef        string          `json:"sendExternalRef"`
@@ -216,10 +555,11 @@ type InterbankTransferParams struct {
 	DestinationBankCode  string          `json:"destinationBankCode"`
 	SenderName           string          `json:"senderName"`
 	SenderAddress        string          `json:"senderAddress"`
-	SenderPhone          string          `json:"senderPhone"`
-	BeneficiaryAccountNo string          `json:"beneficiaryAccountNo"`
+	SenderPhone          string          `json:"senderPhone" phone:"header" redact:"partial"`
+	DebitAccountNo       string          `json:"debitAccountNo,omitempty" redact:"partial"` // optional: with debit type Multiple only
+	BeneficiaryAccountNo string          `json:"beneficiaryAccountNo" redact:"partial"`
 	BeneficiaryName      string          `json:"beneficiaryName"`
-	BeneficiaryPhone     string          `json:"beneficiaryPhone"`
+	BeneficiaryPhone     string          `json:"beneficiaryPhone" phone:"header" redact:"partial"`
 	TransferReferenceNo  string          `json:"transferReferenceNo"`
 	Amount               decimal.Decimal `json:"amount"`
 	Currency             string          `json:"ccy"`
@@ -230,9 +570,9 @@ type InterbankTransferParams struct {
 type InterbankIAParams struct {
 	DestinationCountry   string          `json:"destinationCountry"`
 	DestinationBankCode  string          `json:"destinationBankCode"`
-	BeneficiaryAccountNo string          `json:"beneficiaryAccountNo"`
+	BeneficiaryAccountNo string          `json:"beneficiaryAccountNo" redact:"partial"`
 	BeneficiaryName      string          `json:"beneficiaryName"`
-	BeneficiaryPhone     string          `json:"beneficiaryPhone"`
+	BeneficiaryPhone     string          `json:"beneficiaryPhone" phone:"destinationCountry" redact:"partial"`
 	Amount               decimal.Decimal `json:"amount"`
 	TransferCurrency     string          `json:"transferCurrency"`
 	TransferReason       string          `json:"transferReason"`
@@ -243,7 +583,7 @@ type InterbankIAParams struct {
 type BillPaymentParams struct {
 	BillerCode    string        `json:"billerCode"`
 	BillRefNo     string        `json:"billRefNo"`
-	CbaRefNo      string        `json:"cbaRefNo"`
+	CbaRefNo      string        `json:"cbaRefNo,omitempty"`
 	CustomerName  string        `json:"customerName"`
 	CustomerRefNo string        `json:"customerRefNo"`
 	ProductCode   string        `json:"productCode"`
@@ -254,7 +594,7 @@ type BillPaymentParams struct {
 type AirtimeTopupParams struct {
 	BillerCode    string        `json:"billerCode"`
 	BillRefNo     string        `json:"billRefNo"`
-	CbaRefNo      string        `json:"cbaRefNo"`
+	CbaRefNo      string        `json:"cbaRefNo,omitempty"`
 	CustomerName  string        `json:"customerName"`
 	CustomerRefNo string        `json:"customerRefNo"`
 	ProductCode   string        `json:"productCode"`
@@ -265,7 +605,7 @@ type AirtimeTopupParams struct {
 type MomoParams struct {
 	BillerCode    string        `json:"billerCode"`
 	BillRefNo     string        `json:"billRefNo"`
-	CbaRefNo      string        `json:"cbaRefNo"`
+	CbaRefNo      string        `json:"cbaRefNo,omitempty"`
 	CustomerName  string        `json:"customerName"`
 	CustomerRefNo string        `json:"customerRefNo"`
 	ProductCode   string        `json:"productCode"`
@@ -276,26 +616,18 @@ type MomoParams struct {
 type MomoIAParams struct {
 	DestinationAffiliate   string          `json:"destAffiliate"`
 	DestinationCurrency    string          `json:"destCrncy"`
-	DestinationAccount     string          `json:"destinationAccount"`
+	DestinationAccount     string          `json:"destinationAccount" redact:"partial"`
 	DestinationAccountName string          `json:"destinationAccountName"`
 	ReceiverFirstName      string          `json:"receiveFirstName"`
 	ReceiverLastName       string          `json:"receiveLastName"`
-	ReceiverPhoneNumber    string          `json:"receiverPhoneNumber"`
+	ReceiverPhoneNumber    string          `json:"receiverPhoneNumber" phone:"destAffiliate" redact:"partial"`
 	ReceiverEmailAddress   string          `json:"receiveEmailAddress"`
 	ReceiverIDType         string          `json:"receiveIdType"`
-	ReceiverIDNumber       string          `json:"receiveIdNumber"`
+	ReceiverIDNumber       string          `json:"receiveIdNumber" redact:"partial"`
 	SourceAmount           decimal.Decimal `json:"sourceAmount"`
 	TestQuestion           string          `json:"testQuestion"`
-	TestAnswer             string          `json:"testAnswer"`
+	TestAnswer             string          `json:"testAnswer" redact:"secret"`
 	Narration              string          `json:"narration"`
 	PurposeOfTransfer      string          `json:"purposeOfTransfer"`
 	SendExternalRef        string          `json:"sendExternalRef"`
 }
-
-type FormDataArray []FormData
-
-// FormData represents a fieldName and fieldValue pair.
-type FormData struct {
-	FieldName  string `json:"fieldName"`
-	FieldValue string `json:"fieldValue"`
-}
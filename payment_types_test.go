@@ -0,0 +1,343 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPaymentParamsFromPairs(t *testing.T) {
+	typed, err := json.Marshal(NewPaymentParams(DomesticTransferParams{
+		CreditAccountNo:     "1441001996321",
+		DebitAccountBranch:  "ACCRA",
+		DebitAccountType:    "Corporate",
+		CreditAccountBranch: "Accra",
+		CreditAccountType:   "Corporate",
+		Amount:              decimal.NewFromInt(10),
+		Currency:            "GHS",
+	}))
+	require.NoError(t, err)
+
+	pairs, err := json.Marshal(NewPaymentParamsFromPairs(
+		PaymentParamPair{Key: "creditAccountNo", Value: "1441001996321"},
+		PaymentParamPair{Key: "debitAccountBranch", Value: "ACCRA"},
+		PaymentParamPair{Key: "debitAccountType", Value: "Corporate"},
+		PaymentParamPair{Key: "creditAccountBranch", Value: "Accra"},
+		PaymentParamPair{Key: "creditAccountType", Value: "Corporate"},
+		PaymentParamPair{Key: "amount", Value: "10"},
+		PaymentParamPair{Key: "ccy", Value: "GHS"},
+	))
+	require.NoError(t, err)
+
+	assert.Equal(t, string(typed), string(pairs))
+
+	empty, err := json.Marshal(NewPaymentParamsFromPairs())
+	require.NoError(t, err)
+	assert.Equal(t, `"[]"`, string(empty))
+}
+
+func TestNewPaymentParamsFromMap(t *testing.T) {
+	b, err := json.Marshal(NewPaymentParamsFromMap(map[string]string{
+		"newField": "value",
+		"amount":   "10",
+		"ccy":      "GHS",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, `"[{\"key\": \"amount\", \"value\": \"10\"},{\"key\": \"ccy\", \"value\": \"GHS\"},{\"key\": \"newField\", \"value\": \"value\"}]"`, string(b))
+
+	// the output must be a valid JSON string holding a JSON array
+	var s string
+	require.NoError(t, json.Unmarshal(b, &s))
+	var params []map[string]string
+	require.NoError(t, json.Unmarshal([]byte(s), &params))
+	assert.Equal(t, map[string]string{"key": "newField", "value": "value"}, params[2])
+}
+
+type qrPaymentParams struct {
+	MerchantCode string          `json:"merchantCode"`
+	Amount       decimal.Decimal `json:"amount"`
+	Channel      PaymentType     `json:"channel"`
+	Ignored      string
+}
+
+func TestRegisterPaymentParamType(t *testing.T) {
+	param := qrPaymentParams{
+		MerchantCode: "M123",
+		Amount:       decimal.NewFromInt(10),
+		Channel:      "QR",
+		Ignored:      "ignored",
+	}
+
+	_, err := NewCustomPaymentParams(param)
+	assert.ErrorContains(t, err, "is not registered")
+
+	require.NoError(t, RegisterPaymentParamType[qrPaymentParams]())
+
+	params, err := NewCustomPaymentParams(param)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(params)
+	require.NoError(t, err)
+	assert.Equal(t, `"[{\"key\": \"merchantCode\", \"value\": \"M123\"},{\"key\": \"amount\", \"value\": \"10\"},{\"key\": \"channel\", \"value\": \"QR\"}]"`, string(b))
+}
+
+func TestPaymentParams_OptionalFields(t *testing.T) {
+	type optionalParams struct {
+		BillerCode string           `json:"billerCode"`
+		CbaRefNo   *string          `json:"cbaRefNo,omitempty"`
+		Amount     *decimal.Decimal `json:"amount,omitempty"`
+		Reference  *string          `json:"reference"`
+		Retries    int              `json:"retries,omitempty"`
+	}
+
+	require.NoError(t, RegisterPaymentParamType[optionalParams]())
+
+	t.Run("unset", func(t *testing.T) {
+		params, err := NewCustomPaymentParams(optionalParams{BillerCode: "ECG"})
+		require.NoError(t, err)
+
+		b, err := json.Marshal(params)
+		require.NoError(t, err)
+		assert.Equal(t, `"[{\"key\": \"billerCode\", \"value\": \"ECG\"},{\"key\": \"reference\", \"value\": \"\"}]"`, string(b))
+
+		var got optionalParams
+		require.NoError(t, unmarshalParamList(b, &got))
+		assert.Equal(t, optionalParams{BillerCode: "ECG"}, got)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		cbaRefNo, amount, reference := "CBA1", decimal.NewFromInt(10), "REF"
+		want := optionalParams{BillerCode: "ECG", CbaRefNo: &cbaRefNo, Amount: &amount, Reference: &reference, Retries: 2}
+
+		params, err := NewCustomPaymentParams(want)
+		require.NoError(t, err)
+
+		b, err := json.Marshal(params)
+		require.NoError(t, err)
+		assert.Equal(t, `"[{\"key\": \"billerCode\", \"value\": \"ECG\"},{\"key\": \"cbaRefNo\", \"value\": \"CBA1\"},{\"key\": \"amount\", \"value\": \"`+globalAmountFormat().format(amount)+`\"},{\"key\": \"reference\", \"value\": \"REF\"},{\"key\": \"retries\", \"value\": \"2\"}]"`, string(b))
+
+		var got optionalParams
+		require.NoError(t, unmarshalParamList(b, &got))
+		assert.Equal(t, "CBA1", *got.CbaRefNo)
+		assert.True(t, amount.Equal(*got.Amount))
+		assert.Equal(t, "REF", *got.Reference)
+		assert.Equal(t, 2, got.Retries)
+	})
+
+	t.Run("cbaRefNo", func(t *testing.T) {
+		b, err := json.Marshal(NewPaymentParams(BillPaymentParams{BillerCode: "ECG"}))
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "cbaRefNo")
+
+		b, err = json.Marshal(NewPaymentParams(BillPaymentParams{BillerCode: "ECG", CbaRefNo: "CBA1"}))
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `{\"key\": \"cbaRefNo\", \"value\": \"CBA1\"}`)
+	})
+}
+
+func TestRegisterPaymentParamType_Invalid(t *testing.T) {
+	type unsupported struct {
+		Codes []string `json:"codes"`
+	}
+
+	assert.ErrorContains(t, RegisterPaymentParamType[string](), "must be a struct")
+	assert.ErrorContains(t, RegisterPaymentParamType[unsupported](), "unsupported type []string")
+}
+
+func TestPaymentParams_UnmarshalJSON(t *testing.T) {
+	want := BillPaymentParams{
+		BillerCode:    "Pass_Bio_ECI",
+		BillRefNo:     "239729",
+		CustomerName:  "Freeman Kay",
+		CustomerRefNo: "239729",
+		ProductCode:   "PassBio",
+		FormDataValue: FormDataArray{
+			{FieldName: "LastName", FieldValue: "Kojo"},
+			{FieldName: "FirstName", FieldValue: "Kwame"},
+		},
+	}
+
+	b, err := json.Marshal(NewPaymentParams(want))
+	require.NoError(t, err)
+
+	var got PaymentParams[BillPaymentParams]
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, want, got.Param())
+
+	t.Run("plain array", func(t *testing.T) {
+		var got PaymentParams[DomesticTransferParams]
+		err := json.Unmarshal([]byte(`[{"key": "creditAccountNo", "value": "1441001996321"}, {"key": "amount", "value": "10.50"}, {"key": "unknown", "value": "x"}]`), &got)
+		require.NoError(t, err)
+		assert.Equal(t, "1441001996321", got.Param().CreditAccountNo)
+		assert.Equal(t, "10.5", got.Param().Amount.String())
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		var got PaymentParams[DomesticTransferParams]
+		err := json.Unmarshal([]byte(`"[{\"key\": \"amount\", \"value\": \"ten\"}]"`), &got)
+		assert.ErrorContains(t, err, "invalid value for param amount")
+	})
+}
+
+func TestPaymentOptions_RoundTrip(t *testing.T) {
+	opt := PaymentOptions{
+		PaymentHeader: PaymentHeader{
+			ClientID:         "EGHTelc000043",
+			BatchSequence:    "1",
+			BatchAmount:      decimal.NewFromInt(50),
+			BatchID:          "EG1593490",
+			TransactionCount: 2,
+			AffiliateCode:    "EGH",
+		},
+		Extension: []PaymentExtension{
+			{
+				RequestID:   "2323",
+				RequestType: DOMESTIC,
+				ParamList: NewPaymentParams(DomesticTransferParams{
+					CreditAccountNo: "1441001996321",
+					Amount:          decimal.NewFromInt(10),
+					Currency:        "GHS",
+				}),
+				Amount:   decimal.NewFromInt(10),
+				Currency: "GHS",
+			},
+			{
+				RequestID:   "2324",
+				RequestType: "NEWTYPE",
+				ParamList: NewPaymentParamsFromPairs(
+					PaymentParamPair{Key: "newField", Value: "value"},
+				),
+				Amount:   decimal.NewFromInt(40),
+				Currency: "GHS",
+			},
+		},
+	}
+
+	b, err := json.Marshal(opt)
+	require.NoError(t, err)
+
+	var decoded PaymentOptions
+	require.NoError(t, json.Unmarshal(b, &decoded))
+
+	require.Len(t, decoded.Extension, 2)
+	domestic, ok := decoded.Extension[0].ParamList.(*PaymentParams[DomesticTransferParams])
+	require.True(t, ok)
+	assert.Equal(t, "1441001996321", domestic.Param().CreditAccountNo)
+	assert.Equal(t, "GHS", domestic.Param().Currency)
+
+	// re-encoding the decoded payload must produce the original payload
+	reencoded, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(b), string(reencoded))
+}
+
+func TestMarshalParamList(t *testing.T) {
+	t.Run("escaping", func(t *testing.T) {
+		b, err := json.Marshal(NewPaymentParams(BillPaymentParams{
+			CustomerName:  `Kofi "KK" Mensah\`,
+			FormDataValue: FormDataArray{{FieldName: "Address", FieldValue: `5 "A" Street & Sons`}},
+		}))
+		require.NoError(t, err)
+		require.True(t, json.Valid(b))
+
+		var got PaymentParams[BillPaymentParams]
+		require.NoError(t, json.Unmarshal(b, &got))
+		assert.Equal(t, `Kofi "KK" Mensah\`, got.Param().CustomerName)
+		assert.Equal(t, `5 "A" Street & Sons`, got.Param().FormDataValue[0].FieldValue)
+	})
+
+	t.Run("nil form data", func(t *testing.T) {
+		b, err := json.Marshal(NewPaymentParams(BillPaymentParams{}))
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `{\"key\": \"formDataValue\", \"value\": \"[]\"}`)
+	})
+
+	type Base struct {
+		BillerCode string `json:"billerCode"`
+	}
+	type withPointers struct {
+		*Base
+		CustomerName *string `json:"customerName"`
+		FormData     string  `json:"formDataValue"`
+	}
+
+	t.Run("pointers and embedded structs", func(t *testing.T) {
+		name := "Kofi"
+		b, err := marshalParamList(withPointers{Base: &Base{BillerCode: "ECG"}, CustomerName: &name, FormData: "x"}, AmountFormatTrimZeros)
+		require.NoError(t, err)
+		assert.Equal(t, `"[{\"key\": \"billerCode\", \"value\": \"ECG\"},{\"key\": \"customerName\", \"value\": \"Kofi\"},{\"key\": \"formDataValue\", \"value\": \"x\"}]"`, string(b))
+
+		b, err = marshalParamList(&withPointers{}, AmountFormatTrimZeros)
+		require.NoError(t, err)
+		assert.Equal(t, `"[{\"key\": \"billerCode\", \"value\": \"\"},{\"key\": \"customerName\", \"value\": \"\"},{\"key\": \"formDataValue\", \"value\": \"\"}]"`, string(b))
+
+		var got withPointers
+		require.NoError(t, unmarshalParamList(b, &got))
+		require.NoError(t, unmarshalParamList([]byte(`[{"key": "billerCode", "value": "ECG"}]`), &got))
+		assert.Equal(t, "ECG", got.BillerCode)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		_, err := marshalParamList("billerCode", AmountFormatTrimZeros)
+		assert.Error(t, err)
+
+		_, err = marshalParamList((*BillPaymentParams)(nil), AmountFormatTrimZeros)
+		assert.Error(t, err)
+	})
+}
+
+func BenchmarkPaymentParams_MarshalJSON(b *testing.B) {
+	params := NewPaymentParams(TokenTransferParams{
+		SecretCode:            "12345",
+		SourceAccount:         "1441000574000",
+		SourceAccountCurrency: "GHS",
+		SourceAccountType:     "Corporate",
+		SenderName:            "TEST USER",
+		Currency:              "GHS",
+		SenderMobileNo:        "233244000000",
+		Amount:                decimal.NewFromInt(40),
+		SenderID:              "QWE345Y4",
+		BeneficiaryName:       "Stephen Hunt",
+		BeneficiaryMobileNo:   "233244000001",
+		WithdrawalChannel:     "ATM",
+	})
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func FuzzPaymentParams_MarshalJSON(f *testing.F) {
+	f.Add("Pass_Bio_ECI", "Freeman Kay", "LastName", "Kojo")
+	f.Add("", "", "", "")
+	f.Add(`"`, `\`, `\"}]`, "<&>")
+	f.Add("\x00", "\n\t", "\u2028", "\xff")
+
+	f.Fuzz(func(t *testing.T, billerCode, customerName, fieldName, fieldValue string) {
+		want := BillPaymentParams{
+			BillerCode:    billerCode,
+			CustomerName:  customerName,
+			FormDataValue: FormDataArray{{FieldName: fieldName, FieldValue: fieldValue}},
+		}
+
+		b, err := json.Marshal(NewPaymentParams(want))
+		require.NoError(t, err)
+		require.True(t, json.Valid(b), "invalid JSON: %s", b)
+
+		var got PaymentParams[BillPaymentParams]
+		require.NoError(t, json.Unmarshal(b, &got))
+
+		// invalid UTF-8 is replaced when encoding
+		if utf8.ValidString(billerCode + customerName + fieldName + fieldValue) {
+			assert.Equal(t, want, got.Param())
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package ecobank
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Employee is an entry of the roster paid by a Payroll.
+type Employee struct {
+	// ID identifies the employee in the report, e.g. the staff number. It is optional.
+	ID   string
+	Name string
+	// Account is the account number the salary is credited to.
+	Account string
+	// Bank is the code of the bank of the account. Accounts with Ecobank, or without a bank, are paid
+	// with DOMESTIC transfers, the others with INTERBANK transfers.
+	Bank string
+	// Branch and AccountType are the branch and type of accounts with Ecobank.
+	Branch      string
+	AccountType string
+	Phone       string
+	Amount      decimal.Decimal
+}
+
+// Payroll pays the salaries of a roster of employees in a single batch, with a DOMESTIC transfer for the
+// employees banking with Ecobank and an INTERBANK transfer for the others.
+//
+// The batch and transaction IDs of the payment are the payroll reference, and the request ID of every
+// transfer is the reference followed by the position of the employee in the roster, e.g. SAL2610-3, which
+// is also the transfer reference of INTERBANK transfers. Set the Reference when retrying a payroll run so
+// that its transfers keep their request IDs. The collection does not document whether the API rejects
+// transfers submitted twice, so guard against resubmissions with a DuplicateGuard or an Outbox.
+type Payroll struct {
+	// Header is the template of the payment header, e.g. with the AffiliateCode, DebitType and ClientID.
+	// The batch, transaction and amount fields are set from the roster.
+	Header PaymentHeader
+	// Reference identifies the payroll run. It defaults to a reference generated with GenerateTransactionRef.
+	Reference string
+	Currency  string
+	// BankCode is the code of Ecobank in the affiliate, which tells DOMESTIC transfers from INTERBANK ones.
+	BankCode string
+	// DebitAccountNo is the account debited for the salaries. It is only set in the params of the transfers
+	// with DebitTypeMultiple, since DebitTypeSingle debits the account of the client once.
+	DebitAccountNo     string
+	DebitAccountBranch string
+	DebitAccountType   string
+	// SenderName, SenderAddress and SenderPhone are the sender details of INTERBANK transfers.
+	SenderName    string
+	SenderAddress string
+	SenderPhone   string
+	// TransferType is the transfer type of INTERBANK transfers, and RateType the rate type of all the transfers.
+	TransferType string
+	RateType     string
+}
+
+// Batch returns the payment paying the roster, checked with PaymentOptions.Check, and the report mapping
+// the employees to the request IDs of their transfers. All the invalid entries of the roster are returned
+// joined together, each with the position of the employee in the roster.
+func (p *Payroll) Batch(roster []Employee) (*PaymentOptions, *PayrollReport, error) {
+	if len(roster) == 0 {
+		return nil, nil, errors.New("payroll roster is empty")
+	}
+	if p.Currency == "" {
+		return nil, nil, errors.New("payroll currency is required")
+	}
+
+	ref := p.Reference
+	if ref == "" {
+		ref = GenerateTransactionRef(p.Header.AffiliateCode, time.Now())
+	}
+	multiple := strings.EqualFold(string(p.Header.DebitType), string(DebitTypeMultiple))
+
+	opt := &PaymentOptions{PaymentHeader: p.Header, Extension: make([]PaymentExtension, 0, len(roster))}
+	report := &PayrollReport{BatchID: ref, Currency: p.Currency, Entries: make([]PayrollEntry, 0, len(roster))}
+
+	var errs []error
+	sum := decimal.Zero
+	for i, e := range roster {
+		if err := e.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("employee %d (%s): %w", i+1, e.label(), err))
+			continue
+		}
+
+		ext := PaymentExtension{
+			RequestID: fmt.Sprintf("%s-%d", ref, i+1),
+			Amount:    e.Amount,
+			Currency:  p.Currency,
+			RateType:  p.RateType,
+		}
+
+		if e.Bank == "" || strings.EqualFold(e.Bank, p.BankCode) {
+			params := DomesticTransferParams{
+				CreditAccountNo:     e.Account,
+				DebitAccountBranch:  p.DebitAccountBranch,
+				DebitAccountType:    p.DebitAccountType,
+				CreditAccountBranch: e.Branch,
+				CreditAccountType:   e.AccountType,
+				Amount:              e.Amount,
+				Currency:            p.Currency,
+			}
+			if multiple {
+				params.DebitAccountNo = p.DebitAccountNo
+			}
+			ext.RequestType = DOMESTIC
+			ext.ParamList = NewPaymentParams(params)
+		} else {
+			params := InterbankTransferParams{
+				DestinationBankCode:  e.Bank,
+				SenderName:           p.SenderName,
+				SenderAddress:        p.SenderAddress,
+				SenderPhone:          p.SenderPhone,
+				BeneficiaryAccountNo: e.Account,
+				BeneficiaryName:      e.Name,
+				BeneficiaryPhone:     e.Phone,
+				TransferReferenceNo:  ext.RequestID,
+				Amount:               e.Amount,
+				Currency:             p.Currency,
+				TransferType:         p.TransferType,
+			}
+			if multiple {
+				params.DebitAccountNo = p.DebitAccountNo
+			}
+			ext.RequestType = INTERBANK
+			ext.ParamList = NewPaymentParams(params)
+		}
+
+		sum = sum.Add(e.Amount)
+		opt.Extension = append(opt.Extension, ext)
+		report.Entries = append(report.Entries, PayrollEntry{Employee: e, RequestID: ext.RequestID, RequestType: ext.RequestType})
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+
+	header := &opt.PaymentHeader
+	header.BatchID = ref
+	header.TransactionID = ref
+	header.BatchSequence = "1"
+	header.TotalBatches = "1"
+	header.BatchCount = len(opt.Extension)
+	header.TransactionCount = len(opt.Extension)
+	header.BatchAmount = sum
+	header.TransactionAmount = sum
+
+	if err := opt.Check(); err != nil {
+		return nil, nil, err
+	}
+	return opt, report, nil
+}
+
+func (e Employee) validate() error {
+	var errs []error
+	if strings.TrimSpace(e.Account) == "" {
+		errs = append(errs, errors.New("account is required"))
+	}
+	if !e.Amount.IsPositive() {
+		errs = append(errs, fmt.Errorf("amount %s must be positive", e.Amount))
+	}
+	return errors.Join(errs...)
+}
+
+// label returns how the employee is referred to in errors.
+func (e Employee) label() string {
+	for _, s := range []string{e.ID, e.Name} {
+		if s != "" {
+			return s
+		}
+	}
+	return e.Account
+}
+
+// PayrollReport maps the employees of a payroll to the transfers paying them.
+type PayrollReport struct {
+	BatchID  string
+	Currency string
+	Entries  []PayrollEntry
+}
+
+// PayrollEntry is the transfer paying an employee.
+type PayrollEntry struct {
+	Employee    Employee
+	RequestID   string
+	RequestType PaymentType
+	// Status is PENDING once the payment is accepted by the API, and FinalStatus the final status of the
+	// transfer when the payment was submitted with WaitForStatus. They are set by PayrollReport.Acknowledge.
+	Status      string
+	FinalStatus *TransactionStatus
+}
+
+// Entry returns the entry of the transfer with the given request ID.
+func (r *PayrollReport) Entry(requestID string) (*PayrollEntry, bool) {
+	for i := range r.Entries {
+		if r.Entries[i].RequestID == requestID {
+			return &r.Entries[i], true
+		}
+	}
+	return nil, false
+}
+
+// Acknowledge records the statuses of the transfers acknowledged by PaymentService.Pay.
+func (r *PayrollReport) Acknowledge(ack *PaymentAck) {
+	for _, ext := range ack.Extensions {
+		if entry, ok := r.Entry(ext.RequestID); ok {
+			if ack.Accepted() {
+				entry.Status = string(StatusPending)
+			}
+			if ext.FinalStatus != nil {
+				entry.FinalStatus = ext.FinalStatus
+			}
+		}
+	}
+}
+
+// WriteCSV writes the report as CSV with a header row, one row per employee with their details, the request
+// ID and type of their transfer and its status: the final status if known, else the acknowledged one.
+//
+// Cells starting with =, +, -, @, a tab or a carriage return are prefixed with a single quote, so that
+// spreadsheets opening the report do not evaluate them as formulas.
+func (r *PayrollReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"employee_id", "name", "account", "bank", "amount", "currency", "request_id", "request_type", "status"})
+	for _, entry := range r.Entries {
+		status := entry.Status
+		if entry.FinalStatus != nil {
+			status = string(entry.FinalStatus.Status)
+		}
+		e := entry.Employee
+		row := []string{e.ID, e.Name, e.Account, e.Bank, e.Amount.String(), r.Currency, entry.RequestID, string(entry.RequestType), status}
+		for i, cell := range row {
+			row[i] = escapeCSVFormula(cell)
+		}
+		_ = cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeCSVFormula prefixes the cell with a single quote if spreadsheets would evaluate it as a formula.
+func escapeCSVFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,147 @@
+package ecobank
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPayroll() *Payroll {
+	return &Payroll{
+		Header:             PaymentHeader{AffiliateCode: "EGH", DebitType: DebitTypeMultiple, ClientID: "EGHTelc000043"},
+		Reference:          "SAL2610",
+		Currency:           "GHS",
+		BankCode:           "ECO",
+		DebitAccountNo:     "1441000565015",
+		DebitAccountBranch: "ACCRA",
+		DebitAccountType:   "Corporate",
+		SenderName:         "ECOBANK TEST CO",
+		TransferType:       "spot",
+		RateType:           "spot",
+	}
+}
+
+func TestPayroll_Batch(t *testing.T) {
+	roster := []Employee{
+		{ID: "E001", Name: "Kwame Mensah", Account: "1441001996321", Branch: "Accra", AccountType: "Current", Amount: decimal.RequireFromString("2500.50")},
+		{ID: "E002", Name: "Ama Owusu", Account: "110424812001", Bank: "ASB", Phone: "233543837123", Amount: decimal.NewFromInt(3100)},
+		{ID: "E003", Name: "Kojo Asante", Account: "1441001996322", Bank: "eco", Amount: decimal.NewFromInt(1800)},
+	}
+
+	opt, report, err := newTestPayroll().Batch(roster)
+	require.NoError(t, err)
+
+	header := opt.PaymentHeader
+	assert.Equal(t, "SAL2610", header.BatchID)
+	assert.Equal(t, "SAL2610", header.TransactionID)
+	assert.Equal(t, 3, header.TransactionCount)
+	assert.True(t, decimal.RequireFromString("7400.50").Equal(header.BatchAmount))
+	assert.Equal(t, "EGH", header.AffiliateCode)
+
+	require.Len(t, opt.Extension, 3)
+	assert.Equal(t, []PaymentType{DOMESTIC, INTERBANK, DOMESTIC},
+		[]PaymentType{opt.Extension[0].RequestType, opt.Extension[1].RequestType, opt.Extension[2].RequestType})
+
+	domestic := opt.Extension[0].ParamList.(*PaymentParams[DomesticTransferParams]).Param()
+	assert.Equal(t, "1441001996321", domestic.CreditAccountNo)
+	assert.Equal(t, "1441000565015", domestic.DebitAccountNo)
+	assert.Equal(t, "Accra", domestic.CreditAccountBranch)
+
+	interbank := opt.Extension[1].ParamList.(*PaymentParams[InterbankTransferParams]).Param()
+	assert.Equal(t, "ASB", interbank.DestinationBankCode)
+	assert.Equal(t, "Ama Owusu", interbank.BeneficiaryName)
+	assert.Equal(t, "SAL2610-2", interbank.TransferReferenceNo)
+
+	assert.Equal(t, "SAL2610", report.BatchID)
+	require.Len(t, report.Entries, 3)
+	for i, entry := range report.Entries {
+		assert.Equal(t, roster[i], entry.Employee)
+		assert.Equal(t, opt.Extension[i].RequestID, entry.RequestID)
+	}
+	assert.Equal(t, "SAL2610-3", report.Entries[2].RequestID)
+}
+
+func TestPayroll_Batch_Single(t *testing.T) {
+	payroll := newTestPayroll()
+	payroll.Header.DebitType = DebitTypeSingle
+
+	opt, _, err := payroll.Batch([]Employee{
+		{Account: "1441001996321", Amount: decimal.NewFromInt(2500)},
+		{Account: "110424812001", Bank: "ASB", Amount: decimal.NewFromInt(3100)},
+	})
+	require.NoError(t, err, "the debit account is not set in the params with debit type Single")
+	assert.Empty(t, paramString(opt.Extension[0].ParamList, "debitAccountNo"))
+	assert.Empty(t, paramString(opt.Extension[1].ParamList, "debitAccountNo"))
+}
+
+func TestPayroll_Batch_Invalid(t *testing.T) {
+	_, _, err := newTestPayroll().Batch(nil)
+	assert.Error(t, err)
+
+	_, _, err = newTestPayroll().Batch([]Employee{
+		{ID: "E001", Account: "1441001996321", Amount: decimal.NewFromInt(2500)},
+		{Name: "Ama Owusu", Bank: "ASB", Amount: decimal.NewFromInt(3100)},
+		{Account: "1441001996322"},
+	})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "employee 2 (Ama Owusu): account is required")
+	assert.ErrorContains(t, err, "employee 3 (1441001996322): amount 0 must be positive")
+	assert.NotContains(t, err.Error(), "employee 1")
+
+	payroll := newTestPayroll()
+	payroll.Reference = strings.Repeat("S", MaxPaymentIDLength)
+	_, _, err = payroll.Batch([]Employee{{Account: "1441001996321", Amount: decimal.NewFromInt(2500)}})
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+}
+
+func TestPayrollReport(t *testing.T) {
+	opt, report, err := newTestPayroll().Batch([]Employee{
+		{ID: "E001", Name: "Kwame Mensah", Account: "1441001996321", Amount: decimal.NewFromInt(2500)},
+		{ID: "E002", Name: "Ama Owusu", Account: "110424812001", Bank: "ASB", Amount: decimal.NewFromInt(3100)},
+	})
+	require.NoError(t, err)
+
+	ack := &PaymentAck{}
+	ack.fillFrom(opt, &Response{Code: http.StatusOK})
+	ack.Extensions[1].FinalStatus = &TransactionStatus{Status: StatusFailed}
+	report.Acknowledge(ack)
+
+	entry, ok := report.Entry("SAL2610-2")
+	require.True(t, ok)
+	assert.Equal(t, "E002", entry.Employee.ID)
+	assert.Equal(t, StatusFailed, entry.FinalStatus.Status)
+
+	var b strings.Builder
+	require.NoError(t, report.WriteCSV(&b))
+	assert.Equal(t, "employee_id,name,account,bank,amount,currency,request_id,request_type,status\n"+
+		"E001,Kwame Mensah,1441001996321,,2500,GHS,SAL2610-1,DOMESTIC,PENDING\n"+
+		"E002,Ama Owusu,110424812001,ASB,3100,GHS,SAL2610-2,INTERBANK,FAILED\n", b.String())
+}
+
+func TestPayrollReport_WriteCSV_Formulas(t *testing.T) {
+	report := &PayrollReport{Currency: "GHS", Entries: []PayrollEntry{{
+		Employee: Employee{
+			ID:      "@E001",
+			Name:    `=HYPERLINK("http://example.com","Kwame")`,
+			Account: "+1441001996321",
+			Bank:    "\tASB",
+			Amount:  decimal.NewFromInt(2500),
+		},
+		RequestID:   "-SAL2610-1",
+		RequestType: DOMESTIC,
+		Status:      "\rPENDING",
+	}}}
+
+	var b strings.Builder
+	require.NoError(t, report.WriteCSV(&b))
+
+	rows, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"'@E001", `'=HYPERLINK("http://example.com","Kwame")`, "'+1441001996321", "'\tASB", "2500", "GHS", "'-SAL2610-1", "DOMESTIC", "'\rPENDING"}, rows[1])
+}
@@ -0,0 +1,273 @@
+package ecobank
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/profclems/go-ecobank/calendar"
+)
+
+// ErrInvalidPhoneNumber is returned when a phone number cannot be normalized.
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+// PhoneFormat is the format of a normalized phone number.
+type PhoneFormat int
+
+const (
+	// PhoneFormatMSISDN is the country code followed by the national number, e.g. 233241234567.
+	PhoneFormatMSISDN PhoneFormat = iota
+	// PhoneFormatE164 is the MSISDN prefixed with a plus, e.g. +233241234567.
+	PhoneFormatE164
+	// PhoneFormatLocal is the national number as dialled within the country, e.g. 0241234567.
+	PhoneFormatLocal
+)
+
+// phoneCountry describes the phone numbers of a country.
+type phoneCountry struct {
+	// code is the country calling code.
+	code string
+	// trunk is set if a 0 is dialled before the national number within the country.
+	trunk bool
+	// min and max are the lengths of the national number, without the trunk prefix.
+	min, max int
+}
+
+// phoneCountries are the phone numbering plans of the affiliate countries.
+var phoneCountries = map[string]phoneCountry{
+	"BF": {code: "226", min: 8, max: 8},
+	"BI": {code: "257", min: 8, max: 8},
+	"BJ": {code: "229", min: 8, max: 10},
+	"CD": {code: "243", trunk: true, min: 9, max: 9},
+	"CF": {code: "236", min: 8, max: 8},
+	"CG": {code: "242", min: 9, max: 9},
+	"CI": {code: "225", min: 10, max: 10},
+	"CM": {code: "237", min: 9, max: 9},
+	"CV": {code: "238", min: 7, max: 7},
+	"GA": {code: "241", min: 7, max: 8},
+	"GH": {code: "233", trunk: true, min: 9, max: 9},
+	"GM": {code: "220", min: 7, max: 7},
+	"GN": {code: "224", min: 9, max: 9},
+	"GQ": {code: "240", min: 9, max: 9},
+	"GW": {code: "245", min: 9, max: 9},
+	"KE": {code: "254", trunk: true, min: 9, max: 9},
+	"LR": {code: "231", trunk: true, min: 7, max: 9},
+	"ML": {code: "223", min: 8, max: 8},
+	"MW": {code: "265", trunk: true, min: 7, max: 9},
+	"MZ": {code: "258", min: 8, max: 9},
+	"NE": {code: "227", min: 8, max: 8},
+	"NG": {code: "234", trunk: true, min: 8, max: 10},
+	"RW": {code: "250", trunk: true, min: 9, max: 9},
+	"SL": {code: "232", trunk: true, min: 8, max: 8},
+	"SN": {code: "221", min: 9, max: 9},
+	"SS": {code: "211", trunk: true, min: 9, max: 9},
+	"ST": {code: "239", min: 7, max: 7},
+	"TD": {code: "235", min: 8, max: 8},
+	"TG": {code: "228", min: 8, max: 8},
+	"TZ": {code: "255", trunk: true, min: 9, max: 9},
+	"UG": {code: "256", trunk: true, min: 9, max: 9},
+	"ZM": {code: "260", trunk: true, min: 9, max: 9},
+	"ZW": {code: "263", trunk: true, min: 9, max: 9},
+}
+
+// NormalizeMSISDN returns the phone number in the MSISDN format, the country code followed by the national number,
+// which is what mobile money operators expect. The country is an ISO code such as "GH" or an affiliate code such as "EGH".
+//
+// The number may be given in international format, with or without a leading plus or 00, or as dialled within
+// the country. Spaces, dashes, dots and parentheses are ignored.
+func NormalizeMSISDN(number, country string) (string, error) {
+	return FormatPhoneNumber(number, country, PhoneFormatMSISDN)
+}
+
+// FormatPhoneNumber returns the phone number of the country in the given format. See NormalizeMSISDN for the accepted input.
+func FormatPhoneNumber(number, country string, format PhoneFormat) (string, error) {
+	pc, ok := phoneCountries[calendar.Country(country)]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported country %q", ErrInvalidPhoneNumber, country)
+	}
+
+	nsn, err := pc.nationalNumber(number)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case PhoneFormatE164:
+		return "+" + pc.code + nsn, nil
+	case PhoneFormatLocal:
+		if pc.trunk {
+			return "0" + nsn, nil
+		}
+		return nsn, nil
+	default:
+		return pc.code + nsn, nil
+	}
+}
+
+// nationalNumber returns the national number of the phone number, without the country code or trunk prefix.
+func (pc phoneCountry) nationalNumber(number string) (string, error) {
+	var digits strings.Builder
+	plus := false
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && digits.Len() == 0 && !plus:
+			plus = true
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", fmt.Errorf("%w %q: unexpected character %q", ErrInvalidPhoneNumber, number, r)
+		}
+	}
+
+	d := digits.String()
+	international := true
+	switch {
+	case plus:
+		if !strings.HasPrefix(d, pc.code) {
+			return "", fmt.Errorf("%w %q: country code must be +%s", ErrInvalidPhoneNumber, number, pc.code)
+		}
+		d = d[len(pc.code):]
+	case strings.HasPrefix(d, "00"+pc.code):
+		d = d[2+len(pc.code):]
+	case strings.HasPrefix(d, pc.code) && pc.validLength(len(d)-len(pc.code)):
+		d = d[len(pc.code):]
+	default:
+		international = false
+	}
+
+	// the trunk prefix is dialled within the country, and sometimes written after the country code as in +233 (0)24...
+	if pc.trunk && strings.HasPrefix(d, "0") && (!international || !pc.validLength(len(d))) {
+		d = d[1:]
+	}
+
+	if !pc.validLength(len(d)) {
+		if pc.min == pc.max {
+			return "", fmt.Errorf("%w %q: national number must have %d digits, got %d", ErrInvalidPhoneNumber, number, pc.min, len(d))
+		}
+		return "", fmt.Errorf("%w %q: national number must have %d to %d digits, got %d", ErrInvalidPhoneNumber, number, pc.min, pc.max, len(d))
+	}
+
+	return d, nil
+}
+
+func (pc phoneCountry) validLength(n int) bool {
+	return n >= pc.min && n <= pc.max
+}
+
+// phoneFormat returns the format the phone numbers of the affiliate are normalized to, if any.
+func (c *Client) phoneFormat(affiliateCode string) (PhoneFormat, bool) {
+	if format, ok := c.phoneFormats[calendar.Country(affiliateCode)]; ok {
+		return format, true
+	}
+	format, ok := c.phoneFormats[""]
+	return format, ok
+}
+
+// phoneNormalizer is implemented by payment params with phone number fields.
+type phoneNormalizer interface {
+	// normalizePhoneNumbers returns a copy of the params with their phone numbers normalized.
+	normalizePhoneNumbers(affiliateCode string, formatFor func(affiliateCode string) (PhoneFormat, bool)) (PaymentParamInterface, error)
+}
+
+// normalizePhoneNumbers returns a copy of the payment with the phone numbers of its params normalized with
+// the phone formats of the client, so that the payment of the caller is left untouched.
+func (p *PaymentService) normalizePhoneNumbers(opt *PaymentOptions) (*PaymentOptions, error) {
+	normalized := *opt
+	normalized.Extension = slices.Clone(opt.Extension)
+
+	for i, ext := range normalized.Extension {
+		n, ok := ext.ParamList.(phoneNormalizer)
+		if !ok {
+			continue
+		}
+
+		params, err := n.normalizePhoneNumbers(opt.PaymentHeader.AffiliateCode, p.client.phoneFormat)
+		if err != nil {
+			return nil, fmt.Errorf("extension %s: %w", ext.RequestID, err)
+		}
+		normalized.Extension[i].ParamList = params
+	}
+	return &normalized, nil
+}
+
+// normalizePhoneNumbers returns a copy of *PaymentParams with the phone numbers normalized.
+func (param *PaymentParams[T]) normalizePhoneNumbers(affiliateCode string, formatFor func(string) (PhoneFormat, bool)) (PaymentParamInterface, error) {
+	normalized := *param
+	if err := normalizePhoneFields(reflect.ValueOf(&normalized.param).Elem(), affiliateCode, formatFor); err != nil {
+		return nil, err
+	}
+	return &normalized, nil
+}
+
+// normalizePhoneNumbers returns a copy of custom params with the phone numbers normalized.
+func (param *customPaymentParams) normalizePhoneNumbers(affiliateCode string, formatFor func(string) (PhoneFormat, bool)) (PaymentParamInterface, error) {
+	v := reflect.New(reflect.TypeOf(param.param)).Elem()
+	v.Set(reflect.ValueOf(param.param))
+	if err := normalizePhoneFields(v, affiliateCode, formatFor); err != nil {
+		return nil, err
+	}
+	return &customPaymentParams{param: v.Interface()}, nil
+}
+
+// normalizePhoneFields normalizes the string fields of the param struct v with a phone tag.
+//
+// The tag names the json key of the field holding the affiliate or country of the phone number,
+// such as `phone:"destAffiliate"`, or is "header" for numbers in the affiliate of the payment header.
+func normalizePhoneFields(v reflect.Value, affiliateCode string, formatFor func(string) (PhoneFormat, bool)) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := paramFields(v.Type())
+	for _, field := range fields {
+		source, ok := field.Tag.Lookup("phone")
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		fv, err := v.FieldByIndexErr(field.Index)
+		if err != nil {
+			continue
+		}
+		ptr := fv
+		if fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() != reflect.String || fv.String() == "" {
+			continue
+		}
+
+		affiliate := affiliateCode
+		if source != "header" {
+			affiliate = ""
+			for _, f := range fields {
+				if f.key == source {
+					if sv, err := v.FieldByIndexErr(f.Index); err == nil {
+						affiliate = formatToStr(sv.Interface())
+					}
+				}
+			}
+		}
+
+		format, ok := formatFor(affiliate)
+		if !ok {
+			continue
+		}
+
+		number, err := FormatPhoneNumber(fv.String(), affiliate, format)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.key, err)
+		}
+		if ptr.Kind() == reflect.Pointer {
+			// point to a new string rather than changing the one of the caller
+			fv = reflect.New(fv.Type()).Elem()
+			ptr.Set(fv.Addr())
+		}
+		fv.SetString(number)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPhoneNumber(t *testing.T) {
+	testCases := []struct {
+		name    string
+		number  string
+		country string
+		want    [3]string // MSISDN, E.164 and local
+		wantErr string
+	}{
+		{name: "ghana local", number: "024 123 4567", country: "EGH", want: [3]string{"233241234567", "+233241234567", "0241234567"}},
+		{name: "ghana msisdn", number: "233241234567", country: "GH", want: [3]string{"233241234567", "+233241234567", "0241234567"}},
+		{name: "ghana e164 with trunk", number: "+233 (0)24-123-4567", country: "EGH", want: [3]string{"233241234567", "+233241234567", "0241234567"}},
+		{name: "ghana 00 prefix", number: "00233241234567", country: "EGH", want: [3]string{"233241234567", "+233241234567", "0241234567"}},
+		{name: "nigeria local", number: "08089991325", country: "ENG", want: [3]string{"2348089991325", "+2348089991325", "08089991325"}},
+		{name: "senegal without trunk", number: "77 123 45 67", country: "ESN", want: [3]string{"221771234567", "+221771234567", "771234567"}},
+		{name: "ivory coast leading zero", number: "07 01 23 45 67", country: "ECI", want: [3]string{"2250701234567", "+2250701234567", "0701234567"}},
+		{name: "too short", number: "024123456", country: "EGH", wantErr: "national number must have 9 digits, got 8"},
+		{name: "wrong country code", number: "+234 808 999 1325", country: "EGH", wantErr: "country code must be +233"},
+		{name: "letters", number: "024-CALL-NOW", country: "EGH", wantErr: "unexpected character 'C'"},
+		{name: "unsupported country", number: "0241234567", country: "EXX", wantErr: `unsupported country "EXX"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, format := range []PhoneFormat{PhoneFormatMSISDN, PhoneFormatE164, PhoneFormatLocal} {
+				got, err := FormatPhoneNumber(tc.number, tc.country, format)
+				if tc.wantErr != "" {
+					assert.ErrorIs(t, err, ErrInvalidPhoneNumber)
+					assert.ErrorContains(t, err, tc.wantErr)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tc.want[i], got)
+			}
+		})
+	}
+
+	msisdn, err := NormalizeMSISDN("0241234567", "EGH")
+	require.NoError(t, err)
+	assert.Equal(t, "233241234567", msisdn)
+}
+
+func TestPaymentService_Pay_PhoneFormat(t *testing.T) {
+	newOptions := func() *PaymentOptions {
+		return &PaymentOptions{
+			PaymentHeader: PaymentHeader{AffiliateCode: "EGH"},
+			Extension: []PaymentExtension{
+				{RequestID: "432", RequestType: TOKEN, ParamList: NewPaymentParams(TokenTransferParams{
+					SenderMobileNo:      "0202205113",
+					BeneficiaryMobileNo: "+233 23 344 5566",
+				})},
+				{RequestID: "433", RequestType: MOMOIA, ParamList: NewPaymentParams(MomoIAParams{
+					DestinationAffiliate: "ENG",
+					ReceiverPhoneNumber:  "08089991325",
+				})},
+			},
+		}
+	}
+
+	params := func(t *testing.T, body string) map[string]string {
+		var payload struct {
+			Extension []struct {
+				ParamList json.RawMessage `json:"param_list"`
+			} `json:"extension"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &payload))
+
+		values := make(map[string]string)
+		for _, ext := range payload.Extension {
+			pairs, err := DecodeParamList(ext.ParamList)
+			require.NoError(t, err)
+			for _, pair := range pairs {
+				values[pair.Key] = pair.Value
+			}
+		}
+		return values
+	}
+
+	t.Run("normalized", func(t *testing.T) {
+		client := newMockClient(t, "", http.StatusOK)
+		require.NoError(t, WithPhoneFormat(PhoneFormatMSISDN)(client))
+		require.NoError(t, WithPhoneFormat(PhoneFormatLocal, "EGH")(client))
+
+		var body string
+		client.client.HTTPClient.Transport = &mockHTTPClient{
+			requestHandler: func(req *http.Request) (*http.Response, error) {
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				body = string(b)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"response_code": 200, "response_message": "success", "response_content": "success"}`)),
+				}, nil
+			},
+		}
+
+		opt := newOptions()
+		_, _, err := client.Payment.Pay(t.Context(), opt)
+		require.NoError(t, err)
+		assert.Equal(t, newOptions().Extension[0].ParamList, opt.Extension[0].ParamList, "the params of the caller are not changed")
+		assert.Equal(t, newOptions().Extension[1].ParamList, opt.Extension[1].ParamList)
+
+		values := params(t, body)
+		assert.Equal(t, "0202205113", values["senderMobileNo"])
+		assert.Equal(t, "0233445566", values["beneficiaryMobileNo"])
+		assert.Equal(t, "2348089991325", values["receiverPhoneNumber"])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		client := newMockClient(t, "", http.StatusOK)
+		require.NoError(t, WithPhoneFormat(PhoneFormatE164)(client))
+
+		opt := newOptions()
+		opt.Extension[1].ParamList = NewPaymentParams(MomoIAParams{DestinationAffiliate: "ENG", ReceiverPhoneNumber: "0808999"})
+
+		_, _, err := client.Payment.Pay(t.Context(), opt)
+		assert.ErrorIs(t, err, ErrInvalidPhoneNumber)
+		assert.ErrorContains(t, err, "extension 433: receiverPhoneNumber: invalid phone number")
+	})
+}
@@ -0,0 +1,93 @@
+package ecobank
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// encodeQuery encodes the options of a GET request as url query values.
+//
+// Fields are named by their `url` struct tag, falling back to the `json` tag, and are skipped if
+// the tag is "-" or has the omitempty option and the field holds its zero value. Fields of embedded
+// structs are promoted, slices are encoded as repeated values, and all other values are formatted
+// the same way as for the secure hash, with amounts in the given format.
+func encodeQuery(opts any, format AmountFormat) (url.Values, error) {
+	if values, ok := opts.(url.Values); ok {
+		return values, nil
+	}
+
+	val := reflect.ValueOf(opts)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return url.Values{}, nil
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query options must be a struct, got %T", opts)
+	}
+
+	values := make(url.Values)
+	addQueryValues(values, val, format)
+
+	return values, nil
+}
+
+func addQueryValues(values url.Values, val reflect.Value, format AmountFormat) {
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldValue := val.Field(i)
+
+		tag, ok := field.Tag.Lookup("url")
+		if !ok {
+			tag = field.Tag.Get("json")
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.Kind() == reflect.Struct {
+				addQueryValues(values, fieldValue, format)
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		if strings.Contains(opts, "omitempty") && fieldValue.IsZero() {
+			continue
+		}
+
+		if kind := fieldValue.Kind(); (kind == reflect.Slice || kind == reflect.Array) && fieldValue.Type().Elem().Kind() != reflect.Uint8 {
+			for j := 0; j < fieldValue.Len(); j++ {
+				values.Add(name, formatValue(fieldValue.Index(j).Interface(), format))
+			}
+			continue
+		}
+
+		values.Add(name, formatValue(fieldValue.Interface(), format))
+	}
+}
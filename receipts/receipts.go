@@ -0,0 +1,144 @@
+// Package receipts renders payment advices for completed payments, from the payment submitted with
+// PaymentService.Pay and the final statuses of its transactions, including the bank references.
+//
+// Advices are rendered as plain text or HTML with the default templates of this package, or with custom
+// templates. Other formats, such as PDF, are rendered by implementing Renderer with a PDF library, e.g.
+// by converting the HTML advice.
+//
+//	advices, err := receipts.New(opt, entry.Statuses)
+//	for _, advice := range advices {
+//		err = receipts.HTMLRenderer{}.Render(w, advice)
+//	}
+package receipts
+
+import (
+	_ "embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/profclems/go-ecobank"
+	"github.com/shopspring/decimal"
+)
+
+// Advice is the payment advice of a transaction of a payment.
+type Advice struct {
+	// ClientID, AffiliateCode and BatchID identify the payment.
+	ClientID      string
+	AffiliateCode string
+	BatchID       string
+	// ExecutionDate is when the payment was executed.
+	ExecutionDate time.Time
+
+	// RequestID identifies the transaction in the payment.
+	RequestID   string
+	RequestType ecobank.PaymentType
+	Amount      decimal.Decimal
+	Currency    string
+
+	// Status, StatusCode and StatusReason are the final status of the transaction.
+	Status       string
+	StatusCode   string
+	StatusReason string
+	// BankReference is the reference of the transaction in the bank, from its status.
+	BankReference string
+
+	// Params are the params of the transaction, with the sensitive values redacted.
+	Params []ecobank.PaymentParamPair
+}
+
+// New returns the advices of the transactions of a payment, from their final statuses by request ID, such as
+// the ones of an OutboxEntry. It returns an error if a transaction has no status.
+func New(opt *ecobank.PaymentOptions, statuses map[string]*ecobank.TransactionStatus) ([]*Advice, error) {
+	advices := make([]*Advice, 0, len(opt.Extension))
+	for _, ext := range opt.Extension {
+		status, ok := statuses[ext.RequestID]
+		if !ok || status == nil {
+			return nil, fmt.Errorf("no status for transaction %s", ext.RequestID)
+		}
+
+		advice, err := NewAdvice(opt.PaymentHeader, ext, status)
+		if err != nil {
+			return nil, err
+		}
+		advices = append(advices, advice)
+	}
+	return advices, nil
+}
+
+// NewAdvice returns the advice of a transaction of a payment with its final status.
+func NewAdvice(header ecobank.PaymentHeader, ext ecobank.PaymentExtension, status *ecobank.TransactionStatus) (*Advice, error) {
+	advice := &Advice{
+		ClientID:      header.ClientID,
+		AffiliateCode: header.AffiliateCode,
+		BatchID:       header.BatchID,
+		ExecutionDate: header.ExecutionDate.GetTime(),
+		RequestID:     ext.RequestID,
+		RequestType:   ext.RequestType,
+		Amount:        ext.Amount,
+		Currency:      ext.Currency,
+		Status:        string(status.Status),
+		StatusCode:    status.StatusCode,
+		StatusReason:  status.StatusReason,
+		BankReference: status.TransactionRefNo,
+	}
+
+	if ext.ParamList != nil {
+		params, err := ecobank.ParamPairs(ext.ParamList)
+		if err != nil {
+			return nil, fmt.Errorf("params of transaction %s: %w", ext.RequestID, err)
+		}
+		advice.Params = params
+	}
+	return advice, nil
+}
+
+// Renderer renders advices in a format, e.g. as PDF documents.
+type Renderer interface {
+	Render(w io.Writer, advice *Advice) error
+}
+
+var (
+	//go:embed advice.txt
+	defaultText string
+	//go:embed advice.html
+	defaultHTML string
+)
+
+// DefaultTextTemplate is the template of the plain text advices rendered by TextRenderer.
+var DefaultTextTemplate = texttemplate.Must(texttemplate.New("advice.txt").Parse(defaultText))
+
+// DefaultHTMLTemplate is the template of the HTML advices rendered by HTMLRenderer.
+var DefaultHTMLTemplate = htmltemplate.Must(htmltemplate.New("advice.html").Parse(defaultHTML))
+
+// TextRenderer renders advices as plain text with a template, which is executed with the *Advice.
+type TextRenderer struct {
+	// Template defaults to DefaultTextTemplate.
+	Template *texttemplate.Template
+}
+
+// Render implements Renderer.
+func (r TextRenderer) Render(w io.Writer, advice *Advice) error {
+	tmpl := r.Template
+	if tmpl == nil {
+		tmpl = DefaultTextTemplate
+	}
+	return tmpl.Execute(w, advice)
+}
+
+// HTMLRenderer renders advices as HTML with a template, which is executed with the *Advice.
+type HTMLRenderer struct {
+	// Template defaults to DefaultHTMLTemplate.
+	Template *htmltemplate.Template
+}
+
+// Render implements Renderer.
+func (r HTMLRenderer) Render(w io.Writer, advice *Advice) error {
+	tmpl := r.Template
+	if tmpl == nil {
+		tmpl = DefaultHTMLTemplate
+	}
+	return tmpl.Execute(w, advice)
+}
@@ -0,0 +1,94 @@
+package receipts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/profclems/go-ecobank"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPayment() *ecobank.PaymentOptions {
+	return &ecobank.PaymentOptions{
+		PaymentHeader: ecobank.PaymentHeader{
+			ClientID:      "EGHTelc000043",
+			AffiliateCode: "EGH",
+			BatchID:       "EG1593490",
+			ExecutionDate: ecobank.NewTime(time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)),
+		},
+		Extension: []ecobank.PaymentExtension{{
+			RequestID:   "2323",
+			RequestType: ecobank.DOMESTIC,
+			Amount:      decimal.NewFromInt(500),
+			Currency:    "GHS",
+			ParamList: ecobank.NewPaymentParams(ecobank.DomesticTransferParams{
+				CreditAccountNo: "1441000574000",
+				Amount:          decimal.NewFromInt(500),
+				Currency:        "GHS",
+			}),
+		}},
+	}
+}
+
+var testStatuses = map[string]*ecobank.TransactionStatus{
+	"2323": {Status: "SUCCESS", StatusCode: "000", TransactionRefNo: "FT21307ZLYQ2"},
+}
+
+func TestNew(t *testing.T) {
+	advices, err := New(testPayment(), testStatuses)
+	require.NoError(t, err)
+	require.Len(t, advices, 1)
+
+	advice := advices[0]
+	assert.Equal(t, "EG1593490", advice.BatchID)
+	assert.Equal(t, "2323", advice.RequestID)
+	assert.Equal(t, "FT21307ZLYQ2", advice.BankReference)
+	assert.Equal(t, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC), advice.ExecutionDate)
+	assert.Contains(t, advice.Params, ecobank.PaymentParamPair{Key: "creditAccountNo", Value: "*********4000"})
+
+	_, err = New(testPayment(), nil)
+	assert.EqualError(t, err, "no status for transaction 2323")
+}
+
+func TestTextRenderer(t *testing.T) {
+	advices, err := New(testPayment(), testStatuses)
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, TextRenderer{}.Render(&b, advices[0]))
+	assert.Equal(t, `PAYMENT ADVICE
+
+Client:          EGHTelc000043
+Affiliate:       EGH
+Batch:           EG1593490
+Date:            2026-10-16 09:30
+
+Request:         2323
+Type:            DOMESTIC
+Amount:          GHS 500.00
+Status:          SUCCESS (000)
+Bank reference:  FT21307ZLYQ2
+
+Details:
+  creditAccountNo: *********4000
+  amount: 500
+  ccy: GHS
+`, b.String())
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	advices, err := New(testPayment(), map[string]*ecobank.TransactionStatus{
+		"2323": {Status: "FAILED", StatusReason: "<script>alert(1)</script>", TransactionRefNo: "FT21307ZLYQ2"},
+	})
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, HTMLRenderer{}.Render(&b, advices[0]))
+	assert.Contains(t, b.String(), "<tr><th>Bank reference</th><td>FT21307ZLYQ2</td></tr>")
+	assert.Contains(t, b.String(), "<tr><th>Amount</th><td>GHS 500.00</td></tr>")
+	assert.Contains(t, b.String(), "&lt;script&gt;", "values are escaped")
+	assert.NotContains(t, b.String(), "<script>")
+}
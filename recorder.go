@@ -0,0 +1,383 @@
+package ecobank
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// redacted replaces the values of sensitive fields in recorded interactions.
+const redacted = "REDACTED"
+
+// interaction is a recorded request and its response, saved as a JSON file in the cassette directory.
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string `json:"method"`
+	// Path is relative to the base URL, so interactions can be replayed against another environment.
+	Path  string `json:"path"`
+	Query string `json:"query,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// recorder is an http.RoundTripper which records the interactions with the API to a directory,
+// or replays the interactions recorded there without contacting the API.
+type recorder struct {
+	dir      string
+	replay   bool
+	basePath string
+	next     http.RoundTripper
+
+	mu           sync.Mutex
+	count        int
+	interactions []*interaction
+	used         []bool
+}
+
+// WithRecorder records every request sent by the client and its response as a JSON file in dir,
+// for replaying them in tests with WithReplay. The values of the fields tagged with redact, such as
+// credentials, tokens, secure hashes, secret codes and account numbers, are redacted in the bodies and
+// queries, uploaded files are left out and only the Content-Type header of responses is kept. Record into an empty directory, as the
+// interactions are replayed in the order of their files.
+func WithRecorder(dir string) ClientOptionFunc {
+	return func(c *Client) error {
+		c.recorder = &recorder{dir: dir}
+		return nil
+	}
+}
+
+// WithReplay serves the requests of the client from the interactions recorded with WithRecorder in dir,
+// without contacting the API. Requests are matched by method and path, in the order they were recorded,
+// and fail if no recorded interaction is left for them.
+func WithReplay(dir string) ClientOptionFunc {
+	return func(c *Client) error {
+		c.recorder = &recorder{dir: dir, replay: true}
+		return nil
+	}
+}
+
+// installRecorder wraps the transport of the HTTP client with the recorder, if one is set.
+func (c *Client) installRecorder() error {
+	rec := c.recorder
+	if rec == nil {
+		return nil
+	}
+
+	if err := rec.load(); err != nil {
+		return err
+	}
+
+	rec.basePath = c.baseURL.Path
+
+	// copy the HTTP client rather than modifying one passed with WithHTTPClient
+	httpClient := *c.client.HTTPClient
+	rec.next = httpClient.Transport
+	if rec.next == nil {
+		rec.next = http.DefaultTransport
+	}
+	httpClient.Transport = rec
+	c.client.HTTPClient = &httpClient
+
+	return nil
+}
+
+// load reads the interactions recorded in the directory.
+func (r *recorder) load() error {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if os.IsNotExist(err) && !r.replay {
+			return nil
+		}
+		return fmt.Errorf("reading cassette directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		r.count++
+		if !r.replay {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(r.dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		var in interaction
+		if err := json.Unmarshal(b, &in); err != nil {
+			return fmt.Errorf("reading cassette %s: %w", entry.Name(), err)
+		}
+		r.interactions = append(r.interactions, &in)
+	}
+	r.used = make([]bool, len(r.interactions))
+
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.replay {
+		return r.replayRequest(req)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := &interaction{
+		Request: recordedRequest{
+			Method: req.Method,
+			Path:   r.path(req),
+			Query:  sanitizeQuery(req.URL.RawQuery),
+			Body:   string(sanitizeBody(body, req.Header.Get("Content-Type"))),
+		},
+		Response: recordedResponse{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        string(sanitizeBody(respBody, resp.Header.Get("Content-Type"))),
+		},
+	}
+	if err := r.save(in); err != nil {
+		return nil, fmt.Errorf("recording interaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// save writes the interaction to the next file of the directory.
+func (r *recorder) save(in *interaction) error {
+	b, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return err
+	}
+
+	r.count++
+	slug := strings.Trim(strings.NewReplacer("/", "_", ".", "_").Replace(in.Request.Path), "_")
+	name := fmt.Sprintf("%04d_%s_%s.json", r.count, strings.ToLower(in.Request.Method), slug)
+
+	return os.WriteFile(filepath.Join(r.dir, name), b, 0o600)
+}
+
+// replayRequest returns the response of the first unused interaction recorded for the method and path of req.
+func (r *recorder) replayRequest(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.interactions {
+		if r.used[i] || in.Request.Method != req.Method || in.Request.Path != r.path(req) {
+			continue
+		}
+		r.used[i] = true
+
+		header := make(http.Header)
+		if in.Response.ContentType != "" {
+			header.Set("Content-Type", in.Response.ContentType)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, r.path(req))
+}
+
+// path returns the path of the request relative to the base URL.
+func (r *recorder) path(req *http.Request) string {
+	return strings.TrimPrefix(req.URL.Path, r.basePath)
+}
+
+// sanitizeBody redacts the values of the fields tagged with redact in a JSON or multipart/form-data body,
+// including the params of param_list strings. Files uploaded in multipart bodies are replaced entirely.
+// Other bodies are returned unchanged.
+func sanitizeBody(body []byte, contentType string) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	if mediaType, params, err := mime.ParseMediaType(contentType); err == nil && mediaType == "multipart/form-data" {
+		b, err := sanitizeMultipart(body, params["boundary"])
+		if err != nil {
+			return []byte(redacted)
+		}
+		return b
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+// sanitizeMultipart redacts the form fields tagged with redact and the files of a multipart/form-data body.
+func sanitizeMultipart(body []byte, boundary string) ([]byte, error) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	if err := w.SetBoundary(boundary); err != nil {
+		return nil, err
+	}
+
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		if part.FileName() != "" {
+			value = []byte(redacted)
+		} else {
+			value = []byte(redactField(part.FormName(), string(value)).(string))
+		}
+
+		dst, err := w.CreatePart(part.Header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := dst.Write(value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// sanitizeQuery redacts the values of the query parameters tagged with redact.
+func sanitizeQuery(rawQuery string) string {
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+	for key, values := range query {
+		for i, value := range values {
+			values[i] = redactField(key, value).(string)
+		}
+	}
+	return query.Encode()
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = redactField(key, value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
+// redactField redacts the value of the field key, matched against the json keys tagged with redact.
+// The params of param_list strings are redacted by their keys.
+func redactField(key string, value any) any {
+	key = strings.ToLower(key)
+	mode := redactedKeys()[key]
+
+	switch value := value.(type) {
+	case string:
+		if key == "param_list" {
+			return redactParamList(value)
+		}
+		return redactValueString(value, mode)
+	case json.Number:
+		if mode == "" {
+			return value
+		}
+		return redactValueString(value.String(), mode)
+	default:
+		if mode == redactSecret && value != nil {
+			return redacted
+		}
+		return redactValue(value)
+	}
+}
+
+// redactParamList redacts the values of the params of the param_list s. It is redacted entirely if it cannot be parsed.
+func redactParamList(s string) string {
+	if s == "" {
+		return s
+	}
+
+	pairs, err := DecodeParamList([]byte(s))
+	if err != nil {
+		return redacted
+	}
+	for i, pair := range pairs {
+		pairs[i].Value = redactValueString(pair.Value, redactedKeys()[strings.ToLower(pair.Key)])
+	}
+	return string(EncodeParamList(pairs))
+}
@@ -0,0 +1,166 @@
+package ecobank_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/profclems/go-ecobank"
+	"github.com/profclems/go-ecobank/ecobanktest"
+)
+
+func TestRecorder(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cassettes")
+
+	srv := httptest.NewServer(ecobanktest.MustLoad(t, "merchant/accountbalance", "success"))
+	t.Cleanup(srv.Close)
+
+	opt := &ecobank.AccountBalanceOptions{
+		RequestID:     "14232436312",
+		AffiliateCode: "EGH",
+		AccountNo:     "1441000574000",
+		ClientID:      "client",
+		CompanyName:   "company",
+	}
+
+	client, err := ecobank.NewClient("user", "password", "lab-key",
+		ecobank.WithBaseURL(srv.URL),
+		ecobank.WithTokenAndExpiry("secret-token", time.Now().Add(time.Hour)),
+		ecobank.WithRecorder(dir),
+	)
+	require.NoError(t, err)
+
+	recorded, _, err := client.Account.GetBalance(t.Context(), opt)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "0001_post_merchant_accountbalance.json", filepath.Base(files[0]))
+
+	cassette, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(cassette), "secret-token")
+	assert.NotContains(t, string(cassette), opt.GetHash())
+	assert.Contains(t, string(cassette), `\"secureHash\":\"REDACTED\"`)
+
+	t.Run("replay", func(t *testing.T) {
+		client, err := ecobank.NewClient("user", "password", "lab-key",
+			ecobank.WithTokenAndExpiry("token", time.Now().Add(time.Hour)),
+			ecobank.WithReplay(dir),
+		)
+		require.NoError(t, err)
+
+		replayed, resp, err := client.Account.GetBalance(t.Context(), opt)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "*********4000", replayed.AccountNo, "account numbers are recorded partially redacted")
+		replayed.AccountNo = recorded.AccountNo
+		assert.Equal(t, recorded, replayed)
+
+		// every interaction is replayed once
+		_, _, err = client.Account.GetBalance(t.Context(), opt)
+		assert.ErrorContains(t, err, "no recorded interaction for POST merchant/accountbalance")
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := ecobank.NewClient("user", "password", "lab-key", ecobank.WithReplay(filepath.Join(dir, "missing")))
+		assert.Error(t, err)
+	})
+}
+
+func TestRecorder_Redaction(t *testing.T) {
+	srv := httptest.NewServer(ecobanktest.MustLoad(t, "merchant/payment", "success"))
+	t.Cleanup(srv.Close)
+
+	newClient := func(t *testing.T, dir string) *ecobank.Client {
+		client, err := ecobank.NewClient("user", "password", "lab-key",
+			ecobank.WithBaseURL(srv.URL),
+			ecobank.WithTokenAndExpiry("secret-token", time.Now().Add(time.Hour)),
+			ecobank.WithRecorder(dir),
+		)
+		require.NoError(t, err)
+		return client
+	}
+
+	readCassette := func(t *testing.T, dir string) string {
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+
+		cassette, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+		return string(cassette)
+	}
+
+	t.Run("token payment", func(t *testing.T) {
+		dir := t.TempDir()
+
+		_, _, err := newClient(t, dir).Payment.Pay(t.Context(), &ecobank.PaymentOptions{
+			PaymentHeader: ecobank.PaymentHeader{
+				ClientID:      "EGHTelc000043",
+				AffiliateCode: "EGH",
+				BatchID:       "EG1593490",
+				TransactionID: "E12T443308",
+			},
+			Extension: []ecobank.PaymentExtension{{
+				RequestID:   "432",
+				RequestType: ecobank.TOKEN,
+				Amount:      decimal.NewFromInt(40),
+				Currency:    "GHS",
+				ParamList: ecobank.NewPaymentParams(ecobank.TokenTransferParams{
+					SecretCode:          "739201",
+					SourceAccount:       "1441000574000",
+					SenderName:          "TEST USER",
+					Currency:            "GHS",
+					SenderMobileNo:      "233244000000",
+					Amount:              decimal.NewFromInt(40),
+					BeneficiaryName:     "Stephen Hunt",
+					BeneficiaryMobileNo: "233244000001",
+					WithdrawalChannel:   ecobank.WithdrawalChannelATM,
+				}),
+			}},
+		})
+		require.NoError(t, err)
+
+		cassette := readCassette(t, dir)
+		assert.NotContains(t, cassette, "739201", "the secret code is redacted")
+		assert.NotContains(t, cassette, "1441000574000", "account numbers are partially redacted")
+		assert.NotContains(t, cassette, "233244000000", "phone numbers are partially redacted")
+		assert.Contains(t, cassette, `secretCode`)
+		assert.Contains(t, cassette, `Stephen Hunt`, "other params are kept")
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		type uploadOptions struct {
+			RequestID string            `json:"requestId"`
+			AccountNo string            `json:"accountNo"`
+			Document  *ecobank.FormFile `json:"document"`
+			ecobank.SecureHashOptions
+		}
+
+		dir := t.TempDir()
+
+		opt := &uploadOptions{
+			RequestID: "ECO76383823",
+			AccountNo: "1441000574000",
+			Document:  &ecobank.FormFile{FileName: "passport.pdf", Content: strings.NewReader("%PDF-1.4 passport scan")},
+		}
+		_, err := newClient(t, dir).Call(t.Context(), http.MethodPost, "merchant/upload", opt, nil)
+		require.NoError(t, err)
+
+		cassette := readCassette(t, dir)
+		assert.NotContains(t, cassette, "passport scan", "files are left out")
+		assert.NotContains(t, cassette, "1441000574000")
+		assert.NotContains(t, cassette, opt.SecureHash)
+		assert.Contains(t, cassette, "ECO76383823")
+	})
+}
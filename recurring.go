@@ -0,0 +1,212 @@
+package ecobank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultRecurringMaxCatchUp = 10
+
+// RecurringPaymentStore records the occurrences generated by recurring payment plans, so that a payment is not
+// generated twice across restarts or by concurrent processes. Implementations must be safe for concurrent use.
+type RecurringPaymentStore interface {
+	// Last returns the sequence number and due time of the last occurrence generated for the plan,
+	// or zero values if none was generated.
+	Last(ctx context.Context, planID string) (seq int, at time.Time, err error)
+	// Record records the occurrence as generated. It reports false if the plan already has an occurrence
+	// with that sequence number, e.g. generated by another process.
+	Record(ctx context.Context, planID string, seq int, at time.Time) (bool, error)
+}
+
+// RecurringPaymentPlan is a standing instruction: it generates a payment from a template extension
+// every time its schedule is due.
+//
+// Call Generate periodically, e.g. from a cron job, and submit the returned payments with PaymentService.Pay
+// or an Outbox. Each occurrence is numbered from 1 and its request ID, batch ID and transaction ID are the
+// plan ID followed by the sequence number, e.g. RENT-3, so that a payment submitted twice is rejected by the API.
+type RecurringPaymentPlan struct {
+	// ID identifies the plan. It prefixes the request IDs of the generated payments, so it must be unique.
+	ID string
+	// Template is the extension to pay. Its RequestID is ignored.
+	Template PaymentExtension
+	// Header is the template of the payment header, e.g. with the AffiliateCode, DebitType and ClientID.
+	// The batch, transaction and amount fields are set for every occurrence.
+	Header PaymentHeader
+	// Schedule tells when payments are due, e.g. a CronSchedule.
+	Schedule Schedule
+	// Start is when the plan starts. Occurrences before it are not generated.
+	Start time.Time
+	// End, if set, is when the plan ends. Occurrences after it are not generated.
+	End time.Time
+	// MaxOccurrences, if positive, is the number of payments after which the plan ends.
+	MaxOccurrences int
+	// MaxCatchUp is the maximum number of payments generated by a call to Generate, e.g. after the job generating
+	// them did not run for a while. The remaining ones are generated by the next calls. It defaults to 10.
+	MaxCatchUp int
+	// Calendar, if set, moves the execution date of occurrences which do not fall on a business day
+	// of the affiliate of the header to the next business day. Generate fails with ErrNoBusinessDay
+	// if there is none within a year.
+	Calendar BusinessCalendar
+	// Store records the generated occurrences.
+	Store RecurringPaymentStore
+}
+
+// NewRecurringPaymentPlan returns a plan paying the template extension on the schedule from start,
+// with occurrences recorded in a MemoryRecurringPaymentStore.
+func NewRecurringPaymentPlan(id string, template PaymentExtension, schedule Schedule, start time.Time) *RecurringPaymentPlan {
+	return &RecurringPaymentPlan{
+		ID:         id,
+		Template:   template,
+		Schedule:   schedule,
+		Start:      start,
+		MaxCatchUp: defaultRecurringMaxCatchUp,
+		Store:      NewMemoryRecurringPaymentStore(),
+	}
+}
+
+// Generate returns the payments due at now which were not generated yet, in sequence, and records them in the store.
+//
+// If another process records an occurrence first, Generate stops and returns the payments generated so far,
+// so every occurrence is returned by exactly one call.
+func (p *RecurringPaymentPlan) Generate(ctx context.Context, now time.Time) ([]*PaymentOptions, error) {
+	if p.ID == "" {
+		return nil, errors.New("recurring payment plan must have an ID")
+	}
+	if p.Schedule == nil {
+		return nil, fmt.Errorf("recurring payment plan %s must have a schedule", p.ID)
+	}
+
+	seq, last, err := p.Store.Last(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("loading last occurrence of recurring payment plan %s: %w", p.ID, err)
+	}
+	if seq == 0 {
+		// Next returns times strictly after the given one, so step back for Start itself to be due
+		last = p.Start.Add(-time.Nanosecond)
+	}
+
+	maxCatchUp := p.MaxCatchUp
+	if maxCatchUp <= 0 {
+		maxCatchUp = defaultRecurringMaxCatchUp
+	}
+
+	var payments []*PaymentOptions
+	for len(payments) < maxCatchUp {
+		if p.MaxOccurrences > 0 && seq >= p.MaxOccurrences {
+			break
+		}
+
+		at := p.Schedule.Next(last)
+		if at.IsZero() || at.After(now) || (!p.End.IsZero() && at.After(p.End)) {
+			break
+		}
+
+		payment, err := p.occurrence(seq+1, at)
+		if err != nil {
+			return payments, err
+		}
+
+		ok, err := p.Store.Record(ctx, p.ID, seq+1, at)
+		if err != nil {
+			return payments, fmt.Errorf("recording occurrence %d of recurring payment plan %s: %w", seq+1, p.ID, err)
+		}
+		if !ok {
+			break
+		}
+
+		payments = append(payments, payment)
+		seq, last = seq+1, at
+	}
+
+	return payments, nil
+}
+
+// occurrence returns the payment of the occurrence with the given sequence number, due at at.
+func (p *RecurringPaymentPlan) occurrence(seq int, at time.Time) (*PaymentOptions, error) {
+	ext, err := cloneExtension(p.Template)
+	if err != nil {
+		return nil, fmt.Errorf("copying template of recurring payment plan %s: %w", p.ID, err)
+	}
+
+	id := fmt.Sprintf("%s-%d", p.ID, seq)
+	ext.RequestID = id
+
+	if p.Calendar != nil {
+		at, err = nextBusinessDay(p.Calendar, p.Header.AffiliateCode, at)
+		if err != nil {
+			return nil, fmt.Errorf("scheduling occurrence %d of recurring payment plan %s: %w", seq, p.ID, err)
+		}
+	}
+
+	header := p.Header
+	header.BatchID = id
+	header.TransactionID = id
+	header.BatchSequence = "1"
+	header.TotalBatches = "1"
+	header.BatchCount = 1
+	header.TransactionCount = 1
+	header.BatchAmount = ext.Amount
+	header.TransactionAmount = ext.Amount
+	header.ExecutionDate = NewTime(at)
+
+	return &PaymentOptions{
+		PaymentHeader: header,
+		Extension:     []PaymentExtension{ext},
+	}, nil
+}
+
+// cloneExtension returns a deep copy of the extension, so that generated payments do not share params.
+func cloneExtension(ext PaymentExtension) (PaymentExtension, error) {
+	b, err := json.Marshal(ext)
+	if err != nil {
+		return PaymentExtension{}, err
+	}
+
+	var clone PaymentExtension
+	if err := json.Unmarshal(b, &clone); err != nil {
+		return PaymentExtension{}, err
+	}
+	return clone, nil
+}
+
+// MemoryRecurringPaymentStore is an in-memory RecurringPaymentStore. It only prevents double generation
+// within a single process.
+type MemoryRecurringPaymentStore struct {
+	mu    sync.Mutex
+	plans map[string]recurringOccurrence
+}
+
+type recurringOccurrence struct {
+	seq int
+	at  time.Time
+}
+
+// NewMemoryRecurringPaymentStore returns a new, empty MemoryRecurringPaymentStore.
+func NewMemoryRecurringPaymentStore() *MemoryRecurringPaymentStore {
+	return &MemoryRecurringPaymentStore{plans: make(map[string]recurringOccurrence)}
+}
+
+// Last implements RecurringPaymentStore.
+func (s *MemoryRecurringPaymentStore) Last(_ context.Context, planID string) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := s.plans[planID]
+	return last.seq, last.at, nil
+}
+
+// Record implements RecurringPaymentStore.
+func (s *MemoryRecurringPaymentStore) Record(_ context.Context, planID string, seq int, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if seq <= s.plans[planID].seq {
+		return false, nil
+	}
+	s.plans[planID] = recurringOccurrence{seq: seq, at: at}
+	return true, nil
+}
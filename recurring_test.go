@@ -0,0 +1,121 @@
+package ecobank
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRecurringPlan(t *testing.T, expr string) *RecurringPaymentPlan {
+	t.Helper()
+
+	schedule, err := ParseCron(expr)
+	require.NoError(t, err)
+
+	plan := NewRecurringPaymentPlan("RENT", PaymentExtension{
+		RequestType: DOMESTIC,
+		Amount:      decimal.NewFromInt(500),
+		Currency:    "GHS",
+		ParamList: NewPaymentParams(DomesticTransferParams{
+			CreditAccountNo: "1441001996321",
+			Amount:          decimal.NewFromInt(500),
+			Currency:        "GHS",
+		}),
+	}, schedule, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	plan.Header = PaymentHeader{AffiliateCode: "EGH", DebitType: "Multiple", ClientID: "ECO76383823"}
+	return plan
+}
+
+func TestRecurringPaymentPlan_Generate(t *testing.T) {
+	plan := newTestRecurringPlan(t, "@monthly")
+	ctx := context.Background()
+
+	payments, err := plan.Generate(ctx, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, payments, 3)
+
+	for i, payment := range payments {
+		id := []string{"RENT-1", "RENT-2", "RENT-3"}[i]
+		assert.Equal(t, id, payment.Extension[0].RequestID)
+		assert.Equal(t, id, payment.PaymentHeader.BatchID)
+		assert.Equal(t, id, payment.PaymentHeader.TransactionID)
+		assert.Equal(t, "EGH", payment.PaymentHeader.AffiliateCode)
+		assert.Equal(t, 1, payment.PaymentHeader.TransactionCount)
+		assert.True(t, decimal.NewFromInt(500).Equal(payment.PaymentHeader.BatchAmount))
+		assert.Equal(t, time.Date(2024, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC), payment.PaymentHeader.ExecutionDate.time)
+	}
+	assert.NotSame(t, payments[0].Extension[0].ParamList, payments[1].Extension[0].ParamList)
+
+	payments, err = plan.Generate(ctx, time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Empty(t, payments, "occurrences are generated once")
+
+	payments, err = plan.Generate(ctx, time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	assert.Equal(t, "RENT-4", payments[0].Extension[0].RequestID)
+}
+
+func TestRecurringPaymentPlan_Limits(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	plan := newTestRecurringPlan(t, "@monthly")
+	plan.MaxCatchUp = 2
+	payments, err := plan.Generate(ctx, now)
+	require.NoError(t, err)
+	assert.Len(t, payments, 2)
+
+	plan = newTestRecurringPlan(t, "@monthly")
+	plan.MaxOccurrences = 3
+	payments, err = plan.Generate(ctx, now)
+	require.NoError(t, err)
+	assert.Len(t, payments, 3)
+
+	plan = newTestRecurringPlan(t, "@monthly")
+	plan.End = time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC)
+	payments, err = plan.Generate(ctx, now)
+	require.NoError(t, err)
+	assert.Len(t, payments, 2)
+}
+
+func TestRecurringPaymentPlan_SharedStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	first := newTestRecurringPlan(t, "@monthly")
+	second := newTestRecurringPlan(t, "@monthly")
+	second.Store = first.Store
+
+	payments, err := first.Generate(ctx, now)
+	require.NoError(t, err)
+	assert.Len(t, payments, 2)
+
+	payments, err = second.Generate(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, payments)
+}
+
+func TestRecurringPaymentPlan_Calendar(t *testing.T) {
+	plan := newTestRecurringPlan(t, "0 9 6 * *")
+	plan.Calendar = WeekdayCalendar{}
+
+	payments, err := plan.Generate(context.Background(), time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	// January 6, 2024 is a Saturday
+	assert.Equal(t, time.Date(2024, time.January, 8, 0, 0, 0, 0, time.UTC), payments[0].PaymentHeader.ExecutionDate.time)
+}
+
+func TestRecurringPaymentPlan_NoBusinessDay(t *testing.T) {
+	plan := newTestRecurringPlan(t, "0 9 6 * *")
+	plan.Calendar = closedCalendar{}
+
+	payments, err := plan.Generate(context.Background(), time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrNoBusinessDay)
+	assert.Empty(t, payments)
+}
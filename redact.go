@@ -0,0 +1,212 @@
+package ecobank
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Fields of request options, responses and payment params tagged with redact are hidden by their String
+// method, so that logging them with %v or %+v does not leak credentials or personal data, and in the
+// interactions recorded with WithRecorder:
+//
+//   - redact:"secret" replaces the value with REDACTED, e.g. for passwords and secret codes.
+//   - redact:"partial" only keeps the last 4 characters, e.g. for account and phone numbers.
+//
+// The values are still sent to the API as is. Fields tagged with encrypt are redacted as secrets by the recorder.
+const (
+	redactSecret  = "secret"
+	redactPartial = "partial"
+)
+
+// redactKeep is the number of trailing characters kept by partial redaction.
+const redactKeep = 4
+
+// redactedString formats the struct v like %+v, with the values of the fields tagged with redact hidden.
+// Unexported fields, including those promoted from unexported embedded structs such as the secure hash, are left out.
+func redactedString(v any) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "<nil>"
+		}
+		rv = rv.Elem()
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for _, field := range reflect.VisibleFields(rv.Type()) {
+		if !field.IsExported() || field.Anonymous || !rv.Type().Field(field.Index[0]).IsExported() {
+			continue
+		}
+		if b.Len() > 1 {
+			b.WriteByte(' ')
+		}
+
+		fv := rv.FieldByIndex(field.Index)
+		b.WriteString(field.Name)
+		b.WriteByte(':')
+		b.WriteString(redactValueString(fmt.Sprint(fv.Interface()), field.Tag.Get("redact")))
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+// redactValueString hides the formatted value s with the given redact mode.
+func redactValueString(s, mode string) string {
+	if s == "" {
+		return s
+	}
+
+	switch mode {
+	case redactSecret:
+		return redacted
+	case redactPartial:
+		r := []rune(s)
+		if len(r) <= redactKeep {
+			return strings.Repeat("*", len(r))
+		}
+		return strings.Repeat("*", len(r)-redactKeep) + string(r[len(r)-redactKeep:])
+	default:
+		return s
+	}
+}
+
+// redactedParamKeys returns the redact modes of the keys of the typed payment params, in lower case,
+// which are used to redact raw param pairs.
+var redactedParamKeys = sync.OnceValue(func() map[string]string {
+	keys := make(map[string]string)
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[DomesticTransferParams](),
+		reflect.TypeFor[TokenTransferParams](),
+		reflect.TypeFor[TokenIAParams](),
+		reflect.TypeFor[InterbankTransferParams](),
+		reflect.TypeFor[InterbankIAParams](),
+		reflect.TypeFor[MomoIAParams](),
+	} {
+		for _, field := range paramFields(typ) {
+			mode := field.Tag.Get("redact")
+			if _, ok := field.Tag.Lookup("encrypt"); ok {
+				mode = redactSecret
+			}
+			if mode != "" {
+				keys[strings.ToLower(field.key)] = mode
+			}
+		}
+	}
+	return keys
+})
+
+// redactedKeys returns the redact modes of the json keys of the request options, responses and payment params,
+// in lower case, which are used to redact the bodies and queries of recorded interactions. Keys tagged with
+// different modes in several types are redacted with the stricter one.
+var redactedKeys = sync.OnceValue(func() map[string]string {
+	keys := maps.Clone(redactedParamKeys())
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[AccessTokenOptions](),
+		reflect.TypeFor[BearerToken](),
+		reflect.TypeFor[secureHashOption](),
+		reflect.TypeFor[AccountBalanceOptions](),
+		reflect.TypeFor[AccountEnquiryOptions](),
+		reflect.TypeFor[AccountEnquiryThirdPartyOptions](),
+		reflect.TypeFor[GenerateStatementOptions](),
+		reflect.TypeFor[CreateAccountOptions](),
+		reflect.TypeFor[GetRemitteeAccountOptions](),
+	} {
+		for _, field := range paramFields(typ) {
+			key := strings.ToLower(field.key)
+			if mode := field.Tag.Get("redact"); mode != "" && keys[key] != redactSecret {
+				keys[key] = mode
+			}
+		}
+	}
+	return keys
+})
+
+// ParamPairs returns the key/value pairs of the param_list of params in the order they are sent, with the
+// values of the keys known to be sensitive redacted, e.g. to print them on receipts or in audit logs.
+func ParamPairs(params PaymentParamInterface) ([]PaymentParamPair, error) {
+	b, err := params.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := DecodeParamList(b)
+	if err != nil {
+		return nil, err
+	}
+	for i, pair := range pairs {
+		pairs[i].Value = redactValueString(pair.Value, redactedParamKeys()[strings.ToLower(pair.Key)])
+	}
+	return pairs, nil
+}
+
+// String returns the params with the sensitive values redacted.
+func (param *PaymentParams[T]) String() string {
+	return fmt.Sprint(param.param)
+}
+
+// String returns the params with the values of the keys known to be sensitive, such as secretCode, redacted.
+func (pairs paymentParamPairs) String() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, pair := range pairs {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(pair.Key)
+		b.WriteByte(':')
+		b.WriteString(redactValueString(pair.Value, redactedParamKeys()[strings.ToLower(pair.Key)]))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// String returns the params with the sensitive values redacted.
+func (param *customPaymentParams) String() string {
+	return redactedString(param.param)
+}
+
+// String returns the options with the password redacted and the user ID partially redacted.
+func (opt AccessTokenOptions) String() string { return redactedString(opt) }
+
+// String returns the token with its value redacted.
+func (t BearerToken) String() string { return redactedString(t) }
+
+// String returns the options with the account number partially redacted.
+func (opt AccountBalanceOptions) String() string { return redactedString(opt) }
+
+// String returns the options with the account number partially redacted.
+func (opt AccountEnquiryOptions) String() string { return redactedString(opt) }
+
+// String returns the options with the account number partially redacted.
+func (opt AccountEnquiryThirdPartyOptions) String() string { return redactedString(opt) }
+
+// String returns the options with the account number partially redacted.
+func (opt GenerateStatementOptions) String() string { return redactedString(opt) }
+
+// String returns the options with the personal data of the customer redacted.
+func (opt CreateAccountOptions) String() string { return redactedString(opt) }
+
+// String returns the options with the account number partially redacted.
+func (opt GetRemitteeAccountOptions) String() string { return redactedString(opt) }
+
+// String returns the params with the account number partially redacted.
+func (p DomesticTransferParams) String() string { return redactedString(p) }
+
+// String returns the params with the secret code redacted and the account and phone numbers partially redacted.
+func (p TokenTransferParams) String() string { return redactedString(p) }
+
+// String returns the params with the test answer redacted and the account, phone and ID numbers partially redacted.
+func (p TokenIAParams) String() string { return redactedString(p) }
+
+// String returns the params with the account and phone numbers partially redacted.
+func (p InterbankTransferParams) String() string { return redactedString(p) }
+
+// String returns the params with the account and phone numbers partially redacted.
+func (p InterbankIAParams) String() string { return redactedString(p) }
+
+// String returns the params with the test answer redacted and the account, phone and ID numbers partially redacted.
+func (p MomoIAParams) String() string { return redactedString(p) }
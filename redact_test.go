@@ -0,0 +1,65 @@
+package ecobank
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactedString(t *testing.T) {
+	opt := &AccessTokenOptions{UserID: "iamaunifieddev103", Password: "$2a$10$Wmame"}
+	assert.Equal(t, "{UserID:*************v103 Password:REDACTED}", fmt.Sprintf("%+v", opt))
+	assert.Equal(t, "{UserID:*************v103 Password:REDACTED}", fmt.Sprint(*opt))
+	assert.Equal(t, "{Username:user Token:REDACTED}", fmt.Sprint(BearerToken{Username: "user", Token: "eyJhbGciOi"}))
+
+	balance := &AccountBalanceOptions{RequestID: "ECO76383823", AccountNo: "1441000574000"}
+	balance.SetHash("hash")
+	s := fmt.Sprintf("%v", balance)
+	assert.Contains(t, s, "AccountNo:*********4000")
+	assert.NotContains(t, s, "1441000574000")
+	assert.NotContains(t, s, "hash")
+
+	assert.Contains(t, fmt.Sprint(GenerateStatementOptions{AccountNumber: "123"}), "AccountNumber:***")
+	assert.Contains(t, fmt.Sprint(AccessTokenOptions{}), "Password:}", "empty values are left empty")
+}
+
+func TestPaymentParams_String(t *testing.T) {
+	params := NewPaymentParams(TokenTransferParams{
+		SecretCode:     "123456",
+		SourceAccount:  "1441000574000",
+		SenderMobileNo: "233241234567",
+		Amount:         decimal.NewFromInt(10),
+	})
+	opt := &PaymentOptions{Extension: []PaymentExtension{{RequestType: TOKEN, ParamList: params}}}
+
+	s := fmt.Sprintf("%+v", opt)
+	assert.Contains(t, s, "SecretCode:REDACTED")
+	assert.Contains(t, s, "SourceAccount:*********4000")
+	assert.Contains(t, s, "SenderMobileNo:********4567")
+	assert.Contains(t, s, "Amount:10")
+	assert.NotContains(t, s, "123456")
+
+	pairs := NewPaymentParamsFromPairs(
+		PaymentParamPair{Key: "secretCode", Value: "123456"},
+		PaymentParamPair{Key: "creditAccountNo", Value: "1441000574000"},
+		PaymentParamPair{Key: "ccy", Value: "GHS"},
+	)
+	assert.Equal(t, "[secretCode:REDACTED creditAccountNo:*********4000 ccy:GHS]", fmt.Sprint(pairs))
+}
+
+func TestParamPairs(t *testing.T) {
+	pairs, err := ParamPairs(NewPaymentParams(TokenTransferParams{
+		SecretCode:    "123456",
+		SourceAccount: "1441000574000",
+		Amount:        decimal.NewFromInt(10),
+		Currency:      "GHS",
+	}))
+	require.NoError(t, err)
+	assert.Contains(t, pairs, PaymentParamPair{Key: "secretCode", Value: "REDACTED"})
+	assert.Contains(t, pairs, PaymentParamPair{Key: "sourceAccount", Value: "*********4000"})
+	assert.Contains(t, pairs, PaymentParamPair{Key: "amount", Value: "10"})
+	assert.Equal(t, "transactionDescription", pairs[0].Key, "the pairs are in the order they are sent")
+}
@@ -0,0 +1,40 @@
+package ecobank
+
+import (
+	"crypto/rand"
+	"strings"
+	"time"
+)
+
+// TransactionRefLength is the length of the references generated by GenerateTransactionRef. It is within
+// MaxPaymentIDLength, the limit of the batch and transaction IDs checked by PaymentOptions.Check.
+const TransactionRefLength = 20
+
+// transactionRefAlphabet is the charset of generated references, which all the reference fields accept.
+const transactionRefAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// GenerateTransactionRef returns a unique reference for a payment of an affiliate, e.g. "EGH261016K3Q7ZP2W4XM",
+// made of the affiliate code, the date in UTC as YYMMDD, and random characters. The references are
+// TransactionRefLength upper case letters and digits, and are suitable for the TransactionID and BatchID
+// of a payment header and for the TransferReferenceNo of transfers.
+//
+// The random part carries 55 bits of entropy for three letter affiliate codes, so collisions are negligible
+// even for millions of payments a day.
+func GenerateTransactionRef(affiliateCode string, t time.Time) string {
+	var b strings.Builder
+	b.Grow(TransactionRefLength)
+
+	for _, r := range strings.ToUpper(affiliateCode) {
+		if b.Len() == 3 {
+			break
+		}
+		if strings.ContainsRune(transactionRefAlphabet, r) {
+			b.WriteRune(r)
+		}
+	}
+	b.WriteString(t.UTC().Format("060102"))
+
+	// the text is in base32, so its characters are all in the alphabet
+	b.WriteString(rand.Text()[:TransactionRefLength-b.Len()])
+	return b.String()
+}
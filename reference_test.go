@@ -0,0 +1,37 @@
+package ecobank
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTransactionRef(t *testing.T) {
+	at := time.Date(2026, 10, 16, 23, 30, 0, 0, time.FixedZone("WAT", 3600))
+
+	ref := GenerateTransactionRef("egh", at)
+	assert.Len(t, ref, TransactionRefLength)
+	assert.Regexp(t, `^EGH261016[A-Z2-7]{11}$`, ref, "the date is in UTC")
+
+	assert.Regexp(t, `^EG261016[A-Z2-7]{12}$`, GenerateTransactionRef("E-G", at), "other characters are dropped")
+	assert.Regexp(t, `^ECI261016`, GenerateTransactionRef("ECIX", at), "the affiliate code is cut to 3 characters")
+	assert.Len(t, GenerateTransactionRef("", at), TransactionRefLength)
+}
+
+func TestGenerateTransactionRef_Unique(t *testing.T) {
+	charset := regexp.MustCompile(`^[0-9A-Z]+$`)
+	at := time.Now()
+
+	const n = 200_000
+	seen := make(map[string]bool, n)
+	for range n {
+		ref := GenerateTransactionRef("EGH", at)
+		require.False(t, seen[ref], "duplicate reference %s", ref)
+		require.Regexp(t, charset, ref)
+		require.LessOrEqual(t, len(ref), MaxPaymentIDLength)
+		seen[ref] = true
+	}
+}
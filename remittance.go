@@ -38,8 +38,8 @@ type ListInstitutionsOptions struct {
 // ListInstitutions returns the list of Ecobank affiliates allowed to participate in cross-border transactions.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#eaeb6f0a-107d-4717-b202-b8eee1529b74
-func (s *RemittanceService) ListInstitutions(ctx context.Context, opt *ListInstitutionsOptions) ([]*Institution, *Response, error) {
-	institutions, resp, err := DoRequest[[]*Institution](ctx, s.client, http.MethodPost, "merchant/ecobankafrica/institutions", opt)
+func (s *RemittanceService) ListInstitutions(ctx context.Context, opt *ListInstitutionsOptions, options ...RequestOptionFunc) ([]*Institution, *Response, error) {
+	institutions, resp, err := DoRequest[[]*Institution](ctx, s.client, http.MethodPost, s.client.path("Remittance", "ListInstitutions"), opt, options...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -69,7 +69,7 @@ type GetRemitteeAccountOptions struct {
 	AffiliateCode         string `json:"affiliateCode"`
 	DeliveryMethod        string `json:"deliveryMethod"`
 	DestinationEntityCode string `json:"destinationEntityCode"`
-	AccountNo             string `json:"accountNo"`
+	AccountNo             string `json:"accountNo" redact:"partial"`
 	DestinationCountry    string `json:"destinationCountry"`
 
 	secureHashOption
@@ -78,11 +78,15 @@ type GetRemitteeAccountOptions struct {
 // GetAccount returns account details of a supplied account.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#68970106-787a-4cfe-917f-91b2e3701bf3
-func (s *RemittanceService) GetAccount(ctx context.Context, opt *GetRemitteeAccountOptions) (*RemitteeAccount, *Response, error) {
-	return DoRequest[RemitteeAccount](ctx, s.client, http.MethodPost, "merchant/ecobankafrica/account/enquiry", opt)
+func (s *RemittanceService) GetAccount(ctx context.Context, opt *GetRemitteeAccountOptions, options ...RequestOptionFunc) (*RemitteeAccount, *Response, error) {
+	return DoRequest[RemitteeAccount](ctx, s.client, http.MethodPost, s.client.path("Remittance", "GetAccount"), opt, options...)
 }
 
 // Pay is a wrapper around the PaymentService.Pay method.
-func (s *RemittanceService) Pay(ctx context.Context, opt *PaymentOptions) (*string, *Response, error) {
-	return s.client.Payment.Pay(ctx, opt)
+//
+// The API has no dedicated endpoint for sending remittances. Cross-border transfers are payments of type
+// TOKENIA, INTERBANKIA or MOMOIA, with the receiver and purpose of transfer set in TokenIAParams,
+// InterbankIAParams or MomoIAParams.
+func (s *RemittanceService) Pay(ctx context.Context, opt *PaymentOptions, options ...RequestOptionFunc) (*PaymentAck, *Response, error) {
+	return s.client.Payment.Pay(ctx, opt, options...)
 }
@@ -0,0 +1,138 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/profclems/go-ecobank/calendar"
+	"github.com/shopspring/decimal"
+)
+
+// ErrCorridorNotSupported is returned when cross-border transfers are not allowed from the source affiliate
+// to the destination affiliate.
+var ErrCorridorNotSupported = errors.New("corridor not supported")
+
+// affiliateCurrencies are the local currencies of the countries of Ecobank affiliates.
+var affiliateCurrencies = map[string]string{
+	"BF": "XOF", "BJ": "XOF", "BI": "BIF", "CD": "CDF", "CF": "XAF", "CG": "XAF", "CI": "XOF", "CM": "XAF",
+	"CV": "CVE", "GA": "XAF", "GH": "GHS", "GM": "GMD", "GN": "GNF", "GQ": "XAF", "GW": "XOF", "KE": "KES",
+	"LR": "LRD", "ML": "XOF", "MW": "MWK", "MZ": "MZN", "NE": "XOF", "NG": "NGN", "RW": "RWF", "SL": "SLE",
+	"SN": "XOF", "SS": "SSP", "ST": "STN", "TD": "XAF", "TG": "XOF", "TZ": "TZS", "UG": "UGX", "ZM": "ZMW",
+}
+
+// AffiliateCurrency returns the local currency of an affiliate, e.g. "EGH", or of a country, e.g. "GH".
+// It reports false for unknown affiliates.
+func AffiliateCurrency(affiliateCode string) (string, bool) {
+	currency, ok := affiliateCurrencies[calendar.Country(affiliateCode)]
+	return currency, ok
+}
+
+// CrossBorderOptions describes a cross-border transfer made with NewTokenIAExtension or NewMomoIAExtension.
+type CrossBorderOptions struct {
+	// RequestID is the request ID of the extension. It is also used to list the institutions of the corridor.
+	RequestID string
+	// ClientID is the client ID used to list the institutions of the corridor.
+	ClientID string
+	// SourceAffiliate is the affiliate sending the transfer, e.g. "EGH".
+	SourceAffiliate string
+	// DestinationAffiliate is the affiliate receiving the transfer, e.g. "ENG".
+	DestinationAffiliate string
+	// Amount is the amount sent, in Currency.
+	Amount decimal.Decimal
+	// Currency is the currency of the amount. It defaults to the currency of the source affiliate.
+	Currency string
+}
+
+// NewTokenIAExtension returns a TOKENIA extension sending params from the source to the destination affiliate.
+//
+// The corridor is checked with ListInstitutions, and ErrCorridorNotSupported is returned if the destination
+// is not allowed. The destination affiliate and currency, and the source amount, are filled in the params
+// unless set. The receiver ID is not validated; use TokenIAParams.ValidateReceiverID to check it.
+func (s *RemittanceService) NewTokenIAExtension(ctx context.Context, opt *CrossBorderOptions, params TokenIAParams, options ...RequestOptionFunc) (*PaymentExtension, error) {
+	if err := s.fillCrossBorder(ctx, opt, &params.DestinationAffiliate, &params.DestinationCurrency, &params.SourceAmount, options...); err != nil {
+		return nil, err
+	}
+	return newCrossBorderExtension(opt, TOKENIA, NewPaymentParams(params)), nil
+}
+
+// NewMomoIAExtension returns a MOMOIA extension sending params from the source to the destination affiliate.
+// The corridor is checked and the params are filled as with NewTokenIAExtension.
+func (s *RemittanceService) NewMomoIAExtension(ctx context.Context, opt *CrossBorderOptions, params MomoIAParams, options ...RequestOptionFunc) (*PaymentExtension, error) {
+	if err := s.fillCrossBorder(ctx, opt, &params.DestinationAffiliate, &params.DestinationCurrency, &params.SourceAmount, options...); err != nil {
+		return nil, err
+	}
+	return newCrossBorderExtension(opt, MOMOIA, NewPaymentParams(params)), nil
+}
+
+// fillCrossBorder checks the corridor of the transfer and fills the destination affiliate, destination
+// currency and source amount of its params unless set.
+func (s *RemittanceService) fillCrossBorder(ctx context.Context, opt *CrossBorderOptions, destAffiliate, destCurrency *string, sourceAmount *decimal.Decimal, options ...RequestOptionFunc) error {
+	source := strings.ToUpper(strings.TrimSpace(opt.SourceAffiliate))
+	dest := strings.ToUpper(strings.TrimSpace(opt.DestinationAffiliate))
+	if source == "" || dest == "" {
+		return errors.New("source and destination affiliates must be set")
+	}
+	if calendar.Country(source) == calendar.Country(dest) {
+		return fmt.Errorf("%w: %s -> %s is not cross-border", ErrCorridorNotSupported, source, dest)
+	}
+	if *destAffiliate != "" && !strings.EqualFold(*destAffiliate, dest) {
+		return fmt.Errorf("destination affiliate %s of the params does not match %s", *destAffiliate, dest)
+	}
+
+	if err := s.checkCorridor(ctx, opt, source, dest, options...); err != nil {
+		return err
+	}
+
+	*destAffiliate = dest
+	if *destCurrency == "" {
+		currency, ok := AffiliateCurrency(dest)
+		if !ok {
+			return fmt.Errorf("unknown currency of affiliate %s: set the destination currency of the params", dest)
+		}
+		*destCurrency = currency
+	}
+	if sourceAmount.IsZero() {
+		*sourceAmount = opt.Amount
+	}
+	return nil
+}
+
+// checkCorridor reports ErrCorridorNotSupported unless an institution of the destination is listed
+// for the source affiliate.
+func (s *RemittanceService) checkCorridor(ctx context.Context, opt *CrossBorderOptions, source, dest string, options ...RequestOptionFunc) error {
+	destCountry := calendar.Country(dest)
+
+	institutions, _, err := s.ListInstitutions(ctx, &ListInstitutionsOptions{
+		RequestID:          opt.RequestID,
+		ClientID:           opt.ClientID,
+		AffiliateCode:      source,
+		DestinationCountry: destCountry,
+	}, options...)
+	if err != nil {
+		return fmt.Errorf("listing institutions of corridor %s -> %s: %w", source, dest, err)
+	}
+
+	for _, institution := range institutions {
+		if strings.EqualFold(institution.CountryCode, destCountry) || strings.EqualFold(institution.InstitutionID, dest) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s -> %s", ErrCorridorNotSupported, source, dest)
+}
+
+func newCrossBorderExtension(opt *CrossBorderOptions, paymentType PaymentType, params PaymentParamInterface) *PaymentExtension {
+	currency := opt.Currency
+	if currency == "" {
+		currency, _ = AffiliateCurrency(opt.SourceAffiliate)
+	}
+
+	return &PaymentExtension{
+		RequestID:   opt.RequestID,
+		RequestType: paymentType,
+		ParamList:   params,
+		Amount:      opt.Amount,
+		Currency:    currency,
+	}
+}
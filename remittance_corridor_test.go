@@ -0,0 +1,110 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCorridorClient(t *testing.T, institutions string) (*Client, *ListInstitutionsOptions) {
+	t.Helper()
+
+	var listed ListInstitutionsOptions
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			require.NoError(t, json.Unmarshal(body, &listed))
+
+			rec := httptest.NewRecorder()
+			_, _ = rec.WriteString(`{"response_code": 200, "response_content": ` + institutions + `}`)
+			return rec.Result(), nil
+		},
+	}
+	return client, &listed
+}
+
+func TestRemittanceService_NewTokenIAExtension(t *testing.T) {
+	client, listed := newCorridorClient(t, `[{"institutionId": "ENG", "institutionName": "ECOBANK NIGERIA", "countryCode": "NG"}]`)
+
+	opt := &CrossBorderOptions{
+		RequestID:            "ECO1234",
+		ClientID:             "ECO76383823",
+		SourceAffiliate:      "EGH",
+		DestinationAffiliate: "ENG",
+		Amount:               decimal.NewFromInt(100),
+	}
+	ext, err := client.Remittance.NewTokenIAExtension(t.Context(), opt, TokenIAParams{
+		ReceiverFirstName: "Ada",
+		ReceiverIDType:    IDTypePassport,
+		ReceiverIDNumber:  "A12345678",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "EGH", listed.AffiliateCode)
+	assert.Equal(t, "NG", listed.DestinationCountry)
+
+	assert.Equal(t, "ECO1234", ext.RequestID)
+	assert.Equal(t, TOKENIA, ext.RequestType)
+	assert.Equal(t, "GHS", ext.Currency)
+	assert.True(t, decimal.NewFromInt(100).Equal(ext.Amount))
+
+	params := ext.ParamList.(*PaymentParams[TokenIAParams]).Param()
+	assert.Equal(t, "ENG", params.DestinationAffiliate)
+	assert.Equal(t, "NGN", params.DestinationCurrency)
+	assert.True(t, decimal.NewFromInt(100).Equal(params.SourceAmount))
+	assert.Equal(t, "Ada", params.ReceiverFirstName)
+}
+
+func TestRemittanceService_NewMomoIAExtension(t *testing.T) {
+	opt := &CrossBorderOptions{RequestID: "ECO1234", SourceAffiliate: "EGH", DestinationAffiliate: "EKE", Amount: decimal.NewFromInt(50), Currency: "USD"}
+
+	t.Run("supported", func(t *testing.T) {
+		client, _ := newCorridorClient(t, `[{"institutionId": "EKE", "countryCode": "KE"}]`)
+
+		ext, err := client.Remittance.NewMomoIAExtension(t.Context(), opt, MomoIAParams{DestinationCurrency: "USD"})
+		require.NoError(t, err)
+		assert.Equal(t, MOMOIA, ext.RequestType)
+		assert.Equal(t, "USD", ext.Currency)
+
+		params := ext.ParamList.(*PaymentParams[MomoIAParams]).Param()
+		assert.Equal(t, "EKE", params.DestinationAffiliate)
+		assert.Equal(t, "USD", params.DestinationCurrency, "the destination currency is kept if set")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		client, _ := newCorridorClient(t, `[{"institutionId": "ENG", "countryCode": "NG"}]`)
+
+		_, err := client.Remittance.NewMomoIAExtension(t.Context(), opt, MomoIAParams{})
+		assert.ErrorIs(t, err, ErrCorridorNotSupported)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		client, _ := newCorridorClient(t, `[]`)
+
+		_, err := client.Remittance.NewMomoIAExtension(t.Context(), &CrossBorderOptions{SourceAffiliate: "EGH", DestinationAffiliate: "GH"}, MomoIAParams{})
+		assert.ErrorIs(t, err, ErrCorridorNotSupported)
+
+		_, err = client.Remittance.NewMomoIAExtension(t.Context(), opt, MomoIAParams{DestinationAffiliate: "ENG"})
+		assert.ErrorContains(t, err, "does not match")
+	})
+}
+
+func TestAffiliateCurrency(t *testing.T) {
+	currency, ok := AffiliateCurrency("ENG")
+	assert.True(t, ok)
+	assert.Equal(t, "NGN", currency)
+
+	currency, ok = AffiliateCurrency("ci")
+	assert.True(t, ok)
+	assert.Equal(t, "XOF", currency)
+
+	_, ok = AffiliateCurrency("EXX")
+	assert.False(t, ok)
+}
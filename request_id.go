@@ -0,0 +1,36 @@
+package ecobank
+
+import (
+	"context"
+	"crypto/rand"
+)
+
+// RequestIDHeader is the header the request ID of every request is sent in.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the context key under which the request ID of a request is stored.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the given request ID, which is sent in the X-Request-ID header
+// of the requests made with the context, e.g. the ID of the incoming request being served, so that the calls
+// to the API can be correlated with the logs of the application and quoted in gateway support tickets.
+//
+// Requests made with a context without request ID get a generated one. In both cases the ID is returned
+// in Response.RequestID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set on ctx with WithRequestID, or "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestID returns the request ID of ctx, generating a random one if it has none.
+func requestID(ctx context.Context) string {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return id
+	}
+	return rand.Text()
+}
@@ -0,0 +1,61 @@
+package ecobank
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRequestID(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+
+	var ids []string
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			ids = append(ids, req.Header.Get(RequestIDHeader))
+			resp := httptest.NewRecorder()
+			if len(ids) == 1 {
+				resp.WriteHeader(http.StatusServiceUnavailable)
+			}
+			_, err := resp.WriteString(`{"response_code": 0}`)
+			return resp.Result(), err
+		},
+	}
+
+	ctx := WithRequestID(context.Background(), "req-42")
+	assert.Equal(t, "req-42", RequestIDFromContext(ctx))
+
+	_, resp, err := DoRequest[struct{}](ctx, client, http.MethodPost, "merchant/accountbalance", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "req-42", resp.RequestID)
+	assert.Equal(t, []string{"req-42", "req-42"}, ids, "retries share the request ID")
+
+	t.Run("stream", func(t *testing.T) {
+		ids = nil
+		req, err := client.NewRequest(ctx, http.MethodPost, "merchant/accountbalance", nil)
+		require.NoError(t, err)
+
+		resp, err := client.DoStream(req, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "req-42", resp.RequestID)
+		assert.Equal(t, []string{"req-42", "req-42"}, ids)
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		ids = nil
+		_, first, err := DoRequest[struct{}](context.Background(), client, http.MethodPost, "merchant/accountbalance", nil)
+		require.NoError(t, err)
+		_, second, err := DoRequest[struct{}](context.Background(), client, http.MethodPost, "merchant/accountbalance", nil)
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, first.RequestID)
+		assert.NotEqual(t, first.RequestID, second.RequestID)
+		assert.Equal(t, first.RequestID, ids[0])
+		assert.Equal(t, second.RequestID, ids[len(ids)-1])
+		assert.Empty(t, RequestIDFromContext(context.Background()))
+	})
+}
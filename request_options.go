@@ -0,0 +1,24 @@
+package ecobank
+
+import "github.com/hashicorp/go-retryablehttp"
+
+// RequestOptionFunc can be passed to all API requests to customize the API request.
+type RequestOptionFunc func(*retryablehttp.Request) error
+
+// WithHeader sets a header on the request, replacing any value set by the client.
+func WithHeader(key, value string) RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		req.Header.Set(key, value)
+		return nil
+	}
+}
+
+// WithHeaders sets multiple headers on the request, replacing any values set by the client.
+func WithHeaders(headers map[string]string) RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package ecobank
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrSecureHashMissing is returned when a response checked with HashVerificationStrict has no secure hash.
+	ErrSecureHashMissing = errors.New("secure hash missing")
+	// ErrSecureHashMismatch is returned when the secure hash of a response or webhook payload does not match
+	// its content, which means it was tampered with, truncated, or signed with another lab key.
+	ErrSecureHashMismatch = errors.New("secure hash mismatch")
+)
+
+// HashVerification is how strictly the secure hash of responses is verified, set with WithResponseHashVerification.
+type HashVerification int
+
+const (
+	// HashVerificationOff does not verify responses. This is the default.
+	HashVerificationOff HashVerification = iota
+	// HashVerificationLenient verifies the secure hash of responses which have one.
+	HashVerificationLenient
+	// HashVerificationStrict verifies the secure hash of responses and rejects responses without one.
+	HashVerificationStrict
+)
+
+// WithResponseHashVerification verifies the secureHash returned in the response content with VerifyHash,
+// so that tampered or truncated responses are rejected with an error wrapping ErrSecureHashMismatch.
+//
+// With HashVerificationStrict, responses without a secure hash are rejected with ErrSecureHashMissing,
+// so only enable it if all the endpoints used return one. The content of streaming methods such as
+// ForEachBiller is not buffered, so it is not verified.
+func WithResponseHashVerification(mode HashVerification) ClientOptionFunc {
+	return func(c *Client) error {
+		c.hashVerification = mode
+		return nil
+	}
+}
+
+// VerifyHash verifies the secureHash field of a JSON object returned by the API, such as the content of
+// a response or a webhook payload, against the lab key.
+//
+// As for requests, the hash is the SHA-512 of the values of the other fields of the object, in their order
+// in the payload, followed by the lab key. Nested objects and arrays are not part of the hash.
+// It returns an error wrapping ErrSecureHashMissing if the payload has no secure hash, and
+// ErrSecureHashMismatch if the hash does not match.
+func VerifyHash(payload []byte, labKey string) error {
+	source, hash, err := responseHashSource(payload)
+	if err != nil {
+		return fmt.Errorf("verifying secure hash: %w", err)
+	}
+	if hash == "" {
+		return ErrSecureHashMissing
+	}
+
+	want := generateSecureHash(source, labKey)
+	if subtle.ConstantTimeCompare([]byte(want), []byte(strings.ToLower(hash))) != 1 {
+		return ErrSecureHashMismatch
+	}
+	return nil
+}
+
+// responseHashSource returns the concatenated values of the scalar fields of the JSON object,
+// and the value of its secureHash field.
+func responseHashSource(payload []byte) (source, hash string, err error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+
+	var b strings.Builder
+	err = decodeObject(dec, func(key string) error {
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+
+		if strings.EqualFold(key, "secureHash") {
+			hash, _ = value.(string)
+			return nil
+		}
+
+		switch v := value.(type) {
+		case string:
+			b.WriteString(v)
+		case json.Number:
+			b.WriteString(v.String())
+		case bool:
+			fmt.Fprint(&b, v)
+		}
+		return nil
+	})
+
+	return b.String(), hash, err
+}
+
+// verifyResponseHash verifies the secure hash of the response content with the verification mode of the client.
+func (c *Client) verifyResponseHash(content json.RawMessage) error {
+	if c.hashVerification == HashVerificationOff {
+		return nil
+	}
+
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 || content[0] != '{' {
+		// only objects can carry a secure hash
+		if c.hashVerification == HashVerificationStrict {
+			return ErrSecureHashMissing
+		}
+		return nil
+	}
+
+	err := VerifyHash(content, c.labKey)
+	if errors.Is(err, ErrSecureHashMissing) && c.hashVerification == HashVerificationLenient {
+		return nil
+	}
+	return err
+}
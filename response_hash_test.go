@@ -0,0 +1,56 @@
+package ecobank
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyHash(t *testing.T) {
+	hash := generateSecureHash("ECO76383823EGH15.92true", "lab-key")
+	payload := fmt.Sprintf(`{"requestId": "ECO76383823", "affiliateCode": "EGH", "amount": 15.92, "ok": true, "extra": {"ignored": "x"}, "secureHash": %q, "note": null}`, hash)
+
+	assert.NoError(t, VerifyHash([]byte(payload), "lab-key"))
+	assert.ErrorIs(t, VerifyHash([]byte(payload), "other-key"), ErrSecureHashMismatch)
+
+	tampered := fmt.Sprintf(`{"requestId": "ECO76383823", "affiliateCode": "EGH", "amount": 1592, "ok": true, "secureHash": %q}`, hash)
+	assert.ErrorIs(t, VerifyHash([]byte(tampered), "lab-key"), ErrSecureHashMismatch)
+
+	assert.ErrorIs(t, VerifyHash([]byte(`{"requestId": "ECO76383823"}`), "lab-key"), ErrSecureHashMissing)
+	assert.Error(t, VerifyHash([]byte(`{"requestId": `), "lab-key"))
+}
+
+func TestWithResponseHashVerification(t *testing.T) {
+	hash := generateSecureHash("1441000574000"+"15.92", "mock-lab-key")
+	signed := fmt.Sprintf(`{"response_code": 200, "response_content": {"accountNo": "1441000574000", "availableBalance": "15.92", "secureHash": %q}}`, hash)
+	tampered := fmt.Sprintf(`{"response_code": 200, "response_content": {"accountNo": "1441000574000", "availableBalance": "1592", "secureHash": %q}}`, hash)
+	unsigned := `{"response_code": 200, "response_content": {"accountNo": "1441000574000", "availableBalance": "15.92"}}`
+
+	tests := []struct {
+		mode     HashVerification
+		response string
+		wantErr  error
+	}{
+		{HashVerificationOff, tampered, nil},
+		{HashVerificationLenient, signed, nil},
+		{HashVerificationLenient, unsigned, nil},
+		{HashVerificationLenient, tampered, ErrSecureHashMismatch},
+		{HashVerificationStrict, signed, nil},
+		{HashVerificationStrict, unsigned, ErrSecureHashMissing},
+		{HashVerificationStrict, tampered, ErrSecureHashMismatch},
+	}
+	for _, tt := range tests {
+		client := newMockClient(t, tt.response, http.StatusOK)
+		require.NoError(t, WithResponseHashVerification(tt.mode)(client))
+
+		_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		if tt.wantErr == nil {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorIs(t, err, tt.wantErr)
+		}
+	}
+}
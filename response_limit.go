@@ -0,0 +1,98 @@
+package ecobank
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxDrainBytes is how much of the unread rest of a response body is discarded before closing it, so that
+// its connection can be reused. Longer bodies are closed without reading them to the end, which drops the
+// connection rather than downloading a runaway response.
+const maxDrainBytes = 256 << 10
+
+// ErrResponseTooLarge is matched by the ResponseTooLargeError returned when a response body exceeds the
+// limit set with WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ResponseTooLargeError is returned when a response body exceeds the limit set with WithMaxResponseBytes.
+// It matches ErrResponseTooLarge with errors.Is.
+type ResponseTooLargeError struct {
+	// Path is the URL path of the request.
+	Path string
+	// Limit is the maximum number of bytes of response bodies.
+	Limit int64
+}
+
+// Error implements the error interface.
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response of %s exceeds the limit of %d bytes", e.Path, e.Limit)
+}
+
+// Is reports whether target is ErrResponseTooLarge.
+func (e *ResponseTooLargeError) Is(target error) bool {
+	return target == ErrResponseTooLarge
+}
+
+// WithMaxResponseBytes limits the response bodies the client reads to n bytes, protecting it against
+// runaway responses such as huge statements or biller lists. Requests with larger responses fail with
+// a *ResponseTooLargeError, as soon as the Content-Length is known or the limit is read, and their
+// connection is closed instead of being drained. The limit applies to streaming methods such as
+// ForEachBiller too. By default, response bodies are not limited.
+func WithMaxResponseBytes(n int64) ClientOptionFunc {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("max response bytes %d must be positive", n)
+		}
+		c.maxResponseBytes = n
+		return nil
+	}
+}
+
+// limitBody replaces the body of resp with one failing with a ResponseTooLargeError once more than the
+// limit of the client is read. It returns the error right away if the Content-Length exceeds the limit.
+func (c *Client) limitBody(req *http.Request, resp *http.Response) error {
+	if c.maxResponseBytes <= 0 {
+		return nil
+	}
+
+	tooLarge := &ResponseTooLargeError{Path: req.URL.Path, Limit: c.maxResponseBytes}
+	if resp.ContentLength > c.maxResponseBytes {
+		return tooLarge
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: c.maxResponseBytes, err: tooLarge}
+	return nil
+}
+
+// limitedBody is a response body failing with err once more than remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	err       error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, b.err
+	}
+
+	// read one byte past the limit to tell a body of exactly the limit from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		n = int(b.remaining)
+		b.remaining = -1
+		return n, b.err
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// drainBody discards up to maxDrainBytes of the rest of body and closes it.
+func drainBody(body io.ReadCloser) error {
+	_, err := io.Copy(io.Discard, io.LimitReader(body, maxDrainBytes))
+	return errors.Join(err, body.Close())
+}
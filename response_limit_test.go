@@ -0,0 +1,94 @@
+package ecobank
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trackedBody is a response body recording how much of it is read and whether it is closed.
+type trackedBody struct {
+	io.Reader
+	read   int
+	closed bool
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.read += n
+	return n, err
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestWithMaxResponseBytes(t *testing.T) {
+	client := newMockClient(t, driftedBalanceResponse, http.StatusOK)
+	require.NoError(t, WithMaxResponseBytes(int64(len(driftedBalanceResponse)))(client))
+
+	balance, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	require.NoError(t, err, "a response of exactly the limit is read")
+	assert.Equal(t, "TEST USER", balance.AccountName)
+
+	require.NoError(t, WithMaxResponseBytes(int64(len(driftedBalanceResponse)-1))(client))
+	balance, _, err = client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.Nil(t, balance)
+
+	var tooLarge *ResponseTooLargeError
+	require.ErrorAs(t, err, &tooLarge)
+	assert.Equal(t, "/corporateapi/merchant/accountbalance", tooLarge.Path)
+	assert.EqualValues(t, len(driftedBalanceResponse)-1, tooLarge.Limit)
+
+	assert.Error(t, WithMaxResponseBytes(0)(client))
+}
+
+func TestWithMaxResponseBytes_ContentLength(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithMaxResponseBytes(1024)(client))
+
+	body := &trackedBody{Reader: strings.NewReader(strings.Repeat(" ", 4096))}
+	client.client.HTTPClient.Transport = &mockHTTPClient{requestHandler: func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			ContentLength: 4096,
+			Body:          body,
+			Request:       req,
+		}, nil
+	}}
+
+	_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.True(t, body.closed)
+}
+
+func TestWithMaxResponseBytes_Stream(t *testing.T) {
+	response := `{"response_code": 200, "response_message": "success", "response_content": {"billerInfo": [` +
+		strings.Repeat(`{"billerCode": "DSTV", "billerName": "DSTV Ghana"},`, 100) +
+		`{"billerCode": "GOTV", "billerName": "GOTV Ghana"}]}}`
+
+	client := newMockClient(t, response, http.StatusOK)
+	require.NoError(t, WithMaxResponseBytes(1024)(client))
+
+	var billers int
+	_, err := client.Payment.ForEachBiller(t.Context(), &GetBillerListOptions{}, func(*BillerInfo) error {
+		billers++
+		return nil
+	})
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.Less(t, billers, 101)
+}
+
+func TestDrainBody(t *testing.T) {
+	body := &trackedBody{Reader: strings.NewReader(strings.Repeat(" ", 4*maxDrainBytes))}
+	require.NoError(t, drainBody(body))
+	assert.Equal(t, maxDrainBytes, body.read, "runaway bodies are not read to the end")
+	assert.True(t, body.closed)
+}
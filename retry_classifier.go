@@ -0,0 +1,86 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RetryClassifier decides whether a request which failed with an API error is sent again.
+// It is called with the decoded response and the error, which wraps a *ResponseError for errors
+// reported in the response payload.
+type RetryClassifier func(resp *Response, err error) bool
+
+// retryableResponseCodes are the response codes of the payload which report that a request was rejected
+// without being processed. Server errors such as 500 and 504 do not tell whether a payment went through,
+// so they are not retried.
+var retryableResponseCodes = []int{
+	http.StatusTooManyRequests,
+}
+
+// DefaultRetryClassifier retries API errors which report that the request was not processed, either with
+// the 429 response code in the payload or with a message matching ErrTemporaryFailure, such as
+// "host unavailable". Ambiguous failures after which a payment may have been processed, such as timeouts
+// and server errors, are not retried, and neither are errors which will not go away when retried, such as
+// ErrDuplicateRequest, ErrInvalidHash and ErrInsufficientBalance.
+func DefaultRetryClassifier(resp *Response, err error) bool {
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+
+	for _, permanent := range []error{ErrDuplicateRequest, ErrInvalidHash, ErrInsufficientBalance} {
+		if errors.Is(err, permanent) {
+			return false
+		}
+	}
+
+	if errors.Is(err, ErrTemporaryFailure) {
+		return true
+	}
+	return resp != nil && slices.Contains(retryableResponseCodes, resp.Code)
+}
+
+// WithRetryClassifier retries requests which fail with an API error up to maxRetries times if the classifier,
+// such as DefaultRetryClassifier, says so. Unlike the retries of the retryable client, which only depend on
+// the HTTP status, the classifier is consulted after the response payload is decoded, so it can retry the
+// errors which the gateway reports with a 200 status. The waits between retries use the backoff of the client.
+//
+// Payments are retried too. Only classify errors as retryable if the API guarantees the request was not processed.
+func WithRetryClassifier(classifier RetryClassifier, maxRetries int) ClientOptionFunc {
+	return func(c *Client) error {
+		if maxRetries < 0 {
+			return errors.New("max retries must not be negative")
+		}
+		c.retryClassifier, c.retryClassifierMax = classifier, maxRetries
+		return nil
+	}
+}
+
+// retryResponse reports whether the request which failed with err after the given number of retries is sent again.
+func (c *Client) retryResponse(attempt int, resp *Response, err error) bool {
+	if c.retryClassifier == nil || c.disableRetries || attempt >= c.retryClassifierMax || resp == nil {
+		return false
+	}
+	return c.retryClassifier(resp, err)
+}
+
+// waitRetry waits for the backoff of the client before the next attempt, or until ctx is done.
+func (c *Client) waitRetry(ctx context.Context, attempt int, resp *Response) error {
+	var wait time.Duration
+	if c.client.Backoff != nil {
+		wait = c.client.Backoff(c.client.RetryWaitMin, c.client.RetryWaitMax, attempt, resp.Response)
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
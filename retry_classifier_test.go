@@ -0,0 +1,97 @@
+package ecobank
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRetryClassifier(t *testing.T) {
+	testCases := []struct {
+		name   string
+		code   int
+		errors []string
+		want   bool
+	}{
+		{name: "temporary failure message", code: 400, errors: []string{"Temporary failure at host, try again later"}, want: true},
+		{name: "too many requests code", code: 429, errors: []string{"error"}, want: true},
+		{name: "server error code", code: 503, errors: []string{"error"}},
+		{name: "gateway timeout code", code: 504, errors: []string{"error"}},
+		{name: "timeout", code: 400, errors: []string{"Request timed out"}},
+		{name: "duplicate request", code: 503, errors: []string{"Duplicate request"}},
+		{name: "invalid hash", code: 500, errors: []string{"Invalid secure hash"}},
+		{name: "validation error", code: 400, errors: []string{"accountNo is required"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			respErr := ResponseError(tc.errors)
+			assert.Equal(t, tc.want, DefaultRetryClassifier(&Response{Code: tc.code}, &respErr))
+		})
+	}
+
+	assert.False(t, DefaultRetryClassifier(&Response{Code: 503}, errors.New("connection reset")), "only API errors are classified")
+}
+
+func TestWithRetryClassifier(t *testing.T) {
+	newClient := func(t *testing.T, responses ...string) (*Client, *int) {
+		client := newMockClient(t, "", http.StatusOK)
+		client.client.RetryWaitMin, client.client.RetryWaitMax = time.Millisecond, time.Millisecond
+		require.NoError(t, WithRetryClassifier(DefaultRetryClassifier, 2)(client))
+
+		calls := 0
+		client.client.HTTPClient.Transport = &mockHTTPClient{
+			requestHandler: func(req *http.Request) (*http.Response, error) {
+				body := responses[min(calls, len(responses)-1)]
+				calls++
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(body)),
+				}, nil
+			},
+		}
+		return client, &calls
+	}
+
+	const (
+		temporary = `{"response_code": 503, "response_message": "error", "errors": ["Host unavailable"]}`
+		success   = `{"response_code": 200, "response_message": "success", "response_content": {"responseCode": "000"}}`
+	)
+
+	t.Run("retried until success", func(t *testing.T) {
+		client, calls := newClient(t, temporary, success)
+		_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("max retries", func(t *testing.T) {
+		client, calls := newClient(t, temporary)
+		_, resp, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		assert.ErrorIs(t, err, ErrTemporaryFailure)
+		assert.Equal(t, 503, resp.Code)
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("permanent errors", func(t *testing.T) {
+		client, calls := newClient(t, `{"response_code": 503, "response_message": "error", "errors": ["Duplicate request"]}`)
+		_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		assert.ErrorIs(t, err, ErrDuplicateRequest)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("retries disabled", func(t *testing.T) {
+		client, calls := newClient(t, temporary)
+		require.NoError(t, WithDisableRetries()(client))
+		_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		assert.Error(t, err)
+		assert.Equal(t, 1, *calls)
+	})
+}
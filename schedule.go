@@ -0,0 +1,96 @@
+package ecobank
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/profclems/go-ecobank/calendar"
+)
+
+var (
+	// ErrExecutionDateInPast is returned when the execution date of a payment is before the current day.
+	ErrExecutionDateInPast = errors.New("execution date is in the past")
+	// ErrNotBusinessDay is returned when a payment is scheduled for a day the affiliate is closed.
+	ErrNotBusinessDay = errors.New("execution date is not a business day")
+	// ErrNoBusinessDay is returned when the calendar of an affiliate has no business day within a year.
+	ErrNoBusinessDay = errors.New("no business day within a year")
+)
+
+// maxBusinessDaySearch is how many days are searched for a business day, so that a calendar without
+// business days does not loop forever.
+const maxBusinessDaySearch = 366
+
+// BusinessCalendar tells the days on which an affiliate processes scheduled payments.
+type BusinessCalendar interface {
+	// IsBusinessDay reports whether t falls on a business day of the affiliate.
+	IsBusinessDay(affiliateCode string, t time.Time) bool
+}
+
+// WeekdayCalendar is a BusinessCalendar on which every affiliate does business from Monday to Friday.
+// Unlike the default calendar.Calendar, it does not know about public holidays.
+type WeekdayCalendar struct{}
+
+// IsBusinessDay implements BusinessCalendar.
+func (WeekdayCalendar) IsBusinessDay(_ string, t time.Time) bool {
+	wd := t.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// businessCalendar returns the calendar of the client.
+func (c *Client) businessCalendar() BusinessCalendar {
+	if c.calendar == nil {
+		return calendar.Calendar{}
+	}
+	return c.calendar
+}
+
+// NextBusinessDay returns the start of the first business day of the affiliate after the current day,
+// to schedule a payment for. The current day is taken from the client's clock. It fails with
+// ErrNoBusinessDay if there is none within a year.
+func (p *PaymentService) NextBusinessDay(affiliateCode string) (time.Time, error) {
+	return nextBusinessDay(p.client.businessCalendar(), affiliateCode, startOfDay(p.client.now()).AddDate(0, 0, 1))
+}
+
+// nextBusinessDay returns t if it falls on a business day of the affiliate, else the start of the first
+// business day after it, searching up to maxBusinessDaySearch days.
+func nextBusinessDay(cal BusinessCalendar, affiliateCode string, t time.Time) (time.Time, error) {
+	for range maxBusinessDaySearch {
+		if cal.IsBusinessDay(affiliateCode, t) {
+			return t, nil
+		}
+		t = startOfDay(t).AddDate(0, 0, 1)
+	}
+	return time.Time{}, fmt.Errorf("%w for affiliate %s", ErrNoBusinessDay, affiliateCode)
+}
+
+// ValidateExecutionDate checks the execution date of a payment before it is submitted.
+//
+// Payments can be executed immediately by leaving the execution date unset or setting it to the current day.
+// A date before the current day is rejected with ErrExecutionDateInPast, and a future date which is not a
+// business day of the affiliate is rejected with ErrNotBusinessDay.
+func (p *PaymentService) ValidateExecutionDate(opt *PaymentOptions) error {
+	date := opt.PaymentHeader.ExecutionDate.GetTime()
+	if date.IsZero() {
+		return nil
+	}
+
+	today := startOfDay(p.client.now().In(date.Location()))
+	switch {
+	case date.Before(today):
+		return fmt.Errorf("%w: %s", ErrExecutionDateInPast, date.Format(time.DateOnly))
+	case date.Before(today.AddDate(0, 0, 1)):
+		// payments for the current day are executed immediately
+		return nil
+	case !p.client.businessCalendar().IsBusinessDay(opt.PaymentHeader.AffiliateCode, date):
+		return fmt.Errorf("%w: %s for affiliate %s", ErrNotBusinessDay, date.Format(time.DateOnly), opt.PaymentHeader.AffiliateCode)
+	}
+
+	return nil
+}
+
+// startOfDay returns midnight of the day of t in its location.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,87 @@
+package ecobank
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// holidayCalendar is a WeekdayCalendar with extra holidays.
+type holidayCalendar map[string]bool
+
+func (h holidayCalendar) IsBusinessDay(affiliateCode string, t time.Time) bool {
+	return WeekdayCalendar{}.IsBusinessDay(affiliateCode, t) && !h[affiliateCode+t.Format(time.DateOnly)]
+}
+
+func assertNextBusinessDay(t *testing.T, client *Client, affiliateCode string, want time.Time) {
+	t.Helper()
+	day, err := client.Payment.NextBusinessDay(affiliateCode)
+	require.NoError(t, err)
+	assert.Equal(t, want, day)
+}
+
+// closedCalendar is a calendar without business days.
+type closedCalendar struct{}
+
+func (closedCalendar) IsBusinessDay(string, time.Time) bool { return false }
+
+func TestPaymentService_NextBusinessDay(t *testing.T) {
+	// Wednesday, the day before Independence Day in Ghana
+	now := time.Date(2025, 3, 5, 15, 30, 0, 0, time.UTC)
+
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithClock(&fixedClock{now: now})(client))
+
+	// the default calendar knows about Independence Day in Ghana but not in Nigeria
+	assertNextBusinessDay(t, client, "EGH", time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC))
+	assertNextBusinessDay(t, client, "ENG", time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, WithBusinessCalendar(holidayCalendar{"EGH2025-03-06": true, "EGH2025-03-07": true})(client))
+	assertNextBusinessDay(t, client, "EGH", time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+	assertNextBusinessDay(t, client, "ENG", time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, WithBusinessCalendar(closedCalendar{})(client))
+	_, err := client.Payment.NextBusinessDay("EGH")
+	assert.ErrorIs(t, err, ErrNoBusinessDay)
+}
+
+func TestPaymentService_ValidateExecutionDate(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 3, 5, 15, 30, 0, 0, time.UTC)
+
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithClock(&fixedClock{now: now})(client))
+	require.NoError(t, WithBusinessCalendar(holidayCalendar{"EGH2025-03-06": true})(client))
+
+	testCases := []struct {
+		name    string
+		date    time.Time
+		wantErr error
+	}{
+		{name: "unset"},
+		{name: "earlier today", date: now.Add(-time.Hour)},
+		{name: "yesterday", date: now.AddDate(0, 0, -1), wantErr: ErrExecutionDateInPast},
+		{name: "holiday", date: time.Date(2025, 3, 6, 9, 0, 0, 0, time.UTC), wantErr: ErrNotBusinessDay},
+		{name: "weekend", date: time.Date(2025, 3, 8, 9, 0, 0, 0, time.UTC), wantErr: ErrNotBusinessDay},
+		{name: "business day", date: time.Date(2025, 3, 7, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := &PaymentOptions{PaymentHeader: PaymentHeader{AffiliateCode: "EGH"}}
+			if !tc.date.IsZero() {
+				opt.PaymentHeader.ExecutionDate = NewTime(tc.date)
+			}
+
+			err := client.Payment.ValidateExecutionDate(opt)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
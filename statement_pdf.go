@@ -0,0 +1,189 @@
+package ecobank
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The API does not expose a formatted statement, so statements are rendered locally
+// as a plain A4 PDF using the standard Courier font, which needs no embedding.
+const (
+	pdfPageWidth    = 595
+	pdfPageHeight   = 842
+	pdfMargin       = 40
+	pdfFontSize     = 9
+	pdfLeading      = 12
+	pdfLineWidth    = 95 // characters of Courier at pdfFontSize that fit between the margins
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+
+	// pdfMinNarrativeWidth is the narrower width narratives are wrapped to when long row values
+	// push the narrative column past the line width. Such rows overflow the margin.
+	pdfMinNarrativeWidth = 20
+)
+
+// StatementPDFOptions specifies the details printed in the header of a statement rendered by WriteStatementPDF.
+type StatementPDFOptions struct {
+	// Title defaults to "Account Statement".
+	Title         string
+	AccountName   string
+	AccountNumber string
+	StartDate     Date
+	EndDate       Date
+}
+
+// WriteStatementPDF renders the statement transactions returned by AccountService.GenerateStatement
+// as a PDF document and writes it to w, so statements can be emailed or archived.
+func WriteStatementPDF(w io.Writer, txns []*StatementTransaction, opt *StatementPDFOptions) error {
+	if opt == nil {
+		opt = &StatementPDFOptions{}
+	}
+
+	lines := statementLines(txns, opt)
+
+	var pages [][]string
+	for len(lines) > pdfLinesPerPage {
+		pages = append(pages, lines[:pdfLinesPerPage])
+		lines = lines[pdfLinesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	_, err := w.Write(renderPDF(pages))
+	return err
+}
+
+// statementLines lays out the statement as fixed width text lines.
+func statementLines(txns []*StatementTransaction, opt *StatementPDFOptions) []string {
+	title := opt.Title
+	if title == "" {
+		title = "Account Statement"
+	}
+
+	lines := []string{title, ""}
+	if opt.AccountName != "" {
+		lines = append(lines, "Account Name:   "+opt.AccountName)
+	}
+	if opt.AccountNumber != "" {
+		lines = append(lines, "Account Number: "+opt.AccountNumber)
+	}
+	if !opt.StartDate.GetTime().IsZero() || !opt.EndDate.GetTime().IsZero() {
+		lines = append(lines, fmt.Sprintf("Period:         %s - %s",
+			opt.StartDate.GetTime().Format("02 Jan 2006"), opt.EndDate.GetTime().Format("02 Jan 2006")))
+	}
+
+	row := "%-10s  %-18s  %-5s  %12s  %12s  %s"
+	lines = append(lines, "",
+		fmt.Sprintf(row, "Date", "Reference", "Dr/Cr", "Paid In", "Paid Out", "Narrative"),
+		strings.Repeat("-", pdfLineWidth),
+	)
+
+	for _, txn := range txns {
+		if txn == nil {
+			continue
+		}
+		line := fmt.Sprintf(row,
+			txn.ValueDate.GetTime().Format("2006-01-02"),
+			txn.RefNumber,
+			txn.DebitCredit,
+			txn.PaidIn,
+			txn.PaidOut,
+			"",
+		)
+		// long narratives are wrapped onto continuation lines under the narrative column
+		indent := len(line)
+		narrative := wrapText(txn.Narrative, max(pdfLineWidth-indent, pdfMinNarrativeWidth))
+		lines = append(lines, line+narrative[0])
+		for _, n := range narrative[1:] {
+			lines = append(lines, strings.Repeat(" ", indent)+n)
+		}
+	}
+
+	return lines
+}
+
+// wrapText splits s into lines of at most width characters, breaking at spaces where possible.
+// s is returned unwrapped if width is not positive.
+func wrapText(s string, width int) []string {
+	if width <= 0 {
+		return []string{s}
+	}
+
+	var lines []string
+	for len(s) > width {
+		i := strings.LastIndexByte(s[:width+1], ' ')
+		if i <= 0 {
+			i = width
+		}
+		lines = append(lines, strings.TrimRight(s[:i], " "))
+		s = strings.TrimLeft(s[i:], " ")
+	}
+	return append(lines, s)
+}
+
+// renderPDF builds a PDF document with one page per entry of pages.
+func renderPDF(pages [][]string) []byte {
+	var (
+		buf     bytes.Buffer
+		offsets []int
+	)
+
+	writeObj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// objects 1-3 are the catalog, the page tree and the font,
+	// followed by a page and a content stream object for every page.
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+
+	writeObj("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>")
+
+	for i, lines := range pages {
+		writeObj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 5+2*i))
+
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range lines {
+			fmt.Fprintf(&content, "(%s) '\n", escapePDFString(line))
+		}
+		content.WriteString("ET")
+
+		writeObj(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// escapePDFString escapes s for use in a PDF literal string.
+// Characters outside printable ASCII are not supported by the standard fonts and are replaced with '?'.
+func escapePDFString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
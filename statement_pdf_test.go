@@ -0,0 +1,91 @@
+package ecobank
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteStatementPDF(t *testing.T) {
+	txns := []*StatementTransaction{
+		{
+			AccCurrency: "GHS",
+			DebitCredit: "CR",
+			RefNumber:   "H75ZEXA1923800E0",
+			PaidIn:      "10",
+			ValueDate:   NewTime(time.Date(2019, 9, 2, 20, 0, 0, 0, time.UTC)),
+			Amount:      "10",
+			Narrative:   "MOBILE TRANSFER (BD1441000820520) SA Xpress Account DT0209",
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WriteStatementPDF(&buf, txns, &StatementPDFOptions{
+		AccountName:   "TEST USER",
+		AccountNumber: "1441000574000",
+		StartDate:     NewDate(time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:       NewDate(time.Date(2019, 9, 30, 0, 0, 0, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(out, "%%EOF\n"))
+	assert.Contains(t, out, "/Count 1")
+	assert.Contains(t, out, "(Account Statement) '")
+	assert.Contains(t, out, "Account Number: 1441000574000")
+	assert.Contains(t, out, "Period:         01 Sep 2019 - 30 Sep 2019")
+	assert.Contains(t, out, "(2019-09-02  H75ZEXA1923800E0    CR               10                MOBILE TRANSFER) '")
+	// long narratives wrap onto the next lines and parentheses are escaped
+	assert.Contains(t, out, `(`+strings.Repeat(" ", 67)+`\(BD1441000820520\) SA Xpress) '`)
+	assert.Contains(t, out, `(`+strings.Repeat(" ", 67)+`Account DT0209) '`)
+
+	// the startxref offset must point at the xref table
+	var xref int
+	_, err = fmt.Sscanf(out[strings.LastIndex(out, "startxref\n"):], "startxref\n%d", &xref)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out[xref:], "xref\n"))
+}
+
+func TestWriteStatementPDF_Pages(t *testing.T) {
+	txns := make([]*StatementTransaction, 2*pdfLinesPerPage)
+	for i := range txns {
+		txns[i] = &StatementTransaction{RefNumber: fmt.Sprintf("REF%d", i)}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteStatementPDF(&buf, txns, nil))
+
+	out := buf.String()
+	assert.Contains(t, out, "/Count 3")
+	assert.Equal(t, 3, strings.Count(out, "/Type /Page "))
+}
+
+func TestWriteStatementPDF_LongReference(t *testing.T) {
+	txns := []*StatementTransaction{{
+		RefNumber: strings.Repeat("R", 60),
+		Narrative: "MOBILE TRANSFER (BD1441000820520) SA Xpress Account DT0209",
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteStatementPDF(&buf, txns, nil))
+	assert.Contains(t, buf.String(), "MOBILE TRANSFER) '")
+	assert.Contains(t, buf.String(), `\(BD1441000820520\) SA) '`)
+}
+
+func TestWrapText(t *testing.T) {
+	assert.Equal(t, []string{"MOBILE", "TRANSFER", "DT0209"}, wrapText("MOBILE TRANSFER DT0209", 8))
+	assert.Equal(t, []string{"ABCDE", "FGH"}, wrapText("ABCDEFGH", 5))
+	assert.Equal(t, []string{""}, wrapText("", 5))
+	assert.Equal(t, []string{"MOBILE TRANSFER"}, wrapText("MOBILE TRANSFER", 0))
+	assert.Equal(t, []string{"MOBILE TRANSFER"}, wrapText("MOBILE TRANSFER", -13))
+}
+
+func TestEscapePDFString(t *testing.T) {
+	assert.Equal(t, `a\(b\)c\\d?`, escapePDFString(`a(b)c\dé`))
+}
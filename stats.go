@@ -0,0 +1,267 @@
+package ecobank
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultStatsWindows are the windows the stats of the client are summarized over if WithStats is given none.
+var defaultStatsWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+// OperationStats summarizes the requests of an API operation over a sliding window.
+type OperationStats struct {
+	// Operation is the client service and method name of the operation, e.g. Payment.Pay,
+	// or the path of the endpoint for requests sent with Client.Call.
+	Operation string
+	// Window is the duration the requests are summarized over, up to now.
+	Window time.Duration
+	// Requests is the number of requests in the window, and Errors the number of them which failed.
+	Requests int
+	Errors   int
+	// SuccessRate is the ratio of the requests which succeeded, from 0 to 1.
+	SuccessRate float64
+	// P50, P95 and P99 are percentiles of the latency of the requests, including their retries. They are
+	// read from a histogram, so they may be up to 12.5% higher than the exact percentiles.
+	P50, P95, P99 time.Duration
+}
+
+// WithStats collects the success rate and the latency of the requests of every API operation, which are
+// summarized over the sliding windows by Client.Stats, e.g. to alert on the degradation of the API without
+// an external APM. The windows default to 1, 5 and 15 minutes.
+//
+// A request fails if it returns an error, including the errors returned by the API.
+func WithStats(windows ...time.Duration) ClientOptionFunc {
+	return func(c *Client) error {
+		if len(windows) == 0 {
+			windows = defaultStatsWindows
+		}
+		for _, window := range windows {
+			if window <= 0 {
+				return fmt.Errorf("stats window must be positive, got %s", window)
+			}
+		}
+
+		windows = slices.Clone(windows)
+		slices.Sort(windows)
+		c.stats = newStatsCollector(slices.Compact(windows))
+		return nil
+	}
+}
+
+// Stats returns the stats of the API operations used over the windows set with WithStats, sorted by operation
+// and window. Windows without requests are omitted. It returns nil if the client does not collect stats.
+func (c *Client) Stats() []OperationStats {
+	if c.stats == nil {
+		return nil
+	}
+	return c.stats.summarize(c.now())
+}
+
+// statsCollector keeps the counts and latency histograms of the requests of every operation in time slots,
+// for the longest window. Its memory is bounded by the number of slots of the longest window, however many
+// requests are recorded.
+type statsCollector struct {
+	windows []time.Duration
+	// slot is the duration of the time slots. Windows are summarized from whole slots, so they may include
+	// up to a slot of older requests.
+	slot time.Duration
+
+	// operationsByPath names the operations by their path. It is built on the first request, once the
+	// endpoints of the client are configured.
+	operationsOnce   sync.Once
+	operationsByPath map[string]string
+
+	mu         sync.Mutex
+	operations map[string][]*statsSlot
+}
+
+// newStatsCollector returns a collector for the given sorted windows. The slots are a tenth of the shortest
+// window, but no shorter than the longest window divided by maxStatsSlots.
+func newStatsCollector(windows []time.Duration) *statsCollector {
+	slot := max(windows[0]/10, windows[len(windows)-1]/maxStatsSlots, time.Millisecond)
+	return &statsCollector{
+		windows:    windows,
+		slot:       slot,
+		operations: make(map[string][]*statsSlot),
+	}
+}
+
+// maxStatsSlots is the maximum number of time slots kept per operation.
+const maxStatsSlots = 500
+
+// statsSlot counts the requests of an operation in a time slot.
+type statsSlot struct {
+	start    time.Time
+	requests int
+	errors   int
+	// maxLatency is the highest latency of the slot, which bounds the percentiles read from the histogram.
+	maxLatency time.Duration
+	latencies  [latencyBins]uint32
+}
+
+// record adds a request to the operation, and drops the slots of the operation older than the longest window.
+func (s *statsCollector) record(operation string, at time.Time, latency time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slots := s.prune(s.operations[operation], at)
+	start := at.Truncate(s.slot)
+	// requests recorded out of order, e.g. after a clock adjustment, are added to the latest slot
+	if len(slots) == 0 || slots[len(slots)-1].start.Before(start) {
+		slots = append(slots, &statsSlot{start: start})
+	}
+	s.operations[operation] = slots
+
+	slot := slots[len(slots)-1]
+	slot.requests++
+	if failed {
+		slot.errors++
+	}
+	slot.maxLatency = max(slot.maxLatency, latency)
+	slot.latencies[latencyBin(latency)]++
+}
+
+// prune drops the slots which ended before the longest window. Slots are kept in order.
+func (s *statsCollector) prune(slots []*statsSlot, now time.Time) []*statsSlot {
+	i := s.firstSlot(slots, now.Add(-s.windows[len(s.windows)-1]))
+	return slices.Delete(slots, 0, i)
+}
+
+// firstSlot returns the index of the first slot which ends after since.
+func (s *statsCollector) firstSlot(slots []*statsSlot, since time.Time) int {
+	i, _ := slices.BinarySearchFunc(slots, since, func(slot *statsSlot, t time.Time) int {
+		if slot.start.Add(s.slot).After(t) {
+			return 1
+		}
+		return -1
+	})
+	return i
+}
+
+func (s *statsCollector) summarize(now time.Time) []OperationStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stats []OperationStats
+	for operation, slots := range s.operations {
+		slots = s.prune(slots, now)
+		if len(slots) == 0 {
+			delete(s.operations, operation)
+			continue
+		}
+		s.operations[operation] = slots
+
+		for _, window := range s.windows {
+			if i := s.firstSlot(slots, now.Add(-window)); i < len(slots) {
+				stats = append(stats, summarizeSlots(operation, window, slots[i:]))
+			}
+		}
+	}
+
+	slices.SortFunc(stats, func(a, b OperationStats) int {
+		if c := strings.Compare(a.Operation, b.Operation); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Window, b.Window)
+	})
+	return stats
+}
+
+func summarizeSlots(operation string, window time.Duration, slots []*statsSlot) OperationStats {
+	stats := OperationStats{Operation: operation, Window: window}
+
+	var latencies [latencyBins]uint32
+	var maxLatency time.Duration
+	for _, slot := range slots {
+		stats.Requests += slot.requests
+		stats.Errors += slot.errors
+		maxLatency = max(maxLatency, slot.maxLatency)
+		for i, n := range slot.latencies {
+			latencies[i] += n
+		}
+	}
+
+	stats.SuccessRate = float64(stats.Requests-stats.Errors) / float64(stats.Requests)
+	stats.P50 = percentile(&latencies, stats.Requests, 0.50, maxLatency)
+	stats.P95 = percentile(&latencies, stats.Requests, 0.95, maxLatency)
+	stats.P99 = percentile(&latencies, stats.Requests, 0.99, maxLatency)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile p of the n latencies of the histogram, as the upper bound
+// of its bin, which is at most 12.5% higher than the latency, capped to the highest latency.
+func percentile(latencies *[latencyBins]uint32, n int, p float64, maxLatency time.Duration) time.Duration {
+	rank := max(int(math.Ceil(p*float64(n))), 1)
+
+	var count int
+	for i, c := range latencies {
+		count += int(c)
+		if count >= rank {
+			return min(latencyBinUpperBound(i), maxLatency)
+		}
+	}
+	return maxLatency
+}
+
+// Latencies are counted in a log-linear histogram of microseconds: every power of two is split into
+// latencySubBins bins of equal width, up to 2^maxLatencyExp microseconds, about 71 minutes.
+const (
+	latencySubBinBits = 3
+	latencySubBins    = 1 << latencySubBinBits
+	maxLatencyExp     = 32
+	latencyBins       = (maxLatencyExp - latencySubBinBits + 1) * latencySubBins
+)
+
+// latencyBin returns the bin of the histogram counting the latency d.
+func latencyBin(d time.Duration) int {
+	us := uint64(max(d.Microseconds(), 0))
+	if us < latencySubBins {
+		return int(us)
+	}
+
+	exp := bits.Len64(us) - 1
+	if exp >= maxLatencyExp {
+		return latencyBins - 1
+	}
+	sub := (us >> (exp - latencySubBinBits)) & (latencySubBins - 1)
+	return (exp-latencySubBinBits+1)*latencySubBins + int(sub)
+}
+
+// latencyBinUpperBound returns the exclusive upper bound of the latencies counted in bin i.
+func latencyBinUpperBound(i int) time.Duration {
+	if i < latencySubBins {
+		return time.Duration(i+1) * time.Microsecond
+	}
+
+	exp := i/latencySubBins + latencySubBinBits - 1
+	sub := i % latencySubBins
+	return time.Duration((latencySubBins+sub+1)<<(exp-latencySubBinBits)) * time.Microsecond
+}
+
+// recordStats records a request to the path, relative to the base URL, if the client collects stats.
+func (c *Client) recordStats(path string, start time.Time, err error) {
+	if c.stats == nil {
+		return
+	}
+
+	c.stats.operationsOnce.Do(func() {
+		c.stats.operationsByPath = c.endpointsByPath()
+	})
+	operation, ok := c.stats.operationsByPath[path]
+	if !ok {
+		operation = path
+	}
+	c.stats.record(operation, c.now(), time.Since(start), err != nil)
+}
+
+// relativePath returns the path of the request URL relative to the base URL.
+func (c *Client) relativePath(urlPath string) string {
+	path, _ := strings.CutPrefix(urlPath, c.baseURL.Path)
+	return path
+}
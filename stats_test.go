@@ -0,0 +1,129 @@
+package ecobank
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStats(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2025, 3, 16, 12, 0, 0, 0, time.UTC)}
+
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithClock(clock)(client))
+	assert.Nil(t, client.Stats(), "stats are not collected by default")
+	require.NoError(t, WithStats(time.Minute, 5*time.Minute)(client))
+
+	var fail bool
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			resp := httptest.NewRecorder()
+			body := `{"response_code": 200, "response_content": {}}`
+			if fail {
+				body = `{"response_code": 400, "response_message": "invalid request", "errors": ["invalid request"]}`
+			}
+			_, err := resp.WriteString(body)
+			return resp.Result(), err
+		},
+	}
+
+	_, _, err := client.Status.GetTransactionStatus(t.Context(), &StatusOptions{RequestID: "1"})
+	require.NoError(t, err)
+
+	clock.now = clock.now.Add(3 * time.Minute)
+	fail = true
+	_, _, err = client.Status.GetTransactionStatus(t.Context(), &StatusOptions{RequestID: "2"})
+	require.Error(t, err)
+	_, err = client.Call(t.Context(), http.MethodPost, "merchant/loanenquiry", &struct{ SecureHashOptions }{}, nil)
+	require.Error(t, err)
+
+	stats := client.Stats()
+	require.Len(t, stats, 4)
+
+	assert.Equal(t, "Status.GetTransactionStatus", stats[0].Operation)
+	assert.Equal(t, time.Minute, stats[0].Window)
+	assert.Equal(t, 1, stats[0].Requests)
+	assert.Equal(t, 1, stats[0].Errors)
+	assert.Zero(t, stats[0].SuccessRate)
+
+	assert.Equal(t, "Status.GetTransactionStatus", stats[1].Operation)
+	assert.Equal(t, 5*time.Minute, stats[1].Window)
+	assert.Equal(t, 2, stats[1].Requests)
+	assert.Equal(t, 1, stats[1].Errors)
+	assert.InDelta(t, 0.5, stats[1].SuccessRate, 1e-9)
+	assert.Positive(t, stats[1].P99)
+
+	assert.Equal(t, "merchant/loanenquiry", stats[2].Operation, "operations the library does not model are named by path")
+	assert.Equal(t, time.Minute, stats[2].Window)
+	assert.Equal(t, 5*time.Minute, stats[3].Window)
+
+	t.Run("stream", func(t *testing.T) {
+		fail = false
+		_, err := client.Payment.ForEachBiller(t.Context(), &GetBillerListOptions{}, func(*BillerInfo) error { return nil })
+		require.NoError(t, err)
+
+		stats := client.Stats()
+		require.Len(t, stats, 6)
+		assert.Equal(t, "Payment.GetBillerList", stats[0].Operation, "streamed responses are recorded as their operation")
+		assert.Equal(t, 1, stats[0].Requests)
+		assert.Zero(t, stats[0].Errors)
+	})
+
+	clock.now = clock.now.Add(10 * time.Minute)
+	assert.Empty(t, client.Stats(), "requests older than the longest window are dropped")
+
+	_, err = NewClient("", "", "", WithStats(0))
+	assert.EqualError(t, err, "stats window must be positive, got 0s")
+}
+
+func TestStatsCollector_Percentiles(t *testing.T) {
+	now := time.Date(2025, 3, 16, 12, 0, 0, 0, time.UTC)
+	s := newStatsCollector([]time.Duration{time.Minute})
+	for i := 1; i <= 100; i++ {
+		s.record("Payment.Pay", now, time.Duration(i)*time.Millisecond, i%10 == 0)
+	}
+
+	stats := s.summarize(now)
+	require.Len(t, stats, 1)
+	assert.Equal(t, 100, stats[0].Requests)
+	assert.Equal(t, 10, stats[0].Errors)
+	assert.InDelta(t, 0.9, stats[0].SuccessRate, 1e-9)
+	// percentiles are read from a histogram, so they are up to 12.5% higher
+	assert.InEpsilon(t, 50*time.Millisecond, stats[0].P50, 0.125)
+	assert.InEpsilon(t, 95*time.Millisecond, stats[0].P95, 0.125)
+	assert.InEpsilon(t, 99*time.Millisecond, stats[0].P99, 0.125)
+	assert.GreaterOrEqual(t, stats[0].P99, 99*time.Millisecond)
+	assert.LessOrEqual(t, stats[0].P99, 100*time.Millisecond, "percentiles are capped to the highest latency")
+}
+
+func TestStatsCollector_Bounded(t *testing.T) {
+	now := time.Date(2025, 3, 16, 12, 0, 0, 0, time.UTC)
+	s := newStatsCollector([]time.Duration{time.Minute, 15 * time.Minute})
+	for i := range 100_000 {
+		s.record("Payment.Pay", now.Add(time.Duration(i)*100*time.Millisecond), time.Millisecond, false)
+	}
+
+	// a slot of 6s, a tenth of the shortest window, for the 15 minutes of the longest window
+	assert.LessOrEqual(t, len(s.operations["Payment.Pay"]), 151)
+
+	stats := s.summarize(now.Add(100_000 * 100 * time.Millisecond))
+	require.Len(t, stats, 2)
+	assert.InDelta(t, 600, stats[0].Requests, 60)
+	assert.InDelta(t, 9000, stats[1].Requests, 60)
+}
+
+func TestLatencyBin(t *testing.T) {
+	for _, d := range []time.Duration{0, time.Microsecond, 7 * time.Microsecond, 8 * time.Microsecond, 50 * time.Millisecond, 3 * time.Second, time.Hour} {
+		i := latencyBin(d)
+		assert.Less(t, d, latencyBinUpperBound(i), d)
+		if i > 0 {
+			assert.GreaterOrEqual(t, d, latencyBinUpperBound(i-1), d)
+		}
+	}
+	assert.Equal(t, latencyBins-1, latencyBin(100*time.Hour))
+	assert.Zero(t, latencyBin(-time.Second))
+}
@@ -3,6 +3,7 @@ package ecobank
 import (
 	"context"
 	"net/http"
+	"strings"
 )
 
 // StatusService handles communication with the status related methods of the Ecobank API.
@@ -16,14 +17,42 @@ type StatusService struct {
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#758a9aef-edc6-45de-8ab0-1631c80936a1
 type TransactionStatus struct {
-	RequestType      string `json:"requestType"`
-	AffiliateCode    string `json:"affiliateCode"`
-	Amount           int    `json:"amount"`
-	Currency         string `json:"currency"`
-	Status           string `json:"status"`
-	StatusCode       string `json:"statusCode"`
-	StatusReason     string `json:"statusReason"`
-	TransactionRefNo string `json:"transactionRefNo"`
+	RequestType      string          `json:"requestType"`
+	AffiliateCode    string          `json:"affiliateCode"`
+	Amount           FlexibleDecimal `json:"amount"`
+	Currency         string          `json:"currency"`
+	Status           Status          `json:"status"`
+	StatusCode       string          `json:"statusCode"`
+	StatusReason     string          `json:"statusReason"`
+	TransactionRefNo string          `json:"transactionRefNo"`
+}
+
+// Status is the status of a transaction, as reported by the API.
+type Status string
+
+const (
+	StatusPending    Status = "PENDING"
+	StatusSuccessful Status = "SUCCESSFUL"
+	StatusFailed     Status = "FAILED"
+	StatusReversed   Status = "REVERSED"
+)
+
+// IsTerminal reports whether the status will no longer change, which is only the case of successful,
+// failed and reversed transactions. Unknown statuses are not terminal, so that transactions are polled
+// until they reach a known final status.
+func (s Status) IsTerminal() bool {
+	switch strings.ToUpper(string(s)) {
+	case string(StatusSuccessful), "SUCCESS", string(StatusFailed), string(StatusReversed):
+		return true
+	default:
+		return false
+	}
+}
+
+// Successful reports whether the transaction succeeded. The API reports successful transactions as
+// SUCCESSFUL or SUCCESS.
+func (s Status) Successful() bool {
+	return strings.EqualFold(string(s), string(StatusSuccessful)) || strings.EqualFold(string(s), "SUCCESS")
 }
 
 // StatusOptions specifies the request parameters to get the status of a transaction.
@@ -37,8 +66,8 @@ type StatusOptions struct {
 // GetTransactionStatus gets the status of a transaction.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#758a9aef-edc6-45de-8ab0-1631c80936a1
-func (s *StatusService) GetTransactionStatus(ctx context.Context, opt *StatusOptions) (*TransactionStatus, *Response, error) {
-	return DoRequest[TransactionStatus](ctx, s.client, http.MethodPost, "merchant/txns/status", opt)
+func (s *StatusService) GetTransactionStatus(ctx context.Context, opt *StatusOptions, options ...RequestOptionFunc) (*TransactionStatus, *Response, error) {
+	return DoRequest[TransactionStatus](ctx, s.client, http.MethodPost, s.client.path("Status", "GetTransactionStatus"), opt, options...)
 }
 
 // ETokenStatusOptions specifies the request parameters to get the status of a token.
@@ -54,6 +83,6 @@ type ETokenStatusOptions struct {
 // GetETokenStatus gets the status of a token.
 //
 // API docs: https://documenter.getpostman.com/view/9576712/2s7YtWCtNX#5f689c50-1c6a-4c47-af68-83ac66d8315f
-func (s *StatusService) GetETokenStatus(ctx context.Context, opt *ETokenStatusOptions) (*string, *Response, error) {
-	return DoRequest[string](ctx, s.client, http.MethodPost, "merchant/etoken/status", opt)
+func (s *StatusService) GetETokenStatus(ctx context.Context, opt *ETokenStatusOptions, options ...RequestOptionFunc) (*string, *Response, error) {
+	return DoRequest[string](ctx, s.client, http.MethodPost, s.client.path("Status", "GetETokenStatus"), opt, options...)
 }
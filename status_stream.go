@@ -0,0 +1,289 @@
+package ecobank
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrStatusTimeout is the error of a StatusEvent when the transaction had no final status before the
+// timeout of the StatusStream.
+var ErrStatusTimeout = errors.New("timed out waiting for final transaction status")
+
+const (
+	defaultStatusStreamInterval = 5 * time.Second
+	defaultStatusStreamWorkers  = 4
+	defaultStatusStreamTimeout  = time.Hour
+)
+
+// StatusEvent is emitted by a StatusStream when the status of a watched transaction changes.
+type StatusEvent struct {
+	// ClientID and RequestID identify the transaction.
+	ClientID  string
+	RequestID string
+	// Status is the last status of the transaction, or nil if it was never retrieved.
+	Status *TransactionStatus
+	// Final is set when the status will no longer change, or the transaction timed out.
+	// The transaction is no longer watched after its final event.
+	Final bool
+	// Err wraps ErrStatusTimeout if the transaction timed out.
+	Err error
+}
+
+// StatusStream polls the status of many in-flight transactions and emits their changes on a channel.
+// Unlike PaymentOptions.WaitForStatus, which polls every payment in its own loop, all the watched
+// transactions are checked in one batch every PollInterval, by a fixed pool of workers.
+//
+//	stream := ecobank.NewStatusStream(client)
+//	go stream.Run(ctx)
+//	stream.Watch(clientID, requestID)
+//
+//	for event := range stream.Events() {
+//		if event.Final {
+//			// update the payment
+//		}
+//	}
+type StatusStream struct {
+	client *Client
+
+	// PollInterval is the time between status checks. It defaults to 5 seconds.
+	PollInterval time.Duration
+	// Workers is the number of status requests made concurrently. It defaults to 4.
+	Workers int
+	// Timeout is how long a transaction is watched before a final event with ErrStatusTimeout is emitted.
+	// It defaults to one hour.
+	Timeout time.Duration
+	// OnError, if set, is called with the errors of status requests. The transaction is checked again later.
+	OnError func(requestID string, err error)
+
+	mu      sync.Mutex
+	watched map[string]*watchedTransaction
+	events  chan StatusEvent
+}
+
+// watchedTransaction is a transaction watched by a StatusStream.
+type watchedTransaction struct {
+	clientID  string
+	requestID string
+	since     time.Time
+	status    *TransactionStatus
+}
+
+// NewStatusStream returns a StatusStream checking statuses with the client.
+func NewStatusStream(client *Client) *StatusStream {
+	return &StatusStream{
+		client:  client,
+		watched: make(map[string]*watchedTransaction),
+		events:  make(chan StatusEvent, 64),
+	}
+}
+
+// Watch adds a transaction to the stream, identified by the client and request IDs of its payment.
+// Watching a transaction which is already watched has no effect.
+func (s *StatusStream) Watch(clientID, requestID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.watched[requestID]; ok {
+		return
+	}
+	s.watched[requestID] = &watchedTransaction{
+		clientID:  clientID,
+		requestID: requestID,
+		since:     s.client.now(),
+	}
+}
+
+// Unwatch removes a transaction from the stream.
+func (s *StatusStream) Unwatch(requestID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.watched, requestID)
+}
+
+// Len returns the number of watched transactions.
+func (s *StatusStream) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.watched)
+}
+
+// Events returns the channel the events are emitted on. It is closed when Run returns.
+func (s *StatusStream) Events() <-chan StatusEvent {
+	return s.events
+}
+
+// Run checks the statuses of the watched transactions every PollInterval until ctx is done or the client
+// is closed, and emits their changes on the Events channel, which must be read for polling to continue.
+// Run must only be called once, as the channel is closed when it returns.
+func (s *StatusStream) Run(ctx context.Context, options ...RequestOptionFunc) error {
+	defer close(s.events)
+
+	interval := s.PollInterval
+	if interval <= 0 {
+		interval = defaultStatusStreamInterval
+	}
+	workers := s.Workers
+	if workers <= 0 {
+		workers = defaultStatusStreamWorkers
+	}
+
+	jobs := make(chan *watchedTransaction)
+	results := make(chan StatusEvent)
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case tx := <-jobs:
+					select {
+					case results <- s.check(ctx, tx, options...):
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.client.closed():
+			return ErrClientClosed
+		case <-timer.C:
+		}
+
+		if err := s.poll(ctx, jobs, results); err != nil {
+			return err
+		}
+
+		timer.Reset(interval)
+	}
+}
+
+// poll checks the statuses of all the watched transactions with the workers, and emits the changes.
+func (s *StatusStream) poll(ctx context.Context, jobs chan<- *watchedTransaction, results <-chan StatusEvent) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultStatusStreamTimeout
+	}
+
+	s.mu.Lock()
+	batch := make([]*watchedTransaction, 0, len(s.watched))
+	for _, tx := range s.watched {
+		batch = append(batch, tx)
+	}
+	s.mu.Unlock()
+
+	var events []StatusEvent
+	go func() {
+		for _, tx := range batch {
+			select {
+			case jobs <- tx:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	now := s.client.now()
+	for range batch {
+		var event StatusEvent
+		select {
+		case event = <-results:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if !event.Final && now.Sub(s.since(event.RequestID)) >= timeout {
+			event.Final = true
+			event.Err = ErrStatusTimeout
+		}
+		if s.update(&event) {
+			events = append(events, event)
+		}
+	}
+
+	for _, event := range events {
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.client.closed():
+			return ErrClientClosed
+		}
+	}
+	return nil
+}
+
+// check gets the status of the transaction. The event is not final and has no status on errors.
+func (s *StatusStream) check(ctx context.Context, tx *watchedTransaction, options ...RequestOptionFunc) StatusEvent {
+	event := StatusEvent{ClientID: tx.clientID, RequestID: tx.requestID}
+
+	status, _, err := s.client.Status.GetTransactionStatus(ctx, &StatusOptions{
+		ClientID:  tx.clientID,
+		RequestID: tx.requestID,
+	}, options...)
+	if err != nil {
+		if s.OnError != nil && ctx.Err() == nil {
+			s.OnError(tx.requestID, err)
+		}
+		return event
+	}
+
+	event.Status = status
+	event.Final = status.Status.IsTerminal()
+	return event
+}
+
+// since returns when the transaction started being watched.
+func (s *StatusStream) since(requestID string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tx, ok := s.watched[requestID]; ok {
+		return tx.since
+	}
+	return time.Time{}
+}
+
+// update records the status of the event, and reports whether it must be emitted because the status changed
+// or is final. Transactions with a final event are no longer watched.
+func (s *StatusStream) update(event *StatusEvent) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.watched[event.RequestID]
+	if !ok {
+		// unwatched while it was checked
+		return false
+	}
+
+	if event.Status == nil {
+		event.Status = tx.status
+	}
+	if event.Final {
+		delete(s.watched, event.RequestID)
+		return true
+	}
+
+	changed := event.Status != nil && (tx.status == nil || tx.status.Status != event.Status.Status || tx.status.StatusCode != event.Status.StatusCode)
+	tx.status = event.Status
+	return changed
+}
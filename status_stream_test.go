@@ -0,0 +1,99 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusStream(t *testing.T) {
+	var mu sync.Mutex
+	checks := make(map[string]int)
+
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			var opt StatusOptions
+			require.NoError(t, json.Unmarshal(body, &opt))
+
+			mu.Lock()
+			checks[opt.RequestID]++
+			n := checks[opt.RequestID]
+			mu.Unlock()
+
+			status := "PENDING"
+			switch {
+			case opt.RequestID == "ECO1" && n >= 3:
+				status = "SUCCESSFUL"
+			case opt.RequestID == "ECO2":
+				status = "FAILED"
+			}
+
+			rec := httptest.NewRecorder()
+			_, _ = rec.WriteString(`{"response_code": 200, "response_content": {"status": "` + status + `"}}`)
+			return rec.Result(), nil
+		},
+	}
+
+	stream := NewStatusStream(client)
+	stream.PollInterval = time.Millisecond
+	stream.Timeout = 200 * time.Millisecond
+	stream.Watch("client", "ECO1")
+	stream.Watch("client", "ECO2")
+	stream.Watch("client", "ECO3")
+	stream.Watch("client", "ECO1")
+	assert.Equal(t, 3, stream.Len())
+
+	errc := make(chan error, 1)
+	go func() { errc <- stream.Run(t.Context()) }()
+
+	events := make(map[string][]StatusEvent)
+	for final := 0; final < 3; {
+		select {
+		case event := <-stream.Events():
+			events[event.RequestID] = append(events[event.RequestID], event)
+			if event.Final {
+				final++
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for final events")
+		}
+	}
+
+	require.Len(t, events["ECO1"], 2, "the pending status is only emitted once")
+	assert.Equal(t, StatusPending, events["ECO1"][0].Status.Status)
+	assert.False(t, events["ECO1"][0].Final)
+	assert.Equal(t, StatusSuccessful, events["ECO1"][1].Status.Status)
+	assert.True(t, events["ECO1"][1].Final)
+
+	require.Len(t, events["ECO2"], 1)
+	assert.Equal(t, StatusFailed, events["ECO2"][0].Status.Status)
+	assert.True(t, events["ECO2"][0].Final)
+	assert.NoError(t, events["ECO2"][0].Err)
+
+	last := events["ECO3"][len(events["ECO3"])-1]
+	assert.True(t, last.Final)
+	assert.ErrorIs(t, last.Err, ErrStatusTimeout)
+	assert.Equal(t, StatusPending, last.Status.Status)
+
+	assert.Zero(t, stream.Len())
+
+	require.NoError(t, client.Close())
+	select {
+	case err := <-errc:
+		assert.ErrorIs(t, err, ErrClientClosed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not stop after Close")
+	}
+
+	_, ok := <-stream.Events()
+	assert.False(t, ok, "events are closed when Run returns")
+}
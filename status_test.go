@@ -0,0 +1,44 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionStatus_UnmarshalJSON(t *testing.T) {
+	for _, amount := range []string{`10.75`, `"10.75"`, `"10.750"`} {
+		var status TransactionStatus
+		require.NoError(t, json.Unmarshal([]byte(`{"amount": `+amount+`, "status": "SUCCESS"}`), &status), amount)
+		assert.True(t, decimal.RequireFromString("10.75").Equal(status.Amount.Decimal), amount)
+		assert.Equal(t, Status("SUCCESS"), status.Status)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	testCases := []struct {
+		status     Status
+		terminal   bool
+		successful bool
+	}{
+		{status: ""},
+		{status: StatusPending},
+		{status: "processing"},
+		{status: "IN PROGRESS"},
+		{status: StatusSuccessful, terminal: true, successful: true},
+		{status: "Success", terminal: true, successful: true},
+		{status: StatusFailed, terminal: true},
+		{status: StatusReversed, terminal: true},
+		{status: "reversed", terminal: true},
+		{status: "ON HOLD"},
+		{status: "UNKNOWN"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.terminal, tc.status.IsTerminal(), tc.status)
+		assert.Equal(t, tc.successful, tc.status.Successful(), tc.status)
+	}
+}
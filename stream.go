@@ -0,0 +1,198 @@
+package ecobank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// DoStream sends an authenticated API request like Do, but instead of buffering the
+// whole response it walks the response envelope with a streaming decoder.
+//
+// When the `response_content` field is reached, fn is called with the decoder positioned
+// at the start of its value. fn must consume exactly one JSON value from the decoder.
+// This keeps peak memory low for endpoints that return very large payloads such as the
+// biller list or account statements.
+//
+// The envelope metadata decoded before `response_content` is available in the returned *Response.
+// As with Do, an `errors` field in the envelope is returned as a ResponseError.
+func (c *Client) DoStream(req *retryablehttp.Request, fn func(dec *json.Decoder) error) (*Response, error) {
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.doStreamRequest(req, fn)
+	c.recordStats(c.relativePath(req.URL.Path), start, err)
+	return resp, err
+}
+
+func (c *Client) doStreamRequest(req *retryablehttp.Request, fn func(dec *json.Decoder) error) (r *Response, err error) {
+	req, stats := withAttemptStats(req)
+	c.warnDeprecatedVersion(req.URL.Path)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body := resp.Body
+	defer func() {
+		err = errors.Join(err, drainBody(body))
+	}()
+
+	r = newResponse(resp)
+	r.RequestID = req.Header.Get(RequestIDHeader)
+	stats.fill(r)
+	c.checkDeprecationHeaders(resp)
+
+	if err = c.limitBody(req.Request, resp); err != nil {
+		return r, err
+	}
+	if err = checkGatewayResponse(resp); err != nil {
+		return r, err
+	}
+
+	err = decodeResponseStream(json.NewDecoder(resp.Body), r, fn)
+
+	return r, err
+}
+
+// decodeResponseStream decodes the response envelope token by token, storing the metadata
+// in r and handing the decoder over to fn for the `response_content` value.
+func decodeResponseStream(dec *json.Decoder, r *Response, fn func(dec *json.Decoder) error) error {
+	return decodeObject(dec, func(key string) error {
+		switch key {
+		case "response_code":
+			return dec.Decode(&r.Code)
+		case "response_message":
+			return dec.Decode(&r.Message)
+		case "response_timestamp":
+			return dec.Decode(&r.Time)
+		case "errors":
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			return decodeResponseErrors(raw, r)
+		case "page", "pageSize", "totalRecords":
+			var n pageNumber
+			if err := dec.Decode(&n); err != nil {
+				return err
+			}
+			if r.Pagination == nil {
+				r.Pagination = &Pagination{}
+			}
+			switch key {
+			case "page":
+				r.Pagination.Page = int(n)
+			case "pageSize":
+				r.Pagination.PageSize = int(n)
+			default:
+				r.Pagination.TotalRecords = int(n)
+			}
+			return nil
+		case "response_content":
+			if fn == nil {
+				return skipValue(dec)
+			}
+			return fn(dec)
+		default:
+			return skipValue(dec)
+		}
+	})
+}
+
+// decodeObject walks the JSON object at the current position of the decoder and calls fn
+// for every key. fn must consume the value of the key from the decoder.
+//
+// The API returns an empty string or null in place of empty objects, so both are treated as an empty object.
+func decodeObject(dec *json.Decoder, fn func(key string) error) error {
+	ok, err := openDelim(dec, '{')
+	if err != nil || !ok {
+		return err
+	}
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("unexpected token %v, expected object key", t)
+		}
+
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+
+	// consume the closing delimiter
+	_, err = dec.Token()
+	return err
+}
+
+// decodeArray decodes every element of the JSON array at the current position of the decoder
+// into a new T and passes it to fn. It stops at the first error returned by fn.
+//
+// The API returns an empty string or null in place of empty arrays, so both are treated as an empty array.
+func decodeArray[T any](dec *json.Decoder, fn func(*T) error) error {
+	ok, err := openDelim(dec, '[')
+	if err != nil || !ok {
+		return err
+	}
+
+	for dec.More() {
+		v := new(T)
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+
+	// consume the closing delimiter
+	_, err = dec.Token()
+	return err
+}
+
+// openDelim reads the next token and reports whether it is the opening delimiter want.
+// Empty values (null or "") are consumed and reported as false.
+func openDelim(dec *json.Decoder, want json.Delim) (bool, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+
+	switch v := t.(type) {
+	case json.Delim:
+		if v != want {
+			return false, fmt.Errorf("unexpected token %v, expected %v", v, want)
+		}
+		return true, nil
+	case nil:
+		return false, nil
+	case string:
+		if v == "" {
+			return false, nil
+		}
+	}
+
+	return false, fmt.Errorf("unexpected token %v, expected %v", t, want)
+}
+
+// skipValue discards the next JSON value from the decoder.
+func skipValue(dec *json.Decoder) error {
+	var raw json.RawMessage
+	return dec.Decode(&raw)
+}
@@ -0,0 +1,183 @@
+package ecobank
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// UnknownFieldsFunc is called with the URL path of a request and the keys of its response content which
+// are not decoded by the library, e.g. "billerList[0].logoUrl".
+type UnknownFieldsFunc func(path string, keys []string)
+
+// UnknownFieldsError is returned with WithStrictDecoding when the content of a response has keys which are
+// not decoded by the library.
+type UnknownFieldsError struct {
+	// Path is the URL path of the request.
+	Path string
+	// Keys are the unknown keys, e.g. "billerList[0].logoUrl".
+	Keys []string
+}
+
+// Error implements the error interface.
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("response of %s has unknown fields: %s", e.Path, strings.Join(e.Keys, ", "))
+}
+
+// WithStrictDecoding rejects responses whose content has fields the library does not decode, like
+// json.Decoder.DisallowUnknownFields, with an *UnknownFieldsError listing all of them. The decoded
+// response is still returned along with the error.
+//
+// It is meant for tests and staging environments, to learn when Ecobank adds or renames response fields.
+// In production, prefer WithUnknownFieldsFunc which reports the fields without failing requests.
+func WithStrictDecoding() ClientOptionFunc {
+	return func(c *Client) error {
+		c.strictDecoding = true
+		return nil
+	}
+}
+
+// WithUnknownFieldsFunc calls fn with the fields of response content which the library does not decode,
+// instead of silently dropping them. The content of streaming methods such as ForEachBiller is not checked.
+func WithUnknownFieldsFunc(fn UnknownFieldsFunc) ClientOptionFunc {
+	return func(c *Client) error {
+		c.unknownFields = fn
+		return nil
+	}
+}
+
+// checkUnknownFields reports the keys of the response content which are not decoded into v.
+func (c *Client) checkUnknownFields(path string, content json.RawMessage, v any) error {
+	if !c.strictDecoding && c.unknownFields == nil {
+		return nil
+	}
+
+	keys := unknownFields(content, reflect.TypeOf(v), "")
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if c.unknownFields != nil {
+		c.unknownFields(path, keys)
+	}
+	if c.strictDecoding {
+		return &UnknownFieldsError{Path: path, Keys: keys}
+	}
+	return nil
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// unknownFields returns the keys of the JSON data which are not decoded into a value of type typ,
+// prefixed with prefix. Types which decode themselves, such as FlexibleDecimal, are not inspected.
+func unknownFields(data []byte, typ reflect.Type, prefix string) []string {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if reflect.PointerTo(typ).Implements(jsonUnmarshalerType) || reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			return nil
+		}
+
+		fields := decodedFields(typ)
+		var keys []string
+		for key, value := range object {
+			path := joinFieldPath(prefix, key)
+			field, ok := lookupField(fields, key)
+			if !ok {
+				keys = append(keys, path)
+				continue
+			}
+			keys = append(keys, unknownFields(value, field.Type, path)...)
+		}
+		return sortedKeys(keys)
+
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			return nil
+		}
+
+		var keys []string
+		for key, value := range object {
+			keys = append(keys, unknownFields(value, typ.Elem(), joinFieldPath(prefix, key))...)
+		}
+		return sortedKeys(keys)
+
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			return nil
+		}
+
+		var keys []string
+		for i, elem := range elems {
+			keys = append(keys, unknownFields(elem, typ.Elem(), prefix+"["+strconv.Itoa(i)+"]")...)
+		}
+		return keys
+	}
+	return nil
+}
+
+// decodedFields returns the fields of the struct type typ decoded by encoding/json, by JSON key.
+func decodedFields(typ reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for _, field := range reflect.VisibleFields(typ) {
+		if !field.IsExported() {
+			continue
+		}
+
+		key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if key == "-" {
+			continue
+		}
+		if field.Anonymous && key == "" && field.Type.Kind() == reflect.Struct {
+			// the fields of untagged embedded structs are promoted
+			continue
+		}
+		if key == "" {
+			key = field.Name
+		}
+		fields[key] = field
+	}
+	return fields
+}
+
+// lookupField returns the field decoding key, which encoding/json matches case-insensitively.
+func lookupField(fields map[string]reflect.StructField, key string) (reflect.StructField, bool) {
+	if field, ok := fields[key]; ok {
+		return field, true
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func joinFieldPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// sortedKeys sorts keys collected from a JSON object, whose order is lost when it is decoded into a map.
+func sortedKeys(keys []string) []string {
+	slices.Sort(keys)
+	return keys
+}
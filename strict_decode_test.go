@@ -0,0 +1,84 @@
+package ecobank
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const driftedBalanceResponse = `{
+	"response_code": 200,
+	"response_message": "success",
+	"response_content": {
+		"hostHeaderInfo": {
+			"requestId": "14232436312",
+			"affiliateCode": "EGH",
+			"channel": "API"
+		},
+		"accountNo": "1441000574000",
+		"accountName": "TEST USER",
+		"ccy": "GHS",
+		"availableBalance": "15.92",
+		"blockedAmount": 0,
+		"accountStatus": "ACTIVE"
+	}
+}`
+
+func TestWithStrictDecoding(t *testing.T) {
+	client := newMockClient(t, driftedBalanceResponse, http.StatusOK)
+	require.NoError(t, WithStrictDecoding()(client))
+
+	balance, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+
+	var unknownErr *UnknownFieldsError
+	require.ErrorAs(t, err, &unknownErr)
+	assert.Equal(t, "/corporateapi/merchant/accountbalance", unknownErr.Path)
+	assert.Equal(t, []string{"blockedAmount", "hostHeaderInfo.channel"}, unknownErr.Keys)
+	assert.Nil(t, balance)
+}
+
+func TestWithUnknownFieldsFunc(t *testing.T) {
+	client := newMockClient(t, driftedBalanceResponse, http.StatusOK)
+
+	var reported []string
+	require.NoError(t, WithUnknownFieldsFunc(func(path string, keys []string) {
+		assert.Equal(t, "/corporateapi/merchant/accountbalance", path)
+		reported = keys
+	})(client))
+
+	balance, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "TEST USER", balance.AccountName)
+	assert.Equal(t, []string{"blockedAmount", "hostHeaderInfo.channel"}, reported)
+}
+
+func TestUnknownFields(t *testing.T) {
+	type item struct {
+		Name  string          `json:"name"`
+		Price FlexibleDecimal `json:"price"`
+		Tags  map[string]int  `json:"tags"`
+	}
+	type content struct {
+		secureHashOption
+		Items []item `json:"items"`
+		Count int
+	}
+
+	data := `{
+		"secureHash": "abc",
+		"COUNT": 2,
+		"items": [
+			{"name": "a", "price": {"value": 1}},
+			{"NAME": "b", "logo": "b.png", "tags": {"x": 1}}
+		],
+		"total": 3
+	}`
+
+	keys := unknownFields([]byte(data), reflect.TypeFor[*content](), "")
+	assert.Equal(t, []string{"items[1].logo", "total"}, keys)
+
+	assert.Empty(t, unknownFields([]byte(`{"items": "not an array"}`), reflect.TypeFor[content](), ""))
+}
@@ -0,0 +1,65 @@
+package ecobank
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// tagsKey is the context key under which the tags of a request are stored.
+type tagsKey struct{}
+
+// Tag is a key/value pair attached to a context with WithTag, used to attribute API usage,
+// such as the tenant or customer a request is made for.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+func (t Tag) String() string {
+	return t.Key + "=" + t.Value
+}
+
+// WithTag returns a copy of ctx tagged with the given key and value. Tagging a context
+// again with the same key replaces the value.
+//
+// Tags are only used in two places: they are logged with every attempt when the retryable client has
+// a Logger, and they are sent as request headers when the client is configured with WithTagHeaders.
+// Without either, tagging a context has no effect on the client. Tags are not sent by default, since
+// the API does not document custom headers. Metrics and payment hooks can read them from the
+// request context with Tags.
+func WithTag(ctx context.Context, key, value string) context.Context {
+	parent := Tags(ctx)
+
+	tags := make([]Tag, 0, len(parent)+1)
+	for _, t := range parent {
+		if t.Key != key {
+			tags = append(tags, t)
+		}
+	}
+	tags = append(tags, Tag{Key: key, Value: value})
+
+	return context.WithValue(ctx, tagsKey{}, tags)
+}
+
+// Tags returns the tags set on ctx with WithTag, in the order they were added.
+func Tags(ctx context.Context) []Tag {
+	tags, _ := ctx.Value(tagsKey{}).([]Tag)
+	return tags
+}
+
+// setTagHeaders adds the tags of ctx to header, prefixing each key with prefix.
+func setTagHeaders(ctx context.Context, header http.Header, prefix string) {
+	for _, t := range Tags(ctx) {
+		header.Set(prefix+t.Key, t.Value)
+	}
+}
+
+// formatTags formats tags as space separated key=value pairs.
+func formatTags(tags []Tag) string {
+	s := make([]string, len(tags))
+	for i, t := range tags {
+		s[i] = t.String()
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,42 @@
+package ecobank
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTag(t *testing.T) {
+	ctx := WithTag(context.Background(), "tenant", "acme")
+	ctx = WithTag(ctx, "region", "gh")
+	child := WithTag(ctx, "tenant", "globex")
+
+	assert.Equal(t, []Tag{{"tenant", "acme"}, {"region", "gh"}}, Tags(ctx))
+	assert.Equal(t, []Tag{{"region", "gh"}, {"tenant", "globex"}}, Tags(child))
+	assert.Empty(t, Tags(context.Background()))
+}
+
+func TestWithTagHeaders(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	require.NoError(t, WithTagHeaders("X-Tag-")(client))
+
+	var header http.Header
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			header = req.Header
+			resp := httptest.NewRecorder()
+			_, err := resp.WriteString(`{"response_code": 0}`)
+			return resp.Result(), err
+		},
+	}
+
+	ctx := WithTag(context.Background(), "tenant", "acme")
+	_, _, err := DoRequest[struct{}](ctx, client, http.MethodPost, "merchant/accountbalance", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "acme", header.Get("X-Tag-tenant"))
+}
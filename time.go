@@ -0,0 +1,115 @@
+package ecobank
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/profclems/go-ecobank/calendar"
+)
+
+// affiliateOffsets are the UTC offsets of the countries of Ecobank affiliates, in hours.
+// None of them observe daylight saving time, so fixed zones are used to avoid depending on tzdata.
+var affiliateOffsets = map[string]int{
+	"BF": 0, "BJ": 1, "BI": 2, "CD": 1, "CF": 1, "CG": 1, "CI": 0, "CM": 1, "CV": -1,
+	"GA": 1, "GH": 0, "GM": 0, "GN": 0, "GQ": 1, "GW": 0, "KE": 3, "LR": 0, "ML": 0,
+	"MW": 2, "MZ": 2, "NE": 1, "NG": 1, "RW": 2, "SL": 0, "SN": 0, "SS": 2, "ST": 0,
+	"TD": 1, "TG": 0, "TZ": 3, "UG": 3, "ZM": 2, "ZW": 2,
+}
+
+// AffiliateLocation returns the time zone of an affiliate, e.g. "EGH", or of a country, e.g. "GH".
+// It returns UTC for unknown affiliates.
+func AffiliateLocation(affiliateCode string) *time.Location {
+	country := calendar.Country(affiliateCode)
+
+	offset, ok := affiliateOffsets[country]
+	if !ok {
+		return time.UTC
+	}
+
+	return time.FixedZone(country, offset*60*60)
+}
+
+// IsZero reports whether t is the zero time, e.g. when the API returned an empty timestamp.
+func (t Time) IsZero() bool {
+	return t.time.IsZero()
+}
+
+// In returns t converted to the given location, keeping its layout.
+func (t Time) In(loc *time.Location) Time {
+	t.time = t.time.In(loc)
+	t.loc = loc
+	return t
+}
+
+// WithLocation returns t with its wall clock reinterpreted in the given location.
+//
+// The API returns timestamps in the local time of the affiliate without a time zone, so they are
+// parsed as UTC. Use WithLocation with AffiliateLocation to get the actual instant:
+//
+//	sentAt := resp.Time.WithLocation(ecobank.AffiliateLocation("ENG"))
+func (t Time) WithLocation(loc *time.Location) Time {
+	if !t.time.IsZero() {
+		y, mo, d := t.time.Date()
+		h, mi, s := t.time.Clock()
+		t.time = time.Date(y, mo, d, h, mi, s, t.time.Nanosecond(), loc)
+	}
+	t.loc = loc
+	return t
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The zero time is marshaled as an empty string.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.text()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// An empty string leaves the time zero.
+func (t *Time) UnmarshalText(b []byte) error {
+	return t.parse(string(b))
+}
+
+// Scan implements the sql.Scanner interface so a Time can be read from a database column
+// holding a timestamp or a string.
+func (t *Time) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		t.time = time.Time{}
+		return nil
+	case time.Time:
+		t.time = v
+		return nil
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into Time", src)
+	}
+}
+
+// Value implements the driver.Valuer interface. The zero time is stored as NULL.
+func (t Time) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t.time, nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// An empty string leaves the date zero.
+func (date *Date) UnmarshalText(b []byte) error {
+	if date.layout == "" {
+		date.layout = dateFormat
+	}
+	return date.Time.UnmarshalText(b)
+}
+
+// Scan implements the sql.Scanner interface.
+func (date *Date) Scan(src any) error {
+	if date.layout == "" {
+		date.layout = dateFormat
+	}
+	return date.Time.Scan(src)
+}
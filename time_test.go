@@ -0,0 +1,238 @@
+package ecobank
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTime_ZeroValues(t *testing.T) {
+	var v struct {
+		Null  Time `json:"null"`
+		Empty Time `json:"empty"`
+		Date  Date `json:"date"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"null": null, "empty": "", "date": ""}`), &v))
+
+	assert.True(t, v.Null.IsZero())
+	assert.True(t, v.Empty.IsZero())
+	assert.True(t, v.Date.IsZero())
+
+	text, err := v.Empty.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	value, err := v.Empty.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"null": "", "empty": "", "date": ""}`, string(b), "zero times are marshaled as empty strings")
+	assert.Empty(t, formatToStr(v.Date), "zero times are hashed as sent")
+}
+
+func TestTime_WithLocation(t *testing.T) {
+	var ts Time
+	require.NoError(t, json.Unmarshal([]byte(`"2022-04-19T19:46:57.557"`), &ts))
+
+	lagos := AffiliateLocation("ENG")
+	local := ts.WithLocation(lagos)
+
+	assert.Equal(t, time.Date(2022, 4, 19, 18, 46, 57, 557000000, time.UTC), local.GetTime().UTC())
+	assert.Equal(t, "2022-04-19 19:46:57", local.String())
+	assert.Equal(t, "2022-04-19 20:46:57", ts.In(lagos).String())
+	assert.Equal(t, time.UTC, AffiliateLocation("XYZ"))
+}
+
+func TestTime_Text(t *testing.T) {
+	ts := NewTime(time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC))
+
+	text, err := ts.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-01 10:30:00", string(text))
+
+	var parsed Time
+	require.NoError(t, parsed.UnmarshalText(text))
+	assert.Equal(t, ts.GetTime(), parsed.GetTime())
+
+	var date Date
+	require.NoError(t, date.UnmarshalText([]byte("20250301")))
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), date.GetTime())
+}
+
+func TestTime_Scan(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		src     any
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "time", src: now, want: now},
+		{name: "string", src: "2025-03-01 10:30:00", want: now},
+		{name: "bytes", src: []byte("2025-03-01T10:30:00.000"), want: now},
+		{name: "null", src: nil},
+		{name: "unsupported", src: 42, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ts Time
+			err := ts.Scan(tc.src)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, ts.GetTime())
+		})
+	}
+
+	value, err := NewTime(now).Value()
+	require.NoError(t, err)
+	assert.Equal(t, now, value)
+}
+
+func TestTime_ParseError(t *testing.T) {
+	var tm Time
+	err := json.Unmarshal([]byte(`"19/04/2022 19:46"`), &tm)
+
+	var parseErr *TimeParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "19/04/2022 19:46", parseErr.Value)
+}
+
+func TestAddTimeFormat_Concurrent(t *testing.T) {
+	old := formats.Load()
+	t.Cleanup(func() { formats.Store(old) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			AddTimeFormat("02.01.2006")
+		}()
+		go func() {
+			defer wg.Done()
+			var tm Time
+			_ = json.Unmarshal([]byte(`"2022-04-19 19:46:00"`), &tm)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, *formats.Load(), len(*old)+10)
+}
+
+func TestWithTimeFormats(t *testing.T) {
+	response := `{
+		"response_code": 200,
+		"response_message": "success",
+		"response_content": {"requestId": "14232436312", "transactionDate": "19/04/2022 19:46"},
+		"response_timestamp": "2022-04-19T19:46:00"
+	}`
+
+	type content struct {
+		RequestID       string `json:"requestId"`
+		TransactionDate Time   `json:"transactionDate"`
+	}
+
+	t.Run("client formats", func(t *testing.T) {
+		client := newMockClient(t, response, http.StatusOK)
+		require.NoError(t, WithTimeFormats("02/01/2006 15:04")(client))
+
+		req, err := client.NewRequest(context.Background(), http.MethodPost, "test", nil)
+		require.NoError(t, err)
+
+		var v content
+		_, err = client.Do(req, &v)
+		require.NoError(t, err)
+		assert.Equal(t, "14232436312", v.RequestID)
+		assert.Equal(t, time.Date(2022, 4, 19, 19, 46, 0, 0, time.UTC), v.TransactionDate.GetTime())
+	})
+
+	t.Run("other client", func(t *testing.T) {
+		client := newMockClient(t, response, http.StatusOK)
+		require.NoError(t, WithTimeFormats("01/02/2006 15:04")(client))
+
+		req, err := client.NewRequest(context.Background(), http.MethodPost, "test", nil)
+		require.NoError(t, err)
+
+		var v content
+		_, err = client.Do(req, &v)
+
+		var parseErr *TimeParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, "19/04/2022 19:46", parseErr.Value)
+	})
+}
+
+func TestWithTimeFormats_OnlyTimeFields(t *testing.T) {
+	response := `{
+		"response_code": 200,
+		"response_message": "success",
+		"response_content": {
+			"reference": "19/04/2022 19:46",
+			"transactions": [{"TransactionDate": "19/04/2022 19:46", "valueDate": "20/04/2022 00:00"}],
+			"notes": {"first": "19/04/2022 19:46"}
+		}
+	}`
+
+	type transaction struct {
+		TransactionDate *Time `json:"transactionDate"`
+		ValueDate       Date  `json:"valueDate"`
+	}
+	type content struct {
+		Reference    string            `json:"reference"`
+		Transactions []transaction     `json:"transactions"`
+		Notes        map[string]string `json:"notes"`
+	}
+
+	client := newMockClient(t, response, http.StatusOK)
+	require.NoError(t, WithTimeFormats("02/01/2006 15:04")(client))
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "test", nil)
+	require.NoError(t, err)
+
+	var v content
+	_, err = client.Do(req, &v)
+	require.NoError(t, err)
+	assert.Equal(t, "19/04/2022 19:46", v.Reference, "string fields are not rewritten")
+	assert.Equal(t, map[string]string{"first": "19/04/2022 19:46"}, v.Notes)
+	require.Len(t, v.Transactions, 1)
+	assert.Equal(t, time.Date(2022, 4, 19, 19, 46, 0, 0, time.UTC), v.Transactions[0].TransactionDate.GetTime())
+	assert.Equal(t, time.Date(2022, 4, 20, 0, 0, 0, 0, time.UTC), v.Transactions[0].ValueDate.GetTime())
+}
+
+func FuzzTime_UnmarshalJSON(f *testing.F) {
+	for _, seed := range []string{
+		`"2022-04-19T19:46:57.557"`, `"2022-04-19 19:46:57"`, `"2022-04-19T19:46:57+01:00"`, `"2022-04-19"`,
+		`null`, `""`, `"`, `x`, `"\u0000"`, `"9999-12-31T23:59:59Z"`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var tm Time
+		if err := tm.UnmarshalJSON(b); err != nil {
+			var parseErr *TimeParseError
+			require.ErrorAs(t, err, &parseErr)
+			return
+		}
+
+		out, err := tm.MarshalJSON()
+		require.NoError(t, err)
+		require.True(t, json.Valid(out), "invalid JSON: %s", out)
+
+		var again Time
+		require.NoError(t, again.UnmarshalJSON(out))
+	})
+}
@@ -0,0 +1,43 @@
+package ecobank
+
+import (
+	"context"
+
+	"golang.org/x/oauth2"
+)
+
+// WithTokenSource makes the client authorize requests with the tokens of ts instead of logging in with
+// its credentials, e.g. to share the tokens of a central token service. Wrap ts with oauth2.ReuseTokenSource
+// so that tokens are only fetched when they expire.
+func WithTokenSource(ts oauth2.TokenSource) ClientOptionFunc {
+	return func(c *Client) error {
+		c.tokenSource = ts
+		return nil
+	}
+}
+
+// TokenSource returns an oauth2.TokenSource of the tokens of the client, which logs in with the credentials
+// of the client when the token is not set or has expired, so that the Ecobank authentication can be used
+// with the golang.org/x/oauth2 plumbing. ctx is used for the login requests.
+func (c *Client) TokenSource(ctx context.Context) oauth2.TokenSource {
+	return &clientTokenSource{ctx: ctx, client: c}
+}
+
+// clientTokenSource is the oauth2.TokenSource returned by Client.TokenSource.
+type clientTokenSource struct {
+	ctx    context.Context
+	client *Client
+}
+
+// Token implements the oauth2.TokenSource interface.
+func (s *clientTokenSource) Token() (*oauth2.Token, error) {
+	token, expiry, err := s.client.validToken(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		Expiry:      expiry,
+	}, nil
+}
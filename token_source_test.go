@@ -0,0 +1,79 @@
+package ecobank
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestClient_TokenSource(t *testing.T) {
+	client := newMockClient(t, "", http.StatusOK)
+	client.token = ""
+
+	var logins int
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			logins++
+			resp := httptest.NewRecorder()
+			_, err := resp.WriteString(`{"username": "mock-client-id", "token": "new-token"}`)
+			return resp.Result(), err
+		},
+	}
+
+	ts := client.TokenSource(t.Context())
+
+	token, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "new-token", token.AccessToken)
+	assert.Equal(t, "Bearer", token.Type())
+	assert.WithinDuration(t, time.Now().Add(defaultTokenExpiry), token.Expiry, time.Minute)
+	assert.True(t, token.Valid())
+
+	_, err = ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, 1, logins, "the token is reused until it expires")
+
+	t.Run("no credentials", func(t *testing.T) {
+		client, err := NewClient("", "", "mock-lab-key")
+		require.NoError(t, err)
+
+		_, err = client.TokenSource(t.Context()).Token()
+		assert.EqualError(t, err, "token expired")
+	})
+}
+
+func TestWithTokenSource(t *testing.T) {
+	var authorization []string
+	client := newMockClient(t, "", http.StatusOK)
+	client.client.HTTPClient.Transport = &mockHTTPClient{
+		requestHandler: func(req *http.Request) (*http.Response, error) {
+			authorization = append(authorization, req.Header.Get("Authorization"))
+			resp := httptest.NewRecorder()
+			_, err := resp.WriteString(`{"response_code": 200, "response_content": {}}`)
+			return resp.Result(), err
+		},
+	}
+	require.NoError(t, WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "shared-token"}))(client))
+
+	_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer shared-token"}, authorization, "the client does not log in")
+
+	t.Run("errors", func(t *testing.T) {
+		sourceErr := errors.New("token service unavailable")
+		require.NoError(t, WithTokenSource(errTokenSource{sourceErr})(client))
+
+		_, _, err := client.Account.GetBalance(t.Context(), &AccountBalanceOptions{})
+		assert.ErrorIs(t, err, sourceErr)
+	})
+}
+
+type errTokenSource struct{ err error }
+
+func (s errTokenSource) Token() (*oauth2.Token, error) { return nil, s.err }
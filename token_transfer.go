@@ -0,0 +1,68 @@
+package ecobank
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+// WithdrawalChannel is where the beneficiary of a TOKEN transfer withdraws the cash with the token.
+type WithdrawalChannel string
+
+const (
+	WithdrawalChannelATM         WithdrawalChannel = "ATM"
+	WithdrawalChannelXpressPoint WithdrawalChannel = "Xpress point"
+)
+
+// withdrawalChannels are the known withdrawal channels, in the order they are listed in errors.
+var withdrawalChannels = []WithdrawalChannel{WithdrawalChannelATM, WithdrawalChannelXpressPoint}
+
+// Valid reports whether the withdrawal channel is known.
+func (c WithdrawalChannel) Valid() bool {
+	return slices.Contains(withdrawalChannels, c)
+}
+
+// Validate checks the withdrawal channel of the transfer if it is set.
+func (p TokenTransferParams) Validate() error {
+	if p.WithdrawalChannel != "" && !p.WithdrawalChannel.Valid() {
+		return fmt.Errorf("unknown withdrawal channel %q: must be one of %q", p.WithdrawalChannel, withdrawalChannels)
+	}
+	return nil
+}
+
+// NewTokenTransferExtension returns a TOKEN extension sending amount in currency, to be withdrawn with the
+// token through channel. The amount, currency and withdrawal channel are filled in the params unless set,
+// in which case they must match, and the params are validated.
+func NewTokenTransferExtension(requestID string, amount decimal.Decimal, currency string, channel WithdrawalChannel, params TokenTransferParams) (*PaymentExtension, error) {
+	if params.Amount.IsZero() {
+		params.Amount = amount
+	} else if !params.Amount.Equal(amount) {
+		return nil, fmt.Errorf("amount %s of the params does not match %s", params.Amount, amount)
+	}
+	if params.Currency == "" {
+		params.Currency = currency
+	} else if params.Currency != currency {
+		return nil, fmt.Errorf("currency %s of the params does not match %s", params.Currency, currency)
+	}
+	if params.WithdrawalChannel == "" {
+		params.WithdrawalChannel = channel
+	} else if params.WithdrawalChannel != channel {
+		return nil, fmt.Errorf("withdrawal channel %s of the params does not match %s", params.WithdrawalChannel, channel)
+	}
+
+	if channel == "" {
+		return nil, fmt.Errorf("withdrawal channel is required: must be one of %q", withdrawalChannels)
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &PaymentExtension{
+		RequestID:   requestID,
+		RequestType: TOKEN,
+		ParamList:   NewPaymentParams(params),
+		Amount:      amount,
+		Currency:    currency,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package ecobank
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenTransferParams_Validate(t *testing.T) {
+	assert.NoError(t, TokenTransferParams{}.Validate())
+	assert.NoError(t, TokenTransferParams{WithdrawalChannel: WithdrawalChannelXpressPoint}.Validate())
+	assert.EqualError(t, TokenTransferParams{WithdrawalChannel: "Branch"}.Validate(),
+		`unknown withdrawal channel "Branch": must be one of ["ATM" "Xpress point"]`)
+}
+
+func TestNewTokenTransferExtension(t *testing.T) {
+	ext, err := NewTokenTransferExtension("2323", decimal.NewFromInt(150), "GHS", WithdrawalChannelATM, TokenTransferParams{
+		SourceAccount:       "1441000574000",
+		BeneficiaryName:     "Owen Kay",
+		BeneficiaryMobileNo: "233241234567",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, TOKEN, ext.RequestType)
+	assert.Equal(t, "150", ext.Amount.String())
+	assert.Equal(t, "GHS", ext.Currency)
+
+	params := ext.ParamList.(*PaymentParams[TokenTransferParams]).Param()
+	assert.Equal(t, WithdrawalChannelATM, params.WithdrawalChannel)
+	assert.Equal(t, "150", params.Amount.String())
+	assert.Equal(t, "GHS", params.Currency)
+
+	b, err := ext.ParamList.MarshalJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `withdrawalChannel\", \"value\": \"ATM`)
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewTokenTransferExtension("2323", decimal.NewFromInt(150), "GHS", "", TokenTransferParams{})
+		assert.EqualError(t, err, `withdrawal channel is required: must be one of ["ATM" "Xpress point"]`)
+
+		_, err = NewTokenTransferExtension("2323", decimal.NewFromInt(150), "GHS", "Branch", TokenTransferParams{})
+		assert.ErrorContains(t, err, "unknown withdrawal channel")
+
+		_, err = NewTokenTransferExtension("2323", decimal.NewFromInt(150), "GHS", WithdrawalChannelATM, TokenTransferParams{Currency: "USD"})
+		assert.EqualError(t, err, "currency USD of the params does not match GHS")
+	})
+}